@@ -4,6 +4,8 @@ import (
 	"bondly-api/config"
 	"bondly-api/internal/database"
 	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"bondly-api/internal/services"
 	"log"
 )
 
@@ -26,16 +28,45 @@ func main() {
 
 	// 按依赖关系顺序迁移表
 	err = database.AutoMigrate(db,
-		&models.User{},               // 用户表（基础表）
-		&models.Post{},               // 文章表（新版）
-		&models.Content{},            // 内容表（兼容旧版）
-		&models.Proposal{},           // 提案表
-		&models.Vote{},               // 投票表
-		&models.Transaction{},        // 交易表
-		&models.Comment{},            // 评论表（依赖Post表）
-		&models.UserFollower{},       // 用户关注关系表
-		&models.WalletBinding{},      // 钱包绑定表
-		&models.ContentInteraction{}, // 内容互动表
+		&models.User{},                     // 用户表（基础表）
+		&models.Post{},                     // 文章表（新版）
+		&models.Content{},                  // 内容表（兼容旧版）
+		&models.Proposal{},                 // 提案表
+		&models.Vote{},                     // 投票表
+		&models.Transaction{},              // 交易表
+		&models.Comment{},                  // 评论表（依赖Post表）
+		&models.UserFollower{},             // 用户关注关系表
+		&models.WalletBinding{},            // 钱包绑定表
+		&models.ContentInteraction{},       // 内容互动表
+		&models.VoteDelegation{},           // 投票委托表
+		&models.ProposalPowerSnapshot{},    // 提案投票权快照表
+		&models.ReputationEvent{},          // 声誉事件表
+		&models.ReputationRule{},           // 声誉规则表
+		&models.ReputationRuleHit{},        // 声誉规则命中表
+		&models.ReputationSeason{},         // 声誉赛季表
+		&models.ReputationSeasonStanding{}, // 声誉赛季排行榜表
+		&models.UserPermission{},           // 用户权限表
+		&models.ReputationAuditLog{},       // 声誉变更审计表
+		&models.ReputationReconcileRun{},   // 声誉对账任务表
+		&models.ReputationDrift{},          // 声誉对账差异表
+		&models.Achievement{},              // 成就定义表
+		&models.UserAchievement{},          // 用户成就表
+		&models.CreatorRewardClaim{},       // 创作者互动奖励领取表
+		&models.ChainIndexCursor{},         // 链上事件索引进度表
+		&models.TreasuryFlow{},             // 金库资金流水表
+		&models.TreasuryDisbursement{},     // 金库拨款申请表
+		&models.PlatformDailyStat{},        // 平台每日统计表
+		&models.Tag{},                      // 标签表
+		&models.ContentTag{},               // 内容标签关联表
+		&models.TagFollow{},                // 标签关注表
+		&models.ContentRevision{},          // 内容修订表
+		&models.CommentLike{},              // 评论点赞表
+		&models.CommentEdit{},              // 评论编辑历史表
+		&models.UserBlock{},                // 用户屏蔽表
+		&models.Mention{},                  // 提及表
+		&models.Notification{},             // 通知表
+		&models.NotificationPreference{},   // 通知偏好表
+		&models.NotificationSetting{},      // 邮件摘要设置表
 	)
 
 	if err != nil {
@@ -54,4 +85,94 @@ func main() {
 	log.Println("   - user_followers (用户关注关系表)")
 	log.Println("   - wallet_bindings (钱包绑定表)")
 	log.Println("   - content_interactions (内容互动表)")
+	log.Println("   - vote_delegations (投票委托表)")
+	log.Println("   - proposal_power_snapshots (提案投票权快照表)")
+	log.Println("   - reputation_events (声誉事件表)")
+	log.Println("   - reputation_rules (声誉规则表)")
+	log.Println("   - reputation_rule_hits (声誉规则命中表)")
+	log.Println("   - reputation_seasons (声誉赛季表)")
+	log.Println("   - reputation_season_standings (声誉赛季排行榜表)")
+	log.Println("   - user_permissions (用户权限表)")
+	log.Println("   - reputation_audit_logs (声誉变更审计表)")
+	log.Println("   - reputation_reconcile_runs (声誉对账任务表)")
+	log.Println("   - reputation_drifts (声誉对账差异表)")
+	log.Println("   - achievements (成就定义表)")
+	log.Println("   - user_achievements (用户成就表)")
+	log.Println("   - creator_reward_claims (创作者互动奖励领取表)")
+	log.Println("   - chain_index_cursors (链上事件索引进度表)")
+	log.Println("   - treasury_flows (金库资金流水表)")
+	log.Println("   - treasury_disbursements (金库拨款申请表)")
+	log.Println("   - platform_daily_stats (平台每日统计表)")
+	log.Println("   - tags (标签表)")
+	log.Println("   - content_tags (内容标签关联表)")
+	log.Println("   - tag_follows (标签关注表)")
+	log.Println("   - content_revisions (内容修订表)")
+	log.Println("   - comment_likes (评论点赞表)")
+	log.Println("   - comment_edits (评论编辑历史表)")
+	log.Println("   - user_blocks (用户屏蔽表)")
+	log.Println("   - mentions (提及表)")
+	log.Println("   - notifications (通知表)")
+	log.Println("   - notification_preferences (通知偏好表)")
+	log.Println("   - notification_settings (邮件摘要设置表)")
+
+	// 创建全文检索索引
+	textSearchConfig := services.NormalizeTextSearchConfig(cfg.Search.TextSearchConfig)
+	if err := repositories.NewSearchRepository(db).EnsureSearchIndexes(textSearchConfig, cfg.Search.TrigramFallback); err != nil {
+		log.Fatalf("Failed to create search indexes: %v", err)
+	}
+	log.Printf("✅ Search indexes ready (text search config: %s, trigram fallback: %v)", textSearchConfig, cfg.Search.TrigramFallback)
+
+	// 创建评论树索引并为已有评论补齐物化路径
+	commentRepo := repositories.NewCommentRepository(db)
+	if err := commentRepo.EnsureTreeIndexes(); err != nil {
+		log.Fatalf("Failed to create comment tree indexes: %v", err)
+	}
+	threaded, err := commentRepo.BackfillTree()
+	if err != nil {
+		log.Fatalf("Failed to backfill comment tree: %v", err)
+	}
+	log.Printf("✅ Backfilled tree paths for %d comments", threaded)
+
+	// 清理重复的点赞通知并创建去重索引
+	deduped, err := repositories.NewNotificationRepository(db).EnsureDedupIndex()
+	if err != nil {
+		log.Fatalf("Failed to create notification dedup index: %v", err)
+	}
+	log.Printf("✅ Removed %d duplicate like notifications", deduped)
+
+	// 撤销重复的有效投票委托并创建唯一索引
+	revokedDelegations, err := repositories.NewDelegationRepository(db).EnsureActiveIndex()
+	if err != nil {
+		log.Fatalf("Failed to create vote delegation index: %v", err)
+	}
+	log.Printf("✅ Revoked %d duplicate active vote delegations", revokedDelegations)
+
+	// 归档重复的进行中赛季并创建唯一索引
+	archivedSeasons, err := repositories.NewReputationSeasonRepository(db).EnsureActiveIndex()
+	if err != nil {
+		log.Fatalf("Failed to create reputation season index: %v", err)
+	}
+	log.Printf("✅ Archived %d duplicate active reputation seasons", archivedSeasons)
+
+	// 为账本上线前已有的声誉分数补记期初事件，使分数等于事件之和
+	backfilled, err := repositories.NewReputationEventRepository(db).
+		BackfillOpeningBalances("期初余额", services.ReputationSourceOpeningBalance)
+	if err != nil {
+		log.Fatalf("Failed to backfill reputation events: %v", err)
+	}
+	log.Printf("✅ Backfilled %d opening reputation events", backfilled)
+
+	// 为发布时间字段上线前已发布的内容补齐发布时间，供信息流排序
+	published, err := repositories.NewContentRepository(db).BackfillPublishedAt()
+	if err != nil {
+		log.Fatalf("Failed to backfill content published_at: %v", err)
+	}
+	log.Printf("✅ Backfilled published_at for %d contents", published)
+
+	// 为渲染功能上线前的内容补齐 HTML、摘要、字数和目录
+	rendered, err := services.BackfillRenderedContent(repositories.NewContentRepository(db))
+	if err != nil {
+		log.Fatalf("Failed to backfill rendered content: %v", err)
+	}
+	log.Printf("✅ Rendered %d existing contents", rendered)
 }
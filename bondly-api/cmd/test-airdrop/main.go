@@ -42,7 +42,7 @@ func main() {
 	userRepo := repositories.NewUserRepository(db)
 
 	// 5. 初始化空投服务
-	_ = services.NewAirdropService(ethClient, userRepo, cfg)
+	_ = services.NewAirdropService(ethClient, userRepo, nil, cfg)
 
 	// 6. 测试中转钱包余额
 	relayWalletAddress := "0x2C830B8D1a6A9B840bde165a36df2A69fc9AA075"
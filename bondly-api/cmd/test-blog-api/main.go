@@ -66,7 +66,7 @@ func main() {
 
 	// 初始化服务和仓库
 	contentRepo := repositories.NewContentRepository(db)
-	contentService := services.NewContentService(contentRepo)
+	contentService := services.NewContentService(contentRepo, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	// 测试1: 模拟前端创建博客的请求
 	fmt.Println("\n📝 Test 1: Simulating frontend blog creation request...")
@@ -147,7 +147,7 @@ func main() {
 	}
 
 	// 调用service更新内容
-	updatedContent, err := contentService.UpdateContent(context.Background(), content.ID, &updateData)
+	updatedContent, err := contentService.UpdateContent(context.Background(), content.ID, content.AuthorID, &updateData)
 	if err != nil {
 		log.Fatal("Failed to update content with NFT data:", err)
 	}
@@ -158,7 +158,7 @@ func main() {
 	fmt.Println("\n📝 Test 4: Verifying final result...")
 
 	// 查询最终的内容
-	finalContent, err := contentService.GetContent(context.Background(), content.ID)
+	finalContent, err := contentService.GetContent(context.Background(), content.ID, content.AuthorID)
 	if err != nil {
 		log.Fatal("Failed to get final content:", err)
 	}
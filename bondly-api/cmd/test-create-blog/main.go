@@ -53,7 +53,7 @@ func main() {
 
 	// 初始化服务和仓库
 	contentRepo := repositories.NewContentRepository(db)
-	contentService := services.NewContentService(contentRepo)
+	contentService := services.NewContentService(contentRepo, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	// 测试1: 创建基本博客
 	fmt.Println("\n📝 Test 1: Creating basic blog...")
@@ -73,7 +73,7 @@ func main() {
 	fmt.Printf("✅ Created basic content with ID: %d\n", basicContent.ID)
 
 	// 验证创建的内容
-	createdContent, err := contentService.GetContent(context.Background(), basicContent.ID)
+	createdContent, err := contentService.GetContent(context.Background(), basicContent.ID, basicContent.AuthorID)
 	if err != nil {
 		log.Fatal("Failed to get created content:", err)
 	}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"strconv"
 	"strings"
@@ -10,16 +11,28 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Ethereum EthereumConfig
-	Kafka    KafkaConfig
-	Logging  LoggingConfig
-	CORS     CORSConfig
-	JWT      JWTConfig
-	Wallet   WalletConfig
-	Email    EmailConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	Redis       RedisConfig
+	Ethereum    EthereumConfig
+	Kafka       KafkaConfig
+	Logging     LoggingConfig
+	CORS        CORSConfig
+	JWT         JWTConfig
+	Wallet      WalletConfig
+	Email       EmailConfig
+	Governance  GovernanceConfig
+	Reputation  ReputationConfig
+	Achievement AchievementConfig
+	Treasury    TreasuryConfig
+	Stats       StatsConfig
+	Search      SearchConfig
+	Feed        FeedConfig
+	Ranking     RankingConfig
+	Content     ContentConfig
+	Comment     CommentConfig
+	Realtime    RealtimeConfig
+	Digest      DigestConfig
 }
 
 type ServerConfig struct {
@@ -50,12 +63,16 @@ type RedisConfig struct {
 }
 
 type EthereumConfig struct {
-	RPCURL                 string
-	PrivateKey             string
-	ContractAddress        string
-	RelayWalletKey         string // 中转钱包私钥
-	ReputationVaultAddress string // ReputationVault合约地址
-	ContentNFTAddress      string // ContentNFT合约地址
+	RPCURL                    string
+	PrivateKey                string
+	ContractAddress           string
+	RelayWalletKey            string // 中转钱包私钥
+	ReputationVaultAddress    string // ReputationVault合约地址
+	ContentNFTAddress         string // ContentNFT合约地址
+	GeneralStakingAddress     string // GeneralStaking合约地址
+	AchievementNFTAddress     string // AchievementNFT合约地址
+	InteractionStakingAddress string // InteractionStaking合约地址
+	TreasuryAddress           string // BondlyTreasury合约地址
 }
 
 type KafkaConfig struct {
@@ -87,6 +104,93 @@ type EmailConfig struct {
 	FromEmail string
 }
 
+type GovernanceConfig struct {
+	ConvictionHalfLife      time.Duration // 信念投票权重累积的半衰期
+	ConvictionTallyInterval time.Duration // 信念投票提案定时重新计票的间隔
+	FinalizeInterval        time.Duration // 结算已结束提案的间隔
+}
+
+type ReputationConfig struct {
+	ChainBatchEnabled  bool          // 是否将规则产生的声誉变更批量同步到链上
+	ChainBatchInterval time.Duration // 链上批量同步间隔
+	DecayEnabled       bool          // 是否按半衰期定期衰减声誉分数
+	DecayHalfLife      time.Duration // 声誉分数衰减的半衰期
+	DecayInterval      time.Duration // 衰减任务执行间隔
+	ReconcileEnabled   bool          // 是否定期对账数据库与 ReputationVault 的声誉分数
+	ReconcileInterval  time.Duration // 对账任务执行间隔
+	ReconcileAuthority string        // 以哪一方为准：database 将差额写入链上，chain 校正数据库
+	ReconcileDryRun    bool          // 定时对账只生成报告，不做任何修正
+}
+
+type AchievementConfig struct {
+	MintEnabled  bool          // 是否将获得的成就铸造为 AchievementNFT 发放到用户钱包
+	MintInterval time.Duration // 成就铸造任务执行间隔
+}
+
+type TreasuryConfig struct {
+	IndexEnabled  bool          // 是否定期索引 BondlyTreasury 的资金流入流出事件
+	IndexInterval time.Duration // 索引任务执行间隔
+	StartBlock    uint64        // 首次索引的起始区块（通常为金库合约部署区块）
+	BlockRange    uint64        // 单次 eth_getLogs 查询的最大区块跨度
+	Confirmations uint64        // 只索引至少有这么多个确认的区块，避免链重组后留下已失效的事件
+}
+
+type StatsConfig struct {
+	AggregateInterval time.Duration // 平台统计聚合任务执行间隔
+}
+
+type SearchConfig struct {
+	TextSearchConfig string // PostgreSQL 全文检索配置，如 simple、english，或基于 zhparser 创建的中文配置
+	TrigramFallback  bool   // 是否启用 pg_trgm 子串匹配，弥补分词器无法切分中文的情况
+}
+
+type FeedConfig struct {
+	FanoutThreshold int64         // 粉丝数达到该值的作者不再写扩散，由读取方实时拉取
+	MaxItems        int64         // 每个用户 Redis 信息流保留的最大条数
+	TTL             time.Duration // 用户信息流缓存的过期时间，过期后读取时从数据库重建
+}
+
+type RankingConfig struct {
+	RebuildInterval time.Duration // 排行榜全量重建间隔，用于计入浏览量、淘汰过期窗口并修正增量误差
+	HotHorizon      time.Duration // 参与热度排行的内容发布时间范围
+}
+
+type ContentConfig struct {
+	ReviewTypes       []string      // 需要审核后才能发布的内容类型
+	SchedulerInterval time.Duration // 定时发布任务的检查间隔
+}
+
+type CommentConfig struct {
+	MaxTreeDepth int // 评论树接口单次最多展开的回复层数
+}
+
+type RealtimeConfig struct {
+	SendBuffer        int           // 每个连接待发送消息的缓冲条数，写满时断开该连接
+	MaxTopics         int           // 每个连接最多订阅的主题数
+	HeartbeatInterval time.Duration // WebSocket ping 和 SSE 心跳的发送间隔
+}
+
+type DigestConfig struct {
+	Enabled           bool          // 是否定期发送通知摘要邮件
+	CheckInterval     time.Duration // 检查到期摘要的间隔，同时也是摘要发送时间的最大误差
+	PublicURL         string        // 邮件中退订链接使用的 API 外部访问地址
+	UnsubscribeSecret string        // 退订链接的签名密钥，必须单独配置
+}
+
+// defaultSecret 示例配置中的占位密钥，不能用于签名
+const defaultSecret = "your-secret-key"
+
+// Validate 启用摘要邮件时要求配置专用的退订链接签名密钥
+func (c DigestConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.UnsubscribeSecret == "" || c.UnsubscribeSecret == defaultSecret {
+		return errors.New("DIGEST_UNSUBSCRIBE_SECRET must be set to a dedicated secret when DIGEST_ENABLED is true")
+	}
+	return nil
+}
+
 func Load() (*Config, error) {
 	// 加载 .env 文件
 	if err := godotenv.Load(); err != nil {
@@ -119,12 +223,16 @@ func Load() (*Config, error) {
 			WriteTimeout: getEnvAsInt("REDIS_WRITE_TIMEOUT", 3),
 		},
 		Ethereum: EthereumConfig{
-			RPCURL:                 getEnv("ETH_RPC_URL", "http://localhost:8545"),
-			PrivateKey:             getEnv("ETH_PRIVATE_KEY", ""),
-			ContractAddress:        getEnv("ETH_CONTRACT_ADDRESS", ""),
-			RelayWalletKey:         getEnv("ETH_RELAY_WALLET_KEY", ""),
-			ReputationVaultAddress: getEnv("ETH_REPUTATION_VAULT_ADDRESS", ""),
-			ContentNFTAddress:      getEnv("ETH_CONTENT_NFT_ADDRESS", ""),
+			RPCURL:                    getEnv("ETH_RPC_URL", "http://localhost:8545"),
+			PrivateKey:                getEnv("ETH_PRIVATE_KEY", ""),
+			ContractAddress:           getEnv("ETH_CONTRACT_ADDRESS", ""),
+			RelayWalletKey:            getEnv("ETH_RELAY_WALLET_KEY", ""),
+			ReputationVaultAddress:    getEnv("ETH_REPUTATION_VAULT_ADDRESS", ""),
+			ContentNFTAddress:         getEnv("ETH_CONTENT_NFT_ADDRESS", ""),
+			GeneralStakingAddress:     getEnv("ETH_GENERAL_STAKING_ADDRESS", ""),
+			AchievementNFTAddress:     getEnv("ETH_ACHIEVEMENT_NFT_ADDRESS", ""),
+			InteractionStakingAddress: getEnv("ETH_INTERACTION_STAKING_ADDRESS", ""),
+			TreasuryAddress:           getEnv("ETH_TREASURY_ADDRESS", ""),
 		},
 		Kafka: KafkaConfig{
 			Brokers:     strings.Split(getEnv("KAFKA_BROKERS", "localhost:9092"), ","),
@@ -138,7 +246,7 @@ func Load() (*Config, error) {
 			AllowedOrigins: strings.Split(getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:3000,http://localhost:5173,http://localhost:5174"), ","),
 		},
 		JWT: JWTConfig{
-			Secret:    getEnv("JWT_SECRET", "your-secret-key"),
+			Secret:    getEnv("JWT_SECRET", defaultSecret),
 			ExpiresIn: time.Duration(getEnvAsInt("JWT_EXPIRES_IN_HOURS", 24)) * time.Hour,
 		},
 		Wallet: WalletConfig{
@@ -149,6 +257,67 @@ func Load() (*Config, error) {
 			ResendKey: getEnv("RESEND_API_KEY", ""),
 			FromEmail: getEnv("EMAIL_FROM", ""),
 		},
+		Governance: GovernanceConfig{
+			ConvictionHalfLife:      time.Duration(getEnvAsInt("GOVERNANCE_CONVICTION_HALF_LIFE_HOURS", 72)) * time.Hour,
+			ConvictionTallyInterval: time.Duration(getEnvAsInt("GOVERNANCE_CONVICTION_TALLY_MINUTES", 10)) * time.Minute,
+			FinalizeInterval:        time.Duration(getEnvAsInt("GOVERNANCE_FINALIZE_MINUTES", 5)) * time.Minute,
+		},
+		Reputation: ReputationConfig{
+			ChainBatchEnabled:  getEnvAsBool("REPUTATION_CHAIN_BATCH_ENABLED", false),
+			ChainBatchInterval: time.Duration(getEnvAsInt("REPUTATION_CHAIN_BATCH_INTERVAL_MINUTES", 10)) * time.Minute,
+			DecayEnabled:       getEnvAsBool("REPUTATION_DECAY_ENABLED", false),
+			DecayHalfLife:      time.Duration(getEnvAsInt("REPUTATION_DECAY_HALF_LIFE_DAYS", 180)) * 24 * time.Hour,
+			DecayInterval:      time.Duration(getEnvAsInt("REPUTATION_DECAY_INTERVAL_HOURS", 24)) * time.Hour,
+			ReconcileEnabled:   getEnvAsBool("REPUTATION_RECONCILE_ENABLED", false),
+			ReconcileInterval:  time.Duration(getEnvAsInt("REPUTATION_RECONCILE_INTERVAL_MINUTES", 60)) * time.Minute,
+			ReconcileAuthority: getEnv("REPUTATION_RECONCILE_AUTHORITY", "database"),
+			ReconcileDryRun:    getEnvAsBool("REPUTATION_RECONCILE_DRY_RUN", true),
+		},
+		Achievement: AchievementConfig{
+			MintEnabled:  getEnvAsBool("ACHIEVEMENT_MINT_ENABLED", false),
+			MintInterval: time.Duration(getEnvAsInt("ACHIEVEMENT_MINT_INTERVAL_MINUTES", 10)) * time.Minute,
+		},
+		Treasury: TreasuryConfig{
+			IndexEnabled:  getEnvAsBool("TREASURY_INDEX_ENABLED", false),
+			IndexInterval: time.Duration(getEnvAsInt("TREASURY_INDEX_INTERVAL_MINUTES", 5)) * time.Minute,
+			StartBlock:    uint64(getEnvAsInt("TREASURY_INDEX_START_BLOCK", 0)),
+			BlockRange:    uint64(getEnvAsInt("TREASURY_INDEX_BLOCK_RANGE", 2000)),
+			Confirmations: uint64(getEnvAsInt("TREASURY_INDEX_CONFIRMATIONS", 12)),
+		},
+		Stats: StatsConfig{
+			AggregateInterval: time.Duration(getEnvAsInt("STATS_AGGREGATE_INTERVAL_MINUTES", 15)) * time.Minute,
+		},
+		Search: SearchConfig{
+			TextSearchConfig: getEnv("SEARCH_TEXT_CONFIG", "simple"),
+			TrigramFallback:  getEnvAsBool("SEARCH_TRIGRAM_FALLBACK", true),
+		},
+		Feed: FeedConfig{
+			FanoutThreshold: int64(getEnvAsInt("FEED_FANOUT_THRESHOLD", 1000)),
+			MaxItems:        int64(getEnvAsInt("FEED_MAX_ITEMS", 800)),
+			TTL:             time.Duration(getEnvAsInt("FEED_TTL_HOURS", 72)) * time.Hour,
+		},
+		Ranking: RankingConfig{
+			RebuildInterval: time.Duration(getEnvAsInt("RANKING_REBUILD_INTERVAL_MINUTES", 10)) * time.Minute,
+			HotHorizon:      time.Duration(getEnvAsInt("RANKING_HOT_HORIZON_DAYS", 14)) * 24 * time.Hour,
+		},
+		Content: ContentConfig{
+			ReviewTypes:       getEnvAsList("CONTENT_REVIEW_TYPES", ""),
+			SchedulerInterval: time.Duration(getEnvAsInt("CONTENT_SCHEDULER_INTERVAL_SECONDS", 30)) * time.Second,
+		},
+		Comment: CommentConfig{
+			MaxTreeDepth: getEnvAsInt("COMMENT_TREE_MAX_DEPTH", 5),
+		},
+		Realtime: RealtimeConfig{
+			SendBuffer:        getEnvAsInt("REALTIME_SEND_BUFFER", 64),
+			MaxTopics:         getEnvAsInt("REALTIME_MAX_TOPICS", 50),
+			HeartbeatInterval: time.Duration(getEnvAsInt("REALTIME_HEARTBEAT_SECONDS", 25)) * time.Second,
+		},
+		Digest: DigestConfig{
+			Enabled:           getEnvAsBool("DIGEST_ENABLED", false),
+			CheckInterval:     time.Duration(getEnvAsInt("DIGEST_CHECK_INTERVAL_MINUTES", 60)) * time.Minute,
+			PublicURL:         strings.TrimRight(getEnv("DIGEST_PUBLIC_URL", "http://localhost:8080"), "/"),
+			UnsubscribeSecret: getEnv("DIGEST_UNSUBSCRIBE_SECRET", ""),
+		},
 	}, nil
 }
 
@@ -167,3 +336,23 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsList 读取逗号分隔的环境变量，忽略空白项
+func getEnvAsList(key, defaultValue string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
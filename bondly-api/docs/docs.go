@@ -1449,7 +1449,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "更新指定提案的信息",
+                "description": "更新指定提案的信息；状态由结束时的计票结果自动结算，不能修改，投票开始后也不能修改开始和结束时间",
                 "consumes": [
                     "application/json"
                 ],
@@ -2021,7 +2021,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "创建新用户，支持Web2和Web3用户信息",
+                "description": "创建新用户，支持Web2和Web3用户信息；新用户角色固定为 user",
                 "consumes": [
                     "application/json"
                 ],
@@ -3241,14 +3241,6 @@ const docTemplate = `{
                     "type": "string",
                     "example": "John Doe"
                 },
-                "reputation_score": {
-                    "type": "integer",
-                    "example": 0
-                },
-                "role": {
-                    "type": "string",
-                    "example": "user"
-                },
                 "wallet_address": {
                     "type": "string",
                     "example": "0x1234567890abcdef1234567890abcdef12345678"
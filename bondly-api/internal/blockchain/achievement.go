@@ -0,0 +1,168 @@
+package blockchain
+
+import (
+	"bondly-api/config"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/sirupsen/logrus"
+)
+
+// AchievementNFT 成就 NFT 合约接口
+type AchievementNFT struct {
+	client       *ethclient.Client
+	contractAddr common.Address
+	abi          abi.ABI
+}
+
+// AchievementNFT 合约 ABI（仅包含 API 使用的方法）
+const AchievementNFTABI = `[
+	{
+		"inputs": [
+			{
+				"internalType": "address",
+				"name": "to",
+				"type": "address"
+			},
+			{
+				"internalType": "uint256",
+				"name": "achievementId",
+				"type": "uint256"
+			},
+			{
+				"internalType": "string",
+				"name": "tokenUri",
+				"type": "string"
+			}
+		],
+		"name": "mintAchievement",
+		"outputs": [
+			{
+				"internalType": "uint256",
+				"name": "",
+				"type": "uint256"
+			}
+		],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{
+				"internalType": "address",
+				"name": "",
+				"type": "address"
+			},
+			{
+				"internalType": "uint256",
+				"name": "",
+				"type": "uint256"
+			}
+		],
+		"name": "hasAchievement",
+		"outputs": [
+			{
+				"internalType": "bool",
+				"name": "",
+				"type": "bool"
+			}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+
+// NewAchievementNFT 创建 AchievementNFT 合约实例
+func NewAchievementNFT(config config.EthereumConfig) (*AchievementNFT, error) {
+	if !common.IsHexAddress(config.AchievementNFTAddress) {
+		return nil, fmt.Errorf("invalid AchievementNFT address: %s", config.AchievementNFTAddress)
+	}
+
+	// 连接以太坊客户端
+	client, err := ethclient.Dial(config.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Ethereum client: %w", err)
+	}
+
+	// 解析合约 ABI
+	contractABI, err := abi.JSON(strings.NewReader(AchievementNFTABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse AchievementNFT ABI: %w", err)
+	}
+
+	return &AchievementNFT{
+		client:       client,
+		contractAddr: common.HexToAddress(config.AchievementNFTAddress),
+		abi:          contractABI,
+	}, nil
+}
+
+// HasAchievement 检查用户是否已持有指定成就
+func (an *AchievementNFT) HasAchievement(ctx context.Context, userAddress string, achievementID int64) (bool, error) {
+	// 验证地址格式
+	if !common.IsHexAddress(userAddress) {
+		return false, fmt.Errorf("invalid address format: %s", userAddress)
+	}
+
+	// 构建调用数据
+	data, err := an.abi.Pack("hasAchievement", common.HexToAddress(userAddress), big.NewInt(achievementID))
+	if err != nil {
+		return false, fmt.Errorf("failed to pack hasAchievement call: %w", err)
+	}
+
+	// 调用合约
+	result, err := an.client.CallContract(ctx, buildCallMsg(an.contractAddr, data), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to call hasAchievement: %w", err)
+	}
+
+	// 解析结果
+	var owned bool
+	err = an.abi.UnpackIntoInterface(&owned, "hasAchievement", result)
+	if err != nil {
+		return false, fmt.Errorf("failed to unpack hasAchievement result: %w", err)
+	}
+
+	return owned, nil
+}
+
+// MintAchievement 向用户颁发成就 NFT（需要 MINTER_ROLE），等待交易回执。
+// 交易已广播但未确认成功时同时返回交易哈希和错误
+func (an *AchievementNFT) MintAchievement(ctx context.Context, userAddress string, achievementID int64, tokenURI string, privateKey string) (string, error) {
+	// 验证地址格式
+	if !common.IsHexAddress(userAddress) {
+		return "", fmt.Errorf("invalid address format: %s", userAddress)
+	}
+
+	// 构建交易数据
+	data, err := an.abi.Pack("mintAchievement", common.HexToAddress(userAddress), big.NewInt(achievementID), tokenURI)
+	if err != nil {
+		return "", fmt.Errorf("failed to pack mintAchievement call: %w", err)
+	}
+
+	// 发送交易并等待回执
+	txHash, err := sendContractTransaction(ctx, an.client, an.contractAddr, data, privateKey)
+	if err != nil {
+		return txHash, fmt.Errorf("failed to send mintAchievement transaction: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_address":   userAddress,
+		"achievement_id": achievementID,
+		"tx_hash":        txHash,
+	}).Info("颁发成就 NFT 交易已确认")
+
+	return txHash, nil
+}
+
+// Close 关闭以太坊客户端连接
+func (an *AchievementNFT) Close() {
+	if an.client != nil {
+		an.client.Close()
+	}
+}
@@ -0,0 +1,271 @@
+package blockchain
+
+import (
+	"bondly-api/config"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/sirupsen/logrus"
+)
+
+// InteractionStaking 合约中的互动类型枚举
+const (
+	InteractionTypeLike     uint8 = 0
+	InteractionTypeComment  uint8 = 1
+	InteractionTypeFavorite uint8 = 2
+)
+
+// InteractionStaking 互动质押合约接口
+type InteractionStaking struct {
+	client         *ethclient.Client
+	contractAddr   common.Address
+	abi            abi.ABI
+	contentNFTAddr common.Address // 为零地址时无法查询内容 NFT 的创作者
+	contentNFTABI  abi.ABI
+}
+
+// contentMeta ContentNFT.getContentMeta 的返回值
+type contentMeta struct {
+	Title      string
+	Summary    string
+	CoverImage string
+	IpfsLink   string
+	Creator    common.Address
+}
+
+// InteractionStaking 合约 ABI（仅包含 API 使用的方法）
+const InteractionStakingABI = `[
+	{
+		"inputs": [
+			{
+				"internalType": "uint256",
+				"name": "tokenId",
+				"type": "uint256"
+			},
+			{
+				"internalType": "enum InteractionStaking.InteractionType",
+				"name": "interactionType",
+				"type": "uint8"
+			}
+		],
+		"name": "getTotalStaked",
+		"outputs": [
+			{
+				"internalType": "uint256",
+				"name": "",
+				"type": "uint256"
+			}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{
+				"internalType": "uint256",
+				"name": "",
+				"type": "uint256"
+			},
+			{
+				"internalType": "enum InteractionStaking.InteractionType",
+				"name": "",
+				"type": "uint8"
+			}
+		],
+		"name": "rewardClaimed",
+		"outputs": [
+			{
+				"internalType": "bool",
+				"name": "",
+				"type": "bool"
+			}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{
+				"internalType": "uint256",
+				"name": "tokenId",
+				"type": "uint256"
+			},
+			{
+				"internalType": "enum InteractionStaking.InteractionType",
+				"name": "interactionType",
+				"type": "uint8"
+			}
+		],
+		"name": "claimReward",
+		"outputs": [],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	}
+]`
+
+// ContentNFTMetaABI ContentNFT 合约 ABI（仅包含 getContentMeta），claimReward 只允许元数据中的创作者调用
+const ContentNFTMetaABI = `[
+	{
+		"inputs": [
+			{
+				"internalType": "uint256",
+				"name": "tokenId",
+				"type": "uint256"
+			}
+		],
+		"name": "getContentMeta",
+		"outputs": [
+			{
+				"components": [
+					{"internalType": "string", "name": "title", "type": "string"},
+					{"internalType": "string", "name": "summary", "type": "string"},
+					{"internalType": "string", "name": "coverImage", "type": "string"},
+					{"internalType": "string", "name": "ipfsLink", "type": "string"},
+					{"internalType": "address", "name": "creator", "type": "address"}
+				],
+				"internalType": "struct ContentNFT.ContentMeta",
+				"name": "",
+				"type": "tuple"
+			}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+
+// NewInteractionStaking 创建 InteractionStaking 合约实例
+func NewInteractionStaking(config config.EthereumConfig) (*InteractionStaking, error) {
+	if !common.IsHexAddress(config.InteractionStakingAddress) {
+		return nil, fmt.Errorf("invalid InteractionStaking address: %s", config.InteractionStakingAddress)
+	}
+
+	// 连接以太坊客户端
+	client, err := ethclient.Dial(config.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Ethereum client: %w", err)
+	}
+
+	// 解析合约 ABI
+	contractABI, err := abi.JSON(strings.NewReader(InteractionStakingABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse InteractionStaking ABI: %w", err)
+	}
+	contentNFTABI, err := abi.JSON(strings.NewReader(ContentNFTMetaABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ContentNFT ABI: %w", err)
+	}
+
+	staking := &InteractionStaking{
+		client:        client,
+		contractAddr:  common.HexToAddress(config.InteractionStakingAddress),
+		abi:           contractABI,
+		contentNFTABI: contentNFTABI,
+	}
+	if common.IsHexAddress(config.ContentNFTAddress) {
+		staking.contentNFTAddr = common.HexToAddress(config.ContentNFTAddress)
+	}
+	return staking, nil
+}
+
+// GetTotalStaked 获取内容 NFT 某互动类型尚未领取的累计质押（wei）
+func (is *InteractionStaking) GetTotalStaked(ctx context.Context, tokenID int64, interactionType uint8) (*big.Int, error) {
+	data, err := is.abi.Pack("getTotalStaked", big.NewInt(tokenID), interactionType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack getTotalStaked call: %w", err)
+	}
+
+	result, err := is.client.CallContract(ctx, buildCallMsg(is.contractAddr, data), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call getTotalStaked: %w", err)
+	}
+
+	var total *big.Int
+	err = is.abi.UnpackIntoInterface(&total, "getTotalStaked", result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack getTotalStaked result: %w", err)
+	}
+
+	return total, nil
+}
+
+// RewardClaimed 检查内容 NFT 某互动类型的奖励是否已被领取
+func (is *InteractionStaking) RewardClaimed(ctx context.Context, tokenID int64, interactionType uint8) (bool, error) {
+	data, err := is.abi.Pack("rewardClaimed", big.NewInt(tokenID), interactionType)
+	if err != nil {
+		return false, fmt.Errorf("failed to pack rewardClaimed call: %w", err)
+	}
+
+	result, err := is.client.CallContract(ctx, buildCallMsg(is.contractAddr, data), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to call rewardClaimed: %w", err)
+	}
+
+	var claimed bool
+	err = is.abi.UnpackIntoInterface(&claimed, "rewardClaimed", result)
+	if err != nil {
+		return false, fmt.Errorf("failed to unpack rewardClaimed result: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// ContentCreator 获取内容 NFT 元数据中的创作者地址，claimReward 只允许该地址调用
+func (is *InteractionStaking) ContentCreator(ctx context.Context, tokenID int64) (string, error) {
+	if is.contentNFTAddr == (common.Address{}) {
+		return "", fmt.Errorf("content nft address not configured")
+	}
+
+	data, err := is.contentNFTABI.Pack("getContentMeta", big.NewInt(tokenID))
+	if err != nil {
+		return "", fmt.Errorf("failed to pack getContentMeta call: %w", err)
+	}
+
+	result, err := is.client.CallContract(ctx, buildCallMsg(is.contentNFTAddr, data), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to call getContentMeta: %w", err)
+	}
+
+	values, err := is.contentNFTABI.Unpack("getContentMeta", result)
+	if err != nil || len(values) == 0 {
+		return "", fmt.Errorf("failed to unpack getContentMeta result: %w", err)
+	}
+	meta := *abi.ConvertType(values[0], new(contentMeta)).(*contentMeta)
+
+	return meta.Creator.Hex(), nil
+}
+
+// ClaimReward 以创作者身份领取内容 NFT 某互动类型的奖励，等待交易回执。
+// 交易已广播但未确认成功时同时返回交易哈希和错误
+func (is *InteractionStaking) ClaimReward(ctx context.Context, tokenID int64, interactionType uint8, privateKey string) (string, error) {
+	// 构建交易数据
+	data, err := is.abi.Pack("claimReward", big.NewInt(tokenID), interactionType)
+	if err != nil {
+		return "", fmt.Errorf("failed to pack claimReward call: %w", err)
+	}
+
+	// 发送交易并等待回执
+	txHash, err := sendContractTransaction(ctx, is.client, is.contractAddr, data, privateKey)
+	if err != nil {
+		return txHash, fmt.Errorf("failed to send claimReward transaction: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"token_id":         tokenID,
+		"interaction_type": interactionType,
+		"tx_hash":          txHash,
+	}).Info("领取互动奖励交易已确认")
+
+	return txHash, nil
+}
+
+// Close 关闭以太坊客户端连接
+func (is *InteractionStaking) Close() {
+	if is.client != nil {
+		is.client.Close()
+	}
+}
@@ -0,0 +1,193 @@
+package blockchain
+
+import (
+	"bondly-api/config"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/sirupsen/logrus"
+)
+
+// stakedBatchSize 单个 JSON-RPC 批量请求中的 getStaked 调用数
+const stakedBatchSize = 100
+
+// GeneralStaking 合约接口
+type GeneralStaking struct {
+	client       *ethclient.Client
+	contractAddr common.Address
+	abi          abi.ABI
+}
+
+// GeneralStaking 合约 ABI（只读部分）
+const GeneralStakingABI = `[
+	{
+		"inputs": [
+			{
+				"internalType": "address",
+				"name": "user",
+				"type": "address"
+			}
+		],
+		"name": "getStaked",
+		"outputs": [
+			{
+				"internalType": "uint256",
+				"name": "",
+				"type": "uint256"
+			}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "totalStaked",
+		"outputs": [
+			{
+				"internalType": "uint256",
+				"name": "",
+				"type": "uint256"
+			}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+
+// NewGeneralStaking 创建 GeneralStaking 合约实例
+func NewGeneralStaking(config config.EthereumConfig) (*GeneralStaking, error) {
+	if !common.IsHexAddress(config.GeneralStakingAddress) {
+		return nil, fmt.Errorf("invalid GeneralStaking address: %s", config.GeneralStakingAddress)
+	}
+
+	// 连接以太坊客户端
+	client, err := ethclient.Dial(config.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Ethereum client: %w", err)
+	}
+
+	// 解析合约 ABI
+	contractABI, err := abi.JSON(strings.NewReader(GeneralStakingABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GeneralStaking ABI: %w", err)
+	}
+
+	return &GeneralStaking{
+		client:       client,
+		contractAddr: common.HexToAddress(config.GeneralStakingAddress),
+		abi:          contractABI,
+	}, nil
+}
+
+// GetStaked 获取用户质押的 BOND 数量（wei）
+func (gs *GeneralStaking) GetStaked(ctx context.Context, userAddress string) (*big.Int, error) {
+	// 验证地址格式
+	if !common.IsHexAddress(userAddress) {
+		return nil, fmt.Errorf("invalid address format: %s", userAddress)
+	}
+
+	// 构建调用数据
+	data, err := gs.abi.Pack("getStaked", common.HexToAddress(userAddress))
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack getStaked call: %w", err)
+	}
+
+	// 调用合约
+	result, err := gs.client.CallContract(ctx, buildCallMsg(gs.contractAddr, data), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call getStaked: %w", err)
+	}
+
+	// 解析结果
+	var staked *big.Int
+	err = gs.abi.UnpackIntoInterface(&staked, "getStaked", result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack getStaked result: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_address": userAddress,
+		"staked":       staked.String(),
+	}).Debug("获取用户质押数量成功")
+
+	return staked, nil
+}
+
+// GetStakedBatch 通过 JSON-RPC 批量请求获取多个地址的质押数量（wei），返回以传入地址为键的结果
+func (gs *GeneralStaking) GetStakedBatch(ctx context.Context, userAddresses []string) (map[string]*big.Int, error) {
+	staked := make(map[string]*big.Int, len(userAddresses))
+	for start := 0; start < len(userAddresses); start += stakedBatchSize {
+		chunk := userAddresses[start:min(start+stakedBatchSize, len(userAddresses))]
+
+		results := make([]hexutil.Bytes, len(chunk))
+		batch := make([]rpc.BatchElem, len(chunk))
+		for i, userAddress := range chunk {
+			if !common.IsHexAddress(userAddress) {
+				return nil, fmt.Errorf("invalid address format: %s", userAddress)
+			}
+			data, err := gs.abi.Pack("getStaked", common.HexToAddress(userAddress))
+			if err != nil {
+				return nil, fmt.Errorf("failed to pack getStaked call: %w", err)
+			}
+			batch[i] = rpc.BatchElem{
+				Method: "eth_call",
+				Args: []interface{}{
+					map[string]interface{}{"to": gs.contractAddr, "data": hexutil.Bytes(data)},
+					"latest",
+				},
+				Result: &results[i],
+			}
+		}
+
+		if err := gs.client.Client().BatchCallContext(ctx, batch); err != nil {
+			return nil, fmt.Errorf("failed to batch call getStaked: %w", err)
+		}
+		for i, elem := range batch {
+			if elem.Error != nil {
+				return nil, fmt.Errorf("failed to call getStaked for %s: %w", chunk[i], elem.Error)
+			}
+			var amount *big.Int
+			if err := gs.abi.UnpackIntoInterface(&amount, "getStaked", results[i]); err != nil {
+				return nil, fmt.Errorf("failed to unpack getStaked result: %w", err)
+			}
+			staked[chunk[i]] = amount
+		}
+	}
+
+	return staked, nil
+}
+
+// TotalStaked 获取合约总质押量（wei）
+func (gs *GeneralStaking) TotalStaked(ctx context.Context) (*big.Int, error) {
+	data, err := gs.abi.Pack("totalStaked")
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack totalStaked call: %w", err)
+	}
+
+	result, err := gs.client.CallContract(ctx, buildCallMsg(gs.contractAddr, data), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call totalStaked: %w", err)
+	}
+
+	var total *big.Int
+	err = gs.abi.UnpackIntoInterface(&total, "totalStaked", result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack totalStaked result: %w", err)
+	}
+
+	return total, nil
+}
+
+// Close 关闭以太坊客户端连接
+func (gs *GeneralStaking) Close() {
+	if gs.client != nil {
+		gs.client.Close()
+	}
+}
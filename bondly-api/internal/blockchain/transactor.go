@@ -0,0 +1,70 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// transactionReceiptTimeout 等待交易回执的最长时间，超时后交易可能仍会上链
+const transactionReceiptTimeout = 3 * time.Minute
+
+// ErrTransactionReverted 交易已上链但执行失败
+var ErrTransactionReverted = errors.New("transaction reverted")
+
+// sendContractTransaction 用私钥签名并发送合约调用交易，等待回执。
+// 交易已广播后出错（等待超时或执行失败）时仍返回交易哈希，调用方据此判断交易是否可能上链
+func sendContractTransaction(ctx context.Context, client *ethclient.Client, to common.Address, data []byte, privateKeyHex string) (string, error) {
+	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse private key: %w", err)
+	}
+	from := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	nonce, err := client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return "", fmt.Errorf("failed to get nonce: %w", err)
+	}
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get gas price: %w", err)
+	}
+	// 预估 gas 同时会执行一次调用，合约会回滚的交易在这里就失败，不会广播
+	gasLimit, err := client.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &to, Data: data})
+	if err != nil {
+		return "", fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get chain ID: %w", err)
+	}
+
+	tx := types.NewTransaction(nonce, to, common.Big0, gasLimit, gasPrice, data)
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), privateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	if err := client.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	txHash := signedTx.Hash().Hex()
+
+	waitCtx, cancel := context.WithTimeout(ctx, transactionReceiptTimeout)
+	defer cancel()
+	receipt, err := bind.WaitMined(waitCtx, client, signedTx)
+	if err != nil {
+		return txHash, fmt.Errorf("failed to wait for receipt: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return txHash, ErrTransactionReverted
+	}
+	return txHash, nil
+}
@@ -0,0 +1,398 @@
+package blockchain
+
+import (
+	"bondly-api/config"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// 金库资产
+const (
+	TreasuryAssetETH  = "ETH"
+	TreasuryAssetBOND = "BOND"
+)
+
+// 金库资金流向
+const (
+	TreasuryFlowIn  = "in"
+	TreasuryFlowOut = "out"
+)
+
+// BondlyTreasury 金库合约接口
+type BondlyTreasury struct {
+	client       *ethclient.Client
+	contractAddr common.Address
+	abi          abi.ABI
+	erc20ABI     abi.ABI
+}
+
+// TreasuryFundsStatus 金库合约记账的资金状态（wei）
+type TreasuryFundsStatus struct {
+	Total     *big.Int
+	Available *big.Int
+	Locked    *big.Int
+}
+
+// TreasuryFlowLog 从金库合约事件解析出的一笔资金流入或流出
+type TreasuryFlowLog struct {
+	Event        string
+	Asset        string
+	Direction    string
+	Counterparty string   // 转入方或接收方
+	Amount       *big.Int // wei
+	ProposalID   *big.Int // 仅提案执行事件
+	Reason       string   // 仅提取事件
+	Success      bool     // 提案执行失败时资金会退回，不构成实际流出
+	BlockNumber  uint64
+	BlockTime    time.Time
+	TxHash       string
+	LogIndex     uint
+}
+
+// treasuryFlowEvents 金库资金事件对应的资产和流向
+var treasuryFlowEvents = map[string]struct {
+	asset     string
+	direction string
+}{
+	"EthFundsReceived":     {TreasuryAssetETH, TreasuryFlowIn},
+	"EthFundsWithdrawn":    {TreasuryAssetETH, TreasuryFlowOut},
+	"EthProposalExecuted":  {TreasuryAssetETH, TreasuryFlowOut},
+	"BondFundsReceived":    {TreasuryAssetBOND, TreasuryFlowIn},
+	"BondFundsWithdrawn":   {TreasuryAssetBOND, TreasuryFlowOut},
+	"BondProposalExecuted": {TreasuryAssetBOND, TreasuryFlowOut},
+}
+
+// BondlyTreasury 合约 ABI（仅包含 API 使用的方法和资金事件）
+const BondlyTreasuryABI = `[
+	{
+		"inputs": [],
+		"name": "getEthFundsStatus",
+		"outputs": [
+			{"internalType": "uint256", "name": "total", "type": "uint256"},
+			{"internalType": "uint256", "name": "available", "type": "uint256"},
+			{"internalType": "uint256", "name": "locked", "type": "uint256"}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "getBondFundsStatus",
+		"outputs": [
+			{"internalType": "uint256", "name": "total", "type": "uint256"},
+			{"internalType": "uint256", "name": "available", "type": "uint256"},
+			{"internalType": "uint256", "name": "locked", "type": "uint256"}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "bondToken",
+		"outputs": [
+			{"internalType": "contract IERC20", "name": "", "type": "address"}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "internalType": "address", "name": "sender", "type": "address"},
+			{"indexed": false, "internalType": "uint256", "name": "amount", "type": "uint256"}
+		],
+		"name": "EthFundsReceived",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "internalType": "address", "name": "recipient", "type": "address"},
+			{"indexed": false, "internalType": "uint256", "name": "amount", "type": "uint256"},
+			{"indexed": false, "internalType": "string", "name": "reason", "type": "string"}
+		],
+		"name": "EthFundsWithdrawn",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "internalType": "uint256", "name": "proposalId", "type": "uint256"},
+			{"indexed": true, "internalType": "address", "name": "target", "type": "address"},
+			{"indexed": false, "internalType": "uint256", "name": "amount", "type": "uint256"},
+			{"indexed": false, "internalType": "bool", "name": "success", "type": "bool"}
+		],
+		"name": "EthProposalExecuted",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "internalType": "address", "name": "sender", "type": "address"},
+			{"indexed": false, "internalType": "uint256", "name": "amount", "type": "uint256"}
+		],
+		"name": "BondFundsReceived",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "internalType": "address", "name": "recipient", "type": "address"},
+			{"indexed": false, "internalType": "uint256", "name": "amount", "type": "uint256"},
+			{"indexed": false, "internalType": "string", "name": "reason", "type": "string"}
+		],
+		"name": "BondFundsWithdrawn",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "internalType": "uint256", "name": "proposalId", "type": "uint256"},
+			{"indexed": true, "internalType": "address", "name": "target", "type": "address"},
+			{"indexed": false, "internalType": "uint256", "name": "amount", "type": "uint256"},
+			{"indexed": false, "internalType": "bool", "name": "success", "type": "bool"}
+		],
+		"name": "BondProposalExecuted",
+		"type": "event"
+	}
+]`
+
+// erc20BalanceOfABI ERC20 balanceOf 方法 ABI
+const erc20BalanceOfABI = `[
+	{
+		"inputs": [
+			{"internalType": "address", "name": "account", "type": "address"}
+		],
+		"name": "balanceOf",
+		"outputs": [
+			{"internalType": "uint256", "name": "", "type": "uint256"}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+
+// NewBondlyTreasury 创建 BondlyTreasury 合约实例
+func NewBondlyTreasury(config config.EthereumConfig) (*BondlyTreasury, error) {
+	if !common.IsHexAddress(config.TreasuryAddress) {
+		return nil, fmt.Errorf("invalid BondlyTreasury address: %s", config.TreasuryAddress)
+	}
+
+	// 连接以太坊客户端
+	client, err := ethclient.Dial(config.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Ethereum client: %w", err)
+	}
+
+	// 解析合约 ABI
+	contractABI, err := abi.JSON(strings.NewReader(BondlyTreasuryABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse BondlyTreasury ABI: %w", err)
+	}
+	tokenABI, err := abi.JSON(strings.NewReader(erc20BalanceOfABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ERC20 ABI: %w", err)
+	}
+
+	return &BondlyTreasury{
+		client:       client,
+		contractAddr: common.HexToAddress(config.TreasuryAddress),
+		abi:          contractABI,
+		erc20ABI:     tokenABI,
+	}, nil
+}
+
+// Address 金库合约地址
+func (bt *BondlyTreasury) Address() string {
+	return bt.contractAddr.Hex()
+}
+
+// GetEthFundsStatus 获取金库记账的 ETH 资金状态
+func (bt *BondlyTreasury) GetEthFundsStatus(ctx context.Context) (*TreasuryFundsStatus, error) {
+	return bt.fundsStatus(ctx, "getEthFundsStatus")
+}
+
+// GetBondFundsStatus 获取金库记账的 BOND 资金状态
+func (bt *BondlyTreasury) GetBondFundsStatus(ctx context.Context) (*TreasuryFundsStatus, error) {
+	return bt.fundsStatus(ctx, "getBondFundsStatus")
+}
+
+// EthBalance 获取金库合约实际持有的 ETH（wei）
+func (bt *BondlyTreasury) EthBalance(ctx context.Context) (*big.Int, error) {
+	balance, err := bt.client.BalanceAt(ctx, bt.contractAddr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ETH balance: %w", err)
+	}
+	return balance, nil
+}
+
+// BondBalance 获取金库合约实际持有的 BOND（wei），未设置 BOND 代币时返回 0
+func (bt *BondlyTreasury) BondBalance(ctx context.Context) (*big.Int, error) {
+	data, err := bt.abi.Pack("bondToken")
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack bondToken call: %w", err)
+	}
+	result, err := bt.client.CallContract(ctx, buildCallMsg(bt.contractAddr, data), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call bondToken: %w", err)
+	}
+	var token common.Address
+	if err := bt.abi.UnpackIntoInterface(&token, "bondToken", result); err != nil {
+		return nil, fmt.Errorf("failed to unpack bondToken result: %w", err)
+	}
+	if token == (common.Address{}) {
+		return big.NewInt(0), nil
+	}
+
+	data, err = bt.erc20ABI.Pack("balanceOf", bt.contractAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack balanceOf call: %w", err)
+	}
+	result, err = bt.client.CallContract(ctx, buildCallMsg(token, data), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call balanceOf: %w", err)
+	}
+	var balance *big.Int
+	if err := bt.erc20ABI.UnpackIntoInterface(&balance, "balanceOf", result); err != nil {
+		return nil, fmt.Errorf("failed to unpack balanceOf result: %w", err)
+	}
+	return balance, nil
+}
+
+// LatestBlock 获取最新区块高度
+func (bt *BondlyTreasury) LatestBlock(ctx context.Context) (uint64, error) {
+	block, err := bt.client.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	return block, nil
+}
+
+// FetchFlows 获取区块范围内（含两端）金库合约的资金事件
+func (bt *BondlyTreasury) FetchFlows(ctx context.Context, fromBlock, toBlock uint64) ([]TreasuryFlowLog, error) {
+	topics := make([]common.Hash, 0, len(treasuryFlowEvents))
+	for name := range treasuryFlowEvents {
+		topics = append(topics, bt.abi.Events[name].ID)
+	}
+
+	logs, err := bt.client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{bt.contractAddr},
+		Topics:    [][]common.Hash{topics},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter treasury logs: %w", err)
+	}
+
+	blockTimes := make(map[uint64]time.Time)
+	flows := make([]TreasuryFlowLog, 0, len(logs))
+	for _, log := range logs {
+		if log.Removed || len(log.Topics) == 0 {
+			continue
+		}
+		flow, err := bt.parseFlow(log)
+		if err != nil {
+			return nil, err
+		}
+
+		blockTime, ok := blockTimes[log.BlockNumber]
+		if !ok {
+			header, err := bt.client.HeaderByNumber(ctx, new(big.Int).SetUint64(log.BlockNumber))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get block header: %w", err)
+			}
+			blockTime = time.Unix(int64(header.Time), 0)
+			blockTimes[log.BlockNumber] = blockTime
+		}
+		flow.BlockTime = blockTime
+
+		flows = append(flows, *flow)
+	}
+
+	return flows, nil
+}
+
+// parseFlow 解析单条资金事件日志
+func (bt *BondlyTreasury) parseFlow(log types.Log) (*TreasuryFlowLog, error) {
+	event, err := bt.abi.EventByID(log.Topics[0])
+	if err != nil {
+		return nil, fmt.Errorf("unknown treasury event: %w", err)
+	}
+	meta := treasuryFlowEvents[event.Name]
+
+	values := make(map[string]interface{})
+	if err := bt.abi.UnpackIntoMap(values, event.Name, log.Data); err != nil {
+		return nil, fmt.Errorf("failed to unpack %s: %w", event.Name, err)
+	}
+
+	flow := &TreasuryFlowLog{
+		Event:       event.Name,
+		Asset:       meta.asset,
+		Direction:   meta.direction,
+		Success:     true,
+		BlockNumber: log.BlockNumber,
+		TxHash:      log.TxHash.Hex(),
+		LogIndex:    log.Index,
+	}
+	if amount, ok := values["amount"].(*big.Int); ok {
+		flow.Amount = amount
+	}
+	if reason, ok := values["reason"].(string); ok {
+		flow.Reason = reason
+	}
+	if success, ok := values["success"].(bool); ok {
+		flow.Success = success
+	}
+
+	// 提案执行事件的 proposalId 和 target 为 indexed 参数，其余事件只有一个 indexed 地址
+	if strings.HasSuffix(event.Name, "ProposalExecuted") {
+		if len(log.Topics) < 3 {
+			return nil, fmt.Errorf("malformed %s log", event.Name)
+		}
+		flow.ProposalID = new(big.Int).SetBytes(log.Topics[1].Bytes())
+		flow.Counterparty = common.BytesToAddress(log.Topics[2].Bytes()).Hex()
+	} else {
+		if len(log.Topics) < 2 {
+			return nil, fmt.Errorf("malformed %s log", event.Name)
+		}
+		flow.Counterparty = common.BytesToAddress(log.Topics[1].Bytes()).Hex()
+	}
+
+	return flow, nil
+}
+
+// fundsStatus 调用返回 (total, available, locked) 的资金状态方法
+func (bt *BondlyTreasury) fundsStatus(ctx context.Context, method string) (*TreasuryFundsStatus, error) {
+	data, err := bt.abi.Pack(method)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s call: %w", method, err)
+	}
+
+	result, err := bt.client.CallContract(ctx, buildCallMsg(bt.contractAddr, data), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", method, err)
+	}
+
+	var status TreasuryFundsStatus
+	if err := bt.abi.UnpackIntoInterface(&status, method, result); err != nil {
+		return nil, fmt.Errorf("failed to unpack %s result: %w", method, err)
+	}
+	return &status, nil
+}
+
+// Close 关闭以太坊客户端连接
+func (bt *BondlyTreasury) Close() {
+	if bt.client != nil {
+		bt.client.Close()
+	}
+}
@@ -0,0 +1,30 @@
+package dto
+
+// CreateAchievementRequest 创建成就定义请求结构
+type CreateAchievementRequest struct {
+	Code               string `json:"code" binding:"required,max=64" example:"first_post"`
+	Name               string `json:"name" binding:"required,max=100" example:"初次发声"`
+	Description        string `json:"description" binding:"max=255" example:"发布第一篇内容"`
+	Metric             string `json:"metric" binding:"required" example:"posts_published"` // posts_published, likes_received, comments_created, followers, proposal_votes, proposals_passed
+	Threshold          int64  `json:"threshold" binding:"required,min=1" example:"1"`
+	ChainAchievementID int64  `json:"chain_achievement_id" binding:"min=0" example:"1"` // AchievementNFT 中的 achievementId，0 表示不铸造 NFT
+	TokenURI           string `json:"token_uri" example:"ipfs://bafy.../first_post.json"`
+}
+
+// UpdateAchievementRequest 更新成就定义请求结构，指标不可修改
+type UpdateAchievementRequest struct {
+	Name               *string `json:"name" example:"初次发声"`
+	Description        *string `json:"description" example:"发布第一篇内容"`
+	Threshold          *int64  `json:"threshold" example:"1"`
+	ChainAchievementID *int64  `json:"chain_achievement_id" example:"1"`
+	TokenURI           *string `json:"token_uri" example:"ipfs://bafy.../first_post.json"`
+	Enabled            *bool   `json:"enabled" example:"true"`
+}
+
+// AchievementMintResult 成就 NFT 批量铸造结果
+type AchievementMintResult struct {
+	Minted   int      `json:"minted" example:"8"`
+	Existing int      `json:"existing" example:"1"` // 链上已持有，直接标记为已铸造
+	Failed   int      `json:"failed" example:"0"`   // 保持待铸造，下一批次重试
+	TxHashes []string `json:"tx_hashes"`
+}
@@ -7,14 +7,33 @@ type CreateCommentRequest struct {
 	ParentCommentID *int64 `json:"parent_comment_id,omitempty" example:"2"`
 }
 
+// EditCommentRequest 编辑评论请求
+type EditCommentRequest struct {
+	Content string `json:"content" binding:"required" example:"修改后的评论内容"`
+}
+
+// ModerateCommentRequest 评论审核请求
+type ModerateCommentRequest struct {
+	Action string `json:"action" binding:"required,oneof=hide unhide lock unlock pin unpin" example:"hide"`
+	Reason string `json:"reason,omitempty" example:"广告"` // 审核原因，仅记录日志
+}
+
 type CommentResponse struct {
 	ID              int64             `json:"id" example:"1"`
 	PostID          *int64            `json:"post_id,omitempty" example:"1"`
 	ContentID       *int64            `json:"content_id,omitempty" example:"1"`
 	AuthorID        int64             `json:"author_id" example:"1"`
-	Content         string            `json:"content" example:"这是一条评论内容"`
+	Content         string            `json:"content" example:"这是一条评论内容"` // 已删除为 [deleted]，被隐藏为 [hidden]
 	ParentCommentID *int64            `json:"parent_comment_id,omitempty" example:"2"`
+	Status          string            `json:"status" example:"visible"` // visible, hidden, deleted
+	Pinned          bool              `json:"pinned" example:"false"`
+	Locked          bool              `json:"locked" example:"false"` // 锁定后不能再回复
+	Edited          bool              `json:"edited" example:"true"`
+	EditedAt        *string           `json:"edited_at,omitempty" example:"2023-12-01T11:00:00Z"`
+	Depth           int               `json:"depth" example:"1"`       // 嵌套层级，顶层评论为 0
+	ReplyCount      int               `json:"reply_count" example:"3"` // 直接回复数，大于已展开的回复数时可通过 /comments/{id}/replies 继续加载
 	Likes           int               `json:"likes" example:"5"`
+	Liked           bool              `json:"liked" example:"false"` // 当前用户是否已点赞，未登录时为 false
 	CreatedAt       string            `json:"created_at" example:"2023-12-01T10:00:00Z"`
 	UpdatedAt       string            `json:"updated_at" example:"2023-12-01T10:00:00Z"`
 	Author          *UserResponse     `json:"author"`
@@ -26,6 +45,20 @@ type CommentListResponse struct {
 	Pagination PaginationData    `json:"pagination"`
 }
 
+// CommentTreeResponse 评论树响应，回复嵌套在 child_comments 中
+type CommentTreeResponse struct {
+	Comments   []CommentResponse `json:"comments"`
+	NextCursor string            `json:"next_cursor" example:"1701424800000000_42"` // 为空表示没有更多
+	HasMore    bool              `json:"has_more" example:"true"`
+}
+
+// CommentLikeResponse 点赞或取消点赞后的状态
+type CommentLikeResponse struct {
+	CommentID int64 `json:"comment_id" example:"1"`
+	Liked     bool  `json:"liked" example:"true"`
+	Likes     int   `json:"likes" example:"6"`
+}
+
 type LikeCommentRequest struct {
 	CommentID int64 `json:"comment_id" binding:"required" example:"1"`
 }
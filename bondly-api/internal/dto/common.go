@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // HealthData 健康检查响应数据
 type HealthData struct {
 	Status  string `json:"status" example:"ok"`
@@ -111,10 +113,25 @@ type StakeData struct {
 	Message string `json:"message" example:"Stake successful"`
 }
 
-// StatsData 统计响应数据
+// StatsData 平台统计响应数据，由聚合任务定期计算
 type StatsData struct {
-	TotalUsers     int64 `json:"total_users" example:"1000"`
-	TotalContent   int64 `json:"total_content" example:"500"`
-	TotalProposals int64 `json:"total_proposals" example:"50"`
-	ActiveVotes    int64 `json:"active_votes" example:"10"`
+	TotalUsers       int64     `json:"total_users" example:"1000"`
+	TotalContent     int64     `json:"total_content" example:"500"`
+	TotalProposals   int64     `json:"total_proposals" example:"50"`
+	ActiveStakers    int64     `json:"active_stakers" example:"120"`
+	TotalValueLocked string    `json:"total_value_locked" example:"1250000500000000000000000"` // GeneralStaking 总质押量（wei）
+	UpdatedAt        time.Time `json:"updated_at" example:"2024-01-01T00:00:00Z"`              // 最近一次聚合时间
+}
+
+// StatsDailyPoint 平台每日统计数据点
+type StatsDailyPoint struct {
+	Date             string `json:"date" example:"2024-01-01"`
+	TotalUsers       int64  `json:"total_users" example:"1000"`
+	TotalContent     int64  `json:"total_content" example:"500"`
+	TotalProposals   int64  `json:"total_proposals" example:"50"`
+	NewUsers         int64  `json:"new_users" example:"12"`
+	NewContent       int64  `json:"new_content" example:"30"`
+	NewProposals     int64  `json:"new_proposals" example:"1"`
+	ActiveStakers    int64  `json:"active_stakers" example:"120"`
+	TotalValueLocked string `json:"total_value_locked" example:"1250000500000000000000000"` // wei
 }
@@ -4,24 +4,39 @@ import "time"
 
 // CreateContentRequest 创建内容请求结构
 type CreateContentRequest struct {
-	Title         string  `json:"title" binding:"required" example:"文章标题"`
-	Content       string  `json:"content" binding:"required" example:"文章内容"`
-	Type          string  `json:"type" binding:"required" example:"article"` // article, post, comment
-	Status        string  `json:"status" example:"draft"`                    // draft, published, archived
-	CoverImageURL *string `json:"cover_image_url" example:"https://example.com/image.jpg"`
+	Title         string     `json:"title" binding:"required" example:"文章标题"`
+	Content       string     `json:"content" binding:"required" example:"文章内容"`
+	Type          string     `json:"type" binding:"required" example:"article"` // article, post, comment
+	Status        string     `json:"status" example:"draft"`                    // draft, in_review, scheduled, published, archived
+	CoverImageURL *string    `json:"cover_image_url" example:"https://example.com/image.jpg"`
+	Tags          []string   `json:"tags" example:"web3,governance"`            // 最多5个，不存在的标签自动创建
+	PublishAt     *time.Time `json:"publish_at" example:"2024-06-01T08:00:00Z"` // status 为 scheduled 时必填，须晚于当前时间
 }
 
 // UpdateContentRequest 更新内容请求结构
 type UpdateContentRequest struct {
-	Title              *string `json:"title" example:"更新后的标题"`
-	Content            *string `json:"content" example:"更新后的内容"`
-	Type               *string `json:"type" example:"article"`
-	Status             *string `json:"status" example:"published"`
-	CoverImageURL      *string `json:"cover_image_url" example:"https://example.com/new-image.jpg"`
-	NFTTokenID         *int64  `json:"nft_token_id" example:"123"`
-	NFTContractAddress *string `json:"nft_contract_address" example:"0x1234567890abcdef"`
-	IPFSHash           *string `json:"ip_fs_hash" example:"QmHash123"`
-	MetadataHash       *string `json:"metadata_hash" example:"QmMetadataHash456"`
+	Title              *string    `json:"title" example:"更新后的标题"`
+	Content            *string    `json:"content" example:"更新后的内容"`
+	Type               *string    `json:"type" example:"article"`
+	Status             *string    `json:"status" example:"published"`
+	CoverImageURL      *string    `json:"cover_image_url" example:"https://example.com/new-image.jpg"`
+	NFTTokenID         *int64     `json:"nft_token_id" example:"123"`
+	NFTContractAddress *string    `json:"nft_contract_address" example:"0x1234567890abcdef"`
+	IPFSHash           *string    `json:"ip_fs_hash" example:"QmHash123"`
+	MetadataHash       *string    `json:"metadata_hash" example:"QmMetadataHash456"`
+	Tags               *[]string  `json:"tags" example:"web3,governance"` // 不传时保持不变，空数组清空标签
+	PublishAt          *time.Time `json:"publish_at" example:"2024-06-01T08:00:00Z"`
+}
+
+// PreviewContentRequest 预览 Markdown 渲染结果请求
+type PreviewContentRequest struct {
+	Content string `json:"content" binding:"required" example:"## 标题\n\n正文"`
+}
+
+// ReviewContentRequest 审核内容请求
+type ReviewContentRequest struct {
+	Action string `json:"action" binding:"required,oneof=approve reject" example:"approve"`
+	Note   string `json:"note" example:"请补充引用来源"` // 审核意见，随状态变更通知发送给作者
 }
 
 // ContentResponse 内容响应结构
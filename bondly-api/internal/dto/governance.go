@@ -0,0 +1,77 @@
+package dto
+
+// DelegateRequest 创建投票委托请求结构
+type DelegateRequest struct {
+	DelegateID int64  `json:"delegate_id" binding:"required" example:"2"`
+	Topic      string `json:"topic" example:"treasury"` // 为空表示全局委托
+}
+
+// DelegationItem 投票委托记录
+type DelegationItem struct {
+	ID          int64         `json:"id" example:"1"`
+	DelegatorID int64         `json:"delegator_id" example:"1"`
+	DelegateID  int64         `json:"delegate_id" example:"2"`
+	Topic       string        `json:"topic" example:"treasury"`
+	CreatedAt   string        `json:"created_at" example:"2023-12-01T10:00:00Z"`
+	Delegator   *UserResponse `json:"delegator,omitempty"`
+	Delegate    *UserResponse `json:"delegate,omitempty"`
+}
+
+// UserDelegationsResponse 用户委托关系响应结构
+type UserDelegationsResponse struct {
+	UserID   int64            `json:"user_id" example:"1"`
+	Outgoing []DelegationItem `json:"outgoing"` // 用户委托给他人
+	Incoming []DelegationItem `json:"incoming"` // 他人委托给用户
+}
+
+// DelegationGraphNode 委托图节点
+type DelegationGraphNode struct {
+	UserID         int64  `json:"user_id" example:"1"`
+	Nickname       string `json:"nickname" example:"Alice Crypto"`
+	OwnPower       int64  `json:"own_power" example:"120"`
+	DelegatedPower int64  `json:"delegated_power" example:"300"`
+}
+
+// DelegationGraphEdge 委托图边
+type DelegationGraphEdge struct {
+	From  int64  `json:"from" example:"1"`
+	To    int64  `json:"to" example:"2"`
+	Topic string `json:"topic" example:""`
+}
+
+// DelegationGraphResponse 委托图响应结构
+type DelegationGraphResponse struct {
+	Topic string                `json:"topic" example:""`
+	Nodes []DelegationGraphNode `json:"nodes"`
+	Edges []DelegationGraphEdge `json:"edges"`
+}
+
+// VotingPowerResponse 投票权响应结构
+type VotingPowerResponse struct {
+	UserID         int64  `json:"user_id" example:"1"`
+	Topic          string `json:"topic" example:""`
+	OwnPower       int64  `json:"own_power" example:"120"`
+	DelegatedPower int64  `json:"delegated_power" example:"300"`
+	TotalPower     int64  `json:"total_power" example:"420"`
+}
+
+// CastVoteRequest 投票请求结构
+type CastVoteRequest struct {
+	Vote *bool `json:"vote" binding:"required" example:"true"` // true 赞成，false 反对
+}
+
+// ProposalResultResponse 提案计票结果响应结构
+type ProposalResultResponse struct {
+	ProposalID     int64   `json:"proposal_id" example:"1"`
+	VotingStrategy string  `json:"voting_strategy" example:"quadratic"`
+	Status         string  `json:"status" example:"active"`
+	VotesFor       int64   `json:"votes_for" example:"42"`
+	VotesAgainst   int64   `json:"votes_against" example:"17"`
+	TotalWeight    int64   `json:"total_weight" example:"59"`
+	TotalVoters    int64   `json:"total_voters" example:"12"`
+	ForPercentage  float64 `json:"for_percentage" example:"71.19"`
+	Passing        bool    `json:"passing" example:"true"` // 按当前计票是否通过
+	Final          bool    `json:"final" example:"false"`  // 投票是否已结束
+	TalliedAt      string  `json:"tallied_at" example:"2023-12-01T10:00:00Z"`
+	EndTime        string  `json:"end_time" example:"2023-12-08T10:00:00Z"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+// UpdateNotificationPreferencesRequest 修改通知偏好请求，未提供的项保持不变
+type UpdateNotificationPreferencesRequest struct {
+	DigestFrequency string            `json:"digest_frequency,omitempty" binding:"omitempty,oneof=daily weekly off" example:"weekly"`
+	Channels        map[string]string `json:"channels,omitempty"` // 通知类型 -> in_app、email 或 off，如 {"like": "off"}
+}
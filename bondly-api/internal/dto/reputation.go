@@ -48,21 +48,33 @@ type ReputationRankingResponse struct {
 
 // ReputationHistoryItem 声誉历史记录项目
 type ReputationHistoryItem struct {
-	ID        int64  `json:"id" example:"1"`
-	UserID    int64  `json:"user_id" example:"1"`
-	Change    int    `json:"change" example:"100"` // 正数为增加，负数为减少
-	Reason    string `json:"reason" example:"优质内容创作"`
-	TxHash    string `json:"tx_hash,omitempty" example:"0x1234567890abcdef1234567890abcdef12345678901234567890abcdef1234567890"`
-	CreatedAt string `json:"created_at" example:"2023-12-01T10:00:00Z"`
+	ID          int64  `json:"id" example:"1"`
+	UserID      int64  `json:"user_id" example:"1"`
+	Change      int    `json:"change" example:"100"` // 正数为增加，负数为减少
+	ScoreAfter  int    `json:"score_after" example:"1250"`
+	Reason      string `json:"reason" example:"优质内容创作"`
+	Source      string `json:"source" example:"manual"` // manual/chain_sync/opening_balance
+	RelatedType string `json:"related_type,omitempty" example:"content"`
+	RelatedID   *int64 `json:"related_id,omitempty" example:"42"`
+	TxHash      string `json:"tx_hash,omitempty" example:"0x1234567890abcdef1234567890abcdef12345678901234567890abcdef1234567890"`
+	CreatedAt   string `json:"created_at" example:"2023-12-01T10:00:00Z"`
+}
+
+// ReputationReasonSummary 按原因汇总的声誉变更
+type ReputationReasonSummary struct {
+	Reason     string `json:"reason" example:"优质内容创作"`
+	Count      int64  `json:"count" example:"12"`
+	TotalDelta int64  `json:"total_delta" example:"600"`
 }
 
 // ReputationHistoryResponse 声誉历史记录响应结构
 type ReputationHistoryResponse struct {
-	History    []ReputationHistoryItem `json:"history"`
-	Total      int                     `json:"total" example:"50"`
-	Page       int                     `json:"page" example:"1"`
-	Limit      int                     `json:"limit" example:"20"`
-	TotalPages int                     `json:"total_pages" example:"3"`
+	History    []ReputationHistoryItem   `json:"history"`
+	ByReason   []ReputationReasonSummary `json:"by_reason"`
+	Total      int                       `json:"total" example:"50"`
+	Page       int                       `json:"page" example:"1"`
+	Limit      int                       `json:"limit" example:"20"`
+	TotalPages int                       `json:"total_pages" example:"3"`
 }
 
 // ReputationStatsResponse 声誉统计响应结构
@@ -73,3 +85,114 @@ type ReputationStatsResponse struct {
 	ActiveUsers       int     `json:"active_users" example:"2500"`   // 声誉 > 0 的用户
 	EligibleUsers     int     `json:"eligible_users" example:"1200"` // 符合治理条件的用户
 }
+
+// UpdateReputationRuleRequest 更新声誉规则请求结构
+type UpdateReputationRuleRequest struct {
+	Delta       *int    `json:"delta" example:"2"`
+	DailyCap    *int    `json:"daily_cap" example:"20"` // 0 表示不限
+	Enabled     *bool   `json:"enabled" example:"true"`
+	Description *string `json:"description" example:"内容获得点赞"`
+}
+
+// ReputationChainBatchResult 声誉链上批量同步结果
+type ReputationChainBatchResult struct {
+	Users     int      `json:"users" example:"12"`
+	Submitted int      `json:"submitted" example:"40"`
+	Skipped   int      `json:"skipped" example:"3"`
+	Failed    int      `json:"failed" example:"0"`
+	TxHashes  []string `json:"tx_hashes"`
+}
+
+// ReputationDecayResult 声誉衰减任务执行结果
+type ReputationDecayResult struct {
+	Users        int `json:"users" example:"120"`         // 本次衰减的用户数
+	TotalDecayed int `json:"total_decayed" example:"860"` // 本次衰减的声誉总量
+	Failed       int `json:"failed" example:"0"`
+	Anchored     int `json:"anchored" example:"15"` // 新设置衰减起点的用户数
+}
+
+// StartReputationSeasonRequest 开启新赛季请求结构
+type StartReputationSeasonRequest struct {
+	Name        string `json:"name" binding:"required,max=100" example:"2024 第一赛季"`
+	ResetScores bool   `json:"reset_scores" example:"false"` // 是否将所有用户当前声誉分数清零，累计声誉不受影响
+}
+
+// ReputationSeasonItem 声誉赛季
+type ReputationSeasonItem struct {
+	ID          int64   `json:"id" example:"1"`
+	Name        string  `json:"name" example:"2024 第一赛季"`
+	Status      string  `json:"status" example:"active"` // active/archived
+	ResetScores bool    `json:"reset_scores" example:"false"`
+	StartedAt   string  `json:"started_at" example:"2024-01-01T00:00:00Z"`
+	EndedAt     *string `json:"ended_at,omitempty" example:"2024-04-01T00:00:00Z"`
+}
+
+// ReputationSeasonLeaderboardResponse 赛季排行榜响应结构
+type ReputationSeasonLeaderboardResponse struct {
+	Season     ReputationSeasonItem    `json:"season"`
+	Rankings   []ReputationRankingItem `json:"rankings"` // reputation 为赛季内获得的声誉
+	Total      int                     `json:"total" example:"500"`
+	Page       int                     `json:"page" example:"1"`
+	Limit      int                     `json:"limit" example:"20"`
+	TotalPages int                     `json:"total_pages" example:"25"`
+}
+
+// ReputationSeasonStandingItem 用户在赛季中的排名
+type ReputationSeasonStandingItem struct {
+	SeasonID   int64  `json:"season_id" example:"1"`
+	SeasonName string `json:"season_name" example:"2024 第一赛季"`
+	Rank       int    `json:"rank" example:"3"`
+	Score      int64  `json:"score" example:"820"`
+}
+
+// ReputationLifetimeResponse 用户累计声誉响应结构
+type ReputationLifetimeResponse struct {
+	UserID   int64                          `json:"user_id" example:"1"`
+	Current  int                            `json:"current" example:"640"`   // 当前声誉分数（已衰减）
+	Lifetime int64                          `json:"lifetime" example:"1250"` // 累计获得的声誉，不受衰减和赛季清零影响
+	Decayed  int64                          `json:"decayed" example:"610"`   // 累计衰减的声誉
+	Seasons  []ReputationSeasonStandingItem `json:"seasons"`                 // 已归档赛季中的排名
+}
+
+// ReputationMutationResponse 声誉手动变更结果
+type ReputationMutationResponse struct {
+	AuditID     int64  `json:"audit_id" example:"1"`
+	Action      string `json:"action" example:"add"` // add/subtract/sync
+	UserID      int64  `json:"user_id" example:"1"`
+	ScoreBefore int    `json:"score_before" example:"1200"`
+	ScoreAfter  int    `json:"score_after" example:"1250"`
+	TraceID     string `json:"trace_id" example:"5f1c9a7e-3f0b-4c7e-9f7a-2b1d4e6c8a90"`
+	Replayed    bool   `json:"replayed" example:"false"` // 是否为相同幂等键的重复请求
+}
+
+// ReputationAuditItem 声誉审计记录
+type ReputationAuditItem struct {
+	ID             int64  `json:"id" example:"1"`
+	IdempotencyKey string `json:"idempotency_key" example:"2f6c1d0e-8a4b-4e7b-9c1a-6d2e3f4a5b6c"`
+	Action         string `json:"action" example:"add"`
+	ActorID        int64  `json:"actor_id" example:"2"`
+	TargetUserID   int64  `json:"target_user_id" example:"1"`
+	Amount         int    `json:"amount" example:"50"`
+	Reason         string `json:"reason" example:"优质内容创作"`
+	ScoreBefore    int    `json:"score_before" example:"1200"`
+	ScoreAfter     int    `json:"score_after" example:"1250"`
+	Status         string `json:"status" example:"succeeded"` // pending/succeeded/failed
+	Error          string `json:"error,omitempty"`
+	TraceID        string `json:"trace_id" example:"5f1c9a7e-3f0b-4c7e-9f7a-2b1d4e6c8a90"`
+	CreatedAt      string `json:"created_at" example:"2023-12-01T10:00:00Z"`
+}
+
+// ReputationAuditListResponse 声誉审计记录列表响应结构
+type ReputationAuditListResponse struct {
+	Logs       []ReputationAuditItem `json:"logs"`
+	Total      int                   `json:"total" example:"50"`
+	Page       int                   `json:"page" example:"1"`
+	Limit      int                   `json:"limit" example:"20"`
+	TotalPages int                   `json:"total_pages" example:"3"`
+}
+
+// StartReputationReconcileRequest 手动开始声誉对账请求结构，未填写的字段使用配置中的默认值
+type StartReputationReconcileRequest struct {
+	Authority string `json:"authority" binding:"omitempty,oneof=database chain" example:"database"` // database：将差额写入链上；chain：校正数据库
+	DryRun    *bool  `json:"dry_run" example:"true"`                                                // 只生成报告不做修正
+}
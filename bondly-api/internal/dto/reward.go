@@ -0,0 +1,32 @@
+package dto
+
+// InteractionStakeItem 内容 NFT 某互动类型的质押情况
+type InteractionStakeItem struct {
+	InteractionType string `json:"interaction_type" example:"like"`            // like, comment, favorite
+	TotalStaked     string `json:"total_staked" example:"3000000000000000000"` // 尚未领取的累计质押（wei）
+	RewardClaimed   bool   `json:"reward_claimed" example:"false"`
+}
+
+// ContentStakesResponse 内容的互动质押汇总
+type ContentStakesResponse struct {
+	ContentID   int64                  `json:"content_id" example:"1"`
+	TokenID     int64                  `json:"token_id" example:"12"`
+	Title       string                 `json:"title" example:"Web3 社交的未来"`
+	TotalStaked string                 `json:"total_staked" example:"5000000000000000000"` // 各互动类型合计（wei）
+	Stakes      []InteractionStakeItem `json:"stakes"`
+}
+
+// CreatorEarningsResponse 创作者收益面板
+type CreatorEarningsResponse struct {
+	UserID         int64                   `json:"user_id" example:"1"`
+	WalletAddress  string                  `json:"wallet_address,omitempty" example:"0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b6"` // 用于领取的托管钱包
+	TotalClaimable string                  `json:"total_claimable" example:"5000000000000000000"`                                 // 可领取的奖励合计（wei）
+	TotalClaimed   string                  `json:"total_claimed" example:"12000000000000000000"`                                  // 已领取的奖励合计（wei）
+	Contents       []ContentStakesResponse `json:"contents"`
+}
+
+// ClaimCreatorRewardRequest 领取互动奖励请求结构
+type ClaimCreatorRewardRequest struct {
+	ContentID       int64  `json:"content_id" binding:"required" example:"1"`
+	InteractionType string `json:"interaction_type" binding:"required,oneof=like comment favorite" example:"like"`
+}
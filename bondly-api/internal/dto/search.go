@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+// SearchResultItem 搜索结果
+type SearchResultItem struct {
+	Type      string    `json:"type" example:"content"` // content, user, proposal
+	ID        int64     `json:"id" example:"1"`
+	Title     string    `json:"title" example:"Web3 社交的未来"`                         // 内容或提案标题、用户昵称
+	Snippet   string    `json:"snippet" example:"去中心化<mark>社交</mark>网络让创作者拥有自己的数据"` // 已转义 HTML 的命中片段，匹配词以 <mark> 包裹
+	Rank      float64   `json:"rank" example:"0.42"`
+	CreatedAt time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+// RenameTagRequest 重命名标签请求
+type RenameTagRequest struct {
+	Name string `json:"name" binding:"required" example:"Web3"`
+}
+
+// MergeTagRequest 合并标签请求，将路径中的标签并入 target
+type MergeTagRequest struct {
+	Target string `json:"target" binding:"required" example:"web3"`
+}
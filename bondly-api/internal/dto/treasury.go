@@ -0,0 +1,54 @@
+package dto
+
+// TreasuryAssetSummary 金库单项资产的余额与累计流水
+type TreasuryAssetSummary struct {
+	Asset     string `json:"asset" example:"ETH"`                                // ETH, BOND
+	Balance   string `json:"balance,omitempty" example:"12000000000000000000"`   // 合约实际持有（wei），链上不可用时为空
+	Total     string `json:"total,omitempty" example:"12000000000000000000"`     // 合约记账的资金总额（wei）
+	Available string `json:"available,omitempty" example:"10000000000000000000"` // 可用资金（wei）
+	Locked    string `json:"locked,omitempty" example:"2000000000000000000"`     // 提案锁定的资金（wei）
+	Inflow    string `json:"inflow" example:"20000000000000000000"`              // 已索引的累计流入（wei）
+	Outflow   string `json:"outflow" example:"8000000000000000000"`              // 已索引的累计流出（wei）
+}
+
+// TreasuryBudgetSummary 某预算类别在某资产上的拨款汇总（wei）
+type TreasuryBudgetSummary struct {
+	Category  string `json:"category" example:"development"`
+	Asset     string `json:"asset" example:"BOND"`
+	Requested string `json:"requested" example:"1000000000000000000"` // 提案投票中
+	Approved  string `json:"approved" example:"0"`                    // 提案已通过、等待链上执行
+	Spent     string `json:"spent" example:"5000000000000000000"`     // 已执行
+}
+
+// TreasuryDashboardResponse 金库面板
+type TreasuryDashboardResponse struct {
+	ContractAddress  string                  `json:"contract_address,omitempty" example:"0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b6"`
+	ChainAvailable   bool                    `json:"chain_available" example:"true"` // 是否成功读取链上余额
+	LastIndexedBlock int64                   `json:"last_indexed_block" example:"1234567"`
+	Assets           []TreasuryAssetSummary  `json:"assets"`
+	Budgets          []TreasuryBudgetSummary `json:"budgets"`
+	Categories       []string                `json:"categories"` // 可用的预算类别
+}
+
+// CreateDisbursementRequest 提案申请金库拨款请求结构
+type CreateDisbursementRequest struct {
+	ProposalID int64  `json:"proposal_id" binding:"required" example:"1"`
+	Asset      string `json:"asset" binding:"required,oneof=ETH BOND" example:"BOND"`
+	Amount     string `json:"amount" binding:"required" example:"1000000000000000000"` // wei
+	Recipient  string `json:"recipient" binding:"required" example:"0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b6"`
+	Category   string `json:"category" binding:"required" example:"development"`
+	Purpose    string `json:"purpose" example:"第三季度前端开发费用"`
+}
+
+// LinkChainProposalRequest 登记拨款对应的链上提案请求结构
+type LinkChainProposalRequest struct {
+	ChainProposalID string `json:"chain_proposal_id" binding:"required" example:"12"` // 金库合约中的提案ID（十进制）
+}
+
+// TreasuryIndexResult 一次金库事件索引的结果
+type TreasuryIndexResult struct {
+	FromBlock     int64 `json:"from_block" example:"1234000"`
+	ToBlock       int64 `json:"to_block" example:"1234567"` // 小于 from_block 表示没有新区块
+	Flows         int   `json:"flows" example:"3"`          // 新记录的资金流水
+	Disbursements int   `json:"disbursements" example:"1"`  // 根据提案执行事件更新的拨款
+}
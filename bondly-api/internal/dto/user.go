@@ -7,8 +7,6 @@ type CreateUserRequest struct {
 	Nickname             string  `json:"nickname" binding:"required" example:"John Doe"`
 	AvatarURL            *string `json:"avatar_url" example:"https://example.com/avatar.jpg"`
 	Bio                  *string `json:"bio" example:"Hello, I'm a blockchain enthusiast"`
-	Role                 string  `json:"role" example:"user"`
-	ReputationScore      int     `json:"reputation_score" example:"0"`
 	CustodyWalletAddress *string `json:"custody_wallet_address" example:"0x1234567890abcdef1234567890abcdef12345678"`
 	EncryptedPrivateKey  *string `json:"encrypted_private_key" example:"encrypted_private_key_data"`
 }
@@ -64,3 +62,8 @@ type CustodyWalletResponse struct {
 	Nickname             string  `json:"nickname" example:"John Doe"`
 	CustodyWalletAddress *string `json:"custody_wallet_address" example:"0x1234567890abcdef1234567890abcdef12345678"`
 }
+
+// GrantPermissionRequest 授予用户权限请求结构
+type GrantPermissionRequest struct {
+	Permission string `json:"permission" binding:"required" example:"reputation_manager"`
+}
@@ -0,0 +1,132 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+)
+
+// HeaderEmailSender 支持自定义邮件头的发送器，用于 List-Unsubscribe 等头部
+type HeaderEmailSender interface {
+	SendWithHeaders(to string, subject string, body string, headers map[string]string) error
+}
+
+// DigestActivity 摘要中的一条动态
+type DigestActivity struct {
+	Text string
+	Time string
+}
+
+// DigestContent 摘要中关注的作者新发布的内容
+type DigestContent struct {
+	Title       string
+	Author      string
+	PublishedAt string
+}
+
+// DigestEmailData 通知摘要邮件数据
+type DigestEmailData struct {
+	ServiceName    string
+	Nickname       string
+	PeriodLabel    string // 每日 或 每周
+	Activities     []DigestActivity
+	MoreActivities int64 // 未列出的动态数
+	NewContents    []DigestContent
+	UnsubscribeURL string
+}
+
+// UnsubscribePageData 退订页面数据
+type UnsubscribePageData struct {
+	ServiceName string
+	ActionURL   string // 确认退订时提交的地址
+	Done        bool   // 已退订
+	Invalid     bool   // 链接无效
+}
+
+// NewDigestEmailTemplate 创建通知摘要邮件模板
+func NewDigestEmailTemplate() EmailTemplate {
+	return &htmlEmailTemplate{tmpl: digestEmailTemplate}
+}
+
+// NewUnsubscribePageTemplate 创建退订确认页面模板，点击邮件中的退订链接后展示
+func NewUnsubscribePageTemplate() EmailTemplate {
+	return &htmlEmailTemplate{tmpl: unsubscribePageTemplate}
+}
+
+// htmlEmailTemplate 基于 html/template 的模板，数据中的文本会被自动转义
+type htmlEmailTemplate struct {
+	tmpl *template.Template
+}
+
+func (t *htmlEmailTemplate) Render(data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", t.tmpl.Name(), err)
+	}
+	return buf.String(), nil
+}
+
+var digestEmailTemplate = template.Must(template.New("digest").Parse(`
+<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.PeriodLabel}}动态摘要 - {{.ServiceName}}</title>
+</head>
+<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px; background-color: #f5f5f5;">
+    <div style="background-color: white; border-radius: 8px; padding: 40px; box-shadow: 0 2px 10px rgba(0,0,0,0.1);">
+        <div style="text-align: center; margin-bottom: 30px;">
+            <div style="font-size: 24px; font-weight: bold; color: #3b82f6; margin-bottom: 10px;">{{.ServiceName}}</div>
+            <h1>{{.PeriodLabel}}动态摘要</h1>
+        </div>
+
+        <p>亲爱的 <strong>{{.Nickname}}</strong>，以下是您错过的动态：</p>
+        {{if .Activities}}
+        <h2 style="font-size: 18px;">与您相关</h2>
+        <ul>
+            {{range .Activities}}<li>{{.Text}} <span style="color: #64748b; font-size: 12px;">{{.Time}}</span></li>
+            {{end}}
+        </ul>
+        {{if .MoreActivities}}<p style="color: #64748b;">还有 {{.MoreActivities}} 条动态，请登录查看。</p>{{end}}
+        {{end}}
+        {{if .NewContents}}
+        <h2 style="font-size: 18px;">关注的作者发布了</h2>
+        <ul>
+            {{range .NewContents}}<li>《{{.Title}}》 - {{.Author}} <span style="color: #64748b; font-size: 12px;">{{.PublishedAt}}</span></li>
+            {{end}}
+        </ul>
+        {{end}}
+
+        <div style="margin-top: 30px; padding-top: 20px; border-top: 1px solid #e2e8f0; color: #64748b; font-size: 12px; text-align: center;">
+            <p>此邮件由系统自动发送，请勿回复。可在通知设置中调整各类通知的接收方式。</p>
+            <p><a href="{{.UnsubscribeURL}}" style="color: #64748b;">退订动态摘要邮件</a></p>
+            <p>&copy; 2024 {{.ServiceName}}. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>`))
+
+var unsubscribePageTemplate = template.Must(template.New("unsubscribe").Parse(`
+<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>退订动态摘要 - {{.ServiceName}}</title>
+</head>
+<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px; text-align: center;">
+    <div style="font-size: 24px; font-weight: bold; color: #3b82f6; margin-bottom: 10px;">{{.ServiceName}}</div>
+    {{if .Invalid}}
+    <p>退订链接无效或已过期，请在通知设置中关闭动态摘要邮件。</p>
+    {{else if .Done}}
+    <p>您已退订动态摘要邮件，之后不会再收到此类邮件。</p>
+    <p style="color: #64748b; font-size: 12px;">如需重新订阅，可在通知设置中开启。</p>
+    {{else}}
+    <p>确认不再接收动态摘要邮件？</p>
+    <form method="post" action="{{.ActionURL}}">
+        <button type="submit" style="background-color: #3b82f6; color: white; border: none; border-radius: 6px; padding: 10px 24px; font-size: 16px; cursor: pointer;">确认退订</button>
+    </form>
+    {{end}}
+</body>
+</html>`))
@@ -42,3 +42,12 @@ func (s *MockEmailSender) Send(to string, subject string, body string) error {
 
 	return nil
 }
+
+// SendWithHeaders 模拟发送带自定义邮件头的邮件
+func (s *MockEmailSender) SendWithHeaders(to string, subject string, body string, headers map[string]string) error {
+	s.logger.WithFields(logrus.Fields{
+		"to":      to,
+		"headers": headers,
+	}).Debug("邮件头")
+	return s.Send(to, subject, body)
+}
@@ -35,6 +35,11 @@ func NewResendEmailSender(config EmailConfig) (*ResendEmailSender, error) {
 
 // Send 使用Resend发送邮件
 func (s *ResendEmailSender) Send(to string, subject string, body string) error {
+	return s.SendWithHeaders(to, subject, body, nil)
+}
+
+// SendWithHeaders 使用Resend发送带自定义邮件头的邮件
+func (s *ResendEmailSender) SendWithHeaders(to string, subject string, body string, headers map[string]string) error {
 	s.logger.WithFields(logrus.Fields{
 		"to":      to,
 		"subject": subject,
@@ -46,6 +51,7 @@ func (s *ResendEmailSender) Send(to string, subject string, body string) error {
 		To:      []string{to},
 		Subject: subject,
 		Html:    body,
+		Headers: headers,
 	}
 
 	_, err := s.client.Emails.Send(params)
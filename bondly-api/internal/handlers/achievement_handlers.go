@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/pkg/response"
+	"bondly-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AchievementHandlers 成就处理器
+type AchievementHandlers struct {
+	achievementService *services.AchievementService
+}
+
+func NewAchievementHandlers(achievementService *services.AchievementService) *AchievementHandlers {
+	return &AchievementHandlers{
+		achievementService: achievementService,
+	}
+}
+
+// ListAchievements 获取成就列表
+// @Summary 获取成就列表
+// @Description 获取成就定义及其达成条件（统计指标和阈值），默认只返回启用的成就
+// @Tags 成就系统
+// @Accept json
+// @Produce json
+// @Param include_disabled query bool false "是否包含已停用的成就" default(false)
+// @Success 200 {object} response.Response[[]models.Achievement] "成就列表"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/achievements [get]
+func (h *AchievementHandlers) ListAchievements(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/achievements", nil, "", nil)
+
+	includeDisabled := c.DefaultQuery("include_disabled", "false") == "true"
+
+	achievements, err := h.achievementService.ListAchievements(c.Request.Context(), includeDisabled)
+	if err != nil {
+		bizLog.DatabaseError("select", "achievements", "ListAchievements", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, achievements, "获取成就列表成功")
+}
+
+// CreateAchievement 创建成就
+// @Summary 创建成就
+// @Description 新增成就定义，用户的统计指标达到阈值时自动授予；chain_achievement_id 大于 0 时可铸造为 AchievementNFT（需要管理员权限）
+// @Tags 成就系统
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAchievementRequest true "成就定义"
+// @Success 200 {object} response.Response[models.Achievement] "创建的成就"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 401 {object} response.Response[any] "权限不足"
+// @Failure 409 {object} response.Response[any] "成就标识已存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/achievements [post]
+// @Security BearerAuth
+func (h *AchievementHandlers) CreateAchievement(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/achievements", nil, "", nil)
+
+	var req dto.CreateAchievementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bizLog.ValidationFailed("request_body", "JSON格式错误", err.Error())
+		response.Fail(c, response.CodeRequestFormatError, response.MsgRequestFormatError)
+		return
+	}
+
+	achievement, err := h.achievementService.CreateAchievement(c.Request.Context(), &req)
+	if err != nil {
+		switch err.Error() {
+		case "unknown achievement metric":
+			bizLog.ValidationFailed("metric", "不支持的成就指标", req.Metric)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+		case "achievement already exists":
+			response.Fail(c, response.CodeConflict, err.Error())
+		default:
+			bizLog.DatabaseError("insert", "achievements", "CreateAchievement", err)
+			response.Fail(c, response.CodeInternalError, err.Error())
+		}
+		return
+	}
+
+	response.OK(c, achievement, "创建成就成功")
+}
+
+// UpdateAchievement 更新成就
+// @Summary 更新成就
+// @Description 调整成就名称、阈值、链上成就ID、元数据链接或启用状态，已授予的成就不受影响（需要管理员权限）
+// @Tags 成就系统
+// @Accept json
+// @Produce json
+// @Param code path string true "成就标识"
+// @Param request body dto.UpdateAchievementRequest true "成就更新内容"
+// @Success 200 {object} response.Response[models.Achievement] "更新后的成就"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 401 {object} response.Response[any] "权限不足"
+// @Failure 404 {object} response.Response[any] "成就不存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/achievements/{code} [put]
+// @Security BearerAuth
+func (h *AchievementHandlers) UpdateAchievement(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("PUT", "/api/v1/achievements/{code}", nil, "", nil)
+
+	code := c.Param("code")
+
+	var req dto.UpdateAchievementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bizLog.ValidationFailed("request_body", "JSON格式错误", err.Error())
+		response.Fail(c, response.CodeRequestFormatError, response.MsgRequestFormatError)
+		return
+	}
+
+	achievement, err := h.achievementService.UpdateAchievement(c.Request.Context(), code, &req)
+	if err != nil {
+		switch err.Error() {
+		case "achievement not found":
+			response.Fail(c, response.CodeNotFound, "Achievement not found")
+		case "threshold must be positive", "chain achievement id must not be negative":
+			bizLog.ValidationFailed("request_body", err.Error(), code)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+		default:
+			bizLog.DatabaseError("update", "achievements", "UpdateAchievement", err)
+			response.Fail(c, response.CodeInternalError, err.Error())
+		}
+		return
+	}
+
+	response.OK(c, achievement, "更新成就成功")
+}
+
+// MintPending 铸造待发放的成就 NFT
+// @Summary 铸造待发放的成就 NFT
+// @Description 立即将待铸造的成就通过 AchievementNFT 发放到已绑定钱包的用户，交易回执确认成功后才标记为已铸造，链上已持有的直接标记为已铸造。失败的成就按指数退避重试，连续失败 5 次后标记为 failed（需要管理员权限）
+// @Tags 成就系统
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response[dto.AchievementMintResult] "铸造结果"
+// @Failure 400 {object} response.Response[any] "未配置成就合约或中转钱包"
+// @Failure 401 {object} response.Response[any] "权限不足"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/achievements/mint [post]
+// @Security BearerAuth
+func (h *AchievementHandlers) MintPending(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/achievements/mint", nil, "", nil)
+
+	result, err := h.achievementService.MintPending(c.Request.Context())
+	if err != nil {
+		switch err.Error() {
+		case "achievement nft not available", "relay wallet not configured":
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+		default:
+			bizLog.ThirdPartyError("achievement", "mint_pending", nil, err)
+			response.Fail(c, response.CodeInternalError, err.Error())
+		}
+		return
+	}
+
+	response.OK(c, result, "铸造成就 NFT 成功")
+}
+
+// GetUserAchievements 获取用户成就
+// @Summary 获取用户成就
+// @Description 获取用户已获得的成就及 NFT 铸造状态，最近获得的在前，用于个人主页展示
+// @Tags 成就系统
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} response.Response[[]models.UserAchievement] "用户成就"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 404 {object} response.Response[any] "用户不存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/users/{id}/achievements [get]
+func (h *AchievementHandlers) GetUserAchievements(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/users/{id}/achievements", nil, "", nil)
+
+	userID, ok := parseUserIDParam(c, bizLog)
+	if !ok {
+		return
+	}
+
+	achievements, err := h.achievementService.GetUserAchievements(c.Request.Context(), userID)
+	if err != nil {
+		h.fail(c, bizLog, userID, "GetUserAchievements", err)
+		return
+	}
+
+	response.OK(c, achievements, "获取用户成就成功")
+}
+
+// EvaluateUser 重新评估用户成就
+// @Summary 重新评估用户成就
+// @Description 按全部指标重新统计用户的历史活动，补发已达成但尚未授予的成就（需要管理员权限）
+// @Tags 成就系统
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} response.Response[[]models.UserAchievement] "本次新授予的成就"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 401 {object} response.Response[any] "权限不足"
+// @Failure 404 {object} response.Response[any] "用户不存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/users/{id}/achievements/evaluate [post]
+// @Security BearerAuth
+func (h *AchievementHandlers) EvaluateUser(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/users/{id}/achievements/evaluate", nil, "", nil)
+
+	userID, ok := parseUserIDParam(c, bizLog)
+	if !ok {
+		return
+	}
+
+	awarded, err := h.achievementService.EvaluateUser(c.Request.Context(), userID)
+	if err != nil {
+		h.fail(c, bizLog, userID, "EvaluateUser", err)
+		return
+	}
+
+	response.OK(c, awarded, "评估用户成就成功")
+}
+
+// fail 将用户成就查询错误映射为响应
+func (h *AchievementHandlers) fail(c *gin.Context, bizLog *loggerpkg.BusinessLogger, userID int64, operation string, err error) {
+	if err.Error() == "user not found" {
+		bizLog.UserNotFound("user_id", userID)
+		response.Fail(c, response.CodeUserNotFound, response.MsgUserNotFound)
+		return
+	}
+	bizLog.DatabaseError("select", "user_achievements", operation, err)
+	response.Fail(c, response.CodeInternalError, err.Error())
+}
@@ -74,7 +74,7 @@ func (h *CommentHandlers) ListComments(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	comments, total, err := h.service.ListComments(postID, contentID, parentCommentID, page, limit)
+	comments, total, err := h.service.ListComments(postID, contentID, parentCommentID, page, limit, viewerID(c))
 	if err != nil {
 		bizLog.BusinessLogic("error", map[string]interface{}{"err": err})
 		response.Fail(c, 500, "获取评论列表失败")
@@ -93,6 +93,120 @@ func (h *CommentHandlers) ListComments(c *gin.Context) {
 	response.OK(c, resp, "获取评论列表成功")
 }
 
+// ListCommentTree 获取评论树
+// @Summary 获取评论树
+// @Description 按游标分页获取内容下的顶层评论，并展开其下若干层回复；每条评论最多展开 replies_limit 条直接回复，reply_count 更大或超出展开层数的回复通过 /comments/{id}/replies 加载
+// @Tags 评论
+// @Accept json
+// @Produce json
+// @Param post_id query int false "文章ID"
+// @Param content_id query int false "内容ID"
+// @Param sort query string false "排序方式：oldest、newest 或 top" default(oldest)
+// @Param cursor query string false "分页游标，第一页不传"
+// @Param limit query int false "每页顶层评论数，最大100" default(20)
+// @Param depth query int false "展开的回复层数，0 表示不展开，最大由 COMMENT_TREE_MAX_DEPTH 配置" default(3)
+// @Param replies_limit query int false "每条评论展开的直接回复数，最大50" default(5)
+// @Success 200 {object} response.ResponseAny{data=dto.CommentTreeResponse}
+// @Failure 400 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/comments/tree [get]
+func (h *CommentHandlers) ListCommentTree(c *gin.Context) {
+	bizLog := logger.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/comments/tree", nil, "", nil)
+
+	var postID, contentID int64
+	var err error
+	if postIDStr := c.Query("post_id"); postIDStr != "" {
+		postID, err = strconv.ParseInt(postIDStr, 10, 64)
+		if err != nil {
+			response.Fail(c, response.CodeInvalidParams, "post_id参数格式错误")
+			return
+		}
+	} else if contentIDStr := c.Query("content_id"); contentIDStr != "" {
+		contentID, err = strconv.ParseInt(contentIDStr, 10, 64)
+		if err != nil {
+			response.Fail(c, response.CodeInvalidParams, "content_id参数格式错误")
+			return
+		}
+	} else {
+		response.Fail(c, response.CodeInvalidParams, "post_id或content_id参数必填")
+		return
+	}
+
+	h.respondCommentTree(c, bizLog, postID, contentID, nil)
+}
+
+// ListReplies 获取评论的回复树
+// @Summary 获取评论的回复树
+// @Description 按游标分页获取指定评论的直接回复，并展开其下若干层回复，用于加载评论树中未展开的部分
+// @Tags 评论
+// @Accept json
+// @Produce json
+// @Param id path int true "评论ID"
+// @Param sort query string false "排序方式：oldest、newest 或 top" default(oldest)
+// @Param cursor query string false "分页游标，第一页不传"
+// @Param limit query int false "每页回复数，最大100" default(20)
+// @Param depth query int false "展开的回复层数，0 表示不展开" default(3)
+// @Param replies_limit query int false "每条回复展开的直接回复数，最大50" default(5)
+// @Success 200 {object} response.ResponseAny{data=dto.CommentTreeResponse}
+// @Failure 400 {object} response.ResponseAny
+// @Failure 404 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/comments/{id}/replies [get]
+func (h *CommentHandlers) ListReplies(c *gin.Context) {
+	bizLog := logger.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/comments/{id}/replies", nil, "", nil)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Fail(c, response.CodeInvalidParams, "评论ID格式错误")
+		return
+	}
+
+	h.respondCommentTree(c, bizLog, 0, 0, &id)
+}
+
+// respondCommentTree 解析评论树查询参数并返回分页结果
+func (h *CommentHandlers) respondCommentTree(c *gin.Context, bizLog *logger.BusinessLogger, postID, contentID int64, parentID *int64) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	depth, err := strconv.Atoi(c.DefaultQuery("depth", "-1"))
+	if err != nil {
+		depth = -1
+	}
+	repliesLimit, _ := strconv.Atoi(c.DefaultQuery("replies_limit", "0"))
+
+	comments, nextCursor, err := h.service.ListCommentTree(c.Request.Context(), postID, contentID, parentID, services.CommentTreeOptions{
+		Sort:         c.Query("sort"),
+		Cursor:       c.Query("cursor"),
+		Limit:        limit,
+		Depth:        depth,
+		RepliesLimit: repliesLimit,
+	}, viewerID(c))
+	if err != nil {
+		switch err.Error() {
+		case "invalid sort", "invalid cursor":
+			bizLog.ValidationFailed("query", err.Error(), c.Request.URL.RawQuery)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+		case "comment not found":
+			response.Fail(c, response.CodeNotFound, "评论不存在")
+		default:
+			bizLog.BusinessLogic("error", map[string]interface{}{"err": err})
+			response.Fail(c, response.CodeInternalError, "获取评论树失败")
+		}
+		return
+	}
+
+	commentResponses := make([]dto.CommentResponse, 0, len(comments))
+	for i := range comments {
+		commentResponses = append(commentResponses, toCommentResponse(&comments[i]))
+	}
+	response.OK(c, dto.CommentTreeResponse{
+		Comments:   commentResponses,
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != "",
+	}, "获取评论树成功")
+}
+
 // GetComment 获取评论详情
 // @Summary 获取评论详情
 // @Description 获取单条评论详情
@@ -111,7 +225,7 @@ func (h *CommentHandlers) GetComment(c *gin.Context) {
 		response.Fail(c, 400, "评论ID格式错误")
 		return
 	}
-	comment, err := h.service.GetComment(id)
+	comment, err := h.service.GetComment(id, viewerID(c))
 	if err != nil {
 		bizLog.BusinessLogic("error", map[string]interface{}{"err": err})
 		response.Fail(c, 404, "评论不存在")
@@ -147,22 +261,112 @@ func (h *CommentHandlers) CreateComment(c *gin.Context) {
 	}
 	comment, err := h.service.CreateComment(&req, userID.(int64))
 	if err != nil {
-		bizLog.BusinessLogic("error", map[string]interface{}{"err": err})
-		response.Fail(c, 500, "创建评论失败")
+		switch err.Error() {
+		case "parent comment not found":
+			response.Fail(c, response.CodeNotFound, "父评论不存在")
+		case "parent comment belongs to another thread":
+			response.Fail(c, response.CodeInvalidParams, "父评论不属于该内容")
+		case "parent comment deleted":
+			response.Fail(c, response.CodeInvalidParams, "父评论已删除")
+		case "thread locked":
+			response.Fail(c, response.CodeForbidden, "评论已锁定，不能回复")
+		default:
+			bizLog.BusinessLogic("error", map[string]interface{}{"err": err})
+			response.Fail(c, 500, "创建评论失败")
+		}
 		return
 	}
 	response.OK(c, toCommentResponse(comment), "创建评论成功")
 }
 
+// EditComment 编辑评论
+// @Summary 编辑评论
+// @Description 作者修改自己的评论，修改前的内容记入编辑历史，评论标记为已编辑
+// @Tags 评论
+// @Accept json
+// @Produce json
+// @Param id path int true "评论ID"
+// @Param comment body dto.EditCommentRequest true "评论内容"
+// @Success 200 {object} response.ResponseAny{data=dto.CommentResponse}
+// @Failure 400 {object} response.ResponseAny
+// @Failure 401 {object} response.ResponseAny
+// @Failure 403 {object} response.ResponseAny
+// @Failure 404 {object} response.ResponseAny
+// @Failure 409 {object} response.ResponseAny "评论已删除"
+// @Router /api/v1/comments/{id} [put]
+// @Security BearerAuth
+func (h *CommentHandlers) EditComment(c *gin.Context) {
+	bizLog := logger.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("PUT", "/api/v1/comments/{id}", nil, "", nil)
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Fail(c, 400, "评论ID格式错误")
+		return
+	}
+	var req dto.EditCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bizLog.ValidationFailed("request_body", "JSON格式错误", err.Error())
+		response.Fail(c, response.CodeInvalidParams, err.Error())
+		return
+	}
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Fail(c, 401, "未认证")
+		return
+	}
+	comment, err := h.service.EditComment(id, userID.(int64), req.Content)
+	if err != nil {
+		h.failOwnComment(c, bizLog, err, "编辑评论失败")
+		return
+	}
+	response.OK(c, toCommentResponse(comment), "编辑评论成功")
+}
+
+// ListCommentEdits 获取评论编辑历史
+// @Summary 获取评论编辑历史
+// @Description 返回评论每次编辑前的内容，最近的在前；已删除或被隐藏的评论不公开历史
+// @Tags 评论
+// @Accept json
+// @Produce json
+// @Param id path int true "评论ID"
+// @Success 200 {object} response.ResponseAny
+// @Failure 400 {object} response.ResponseAny
+// @Failure 404 {object} response.ResponseAny
+// @Router /api/v1/comments/{id}/edits [get]
+func (h *CommentHandlers) ListCommentEdits(c *gin.Context) {
+	bizLog := logger.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/comments/{id}/edits", nil, "", nil)
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		response.Fail(c, 400, "评论ID格式错误")
+		return
+	}
+	edits, err := h.service.ListCommentEdits(id)
+	if err != nil {
+		if err.Error() == "comment not found" {
+			response.Fail(c, response.CodeNotFound, "评论不存在")
+			return
+		}
+		bizLog.BusinessLogic("error", map[string]interface{}{"err": err})
+		response.Fail(c, 500, "获取编辑历史失败")
+		return
+	}
+	response.OK(c, gin.H{"comment_id": id, "edits": edits}, "获取编辑历史成功")
+}
+
 // DeleteComment 删除评论
 // @Summary 删除评论
-// @Description 用户删除自己的评论
+// @Description 用户删除自己的评论；评论保留为 [deleted] 占位，其下的回复仍然可见
 // @Tags 评论
 // @Accept json
 // @Produce json
 // @Param id path int true "评论ID"
 // @Success 200 {object} response.ResponseAny
 // @Failure 401 {object} response.ResponseAny
+// @Failure 403 {object} response.ResponseAny
+// @Failure 404 {object} response.ResponseAny
 // @Router /api/v1/comments/{id} [delete]
 // @Security BearerAuth
 func (h *CommentHandlers) DeleteComment(c *gin.Context) {
@@ -180,79 +384,153 @@ func (h *CommentHandlers) DeleteComment(c *gin.Context) {
 		return
 	}
 	if err := h.service.DeleteComment(id, userID.(int64)); err != nil {
-		bizLog.BusinessLogic("error", map[string]interface{}{"err": err})
-		response.Fail(c, 500, "删除评论失败")
+		h.failOwnComment(c, bizLog, err, "删除评论失败")
 		return
 	}
 	response.OKMsg(c, "删除评论成功")
 }
 
-// LikeComment 点赞评论
-// @Summary 点赞评论
-// @Description 用户点赞评论
+// ModerateComment 审核评论
+// @Summary 审核评论
+// @Description 评论审核员或管理员隐藏/取消隐藏、锁定/解锁（禁止回复本评论及其子树）、置顶/取消置顶（仅顶层评论）评论
 // @Tags 评论
 // @Accept json
 // @Produce json
 // @Param id path int true "评论ID"
-// @Success 200 {object} response.ResponseAny
+// @Param request body dto.ModerateCommentRequest true "审核操作"
+// @Success 200 {object} response.ResponseAny{data=dto.CommentResponse}
+// @Failure 400 {object} response.ResponseAny
 // @Failure 401 {object} response.ResponseAny
-// @Router /api/v1/comments/{id}/like [post]
+// @Failure 403 {object} response.ResponseAny
+// @Failure 404 {object} response.ResponseAny
+// @Failure 409 {object} response.ResponseAny "评论已删除"
+// @Router /api/v1/comments/{id}/moderate [post]
 // @Security BearerAuth
-func (h *CommentHandlers) LikeComment(c *gin.Context) {
+func (h *CommentHandlers) ModerateComment(c *gin.Context) {
 	bizLog := logger.NewBusinessLogger(c.Request.Context())
-	bizLog.StartAPI("POST", "/api/v1/comments/{id}/like", nil, "", nil)
+	bizLog.StartAPI("POST", "/api/v1/comments/{id}/moderate", nil, "", nil)
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
 		response.Fail(c, 400, "评论ID格式错误")
 		return
 	}
-	// 假设已通过中间件获取用户ID
-	_, exists := c.Get("user_id")
-	if !exists {
-		response.Fail(c, 401, "未认证")
+	var req dto.ModerateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bizLog.ValidationFailed("request_body", "JSON格式错误", err.Error())
+		response.Fail(c, response.CodeInvalidParams, err.Error())
 		return
 	}
-	if err := h.service.LikeComment(id); err != nil {
-		bizLog.BusinessLogic("error", map[string]interface{}{"err": err})
-		response.Fail(c, 500, "点赞评论失败")
+	userID, _ := c.Get("user_id")
+
+	comment, err := h.service.ModerateComment(c.Request.Context(), id, userID.(int64), req.Action, req.Reason)
+	if err != nil {
+		switch err.Error() {
+		case "comment not found":
+			response.Fail(c, response.CodeNotFound, "评论不存在")
+		case "comment deleted":
+			response.Fail(c, response.CodeConflict, "评论已删除")
+		case "only top-level comments can be pinned", "invalid action":
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+		default:
+			bizLog.BusinessLogic("error", map[string]interface{}{"err": err})
+			response.Fail(c, 500, "审核评论失败")
+		}
 		return
 	}
-	response.OKMsg(c, "点赞成功")
+	response.OK(c, toCommentResponse(comment), "审核评论成功")
+}
+
+// failOwnComment 将作者操作自己评论时的错误映射为响应
+func (h *CommentHandlers) failOwnComment(c *gin.Context, bizLog *logger.BusinessLogger, err error, msg string) {
+	switch err.Error() {
+	case "comment not found":
+		response.Fail(c, response.CodeNotFound, "评论不存在")
+	case "forbidden":
+		response.Fail(c, response.CodeForbidden, "只能操作自己的评论")
+	case "comment deleted":
+		response.Fail(c, response.CodeConflict, "评论已删除")
+	default:
+		bizLog.BusinessLogic("error", map[string]interface{}{"err": err})
+		response.Fail(c, 500, msg)
+	}
+}
+
+// LikeComment 点赞评论
+// @Summary 点赞评论
+// @Description 用户点赞评论，重复点赞不会重复计数
+// @Tags 评论
+// @Accept json
+// @Produce json
+// @Param id path int true "评论ID"
+// @Success 200 {object} response.ResponseAny{data=dto.CommentLikeResponse}
+// @Failure 401 {object} response.ResponseAny
+// @Failure 404 {object} response.ResponseAny
+// @Router /api/v1/comments/{id}/like [post]
+// @Security BearerAuth
+func (h *CommentHandlers) LikeComment(c *gin.Context) {
+	bizLog := logger.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/comments/{id}/like", nil, "", nil)
+	h.toggleLike(c, bizLog, true)
 }
 
 // UnlikeComment 取消点赞
 // @Summary 取消点赞
-// @Description 用户取消点赞评论
+// @Description 用户取消点赞评论，未点赞时不变
 // @Tags 评论
 // @Accept json
 // @Produce json
 // @Param id path int true "评论ID"
-// @Success 200 {object} response.ResponseAny
+// @Success 200 {object} response.ResponseAny{data=dto.CommentLikeResponse}
 // @Failure 401 {object} response.ResponseAny
+// @Failure 404 {object} response.ResponseAny
 // @Router /api/v1/comments/{id}/unlike [post]
 // @Security BearerAuth
 func (h *CommentHandlers) UnlikeComment(c *gin.Context) {
 	bizLog := logger.NewBusinessLogger(c.Request.Context())
 	bizLog.StartAPI("POST", "/api/v1/comments/{id}/unlike", nil, "", nil)
+	h.toggleLike(c, bizLog, false)
+}
+
+// toggleLike 点赞或取消点赞并返回最新点赞状态
+func (h *CommentHandlers) toggleLike(c *gin.Context, bizLog *logger.BusinessLogger, like bool) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
 		response.Fail(c, 400, "评论ID格式错误")
 		return
 	}
-	// 假设已通过中间件获取用户ID
-	_, exists := c.Get("user_id")
+	userID, exists := c.Get("user_id")
 	if !exists {
 		response.Fail(c, 401, "未认证")
 		return
 	}
-	if err := h.service.UnlikeComment(id); err != nil {
+
+	var likes int
+	if like {
+		likes, err = h.service.LikeComment(id, userID.(int64))
+	} else {
+		likes, err = h.service.UnlikeComment(id, userID.(int64))
+	}
+	if err != nil {
+		if err.Error() == "comment not found" {
+			response.Fail(c, response.CodeNotFound, "评论不存在")
+			return
+		}
 		bizLog.BusinessLogic("error", map[string]interface{}{"err": err})
-		response.Fail(c, 500, "取消点赞失败")
+		if like {
+			response.Fail(c, 500, "点赞评论失败")
+		} else {
+			response.Fail(c, 500, "取消点赞失败")
+		}
 		return
 	}
-	response.OKMsg(c, "取消点赞成功")
+
+	msg := "点赞成功"
+	if !like {
+		msg = "取消点赞成功"
+	}
+	response.OK(c, dto.CommentLikeResponse{CommentID: id, Liked: like, Likes: likes}, msg)
 }
 
 // GetCommentCount 获取评论数量
@@ -310,6 +588,22 @@ func toCommentResponse(comment *models.Comment) dto.CommentResponse {
 		childComments = append(childComments, toCommentResponse(&child))
 	}
 
+	var editedAt *string
+	if comment.EditedAt != nil {
+		formatted := comment.EditedAt.Format(time.RFC3339)
+		editedAt = &formatted
+	}
+	// 已删除和被隐藏的评论不返回作者
+	var author *dto.UserResponse
+	if comment.AuthorID != 0 {
+		author = &dto.UserResponse{
+			ID:              comment.Author.ID,
+			Nickname:        comment.Author.Nickname,
+			AvatarURL:       comment.Author.AvatarURL,
+			ReputationScore: comment.Author.ReputationScore,
+		}
+	}
+
 	return dto.CommentResponse{
 		ID:              comment.ID,
 		PostID:          comment.PostID,
@@ -317,15 +611,28 @@ func toCommentResponse(comment *models.Comment) dto.CommentResponse {
 		AuthorID:        comment.AuthorID,
 		Content:         comment.Content,
 		ParentCommentID: comment.ParentCommentID,
+		Status:          comment.Status,
+		Pinned:          comment.Pinned,
+		Locked:          comment.Locked,
+		Edited:          comment.EditCount > 0,
+		EditedAt:        editedAt,
+		Depth:           comment.Depth,
+		ReplyCount:      comment.ReplyCount,
 		Likes:           comment.Likes,
+		Liked:           comment.Liked,
 		CreatedAt:       comment.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:       comment.UpdatedAt.Format(time.RFC3339),
-		Author: &dto.UserResponse{
-			ID:              comment.Author.ID,
-			Nickname:        comment.Author.Nickname,
-			AvatarURL:       comment.Author.AvatarURL,
-			ReputationScore: comment.Author.ReputationScore,
-		},
-		ChildComments: childComments,
+		Author:          author,
+		ChildComments:   childComments,
+	}
+}
+
+// viewerID 获取可选认证下的当前用户ID，未登录时为 0
+func viewerID(c *gin.Context) int64 {
+	if userID, exists := c.Get("user_id"); exists {
+		if id, ok := userID.(int64); ok {
+			return id
+		}
 	}
+	return 0
 }
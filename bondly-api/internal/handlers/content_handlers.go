@@ -25,7 +25,7 @@ func NewContentHandlers(contentService *services.ContentService) *ContentHandler
 
 // CreateContent 创建内容
 // @Summary 创建内容
-// @Description 创建新的内容（文章、帖子、评论等）
+// @Description 创建新的内容（文章、帖子、评论等）；status 为 scheduled 时按 publish_at 定时发布，需要审核的类型提交发布后进入 in_review
 // @Tags 内容管理
 // @Accept json
 // @Produce json
@@ -63,6 +63,10 @@ func (h *ContentHandlers) CreateContent(c *gin.Context) {
 		Type:          req.Type,
 		Status:        req.Status,
 		CoverImageURL: req.CoverImageURL,
+		PublishAt:     req.PublishAt,
+	}
+	if req.Tags != nil {
+		content.Tags = toTagModels(req.Tags)
 	}
 
 	bizLog.BusinessLogic("参数处理", map[string]interface{}{
@@ -73,6 +77,16 @@ func (h *ContentHandlers) CreateContent(c *gin.Context) {
 	})
 
 	if err := h.contentService.CreateContent(c.Request.Context(), &content); err != nil {
+		if isTagValidationError(err) {
+			bizLog.ValidationFailed("tags", "标签不合法", req.Tags)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+			return
+		}
+		if isStatusValidationError(err) {
+			bizLog.ValidationFailed("status", err.Error(), req.Status)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+			return
+		}
 		bizLog.ThirdPartyError("content_service", "create_content", map[string]interface{}{
 			"author_id": content.AuthorID,
 		}, err)
@@ -86,7 +100,7 @@ func (h *ContentHandlers) CreateContent(c *gin.Context) {
 
 // GetContent 获取单个内容
 // @Summary 获取内容详情
-// @Description 根据ID获取内容详情
+// @Description 根据ID获取内容详情，未发布的内容仅作者、管理员和内容审核员可见，其他访问者返回 404
 // @Tags 内容管理
 // @Accept json
 // @Produce json
@@ -106,7 +120,7 @@ func (h *ContentHandlers) GetContent(c *gin.Context) {
 		return
 	}
 
-	content, err := h.contentService.GetContent(c.Request.Context(), id)
+	content, err := h.contentService.GetContent(c.Request.Context(), id, viewerID(c))
 	if err != nil {
 		if err.Error() == "content not found" {
 			bizLog.ValidationFailed("content_id", "内容不存在", id)
@@ -124,7 +138,7 @@ func (h *ContentHandlers) GetContent(c *gin.Context) {
 
 // ListContent 获取内容列表
 // @Summary 获取内容列表
-// @Description 分页获取内容列表，支持筛选
+// @Description 分页获取内容列表，支持按作者、状态和标签筛选；sort 为 hot、top 或 controversial 时返回已发布内容的排行，不能与 author_id、tag 同时使用
 // @Tags 内容管理
 // @Accept json
 // @Produce json
@@ -133,6 +147,9 @@ func (h *ContentHandlers) GetContent(c *gin.Context) {
 // @Param type query string false "内容类型"
 // @Param status query string false "内容状态"
 // @Param author_id query int false "作者ID"
+// @Param tag query string false "标签 slug"
+// @Param sort query string false "排序方式：new（默认，按创建时间）、hot（热度）、top（好评）、controversial（争议）"
+// @Param window query string false "top 和 controversial 的时间窗口：day、week（默认）、month"
 // @Success 200 {object} response.ResponseContent
 // @Failure 500 {object} response.ResponseAny
 // @Router /api/v1/content [get]
@@ -144,6 +161,19 @@ func (h *ContentHandlers) ListContent(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	authorID := c.Query("author_id")
 	status := c.Query("status")
+	tag := c.Query("tag")
+
+	sort, window, err := services.ValidateContentSort(c.Query("sort"), c.Query("window"))
+	if err != nil {
+		bizLog.ValidationFailed("sort", err.Error(), c.Query("sort")+"/"+c.Query("window"))
+		response.Fail(c, response.CodeInvalidParams, err.Error())
+		return
+	}
+	if sort != services.ContentSortNew && (authorID != "" || tag != "") {
+		bizLog.ValidationFailed("sort", "排行不能与作者或标签筛选同时使用", sort)
+		response.Fail(c, response.CodeInvalidParams, "sort cannot be combined with author_id or tag")
+		return
+	}
 
 	if page < 1 {
 		page = 1
@@ -154,9 +184,10 @@ func (h *ContentHandlers) ListContent(c *gin.Context) {
 
 	var contents []models.Content
 	var total int64
-	var err error
 
-	if authorID != "" {
+	if sort != services.ContentSortNew {
+		contents, total, err = h.contentService.ListRankedContent(c.Request.Context(), sort, window, page, limit)
+	} else if authorID != "" {
 		if authorIDInt, err := strconv.ParseInt(authorID, 10, 64); err == nil {
 			contents, total, err = h.contentService.GetContentByAuthor(c.Request.Context(), authorIDInt, page, limit)
 		} else {
@@ -164,11 +195,17 @@ func (h *ContentHandlers) ListContent(c *gin.Context) {
 			response.Fail(c, response.CodeInvalidParams, "Invalid author ID")
 			return
 		}
+	} else if tag != "" {
+		contents, total, err = h.contentService.ListContentByTag(c.Request.Context(), tag, page, limit, status)
 	} else {
 		contents, total, err = h.contentService.ListContent(c.Request.Context(), page, limit, status)
 	}
 
 	if err != nil {
+		if err.Error() == "tag not found" {
+			response.Fail(c, response.CodeNotFound, "Tag not found")
+			return
+		}
 		bizLog.ThirdPartyError("content_service", "list_content", map[string]interface{}{"page": page, "limit": limit}, err)
 		response.Fail(c, response.CodeInternalError, err.Error())
 		return
@@ -178,6 +215,8 @@ func (h *ContentHandlers) ListContent(c *gin.Context) {
 		"author_id": authorID,
 		"type":      c.Query("type"),
 		"status":    c.Query("status"),
+		"tag":       tag,
+		"sort":      sort,
 	})
 	result := gin.H{
 		"contents": contents,
@@ -193,7 +232,7 @@ func (h *ContentHandlers) ListContent(c *gin.Context) {
 
 // UpdateContent 更新内容
 // @Summary 更新内容
-// @Description 更新指定内容的信息
+// @Description 更新指定内容的信息；状态流转受限（如已归档的内容不能直接改回草稿），需要审核的类型提交发布后进入 in_review；已发布或定时发布的内容只有管理员和内容审核员可以改为需要审核的类型
 // @Tags 内容管理
 // @Accept json
 // @Produce json
@@ -263,6 +302,12 @@ func (h *ContentHandlers) UpdateContent(c *gin.Context) {
 	if req.MetadataHash != nil {
 		updateData.MetadataHash = req.MetadataHash
 	}
+	if req.Tags != nil {
+		updateData.Tags = toTagModels(*req.Tags)
+	}
+	if req.PublishAt != nil {
+		updateData.PublishAt = req.PublishAt
+	}
 
 	bizLog.BusinessLogic("参数处理", map[string]interface{}{
 		"content_id": id,
@@ -271,13 +316,28 @@ func (h *ContentHandlers) UpdateContent(c *gin.Context) {
 		"has_nft":    req.NFTTokenID != nil || req.NFTContractAddress != nil,
 	})
 
-	content, err := h.contentService.UpdateContent(c.Request.Context(), id, &updateData)
+	content, err := h.contentService.UpdateContent(c.Request.Context(), id, userID.(int64), &updateData)
 	if err != nil {
 		if err.Error() == "content not found" {
 			bizLog.ValidationFailed("content_id", "内容不存在", id)
 			response.Fail(c, response.CodeNotFound, "Content not found")
 			return
 		}
+		if isTagValidationError(err) {
+			bizLog.ValidationFailed("tags", "标签不合法", *req.Tags)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+			return
+		}
+		if isStatusValidationError(err) {
+			bizLog.ValidationFailed("status", err.Error(), req.Status)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+			return
+		}
+		if err.Error() == "content type change requires review" {
+			bizLog.ValidationFailed("type", "已发布内容不能改为需要审核的类型", req.Type)
+			response.Fail(c, response.CodeForbidden, err.Error())
+			return
+		}
 		bizLog.ThirdPartyError("content_service", "update_content", map[string]interface{}{"content_id": id}, err)
 		response.Fail(c, response.CodeInternalError, err.Error())
 		return
@@ -312,6 +372,12 @@ func (h *ContentHandlers) UpdateContent(c *gin.Context) {
 	if req.MetadataHash != nil {
 		updatedFields = append(updatedFields, "metadata_hash")
 	}
+	if req.Tags != nil {
+		updatedFields = append(updatedFields, "tags")
+	}
+	if req.PublishAt != nil {
+		updatedFields = append(updatedFields, "publish_at")
+	}
 
 	bizLog.ContentUpdated(id, userID.(int64), updatedFields)
 	response.OK(c, content, "Content updated successfully")
@@ -370,3 +436,121 @@ func (h *ContentHandlers) DeleteContent(c *gin.Context) {
 	bizLog.ContentDeleted(id, userID.(int64))
 	response.OK(c, gin.H{}, "Content deleted successfully")
 }
+
+// PreviewContent 预览内容渲染结果
+// @Summary 预览内容渲染结果
+// @Description 将 Markdown 正文渲染为净化后的 HTML，并返回摘要、字数、阅读时间和目录，不保存内容
+// @Tags 内容管理
+// @Accept json
+// @Produce json
+// @Param request body dto.PreviewContentRequest true "Markdown 正文"
+// @Success 200 {object} response.Response[services.RenderedContent] "渲染结果"
+// @Failure 400 {object} response.ResponseAny
+// @Failure 401 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/content/preview [post]
+// @Security BearerAuth
+func (h *ContentHandlers) PreviewContent(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/content/preview", nil, "", nil)
+
+	var req dto.PreviewContentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bizLog.ValidationFailed("request_body", "JSON格式错误", err.Error())
+		response.Fail(c, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	rendered, err := services.RenderMarkdown(req.Content)
+	if err != nil {
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, rendered, "Content rendered successfully")
+}
+
+// ReviewContent 审核内容
+// @Summary 审核内容
+// @Description 内容审核员或管理员审核处于 in_review 状态的内容：approve 时发布（publish_at 未到时转为定时发布），reject 时退回草稿；状态变更会通知作者
+// @Tags 内容管理
+// @Accept json
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param request body dto.ReviewContentRequest true "审核结果"
+// @Success 200 {object} response.ResponseContent
+// @Failure 400 {object} response.ResponseAny
+// @Failure 401 {object} response.ResponseAny
+// @Failure 403 {object} response.ResponseAny
+// @Failure 404 {object} response.ResponseAny
+// @Failure 409 {object} response.ResponseAny "内容不在审核中"
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/content/{id}/review [post]
+// @Security BearerAuth
+func (h *ContentHandlers) ReviewContent(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/content/{id}/review", nil, "", nil)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		bizLog.ValidationFailed("content_id", "无效的内容ID", c.Param("id"))
+		response.Fail(c, response.CodeInvalidParams, "Invalid content ID")
+		return
+	}
+
+	var req dto.ReviewContentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bizLog.ValidationFailed("request_body", "JSON格式错误", err.Error())
+		response.Fail(c, response.CodeInvalidParams, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+
+	content, err := h.contentService.ReviewContent(c.Request.Context(), id, userID.(int64), req.Action == "approve", req.Note)
+	if err != nil {
+		switch {
+		case err.Error() == "content not found":
+			response.Fail(c, response.CodeNotFound, "Content not found")
+		case err.Error() == "content not in review":
+			response.Fail(c, response.CodeConflict, err.Error())
+		case isStatusValidationError(err):
+			bizLog.ValidationFailed("status", err.Error(), req.Action)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+		default:
+			bizLog.ThirdPartyError("content_service", "review_content", map[string]interface{}{"content_id": id}, err)
+			response.Fail(c, response.CodeInternalError, err.Error())
+		}
+		return
+	}
+
+	bizLog.BusinessLogic("审核内容", map[string]interface{}{
+		"content_id":  id,
+		"reviewer_id": userID,
+		"action":      req.Action,
+		"status":      content.Status,
+	})
+	response.OK(c, content, "Content reviewed successfully")
+}
+
+// toTagModels 将请求中的标签名称转换为标签模型，由服务层规范化
+func toTagModels(names []string) []models.Tag {
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, models.Tag{Name: name})
+	}
+	return tags
+}
+
+// isTagValidationError 判断是否为标签校验错误
+func isTagValidationError(err error) bool {
+	return err.Error() == "invalid tag" || err.Error() == "too many tags"
+}
+
+// isStatusValidationError 判断是否为状态流转校验错误
+func isStatusValidationError(err error) bool {
+	switch err.Error() {
+	case "invalid status", "invalid status transition", "publish_at must be in the future":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/pkg/response"
+	"bondly-api/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentRevisionHandlers 内容修订历史处理器
+type ContentRevisionHandlers struct {
+	revisionService *services.ContentRevisionService
+	contentService  *services.ContentService
+}
+
+func NewContentRevisionHandlers(revisionService *services.ContentRevisionService, contentService *services.ContentService) *ContentRevisionHandlers {
+	return &ContentRevisionHandlers{
+		revisionService: revisionService,
+		contentService:  contentService,
+	}
+}
+
+// ListRevisions 获取内容修订历史
+// @Summary 获取内容修订历史
+// @Description 按修订号倒序分页返回内容的修订；内容已铸造时返回 minted_revision，且与铸造快照不一致的修订 diverges_from_mint 为 true；修订可能包含草稿和定时发布的正文，仅作者或管理员可查看
+// @Tags 内容管理
+// @Accept json
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量，最大100" default(20)
+// @Success 200 {object} response.Response[any] "修订列表"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 401 {object} response.Response[any] "未认证"
+// @Failure 403 {object} response.Response[any] "无权限"
+// @Failure 404 {object} response.Response[any] "内容不存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/content/{id}/revisions [get]
+// @Security BearerAuth
+func (h *ContentRevisionHandlers) ListRevisions(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/content/{id}/revisions", nil, "", nil)
+
+	id, ok := parseContentID(c, bizLog)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	revisions, total, mintedRevision, err := h.revisionService.ListRevisions(c.Request.Context(), id, page, limit)
+	if err != nil {
+		h.fail(c, bizLog, err)
+		return
+	}
+
+	response.OK(c, gin.H{
+		"items":           revisions,
+		"minted_revision": mintedRevision,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	}, "获取修订历史成功")
+}
+
+// GetRevision 获取单个修订
+// @Summary 获取单个修订
+// @Description 获取内容指定修订号的标题和正文；仅作者或管理员可查看
+// @Tags 内容管理
+// @Accept json
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param revision path int true "修订号"
+// @Success 200 {object} response.Response[any] "修订详情"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 401 {object} response.Response[any] "未认证"
+// @Failure 403 {object} response.Response[any] "无权限"
+// @Failure 404 {object} response.Response[any] "内容或修订不存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/content/{id}/revisions/{revision} [get]
+// @Security BearerAuth
+func (h *ContentRevisionHandlers) GetRevision(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/content/{id}/revisions/{revision}", nil, "", nil)
+
+	id, ok := parseContentID(c, bizLog)
+	if !ok {
+		return
+	}
+	revision, ok := parseRevision(c, bizLog, "revision", c.Param("revision"))
+	if !ok {
+		return
+	}
+
+	rev, err := h.revisionService.GetRevision(c.Request.Context(), id, revision)
+	if err != nil {
+		h.fail(c, bizLog, err)
+		return
+	}
+
+	response.OK(c, rev, "获取修订成功")
+}
+
+// DiffRevisions 对比两个修订
+// @Summary 对比两个修订
+// @Description 对比两个修订的标题和正文，mode 为 line 时按行对比，为 word 时按词对比（中文按字）；仅作者或管理员可查看
+// @Tags 内容管理
+// @Accept json
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param from query int true "起始修订号"
+// @Param to query int true "目标修订号"
+// @Param mode query string false "对比粒度：line 或 word" default(line)
+// @Success 200 {object} response.Response[any] "对比结果"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 401 {object} response.Response[any] "未认证"
+// @Failure 403 {object} response.Response[any] "无权限"
+// @Failure 404 {object} response.Response[any] "内容或修订不存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/content/{id}/revisions/diff [get]
+// @Security BearerAuth
+func (h *ContentRevisionHandlers) DiffRevisions(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/content/{id}/revisions/diff", nil, "", nil)
+
+	id, ok := parseContentID(c, bizLog)
+	if !ok {
+		return
+	}
+	from, ok := parseRevision(c, bizLog, "from", c.Query("from"))
+	if !ok {
+		return
+	}
+	to, ok := parseRevision(c, bizLog, "to", c.Query("to"))
+	if !ok {
+		return
+	}
+
+	diff, err := h.revisionService.DiffRevisions(c.Request.Context(), id, from, to, c.Query("mode"))
+	if err != nil {
+		h.fail(c, bizLog, err)
+		return
+	}
+
+	response.OK(c, diff, "对比修订成功")
+}
+
+// RestoreRevision 恢复到指定修订
+// @Summary 恢复到指定修订
+// @Description 用指定修订的标题和正文覆盖当前内容，并记录一条新的修订；仅作者或管理员可操作
+// @Tags 内容管理
+// @Accept json
+// @Produce json
+// @Param id path int true "内容ID"
+// @Param revision path int true "修订号"
+// @Success 200 {object} response.Response[any] "恢复成功"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 401 {object} response.Response[any] "未认证"
+// @Failure 403 {object} response.Response[any] "无权限"
+// @Failure 404 {object} response.Response[any] "内容或修订不存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/content/{id}/revisions/{revision}/restore [post]
+// @Security BearerAuth
+func (h *ContentRevisionHandlers) RestoreRevision(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/content/{id}/revisions/{revision}/restore", nil, "", nil)
+
+	id, ok := parseContentID(c, bizLog)
+	if !ok {
+		return
+	}
+	revision, ok := parseRevision(c, bizLog, "revision", c.Param("revision"))
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("user_id")
+
+	content, err := h.contentService.RestoreRevision(c.Request.Context(), id, revision, userID.(int64))
+	if err != nil {
+		h.fail(c, bizLog, err)
+		return
+	}
+
+	bizLog.BusinessLogic("恢复内容修订", map[string]interface{}{
+		"content_id": id,
+		"revision":   revision,
+		"editor_id":  userID,
+	})
+	response.OK(c, content, "恢复修订成功")
+}
+
+// fail 将修订服务的错误映射为响应
+func (h *ContentRevisionHandlers) fail(c *gin.Context, bizLog *loggerpkg.BusinessLogger, err error) {
+	switch err.Error() {
+	case "content not found", "revision not found":
+		response.Fail(c, response.CodeNotFound, err.Error())
+	case "invalid diff mode":
+		bizLog.ValidationFailed("mode", "对比粒度必须为 line 或 word", c.Query("mode"))
+		response.Fail(c, response.CodeInvalidParams, err.Error())
+	default:
+		response.Fail(c, response.CodeInternalError, err.Error())
+	}
+}
+
+// parseContentID 解析路径中的内容ID，失败时已写入响应
+func parseContentID(c *gin.Context, bizLog *loggerpkg.BusinessLogger) (int64, bool) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		bizLog.ValidationFailed("id", "无效的内容ID", idStr)
+		response.Fail(c, response.CodeInvalidParams, "Invalid content ID")
+		return 0, false
+	}
+	return id, true
+}
+
+// parseRevision 解析修订号，失败时已写入响应
+func parseRevision(c *gin.Context, bizLog *loggerpkg.BusinessLogger, field, value string) (int, bool) {
+	revision, err := strconv.Atoi(value)
+	if err != nil || revision < 1 {
+		bizLog.ValidationFailed(field, "无效的修订号", value)
+		response.Fail(c, response.CodeInvalidParams, "invalid "+field)
+		return 0, false
+	}
+	return revision, true
+}
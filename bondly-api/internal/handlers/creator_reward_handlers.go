@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/pkg/response"
+	"bondly-api/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreatorRewardHandlers 创作者互动质押收益处理器
+type CreatorRewardHandlers struct {
+	rewardService *services.CreatorRewardService
+}
+
+func NewCreatorRewardHandlers(rewardService *services.CreatorRewardService) *CreatorRewardHandlers {
+	return &CreatorRewardHandlers{
+		rewardService: rewardService,
+	}
+}
+
+// GetContentStakes 获取内容互动质押
+// @Summary 获取内容互动质押
+// @Description 从 InteractionStaking 读取内容 NFT 按互动类型（like、comment、favorite）累计的质押数量（wei）及奖励是否已领取
+// @Tags 内容管理
+// @Accept json
+// @Produce json
+// @Param id path int true "内容ID"
+// @Success 200 {object} response.Response[dto.ContentStakesResponse] "内容互动质押"
+// @Failure 400 {object} response.Response[any] "参数错误或内容未铸造NFT"
+// @Failure 404 {object} response.Response[any] "内容不存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/content/{id}/stakes [get]
+func (h *CreatorRewardHandlers) GetContentStakes(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/content/{id}/stakes", nil, "", nil)
+
+	idStr := c.Param("id")
+	contentID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		bizLog.ValidationFailed("content_id", "内容ID格式错误", idStr)
+		response.Fail(c, response.CodeInvalidParams, "Invalid content ID")
+		return
+	}
+
+	stakes, err := h.rewardService.GetContentStakes(c.Request.Context(), contentID)
+	if err != nil {
+		h.fail(c, bizLog, "getTotalStaked", err)
+		return
+	}
+
+	response.OK(c, stakes, "获取内容互动质押成功")
+}
+
+// GetMyEarnings 获取创作者收益面板
+// @Summary 获取创作者收益面板
+// @Description 获取当前用户已铸造NFT的内容在 InteractionStaking 中的可领取奖励，以及通过平台已领取的奖励总额（wei）
+// @Tags 创作者收益
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response[dto.CreatorEarningsResponse] "收益面板"
+// @Failure 401 {object} response.Response[any] "未认证"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/rewards/earnings [get]
+// @Security BearerAuth
+func (h *CreatorRewardHandlers) GetMyEarnings(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/rewards/earnings", nil, "", nil)
+
+	userID, _ := c.Get("user_id")
+
+	earnings, err := h.rewardService.GetCreatorEarnings(c.Request.Context(), userID.(int64))
+	if err != nil {
+		h.fail(c, bizLog, "getTotalStaked", err)
+		return
+	}
+
+	response.OK(c, earnings, "获取创作者收益成功")
+}
+
+// ListMyClaims 获取奖励领取记录
+// @Summary 获取奖励领取记录
+// @Description 分页获取当前用户通过托管钱包领取互动奖励的记录，最新的在前
+// @Tags 创作者收益
+// @Accept json
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量，最大100" default(20)
+// @Success 200 {object} response.Response[any] "领取记录"
+// @Failure 401 {object} response.Response[any] "未认证"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/rewards/claims [get]
+// @Security BearerAuth
+func (h *CreatorRewardHandlers) ListMyClaims(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/rewards/claims", nil, "", nil)
+
+	userID, _ := c.Get("user_id")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	claims, total, err := h.rewardService.ListClaims(c.Request.Context(), userID.(int64), page, limit)
+	if err != nil {
+		bizLog.DatabaseError("select", "creator_reward_claims", "ListClaims", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	result := gin.H{
+		"claims": claims,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	}
+	response.OK(c, result, "获取奖励领取记录成功")
+}
+
+// ClaimReward 领取互动奖励
+// @Summary 领取互动奖励
+// @Description 以当前用户的托管钱包调用 InteractionStaking.claimReward 领取内容 NFT 某互动类型的全部质押，仅内容创作者可领取，且托管钱包须为 NFT 元数据中的创作者。每个互动类型只能领取一次，同一时间只能有一笔领取。等待交易回执后返回 confirmed；未等到回执时返回 pending，之后按链上状态确认
+// @Tags 创作者收益
+// @Accept json
+// @Produce json
+// @Param request body dto.ClaimCreatorRewardRequest true "领取信息"
+// @Success 200 {object} response.Response[models.CreatorRewardClaim] "领取记录"
+// @Failure 400 {object} response.Response[any] "参数错误、内容未铸造NFT、没有托管钱包或没有可领取的奖励"
+// @Failure 401 {object} response.Response[any] "未认证"
+// @Failure 403 {object} response.Response[any] "不是内容创作者或托管钱包不是链上创作者"
+// @Failure 404 {object} response.Response[any] "内容不存在"
+// @Failure 409 {object} response.Response[any] "奖励已领取或领取进行中"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/rewards/claim [post]
+// @Security BearerAuth
+func (h *CreatorRewardHandlers) ClaimReward(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/rewards/claim", nil, "", nil)
+
+	var req dto.ClaimCreatorRewardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bizLog.ValidationFailed("request_body", "JSON格式错误", err.Error())
+		response.Fail(c, response.CodeRequestFormatError, response.MsgRequestFormatError)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	claim, err := h.rewardService.ClaimReward(c.Request.Context(), userID.(int64), &req)
+	if err != nil {
+		h.fail(c, bizLog, "claimReward", err)
+		return
+	}
+
+	response.OK(c, claim, "领取互动奖励成功")
+}
+
+// fail 将创作者收益服务错误映射为响应
+func (h *CreatorRewardHandlers) fail(c *gin.Context, bizLog *loggerpkg.BusinessLogger, method string, err error) {
+	switch err.Error() {
+	case "content not found":
+		response.Fail(c, response.CodeNotFound, "Content not found")
+	case "user not found":
+		response.Fail(c, response.CodeUserNotFound, response.MsgUserNotFound)
+	case "not content creator":
+		response.Fail(c, response.CodeForbidden, "Only the content creator can claim rewards")
+	case "custody wallet is not content creator":
+		response.Fail(c, response.CodeForbidden, err.Error())
+	case "reward already claimed", "reward claim in progress":
+		response.Fail(c, response.CodeConflict, err.Error())
+	case "content not minted", "custody wallet not found", "no reward to claim", "invalid interaction type":
+		response.Fail(c, response.CodeInvalidParams, err.Error())
+	default:
+		bizLog.ThirdPartyError("blockchain", method, nil, err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+	}
+}
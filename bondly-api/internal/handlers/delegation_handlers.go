@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/pkg/response"
+	"bondly-api/internal/services"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DelegationHandlers 投票委托处理器
+type DelegationHandlers struct {
+	delegationService *services.DelegationService
+}
+
+func NewDelegationHandlers(delegationService *services.DelegationService) *DelegationHandlers {
+	return &DelegationHandlers{
+		delegationService: delegationService,
+	}
+}
+
+// Delegate 委托投票权
+// @Summary 委托投票权
+// @Description 将自己的投票权委托给其他用户，可按主题委托；同一主题下的旧委托会被替换，形成环路的委托会被拒绝
+// @Tags 治理委托
+// @Accept json
+// @Produce json
+// @Param request body dto.DelegateRequest true "委托信息"
+// @Success 200 {object} response.ResponseAny{data=dto.DelegationItem}
+// @Failure 400 {object} response.ResponseAny
+// @Failure 401 {object} response.ResponseAny
+// @Failure 404 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/delegations [post]
+// @Security BearerAuth
+func (h *DelegationHandlers) Delegate(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/delegations", nil, "", nil)
+
+	var req dto.DelegateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bizLog.ValidationFailed("request_body", "JSON格式错误", err.Error())
+		response.Fail(c, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		bizLog.ValidationFailed("user_id", "用户未认证", "")
+		response.Fail(c, response.CodeUnauthorized, "User not authenticated")
+		return
+	}
+
+	delegation, err := h.delegationService.Delegate(c.Request.Context(), userID.(int64), req.DelegateID, req.Topic)
+	if err != nil {
+		switch err.Error() {
+		case "cannot delegate to yourself", "delegation cycle detected", "topic too long":
+			bizLog.ValidationFailed("delegate_id", err.Error(), req.DelegateID)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+		case "delegate not found":
+			bizLog.ValidationFailed("delegate_id", "被委托用户不存在", req.DelegateID)
+			response.Fail(c, response.CodeNotFound, "Delegate not found")
+		default:
+			bizLog.ThirdPartyError("delegation_service", "delegate", map[string]interface{}{
+				"delegator_id": userID,
+				"delegate_id":  req.DelegateID,
+			}, err)
+			response.Fail(c, response.CodeInternalError, err.Error())
+		}
+		return
+	}
+
+	bizLog.BusinessLogic("委托投票权成功", map[string]interface{}{
+		"delegation_id": delegation.ID,
+		"delegator_id":  delegation.DelegatorID,
+		"delegate_id":   delegation.DelegateID,
+		"topic":         delegation.Topic,
+	})
+	response.OK(c, toDelegationItem(delegation), "Delegation created successfully")
+}
+
+// Undelegate 撤销委托
+// @Summary 撤销委托
+// @Description 撤销自己在指定主题下的投票委托，不传主题则撤销全局委托
+// @Tags 治理委托
+// @Accept json
+// @Produce json
+// @Param topic query string false "委托主题"
+// @Success 200 {object} response.ResponseAny
+// @Failure 401 {object} response.ResponseAny
+// @Failure 404 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/delegations [delete]
+// @Security BearerAuth
+func (h *DelegationHandlers) Undelegate(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("DELETE", "/api/v1/delegations", nil, "", nil)
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		bizLog.ValidationFailed("user_id", "用户未认证", "")
+		response.Fail(c, response.CodeUnauthorized, "User not authenticated")
+		return
+	}
+
+	topic := c.Query("topic")
+	if err := h.delegationService.Undelegate(c.Request.Context(), userID.(int64), topic); err != nil {
+		if err.Error() == "delegation not found" {
+			bizLog.ValidationFailed("topic", "委托不存在", topic)
+			response.Fail(c, response.CodeNotFound, "Delegation not found")
+			return
+		}
+		bizLog.ThirdPartyError("delegation_service", "undelegate", map[string]interface{}{
+			"delegator_id": userID,
+			"topic":        topic,
+		}, err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, gin.H{}, "Delegation revoked successfully")
+}
+
+// GetUserDelegations 获取用户委托关系
+// @Summary 获取用户委托关系
+// @Description 获取用户发出和收到的有效投票委托
+// @Tags 治理委托
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} response.ResponseAny{data=dto.UserDelegationsResponse}
+// @Failure 400 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/delegations/user/{id} [get]
+func (h *DelegationHandlers) GetUserDelegations(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/delegations/user/{id}", nil, "", nil)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		bizLog.ValidationFailed("user_id", "无效的用户ID", c.Param("id"))
+		response.Fail(c, response.CodeUserIDInvalid, response.MsgUserIDInvalid)
+		return
+	}
+
+	outgoing, incoming, err := h.delegationService.GetUserDelegations(c.Request.Context(), userID)
+	if err != nil {
+		bizLog.ThirdPartyError("delegation_service", "get_user_delegations", map[string]interface{}{"user_id": userID}, err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	data := dto.UserDelegationsResponse{
+		UserID:   userID,
+		Outgoing: make([]dto.DelegationItem, 0, len(outgoing)),
+		Incoming: make([]dto.DelegationItem, 0, len(incoming)),
+	}
+	for i := range outgoing {
+		data.Outgoing = append(data.Outgoing, toDelegationItem(&outgoing[i]))
+	}
+	for i := range incoming {
+		data.Incoming = append(data.Incoming, toDelegationItem(&incoming[i]))
+	}
+
+	response.OK(c, data, "User delegations retrieved successfully")
+}
+
+// GetDelegationGraph 获取委托图
+// @Summary 获取委托图
+// @Description 获取指定主题下当前有效的委托关系图，节点包含自有投票权和可获得的委托投票权
+// @Tags 治理委托
+// @Accept json
+// @Produce json
+// @Param topic query string false "委托主题，不传则为全局委托图"
+// @Success 200 {object} response.ResponseAny{data=dto.DelegationGraphResponse}
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/delegations/graph [get]
+func (h *DelegationHandlers) GetDelegationGraph(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/delegations/graph", nil, "", nil)
+
+	graph, err := h.delegationService.GetDelegationGraph(c.Request.Context(), c.Query("topic"))
+	if err != nil {
+		bizLog.ThirdPartyError("delegation_service", "get_delegation_graph", map[string]interface{}{"topic": c.Query("topic")}, err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	bizLog.BusinessLogic("获取委托图成功", map[string]interface{}{
+		"topic": graph.Topic,
+		"nodes": len(graph.Nodes),
+		"edges": len(graph.Edges),
+	})
+	response.OK(c, graph, "Delegation graph retrieved successfully")
+}
+
+// GetVotingPower 获取用户投票权
+// @Summary 获取用户投票权
+// @Description 获取用户在指定主题下的投票权（自有声誉分数 + 委托投票权）
+// @Tags 治理委托
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param topic query string false "提案主题"
+// @Success 200 {object} response.ResponseAny{data=dto.VotingPowerResponse}
+// @Failure 400 {object} response.ResponseAny
+// @Failure 404 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/delegations/power/{id} [get]
+func (h *DelegationHandlers) GetVotingPower(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/delegations/power/{id}", nil, "", nil)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		bizLog.ValidationFailed("user_id", "无效的用户ID", c.Param("id"))
+		response.Fail(c, response.CodeUserIDInvalid, response.MsgUserIDInvalid)
+		return
+	}
+
+	power, err := h.delegationService.GetVotingPower(c.Request.Context(), userID, c.Query("topic"))
+	if err != nil {
+		if err.Error() == "user not found" {
+			bizLog.UserNotFound("user_id", userID)
+			response.Fail(c, response.CodeUserNotFound, response.MsgUserNotFound)
+			return
+		}
+		bizLog.ThirdPartyError("delegation_service", "get_voting_power", map[string]interface{}{"user_id": userID}, err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, power, "Voting power retrieved successfully")
+}
+
+// toDelegationItem 工具函数
+func toDelegationItem(delegation *models.VoteDelegation) dto.DelegationItem {
+	item := dto.DelegationItem{
+		ID:          delegation.ID,
+		DelegatorID: delegation.DelegatorID,
+		DelegateID:  delegation.DelegateID,
+		Topic:       delegation.Topic,
+		CreatedAt:   delegation.CreatedAt.Format(time.RFC3339),
+	}
+	if delegation.Delegator.ID != 0 {
+		item.Delegator = toUserBrief(&delegation.Delegator)
+	}
+	if delegation.Delegate.ID != 0 {
+		item.Delegate = toUserBrief(&delegation.Delegate)
+	}
+	return item
+}
+
+// toUserBrief 转换为精简的用户信息
+func toUserBrief(user *models.User) *dto.UserResponse {
+	return &dto.UserResponse{
+		ID:              user.ID,
+		Nickname:        user.Nickname,
+		AvatarURL:       user.AvatarURL,
+		ReputationScore: user.ReputationScore,
+	}
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/pkg/response"
+	"bondly-api/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeedHandlers 信息流处理器
+type FeedHandlers struct {
+	feedService *services.FeedService
+}
+
+func NewFeedHandlers(feedService *services.FeedService) *FeedHandlers {
+	return &FeedHandlers{
+		feedService: feedService,
+	}
+}
+
+// GetFeed 获取个性化信息流
+// @Summary 获取个性化信息流
+// @Description 获取当前用户关注的作者已发布的内容，按发布时间倒序，使用游标分页：将返回的 next_cursor 作为下一次请求的 cursor，next_cursor 为空表示没有更多
+// @Tags 内容管理
+// @Accept json
+// @Produce json
+// @Param cursor query string false "分页游标，第一页不传"
+// @Param limit query int false "每页数量，最大50" default(20)
+// @Success 200 {object} response.Response[any] "信息流"
+// @Failure 400 {object} response.Response[any] "游标无效"
+// @Failure 401 {object} response.Response[any] "未认证"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/feed [get]
+// @Security BearerAuth
+func (h *FeedHandlers) GetFeed(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/feed", nil, "", nil)
+
+	userID, _ := c.Get("user_id")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+	cursor := c.Query("cursor")
+
+	items, nextCursor, err := h.feedService.GetFeed(c.Request.Context(), userID.(int64), cursor, limit)
+	if err != nil {
+		if err.Error() == "invalid cursor" {
+			bizLog.ValidationFailed("cursor", "无效的分页游标", cursor)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+			return
+		}
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, gin.H{
+		"items":       items,
+		"next_cursor": nextCursor,
+		"has_more":    nextCursor != "",
+	}, "获取信息流成功")
+}
@@ -100,15 +100,6 @@ type StakeData struct {
 	Message string `json:"message" example:"Tokens staked successfully"`
 }
 
-// StatsData 统计信息响应数据
-type StatsData struct {
-	TotalUsers       int    `json:"total_users" example:"10000"`
-	TotalContent     int    `json:"total_content" example:"25600"`
-	TotalProposals   int    `json:"total_proposals" example:"125"`
-	ActiveStakers    int    `json:"active_stakers" example:"3500"`
-	TotalValueLocked string `json:"total_value_locked" example:"1250000.50"`
-}
-
 // HealthCheck 健康检查
 // @Summary 健康检查
 // @Description 检查API服务是否正常运行，返回服务状态、版本信息和运行时长。用于负载均衡器和监控系统的健康检查。
@@ -544,37 +535,3 @@ func StakeTokens(c *gin.Context) {
 	}
 	response.OK(c, data, response.MsgTokenStaked)
 }
-
-// GetStats 获取统计信息
-// @Summary 获取平台统计信息
-// @Description 获取平台的各项统计数据，包括用户数量、内容数量、提案数量、质押总额等信息。
-// @Tags 系统监控
-// @Accept json
-// @Produce json
-// @Success 200 {object} response.Response[StatsData] "统计信息"
-// @Router /api/v1/stats [get]
-func GetStats(c *gin.Context) {
-	// 创建业务日志工具
-	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
-
-	// 记录接口开始
-	bizLog.StartAPI("GET", "/api/v1/stats", nil, "", nil)
-
-	// 获取统计信息成功
-	bizLog.Success("get_stats", map[string]interface{}{
-		"total_users":        10000,
-		"total_content":      25600,
-		"total_proposals":    125,
-		"active_stakers":     3500,
-		"total_value_locked": "1250000.50",
-	})
-
-	data := StatsData{
-		TotalUsers:       10000,
-		TotalContent:     25600,
-		TotalProposals:   125,
-		ActiveStakers:    3500,
-		TotalValueLocked: "1250000.50",
-	}
-	response.OK(c, data, response.MsgStatisticsRetrieved)
-}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/pkg/response"
+	"bondly-api/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MentionHandlers 提及处理器
+type MentionHandlers struct {
+	mentionService *services.MentionService
+}
+
+func NewMentionHandlers(mentionService *services.MentionService) *MentionHandlers {
+	return &MentionHandlers{
+		mentionService: mentionService,
+	}
+}
+
+// ListMentions 获取提及我的记录
+// @Summary 获取提及我的记录
+// @Description 分页获取在评论（source_type=comment）或已发布内容（source_type=content）中 @ 当前用户的记录，最近的在前；与当前用户存在任一方向屏蔽的用户发出的提及不返回
+// @Tags 评论
+// @Accept json
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量，最大100" default(20)
+// @Success 200 {object} response.ResponseAny
+// @Failure 401 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/mentions [get]
+// @Security BearerAuth
+func (h *MentionHandlers) ListMentions(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/mentions", nil, "", nil)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	userID, _ := c.Get("user_id")
+
+	mentions, total, err := h.mentionService.ListMentions(c.Request.Context(), userID.(int64), page, limit)
+	if err != nil {
+		bizLog.ThirdPartyError("mention_service", "list_mentions", map[string]interface{}{"user_id": userID}, err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, gin.H{
+		"items": mentions,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	}, "获取提及记录成功")
+}
@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/pkg/response"
+	"bondly-api/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandlers 站内通知处理器
+type NotificationHandlers struct {
+	notificationService *services.NotificationService
+	preferenceService   *services.NotificationPreferenceService
+}
+
+func NewNotificationHandlers(notificationService *services.NotificationService, preferenceService *services.NotificationPreferenceService) *NotificationHandlers {
+	return &NotificationHandlers{
+		notificationService: notificationService,
+		preferenceService:   preferenceService,
+	}
+}
+
+// ListNotifications 获取通知列表
+// @Summary 获取通知列表
+// @Description 分页获取当前用户的通知，最近的在前，同时返回未读数。type 为 mention、follow、comment、reply、like、airdrop 或 proposal_ended
+// @Tags 通知
+// @Accept json
+// @Produce json
+// @Param unread_only query bool false "只返回未读通知" default(false)
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量，最大100" default(20)
+// @Success 200 {object} response.ResponseAny
+// @Failure 401 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/notifications [get]
+// @Security BearerAuth
+func (h *NotificationHandlers) ListNotifications(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/notifications", nil, "", nil)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	unreadOnly := c.Query("unread_only") == "true"
+	userID, _ := c.Get("user_id")
+
+	notifications, total, err := h.notificationService.ListNotifications(c.Request.Context(), userID.(int64), unreadOnly, page, limit)
+	if err != nil {
+		bizLog.ThirdPartyError("notification_service", "list_notifications", map[string]interface{}{"user_id": userID}, err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+	unread, err := h.notificationService.GetUnreadCount(c.Request.Context(), userID.(int64))
+	if err != nil {
+		bizLog.ThirdPartyError("notification_service", "get_unread_count", map[string]interface{}{"user_id": userID}, err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, gin.H{
+		"items":        notifications,
+		"unread_count": unread,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	}, "获取通知成功")
+}
+
+// GetUnreadCount 获取未读通知数
+// @Summary 获取未读通知数
+// @Description 获取当前用户的未读通知数
+// @Tags 通知
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.ResponseAny
+// @Failure 401 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/notifications/unread-count [get]
+// @Security BearerAuth
+func (h *NotificationHandlers) GetUnreadCount(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/notifications/unread-count", nil, "", nil)
+	userID, _ := c.Get("user_id")
+
+	unread, err := h.notificationService.GetUnreadCount(c.Request.Context(), userID.(int64))
+	if err != nil {
+		bizLog.ThirdPartyError("notification_service", "get_unread_count", map[string]interface{}{"user_id": userID}, err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, gin.H{"unread_count": unread}, "获取未读数成功")
+}
+
+// MarkRead 标记通知为已读
+// @Summary 标记通知为已读
+// @Description 将当前用户的一条通知标记为已读，已读时不变
+// @Tags 通知
+// @Accept json
+// @Produce json
+// @Param id path int true "通知ID"
+// @Success 200 {object} response.ResponseAny
+// @Failure 400 {object} response.ResponseAny
+// @Failure 401 {object} response.ResponseAny
+// @Failure 404 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/notifications/{id}/read [post]
+// @Security BearerAuth
+func (h *NotificationHandlers) MarkRead(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/notifications/{id}/read", nil, "", nil)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		bizLog.ValidationFailed("id", "无效的通知ID", c.Param("id"))
+		response.Fail(c, response.CodeInvalidParams, "Invalid notification ID")
+		return
+	}
+	userID, _ := c.Get("user_id")
+
+	if err := h.notificationService.MarkRead(c.Request.Context(), userID.(int64), id); err != nil {
+		if err.Error() == "notification not found" {
+			response.Fail(c, response.CodeNotFound, err.Error())
+			return
+		}
+		bizLog.ThirdPartyError("notification_service", "mark_read", map[string]interface{}{"user_id": userID, "id": id}, err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, gin.H{}, "标记已读成功")
+}
+
+// MarkAllRead 全部标记为已读
+// @Summary 全部标记为已读
+// @Description 将当前用户的全部未读通知标记为已读，返回标记的条数
+// @Tags 通知
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.ResponseAny
+// @Failure 401 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/notifications/read-all [post]
+// @Security BearerAuth
+func (h *NotificationHandlers) MarkAllRead(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/notifications/read-all", nil, "", nil)
+	userID, _ := c.Get("user_id")
+
+	updated, err := h.notificationService.MarkAllRead(c.Request.Context(), userID.(int64))
+	if err != nil {
+		bizLog.ThirdPartyError("notification_service", "mark_all_read", map[string]interface{}{"user_id": userID}, err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, gin.H{"updated": updated}, "全部标记已读成功")
+}
+
+// GetPreferences 获取通知偏好
+// @Summary 获取通知偏好
+// @Description 获取邮件摘要频率（daily、weekly、off）和各类通知的接收方式：in_app 只在站内展示，email 站内展示并汇总进邮件摘要，off 不产生通知；未设置的项返回默认值，摘要频率默认为 off
+// @Tags 通知
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.ResponseAny
+// @Failure 401 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/notifications/preferences [get]
+// @Security BearerAuth
+func (h *NotificationHandlers) GetPreferences(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/notifications/preferences", nil, "", nil)
+	userID, _ := c.Get("user_id")
+
+	preferences, err := h.preferenceService.GetPreferences(c.Request.Context(), userID.(int64))
+	if err != nil {
+		bizLog.ThirdPartyError("notification_preference_service", "get_preferences", map[string]interface{}{"user_id": userID}, err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, preferences, "获取通知偏好成功")
+}
+
+// UpdatePreferences 修改通知偏好
+// @Summary 修改通知偏好
+// @Description 修改邮件摘要频率和各类通知的接收方式，未提供的项保持不变；通知类型为 mention、follow、comment、reply、like、airdrop 或 proposal_ended
+// @Tags 通知
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdateNotificationPreferencesRequest true "通知偏好"
+// @Success 200 {object} response.ResponseAny
+// @Failure 400 {object} response.ResponseAny
+// @Failure 401 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/notifications/preferences [put]
+// @Security BearerAuth
+func (h *NotificationHandlers) UpdatePreferences(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("PUT", "/api/v1/notifications/preferences", nil, "", nil)
+
+	var req dto.UpdateNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bizLog.ValidationFailed("request_body", "JSON格式错误", err.Error())
+		response.Fail(c, response.CodeInvalidParams, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+
+	preferences, err := h.preferenceService.UpdatePreferences(c.Request.Context(), userID.(int64), req.DigestFrequency, req.Channels)
+	if err != nil {
+		switch err.Error() {
+		case "invalid digest frequency", "invalid notification type", "invalid channel":
+			bizLog.ValidationFailed("preferences", err.Error(), req)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+		default:
+			bizLog.ThirdPartyError("notification_preference_service", "update_preferences", map[string]interface{}{"user_id": userID}, err)
+			response.Fail(c, response.CodeInternalError, err.Error())
+		}
+		return
+	}
+
+	response.OK(c, preferences, "修改通知偏好成功")
+}
+
+// UnsubscribePage 退订确认页
+// @Summary 退订确认页
+// @Description 摘要邮件中的退订链接，返回 HTML 确认页，用户确认后提交到同一地址；只展示页面不修改设置，避免邮件客户端预取链接时误退订
+// @Tags 通知
+// @Produce html
+// @Param token query string true "退订令牌"
+// @Success 200 {string} string "确认页"
+// @Failure 400 {string} string "链接无效"
+// @Router /api/v1/notifications/unsubscribe [get]
+func (h *NotificationHandlers) UnsubscribePage(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/notifications/unsubscribe", nil, "", nil)
+
+	h.renderUnsubscribePage(c, bizLog, false)
+}
+
+// Unsubscribe 一键退订邮件摘要
+// @Summary 一键退订邮件摘要
+// @Description 校验签名后关闭用户的邮件摘要，无需登录；同时作为 List-Unsubscribe-Post 一键退订地址，返回 HTML 结果页
+// @Tags 通知
+// @Produce html
+// @Param token query string true "退订令牌"
+// @Success 200 {string} string "已退订"
+// @Failure 400 {string} string "链接无效"
+// @Failure 500 {string} string "服务器错误"
+// @Router /api/v1/notifications/unsubscribe [post]
+func (h *NotificationHandlers) Unsubscribe(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/notifications/unsubscribe", nil, "", nil)
+
+	if err := h.preferenceService.Unsubscribe(c.Request.Context(), c.Query("token")); err != nil {
+		if err.Error() != "invalid unsubscribe token" {
+			bizLog.ThirdPartyError("notification_preference_service", "unsubscribe", nil, err)
+			c.String(http.StatusInternalServerError, err.Error())
+			return
+		}
+		bizLog.ValidationFailed("token", err.Error(), nil)
+	}
+	h.renderUnsubscribePage(c, bizLog, true)
+}
+
+// renderUnsubscribePage 渲染退订页面，令牌无效时返回 400
+func (h *NotificationHandlers) renderUnsubscribePage(c *gin.Context, bizLog *loggerpkg.BusinessLogger, done bool) {
+	page, valid, err := h.preferenceService.RenderUnsubscribePage(c.Query("token"), done)
+	if err != nil {
+		bizLog.ThirdPartyError("notification_preference_service", "render_unsubscribe_page", nil, err)
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	status := http.StatusOK
+	if !valid {
+		status = http.StatusBadRequest
+	}
+	c.Data(status, "text/html; charset=utf-8", []byte(page))
+}
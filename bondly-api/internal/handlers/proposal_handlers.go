@@ -23,7 +23,7 @@ func NewProposalHandlers(proposalService *services.ProposalService) *ProposalHan
 
 // CreateProposal 创建提案
 // @Summary 创建提案
-// @Description 创建新的提案
+// @Description 创建新的提案，voting_strategy 可选 simple、linear（默认）、quadratic、quadratic_stake、conviction；新提案状态固定为 active，请求中的 status 和票数会被忽略
 // @Tags 提案管理
 // @Accept json
 // @Produce json
@@ -55,12 +55,16 @@ func (h *ProposalHandlers) CreateProposal(c *gin.Context) {
 
 	bizLog.BusinessLogic("参数处理", map[string]interface{}{
 		"proposer_id": proposal.ProposerID,
-		"status":      proposal.Status,
 		"start_time":  proposal.StartTime,
 		"end_time":    proposal.EndTime,
 	})
 
 	if err := h.proposalService.CreateProposal(c.Request.Context(), &proposal); err != nil {
+		if err.Error() == "invalid voting strategy" {
+			bizLog.ValidationFailed("voting_strategy", "不支持的投票策略", proposal.VotingStrategy)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+			return
+		}
 		bizLog.ThirdPartyError("proposal_service", "create_proposal", map[string]interface{}{"proposer_id": proposal.ProposerID}, err)
 		response.Fail(c, response.CodeInternalError, err.Error())
 		return
@@ -110,7 +114,7 @@ func (h *ProposalHandlers) GetProposal(c *gin.Context) {
 
 // UpdateProposal 更新提案
 // @Summary 更新提案
-// @Description 更新指定提案的信息
+// @Description 更新指定提案的信息；状态由结束时的计票结果自动结算，不能修改，投票开始后也不能修改开始和结束时间
 // @Tags 提案管理
 // @Accept json
 // @Produce json
@@ -153,6 +157,21 @@ func (h *ProposalHandlers) UpdateProposal(c *gin.Context) {
 			response.Fail(c, response.CodeNotFound, "Proposal not found")
 			return
 		}
+		if err.Error() == "voting strategy cannot be changed" {
+			bizLog.ValidationFailed("voting_strategy", "提案投票策略不可修改", updateData.VotingStrategy)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+			return
+		}
+		if err.Error() == "proposal status cannot be changed" {
+			bizLog.ValidationFailed("status", "提案状态由计票结果决定", updateData.Status)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+			return
+		}
+		if err.Error() == "proposal schedule cannot be changed after voting starts" {
+			bizLog.ValidationFailed("end_time", "投票开始后不可修改提案时间", updateData.EndTime)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+			return
+		}
 		bizLog.ThirdPartyError("proposal_service", "update_proposal", map[string]interface{}{"proposal_id": id}, err)
 		response.Fail(c, response.CodeInternalError, err.Error())
 		return
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/pkg/response"
+	"bondly-api/internal/services"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// WebSocket 连接参数
+const (
+	realtimeWriteTimeout   = 10 * time.Second // 单条消息的写超时，超时视为连接失效
+	realtimeMaxRequestSize = 1024             // 客户端订阅请求的最大字节数
+)
+
+// realtimeRequest 客户端通过 WebSocket 发送的订阅请求
+type realtimeRequest struct {
+	Action string `json:"action"` // subscribe 或 unsubscribe
+	Topic  string `json:"topic"`
+}
+
+// RealtimeHandlers 实时推送处理器
+type RealtimeHandlers struct {
+	realtimeService *services.RealtimeService
+	upgrader        websocket.Upgrader
+}
+
+func NewRealtimeHandlers(realtimeService *services.RealtimeService, allowedOrigins []string) *RealtimeHandlers {
+	origins := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		origins[strings.TrimSpace(origin)] = true
+	}
+	return &RealtimeHandlers{
+		realtimeService: realtimeService,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			// 与 CORS 使用相同的来源白名单，非浏览器客户端不带 Origin
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				return origin == "" || origins[origin]
+			},
+		},
+	}
+}
+
+// WebSocket 建立 WebSocket 实时推送连接
+// @Summary 建立 WebSocket 实时推送连接
+// @Description 连接后自动订阅 user:{id}:notifications，可通过 topics 参数或发送 {"action":"subscribe","topic":"..."} 订阅 content:{id}:comments 和 proposal:{id}:votes；未发布内容的评论主题仅作者和管理员可订阅。服务端定期发送 ping；客户端消费过慢时连接被断开，重连后应重新拉取数据
+// @Tags 实时推送
+// @Param access_token query string false "JWT，浏览器无法设置 Authorization 头时使用"
+// @Param topics query string false "初始订阅的主题，逗号分隔"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 400 {object} response.ResponseAny
+// @Failure 401 {object} response.ResponseAny
+// @Failure 403 {object} response.ResponseAny
+// @Router /api/v1/realtime/ws [get]
+// @Security BearerAuth
+func (h *RealtimeHandlers) WebSocket(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/realtime/ws", nil, "", nil)
+
+	sub, ok := h.connect(c, bizLog)
+	if !ok {
+		return
+	}
+	defer h.realtimeService.Disconnect(sub)
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade 失败时已写入错误响应
+		bizLog.ThirdPartyError("websocket", "upgrade", map[string]interface{}{"user_id": sub.UserID}, err)
+		return
+	}
+	defer conn.Close()
+
+	heartbeat := h.realtimeService.HeartbeatInterval()
+	conn.SetReadLimit(realtimeMaxRequestSize)
+	// 两个心跳周期内没有收到任何消息或 pong 时视为连接失效
+	_ = conn.SetReadDeadline(time.Now().Add(2 * heartbeat))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * heartbeat))
+	})
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			var req realtimeRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			_ = conn.SetReadDeadline(time.Now().Add(2 * heartbeat))
+			h.handleRequest(sub, req)
+		}
+	}()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-sub.Done():
+			_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "disconnected"), time.Now().Add(realtimeWriteTimeout))
+			return
+		case payload := <-sub.Messages():
+			_ = conn.SetWriteDeadline(time.Now().Add(realtimeWriteTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(realtimeWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Stream 建立 SSE 实时推送连接
+// @Summary 建立 SSE 实时推送连接
+// @Description WebSocket 不可用时的降级方案。连接后自动订阅 user:{id}:notifications，其余主题在连接时通过 topics 参数指定。每条消息以 data 行发送 JSON，定期发送注释行作为心跳
+// @Tags 实时推送
+// @Produce text/event-stream
+// @Param access_token query string false "JWT，EventSource 无法设置 Authorization 头时使用"
+// @Param topics query string false "订阅的主题，逗号分隔"
+// @Success 200 {string} string "事件流"
+// @Failure 400 {object} response.ResponseAny
+// @Failure 401 {object} response.ResponseAny
+// @Failure 403 {object} response.ResponseAny
+// @Router /api/v1/realtime/sse [get]
+// @Security BearerAuth
+func (h *RealtimeHandlers) Stream(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/realtime/sse", nil, "", nil)
+
+	sub, ok := h.connect(c, bizLog)
+	if !ok {
+		return
+	}
+	defer h.realtimeService.Disconnect(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // 关闭 nginx 缓冲
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(h.realtimeService.HeartbeatInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-sub.Done():
+			return
+		case payload := <-sub.Messages():
+			if _, err := fmt.Fprintf(c.Writer, "data: %s\n\n", payload); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-ticker.C:
+			if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// connect 注册连接并订阅 topics 参数中的主题，失败时已写入响应
+func (h *RealtimeHandlers) connect(c *gin.Context, bizLog *loggerpkg.BusinessLogger) (*services.RealtimeSubscriber, bool) {
+	userID, _ := c.Get("user_id")
+	role, _ := c.Get("user_role")
+	roleName, _ := role.(string)
+	sub := h.realtimeService.Connect(userID.(int64), roleName)
+	for _, topic := range strings.Split(c.Query("topics"), ",") {
+		if topic = strings.TrimSpace(topic); topic == "" {
+			continue
+		}
+		if err := h.realtimeService.Subscribe(sub, topic); err != nil {
+			h.realtimeService.Disconnect(sub)
+			bizLog.ValidationFailed("topics", err.Error(), topic)
+			switch err.Error() {
+			case "topic forbidden":
+				response.Fail(c, response.CodeForbidden, err.Error())
+			default:
+				response.Fail(c, response.CodeInvalidParams, err.Error())
+			}
+			return nil, false
+		}
+	}
+	return sub, true
+}
+
+// handleRequest 处理客户端的订阅请求，结果以控制消息回复
+func (h *RealtimeHandlers) handleRequest(sub *services.RealtimeSubscriber, req realtimeRequest) {
+	switch req.Action {
+	case "subscribe":
+		if err := h.realtimeService.Subscribe(sub, req.Topic); err != nil {
+			h.realtimeService.Reply(sub, services.RealtimeMessageError, req.Topic, err.Error())
+			return
+		}
+		h.realtimeService.Reply(sub, services.RealtimeMessageSubscribed, req.Topic, "")
+	case "unsubscribe":
+		h.realtimeService.Unsubscribe(sub, req.Topic)
+		h.realtimeService.Reply(sub, services.RealtimeMessageUnsubscribed, req.Topic, "")
+	default:
+		h.realtimeService.Reply(sub, services.RealtimeMessageError, req.Topic, "invalid action")
+	}
+}
@@ -6,23 +6,29 @@ import (
 	"bondly-api/internal/pkg/response"
 	"bondly-api/internal/services"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// IdempotencyKeyHeader 声誉变更请求必须携带的幂等键请求头
+const IdempotencyKeyHeader = "Idempotency-Key"
+
 type ReputationHandlers struct {
 	reputationService *services.ReputationService
+	auditService      *services.ReputationAuditService
 }
 
-func NewReputationHandlers(reputationService *services.ReputationService) *ReputationHandlers {
+func NewReputationHandlers(reputationService *services.ReputationService, auditService *services.ReputationAuditService) *ReputationHandlers {
 	return &ReputationHandlers{
 		reputationService: reputationService,
+		auditService:      auditService,
 	}
 }
 
 // GetUserReputation 获取用户声誉分数
 // @Summary 获取用户声誉分数
-// @Description 根据用户ID获取声誉分数，优先从链上获取并同步到数据库
+// @Description 根据用户ID获取声誉分数，优先从链上获取；只读取，不修改数据库中的分数
 // @Tags 声誉系统
 // @Accept json
 // @Produce json
@@ -64,6 +70,94 @@ func (h *ReputationHandlers) GetUserReputation(c *gin.Context) {
 	response.OK(c, data, "获取声誉分数成功")
 }
 
+// GetReputationHistory 获取用户声誉变更历史
+// @Summary 获取用户声誉变更历史
+// @Description 分页获取用户的声誉变更事件（原因、来源、关联实体、交易哈希），并返回按原因汇总的变更统计
+// @Tags 声誉系统
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param reason query string false "按原因过滤"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量，最大100" default(20)
+// @Success 200 {object} response.Response[dto.ReputationHistoryResponse] "声誉历史"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 404 {object} response.Response[any] "用户不存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/reputation/user/{id}/history [get]
+func (h *ReputationHandlers) GetReputationHistory(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/reputation/user/{id}/history", nil, "", nil)
+
+	idStr := c.Param("id")
+	userID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		bizLog.ValidationFailed("user_id", "用户ID格式错误", idStr)
+		response.Fail(c, response.CodeUserIDInvalid, response.MsgUserIDInvalid)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	reason := c.Query("reason")
+
+	events, total, aggregates, err := h.reputationService.GetReputationHistory(c.Request.Context(), userID, reason, page, limit)
+	if err != nil {
+		if err.Error() == "user not found" {
+			bizLog.UserNotFound("user_id", userID)
+			response.Fail(c, response.CodeUserNotFound, response.MsgUserNotFound)
+			return
+		}
+		bizLog.DatabaseError("select", "reputation_events", "GetReputationHistory", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	data := &dto.ReputationHistoryResponse{
+		History:    make([]dto.ReputationHistoryItem, 0, len(events)),
+		ByReason:   make([]dto.ReputationReasonSummary, 0, len(aggregates)),
+		Total:      int(total),
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}
+	for _, event := range events {
+		data.History = append(data.History, dto.ReputationHistoryItem{
+			ID:          event.ID,
+			UserID:      event.UserID,
+			Change:      event.Delta,
+			ScoreAfter:  event.ScoreAfter,
+			Reason:      event.Reason,
+			Source:      event.Source,
+			RelatedType: event.RelatedType,
+			RelatedID:   event.RelatedID,
+			TxHash:      event.TxHash,
+			CreatedAt:   event.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	for _, aggregate := range aggregates {
+		data.ByReason = append(data.ByReason, dto.ReputationReasonSummary{
+			Reason:     aggregate.Reason,
+			Count:      aggregate.Count,
+			TotalDelta: aggregate.TotalDelta,
+		})
+	}
+
+	bizLog.Success("getReputationHistory", map[string]interface{}{
+		"user_id": userID,
+		"reason":  reason,
+		"total":   total,
+	})
+
+	response.OK(c, data, "获取声誉历史成功")
+}
+
 // GetUserReputationByAddress 根据钱包地址获取用户声誉分数
 // @Summary 根据钱包地址获取用户声誉分数
 // @Description 根据钱包地址获取声誉分数，优先从链上获取
@@ -109,15 +203,18 @@ func (h *ReputationHandlers) GetUserReputationByAddress(c *gin.Context) {
 
 // AddReputation 增加用户声誉分数
 // @Summary 增加用户声誉分数
-// @Description 为用户增加声誉分数，同时更新链上和数据库（需要管理员权限）
+// @Description 为用户增加声誉分数，同时更新链上和数据库，链上写入失败时不修改数据库并返回错误，并写入审计记录（需要声誉管理权限）
 // @Tags 声誉系统
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string true "幂等键，重复请求返回首次执行的结果"
 // @Param request body dto.AddReputationRequest true "增加声誉请求"
-// @Success 200 {object} response.Response[any] "操作成功"
+// @Success 200 {object} response.Response[dto.ReputationMutationResponse] "操作成功"
 // @Failure 400 {object} response.Response[any] "参数错误"
-// @Failure 401 {object} response.Response[any] "权限不足"
+// @Failure 401 {object} response.Response[any] "未认证"
+// @Failure 403 {object} response.Response[any] "权限不足"
 // @Failure 404 {object} response.Response[any] "用户不存在"
+// @Failure 409 {object} response.Response[any] "幂等键冲突"
 // @Failure 500 {object} response.Response[any] "服务器错误"
 // @Router /api/v1/reputation/add [post]
 // @Security BearerAuth
@@ -155,33 +252,28 @@ func (h *ReputationHandlers) AddReputation(c *gin.Context) {
 		"reason":  req.Reason,
 	})
 
-	err := h.reputationService.AddReputation(c.Request.Context(), req.UserID, req.Amount, req.Reason)
-	if err != nil {
-		bizLog.DatabaseError("update", "reputation", "AddReputation", err)
-		response.Fail(c, response.CodeInternalError, err.Error())
-		return
-	}
-
-	bizLog.Success("addReputation", map[string]interface{}{
-		"user_id": req.UserID,
-		"amount":  req.Amount,
-		"reason":  req.Reason,
-	})
-
-	response.OK(c, map[string]interface{}{}, "增加声誉分数成功")
+	h.executeMutation(c, bizLog, services.ReputationMutation{
+		Action:       services.ReputationActionAdd,
+		TargetUserID: req.UserID,
+		Amount:       req.Amount,
+		Reason:       req.Reason,
+	}, "增加声誉分数成功")
 }
 
 // SubtractReputation 减少用户声誉分数
 // @Summary 减少用户声誉分数
-// @Description 为用户减少声誉分数，同时更新链上和数据库（需要管理员权限）
+// @Description 为用户减少声誉分数，同时更新链上和数据库，链上写入失败时不修改数据库并返回错误，并写入审计记录（需要声誉管理权限）
 // @Tags 声誉系统
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string true "幂等键，重复请求返回首次执行的结果"
 // @Param request body dto.SubtractReputationRequest true "减少声誉请求"
-// @Success 200 {object} response.Response[any] "操作成功"
+// @Success 200 {object} response.Response[dto.ReputationMutationResponse] "操作成功"
 // @Failure 400 {object} response.Response[any] "参数错误"
-// @Failure 401 {object} response.Response[any] "权限不足"
+// @Failure 401 {object} response.Response[any] "未认证"
+// @Failure 403 {object} response.Response[any] "权限不足"
 // @Failure 404 {object} response.Response[any] "用户不存在"
+// @Failure 409 {object} response.Response[any] "幂等键冲突"
 // @Failure 500 {object} response.Response[any] "服务器错误"
 // @Router /api/v1/reputation/subtract [post]
 // @Security BearerAuth
@@ -219,34 +311,31 @@ func (h *ReputationHandlers) SubtractReputation(c *gin.Context) {
 		"reason":  req.Reason,
 	})
 
-	err := h.reputationService.SubtractReputation(c.Request.Context(), req.UserID, req.Amount, req.Reason)
-	if err != nil {
-		bizLog.DatabaseError("update", "reputation", "SubtractReputation", err)
-		response.Fail(c, response.CodeInternalError, err.Error())
-		return
-	}
-
-	bizLog.Success("subtractReputation", map[string]interface{}{
-		"user_id": req.UserID,
-		"amount":  req.Amount,
-		"reason":  req.Reason,
-	})
-
-	response.OK(c, map[string]interface{}{}, "减少声誉分数成功")
+	h.executeMutation(c, bizLog, services.ReputationMutation{
+		Action:       services.ReputationActionSubtract,
+		TargetUserID: req.UserID,
+		Amount:       req.Amount,
+		Reason:       req.Reason,
+	}, "减少声誉分数成功")
 }
 
 // SyncReputationFromChain 从链上同步用户声誉分数
 // @Summary 从链上同步用户声誉分数
-// @Description 从链上同步用户声誉分数到数据库
+// @Description 从链上同步用户声誉分数到数据库，并写入审计记录（需要声誉管理权限）
 // @Tags 声誉系统
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string true "幂等键，重复请求返回首次执行的结果"
 // @Param id path int true "用户ID"
-// @Success 200 {object} response.Response[any] "同步成功"
+// @Success 200 {object} response.Response[dto.ReputationMutationResponse] "同步成功"
 // @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 401 {object} response.Response[any] "未认证"
+// @Failure 403 {object} response.Response[any] "权限不足"
 // @Failure 404 {object} response.Response[any] "用户不存在"
+// @Failure 409 {object} response.Response[any] "幂等键冲突"
 // @Failure 500 {object} response.Response[any] "服务器错误"
 // @Router /api/v1/reputation/sync/{id} [post]
+// @Security BearerAuth
 func (h *ReputationHandlers) SyncReputationFromChain(c *gin.Context) {
 	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
 	bizLog.StartAPI("POST", "/api/v1/reputation/sync/{id}", nil, "", nil)
@@ -259,20 +348,11 @@ func (h *ReputationHandlers) SyncReputationFromChain(c *gin.Context) {
 		return
 	}
 
-	err = h.reputationService.SyncReputationFromChain(c.Request.Context(), userID)
-	if err != nil {
-		bizLog.ThirdPartyError("blockchain", "syncReputation", map[string]interface{}{
-			"user_id": userID,
-		}, err)
-		response.Fail(c, response.CodeInternalError, err.Error())
-		return
-	}
-
-	bizLog.Success("syncReputationFromChain", map[string]interface{}{
-		"user_id": userID,
-	})
-
-	response.OK(c, map[string]interface{}{}, "同步声誉分数成功")
+	h.executeMutation(c, bizLog, services.ReputationMutation{
+		Action:       services.ReputationActionSync,
+		TargetUserID: userID,
+		Reason:       "链上同步",
+	}, "同步声誉分数成功")
 }
 
 // IsEligibleForGovernance 检查用户是否符合治理条件
@@ -372,3 +452,128 @@ func (h *ReputationHandlers) GetTopUsersByReputation(c *gin.Context) {
 
 	response.OK(c, data, "获取声誉排行榜成功")
 }
+
+// ListAuditLogs 获取声誉变更审计记录
+// @Summary 获取声誉变更审计记录
+// @Description 分页获取声誉手动增加、减少和链上同步的审计记录，包含操作人、目标用户、变更前后分数和 trace ID（需要声誉管理权限）
+// @Tags 声誉系统
+// @Accept json
+// @Produce json
+// @Param actor_id query int false "操作人ID"
+// @Param target_user_id query int false "目标用户ID"
+// @Param action query string false "变更动作" Enums(add, subtract, sync)
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量，最大100" default(20)
+// @Success 200 {object} response.Response[dto.ReputationAuditListResponse] "审计记录"
+// @Failure 401 {object} response.Response[any] "未认证"
+// @Failure 403 {object} response.Response[any] "权限不足"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/reputation/audit [get]
+// @Security BearerAuth
+func (h *ReputationHandlers) ListAuditLogs(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/reputation/audit", nil, "", nil)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	actorID, _ := strconv.ParseInt(c.Query("actor_id"), 10, 64)
+	targetUserID, _ := strconv.ParseInt(c.Query("target_user_id"), 10, 64)
+	action := c.Query("action")
+
+	logs, total, err := h.auditService.ListAuditLogs(c.Request.Context(), actorID, targetUserID, action, page, limit)
+	if err != nil {
+		bizLog.DatabaseError("select", "reputation_audit_logs", "ListAuditLogs", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	data := &dto.ReputationAuditListResponse{
+		Logs:       make([]dto.ReputationAuditItem, 0, len(logs)),
+		Total:      int(total),
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}
+	for _, log := range logs {
+		data.Logs = append(data.Logs, dto.ReputationAuditItem{
+			ID:             log.ID,
+			IdempotencyKey: log.IdempotencyKey,
+			Action:         log.Action,
+			ActorID:        log.ActorID,
+			TargetUserID:   log.TargetUserID,
+			Amount:         log.Amount,
+			Reason:         log.Reason,
+			ScoreBefore:    log.ScoreBefore,
+			ScoreAfter:     log.ScoreAfter,
+			Status:         log.Status,
+			Error:          log.Error,
+			TraceID:        log.TraceID,
+			CreatedAt:      log.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	response.OK(c, data, "获取声誉审计记录成功")
+}
+
+// executeMutation 校验幂等键和操作人，执行声誉变更并返回审计结果
+func (h *ReputationHandlers) executeMutation(c *gin.Context, bizLog *loggerpkg.BusinessLogger, mutation services.ReputationMutation, successMsg string) {
+	mutation.IdempotencyKey = c.GetHeader(IdempotencyKeyHeader)
+	if mutation.IdempotencyKey == "" || len(mutation.IdempotencyKey) > 128 {
+		bizLog.ValidationFailed("idempotency_key", "幂等键不能为空且不超过128个字符", mutation.IdempotencyKey)
+		response.Fail(c, response.CodeInvalidParams, "Idempotency-Key header is required (max 128 characters)")
+		return
+	}
+
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		bizLog.ValidationFailed("user_id", "用户未认证", "")
+		response.Fail(c, response.CodeUnauthorized, "User not authenticated")
+		return
+	}
+	mutation.ActorID = actorID.(int64)
+
+	log, replayed, err := h.auditService.Execute(c.Request.Context(), mutation)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			bizLog.UserNotFound("user_id", mutation.TargetUserID)
+			response.Fail(c, response.CodeUserNotFound, response.MsgUserNotFound)
+		case "idempotency key reused with different request", "request with this idempotency key is in progress":
+			bizLog.ValidationFailed("idempotency_key", err.Error(), mutation.IdempotencyKey)
+			response.Fail(c, response.CodeConflict, err.Error())
+		default:
+			bizLog.ThirdPartyError("reputation", mutation.Action, map[string]interface{}{
+				"user_id":  mutation.TargetUserID,
+				"actor_id": mutation.ActorID,
+			}, err)
+			response.Fail(c, response.CodeInternalError, err.Error())
+		}
+		return
+	}
+
+	bizLog.Success("reputationMutation", map[string]interface{}{
+		"audit_id": log.ID,
+		"action":   log.Action,
+		"user_id":  log.TargetUserID,
+		"actor_id": log.ActorID,
+		"replayed": replayed,
+	})
+
+	data := &dto.ReputationMutationResponse{
+		AuditID:     log.ID,
+		Action:      log.Action,
+		UserID:      log.TargetUserID,
+		ScoreBefore: log.ScoreBefore,
+		ScoreAfter:  log.ScoreAfter,
+		TraceID:     log.TraceID,
+		Replayed:    replayed,
+	}
+	response.OK(c, data, successMsg)
+}
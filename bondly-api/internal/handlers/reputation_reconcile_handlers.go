@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/pkg/response"
+	"bondly-api/internal/services"
+	"io"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReputationReconcileHandlers 声誉对账处理器
+type ReputationReconcileHandlers struct {
+	reconcileService *services.ReputationReconcileService
+}
+
+func NewReputationReconcileHandlers(reconcileService *services.ReputationReconcileService) *ReputationReconcileHandlers {
+	return &ReputationReconcileHandlers{
+		reconcileService: reconcileService,
+	}
+}
+
+// StartReconcile 开始声誉对账
+// @Summary 开始声誉对账
+// @Description 在后台对账所有绑定钱包用户的数据库与 ReputationVault 声誉分数，按权威方修正差异或仅生成报告（需要声誉管理权限）
+// @Tags 声誉系统
+// @Accept json
+// @Produce json
+// @Param request body dto.StartReputationReconcileRequest false "对账选项"
+// @Success 200 {object} response.Response[models.ReputationReconcileRun] "已开始的对账任务"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 401 {object} response.Response[any] "未认证"
+// @Failure 403 {object} response.Response[any] "权限不足"
+// @Failure 409 {object} response.Response[any] "已有对账任务在执行"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/reputation/reconcile [post]
+// @Security BearerAuth
+func (h *ReputationReconcileHandlers) StartReconcile(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/reputation/reconcile", nil, "", nil)
+
+	var req dto.StartReputationReconcileRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		bizLog.ValidationFailed("request_body", "JSON格式错误", err.Error())
+		response.Fail(c, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	opts := h.reconcileService.DefaultOptions(services.ReconcileTriggerManual)
+	if req.Authority != "" {
+		opts.Authority = req.Authority
+	}
+	if req.DryRun != nil {
+		opts.DryRun = *req.DryRun
+	}
+
+	run, err := h.reconcileService.Start(c.Request.Context(), opts)
+	if err != nil {
+		switch err.Error() {
+		case "reconciliation already running":
+			response.Fail(c, response.CodeConflict, err.Error())
+		case "invalid reconcile authority", "reputation vault not available", "relay wallet not configured":
+			bizLog.ValidationFailed("authority", err.Error(), opts.Authority)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+		default:
+			bizLog.DatabaseError("insert", "reputation_reconcile_runs", "StartReconcile", err)
+			response.Fail(c, response.CodeInternalError, err.Error())
+		}
+		return
+	}
+
+	response.OK(c, run, "开始声誉对账成功")
+}
+
+// ListRuns 获取声誉对账任务列表
+// @Summary 获取声誉对账任务列表
+// @Description 分页获取对账任务及其漂移指标：检查用户数、差异用户数、差额总和与最大值、修正和失败数量（需要声誉管理权限）
+// @Tags 声誉系统
+// @Accept json
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量，最大100" default(20)
+// @Success 200 {object} response.Response[any] "对账任务列表"
+// @Failure 401 {object} response.Response[any] "未认证"
+// @Failure 403 {object} response.Response[any] "权限不足"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/reputation/reconcile/runs [get]
+// @Security BearerAuth
+func (h *ReputationReconcileHandlers) ListRuns(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/reputation/reconcile/runs", nil, "", nil)
+
+	page, limit := parseReconcilePage(c)
+
+	runs, total, err := h.reconcileService.ListRuns(c.Request.Context(), page, limit)
+	if err != nil {
+		bizLog.DatabaseError("select", "reputation_reconcile_runs", "ListRuns", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	result := gin.H{
+		"runs": runs,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	}
+	response.OK(c, result, "获取声誉对账任务成功")
+}
+
+// GetRunReport 获取声誉对账报告
+// @Summary 获取声誉对账报告
+// @Description 获取对账任务的漂移指标和逐用户差异（数据库分数、待上链变更、链上分数、差额及处理结果），差额绝对值大的在前（需要声誉管理权限）
+// @Tags 声誉系统
+// @Accept json
+// @Produce json
+// @Param id path int true "对账任务ID"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量，最大100" default(20)
+// @Success 200 {object} response.Response[any] "对账报告"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 401 {object} response.Response[any] "未认证"
+// @Failure 403 {object} response.Response[any] "权限不足"
+// @Failure 404 {object} response.Response[any] "对账任务不存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/reputation/reconcile/runs/{id} [get]
+// @Security BearerAuth
+func (h *ReputationReconcileHandlers) GetRunReport(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/reputation/reconcile/runs/{id}", nil, "", nil)
+
+	idStr := c.Param("id")
+	runID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		bizLog.ValidationFailed("run_id", "对账任务ID格式错误", idStr)
+		response.Fail(c, response.CodeInvalidParams, "Invalid run ID")
+		return
+	}
+
+	page, limit := parseReconcilePage(c)
+
+	run, drifts, total, err := h.reconcileService.GetRun(c.Request.Context(), runID, page, limit)
+	if err != nil {
+		if err.Error() == "reconcile run not found" {
+			response.Fail(c, response.CodeNotFound, "Reconcile run not found")
+			return
+		}
+		bizLog.DatabaseError("select", "reputation_drifts", "GetRun", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	result := gin.H{
+		"run":    run,
+		"drifts": drifts,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	}
+	response.OK(c, result, "获取声誉对账报告成功")
+}
+
+// parseReconcilePage 解析分页参数
+func parseReconcilePage(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return page, limit
+}
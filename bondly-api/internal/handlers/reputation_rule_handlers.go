@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/pkg/response"
+	"bondly-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReputationRuleHandlers 声誉规则处理器
+type ReputationRuleHandlers struct {
+	ruleService *services.ReputationRuleService
+}
+
+func NewReputationRuleHandlers(ruleService *services.ReputationRuleService) *ReputationRuleHandlers {
+	return &ReputationRuleHandlers{
+		ruleService: ruleService,
+	}
+}
+
+// ListRules 获取声誉规则列表
+// @Summary 获取声誉规则列表
+// @Description 获取根据平台活动自动奖惩声誉的全部规则，包括变更值、每日上限和启用状态
+// @Tags 声誉系统
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response[[]models.ReputationRule] "规则列表"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/reputation/rules [get]
+func (h *ReputationRuleHandlers) ListRules(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/reputation/rules", nil, "", nil)
+
+	rules, err := h.ruleService.ListRules(c.Request.Context())
+	if err != nil {
+		bizLog.DatabaseError("select", "reputation_rules", "ListRules", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, rules, "获取声誉规则成功")
+}
+
+// UpdateRule 更新声誉规则
+// @Summary 更新声誉规则
+// @Description 调整指定事件的声誉变更值、每日上限、启用状态或原因描述（需要管理员权限）
+// @Tags 声誉系统
+// @Accept json
+// @Produce json
+// @Param event path string true "规则事件" Enums(content_published, content_liked, content_disliked, comment_created, comment_received, follower_gained, proposal_passed)
+// @Param request body dto.UpdateReputationRuleRequest true "规则更新内容"
+// @Success 200 {object} response.Response[models.ReputationRule] "更新后的规则"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 401 {object} response.Response[any] "权限不足"
+// @Failure 404 {object} response.Response[any] "规则不存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/reputation/rules/{event} [put]
+// @Security BearerAuth
+func (h *ReputationRuleHandlers) UpdateRule(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("PUT", "/api/v1/reputation/rules/{event}", nil, "", nil)
+
+	event := c.Param("event")
+
+	var req dto.UpdateReputationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bizLog.ValidationFailed("request_body", "JSON格式错误", err.Error())
+		response.Fail(c, response.CodeRequestFormatError, response.MsgRequestFormatError)
+		return
+	}
+
+	rule, err := h.ruleService.UpdateRule(c.Request.Context(), event, &req)
+	if err != nil {
+		switch err.Error() {
+		case "rule not found":
+			bizLog.ValidationFailed("event", "规则不存在", event)
+			response.Fail(c, response.CodeNotFound, "Rule not found")
+		case "daily cap must not be negative":
+			bizLog.ValidationFailed("daily_cap", "每日上限不能为负数", req.DailyCap)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+		default:
+			bizLog.DatabaseError("update", "reputation_rules", "UpdateRule", err)
+			response.Fail(c, response.CodeInternalError, err.Error())
+		}
+		return
+	}
+
+	response.OK(c, rule, "更新声誉规则成功")
+}
+
+// FlushToChain 批量同步规则声誉变更到链上
+// @Summary 批量同步规则声誉变更到链上
+// @Description 立即将待同步的规则声誉变更按用户汇总，通过 ReputationVault 批量写入链上（需要管理员权限）
+// @Tags 声誉系统
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response[dto.ReputationChainBatchResult] "同步结果"
+// @Failure 401 {object} response.Response[any] "权限不足"
+// @Failure 409 {object} response.Response[any] "对账或另一次同步正在进行"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/reputation/rules/flush [post]
+// @Security BearerAuth
+func (h *ReputationRuleHandlers) FlushToChain(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/reputation/rules/flush", nil, "", nil)
+
+	result, err := h.ruleService.FlushToChain(c.Request.Context())
+	if err != nil {
+		if err.Error() == "reputation chain sync in progress" {
+			response.Fail(c, response.CodeConflict, err.Error())
+			return
+		}
+		bizLog.ThirdPartyError("reputation_rule", "flush_to_chain", nil, err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, result, "同步声誉变更到链上成功")
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/pkg/response"
+	"bondly-api/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReputationSeasonHandlers 声誉赛季与累计声誉处理器
+type ReputationSeasonHandlers struct {
+	seasonService *services.ReputationSeasonService
+}
+
+func NewReputationSeasonHandlers(seasonService *services.ReputationSeasonService) *ReputationSeasonHandlers {
+	return &ReputationSeasonHandlers{
+		seasonService: seasonService,
+	}
+}
+
+// ListSeasons 获取声誉赛季列表
+// @Summary 获取声誉赛季列表
+// @Description 获取全部声誉赛季，最新的在前
+// @Tags 声誉系统
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response[[]models.ReputationSeason] "赛季列表"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/reputation/seasons [get]
+func (h *ReputationSeasonHandlers) ListSeasons(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/reputation/seasons", nil, "", nil)
+
+	seasons, err := h.seasonService.ListSeasons(c.Request.Context())
+	if err != nil {
+		bizLog.DatabaseError("select", "reputation_seasons", "ListSeasons", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, seasons, "获取声誉赛季成功")
+}
+
+// StartSeason 开启新的声誉赛季
+// @Summary 开启新的声誉赛季
+// @Description 结束当前赛季并冻结其排行榜，然后开启新赛季，可选将所有用户当前声誉清零（需要管理员权限）
+// @Tags 声誉系统
+// @Accept json
+// @Produce json
+// @Param request body dto.StartReputationSeasonRequest true "赛季信息"
+// @Success 200 {object} response.Response[models.ReputationSeason] "新赛季"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 401 {object} response.Response[any] "权限不足"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/reputation/seasons [post]
+// @Security BearerAuth
+func (h *ReputationSeasonHandlers) StartSeason(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/reputation/seasons", nil, "", nil)
+
+	var req dto.StartReputationSeasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bizLog.ValidationFailed("request_body", "JSON格式错误", err.Error())
+		response.Fail(c, response.CodeRequestFormatError, response.MsgRequestFormatError)
+		return
+	}
+
+	season, err := h.seasonService.StartSeason(c.Request.Context(), &req)
+	if err != nil {
+		bizLog.DatabaseError("insert", "reputation_seasons", "StartSeason", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, season, "开启声誉赛季成功")
+}
+
+// EndSeason 结束当前声誉赛季
+// @Summary 结束当前声誉赛季
+// @Description 结束进行中的赛季并冻结排行榜归档，不开启新赛季（需要管理员权限）
+// @Tags 声誉系统
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response[models.ReputationSeason] "已归档的赛季"
+// @Failure 401 {object} response.Response[any] "权限不足"
+// @Failure 404 {object} response.Response[any] "没有进行中的赛季"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/reputation/seasons/end [post]
+// @Security BearerAuth
+func (h *ReputationSeasonHandlers) EndSeason(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/reputation/seasons/end", nil, "", nil)
+
+	season, err := h.seasonService.EndSeason(c.Request.Context())
+	if err != nil {
+		if err.Error() == "no active season" {
+			response.Fail(c, response.CodeNotFound, "No active season")
+			return
+		}
+		bizLog.DatabaseError("update", "reputation_seasons", "EndSeason", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, season, "结束声誉赛季成功")
+}
+
+// GetSeasonLeaderboard 获取赛季排行榜
+// @Summary 获取赛季排行榜
+// @Description 已归档赛季返回结束时冻结的排名，进行中的赛季按赛季内获得的声誉实时排名
+// @Tags 声誉系统
+// @Accept json
+// @Produce json
+// @Param id path int true "赛季ID"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量，最大100" default(20)
+// @Success 200 {object} response.Response[dto.ReputationSeasonLeaderboardResponse] "赛季排行榜"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 404 {object} response.Response[any] "赛季不存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/reputation/seasons/{id}/leaderboard [get]
+func (h *ReputationSeasonHandlers) GetSeasonLeaderboard(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/reputation/seasons/{id}/leaderboard", nil, "", nil)
+
+	idStr := c.Param("id")
+	seasonID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		bizLog.ValidationFailed("season_id", "赛季ID格式错误", idStr)
+		response.Fail(c, response.CodeInvalidParams, "Invalid season ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	data, err := h.seasonService.GetSeasonLeaderboard(c.Request.Context(), seasonID, page, limit)
+	if err != nil {
+		if err.Error() == "season not found" {
+			response.Fail(c, response.CodeNotFound, "Season not found")
+			return
+		}
+		bizLog.DatabaseError("select", "reputation_season_standings", "GetSeasonLeaderboard", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, data, "获取赛季排行榜成功")
+}
+
+// GetLifetimeRanking 获取累计声誉排行榜
+// @Summary 获取累计声誉排行榜
+// @Description 按累计获得的声誉排名，不受衰减和赛季清零影响
+// @Tags 声誉系统
+// @Accept json
+// @Produce json
+// @Param limit query int false "返回数量，默认10，最大100" example(10)
+// @Success 200 {object} response.Response[dto.ReputationRankingResponse] "累计声誉排行榜"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/reputation/ranking/lifetime [get]
+func (h *ReputationSeasonHandlers) GetLifetimeRanking(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/reputation/ranking/lifetime", nil, "", nil)
+
+	limitStr := c.DefaultQuery("limit", "10")
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 || limit > 100 {
+		bizLog.ValidationFailed("limit", "限制数量必须在1-100之间", limitStr)
+		limit = 10
+	}
+
+	rankings, err := h.seasonService.GetLifetimeRanking(c.Request.Context(), limit)
+	if err != nil {
+		bizLog.DatabaseError("select", "reputation_events", "GetLifetimeRanking", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	data := &dto.ReputationRankingResponse{
+		Rankings: rankings,
+		Total:    len(rankings),
+	}
+
+	response.OK(c, data, "获取累计声誉排行榜成功")
+}
+
+// GetUserLifetime 获取用户累计声誉
+// @Summary 获取用户累计声誉
+// @Description 获取用户当前声誉、累计获得的声誉、累计衰减以及历史赛季排名
+// @Tags 声誉系统
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} response.Response[dto.ReputationLifetimeResponse] "累计声誉"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 404 {object} response.Response[any] "用户不存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/reputation/user/{id}/lifetime [get]
+func (h *ReputationSeasonHandlers) GetUserLifetime(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/reputation/user/{id}/lifetime", nil, "", nil)
+
+	idStr := c.Param("id")
+	userID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		bizLog.ValidationFailed("user_id", "用户ID格式错误", idStr)
+		response.Fail(c, response.CodeUserIDInvalid, response.MsgUserIDInvalid)
+		return
+	}
+
+	data, err := h.seasonService.GetUserLifetime(c.Request.Context(), userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			bizLog.UserNotFound("user_id", userID)
+			response.Fail(c, response.CodeUserNotFound, response.MsgUserNotFound)
+			return
+		}
+		bizLog.DatabaseError("select", "reputation_events", "GetUserLifetime", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, data, "获取累计声誉成功")
+}
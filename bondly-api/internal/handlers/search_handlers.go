@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/pkg/response"
+	"bondly-api/internal/services"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchHandlers 搜索处理器
+type SearchHandlers struct {
+	searchService *services.SearchService
+}
+
+func NewSearchHandlers(searchService *services.SearchService) *SearchHandlers {
+	return &SearchHandlers{
+		searchService: searchService,
+	}
+}
+
+// Search 全文搜索
+// @Summary 全文搜索
+// @Description 按相关度搜索已发布的内容、用户（昵称、简介）和提案（标题、描述），支持 websearch 语法（"短语"、OR、-排除）；结果包含命中片段，匹配词以 <mark> 包裹，counts 为各类型的命中数量
+// @Tags 搜索
+// @Accept json
+// @Produce json
+// @Param q query string true "关键词，最多100个字符"
+// @Param type query string false "搜索类型，逗号分隔：content、user、proposal，默认全部"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量，最大50" default(20)
+// @Success 200 {object} response.Response[any] "搜索结果"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/search [get]
+func (h *SearchHandlers) Search(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/search", nil, "", nil)
+
+	keyword := c.Query("q")
+
+	var types []string
+	if typeParam := c.Query("type"); typeParam != "" {
+		types = strings.Split(typeParam, ",")
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	results, counts, total, err := h.searchService.Search(c.Request.Context(), keyword, types, page, limit)
+	if err != nil {
+		switch err.Error() {
+		case "search keyword required", "search keyword too long", "invalid search type":
+			bizLog.ValidationFailed("q", err.Error(), keyword)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+		default:
+			response.Fail(c, response.CodeInternalError, err.Error())
+		}
+		return
+	}
+
+	result := gin.H{
+		"query":   keyword,
+		"results": results,
+		"counts":  counts,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	}
+	response.OK(c, result, "搜索成功")
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/pkg/response"
+	"bondly-api/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatsHandlers 平台统计处理器
+type StatsHandlers struct {
+	statsService *services.PlatformStatsService
+}
+
+func NewStatsHandlers(statsService *services.PlatformStatsService) *StatsHandlers {
+	return &StatsHandlers{
+		statsService: statsService,
+	}
+}
+
+// GetStats 获取统计信息
+// @Summary 获取平台统计信息
+// @Description 获取平台的用户数量、内容数量、提案数量、质押用户数和 GeneralStaking 总质押量（wei）。数据由聚合任务定期计算并缓存，updated_at 为最近一次聚合时间；首次聚合完成前返回全零数据。
+// @Tags 系统监控
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response[dto.StatsData] "统计信息"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/stats [get]
+func (h *StatsHandlers) GetStats(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/stats", nil, "", nil)
+
+	stats, err := h.statsService.GetStats(c.Request.Context())
+	if err != nil {
+		bizLog.DatabaseError("select", "platform_daily_stats", "GetStats", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, stats, response.MsgStatisticsRetrieved)
+}
+
+// GetDailyStats 获取每日统计
+// @Summary 获取每日统计
+// @Description 获取最近若干天的平台累计数量与每日新增数量，用于增长曲线；没有快照的日期沿用前一天的累计值
+// @Tags 系统监控
+// @Accept json
+// @Produce json
+// @Param days query int false "天数，最大365" default(30)
+// @Success 200 {object} response.Response[[]dto.StatsDailyPoint] "每日统计"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/stats/daily [get]
+func (h *StatsHandlers) GetDailyStats(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/stats/daily", nil, "", nil)
+
+	daysStr := c.DefaultQuery("days", "30")
+	days, err := strconv.Atoi(daysStr)
+	if err != nil || days < 1 || days > 365 {
+		bizLog.ValidationFailed("days", "天数必须在1到365之间", daysStr)
+		response.Fail(c, response.CodeInvalidParams, "days must be between 1 and 365")
+		return
+	}
+
+	series, err := h.statsService.GetDailySeries(c.Request.Context(), days)
+	if err != nil {
+		bizLog.DatabaseError("select", "platform_daily_stats", "GetDailySeries", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, series, response.MsgStatisticsRetrieved)
+}
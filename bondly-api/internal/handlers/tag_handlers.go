@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/pkg/response"
+	"bondly-api/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagHandlers 标签处理器
+type TagHandlers struct {
+	tagService *services.TagService
+}
+
+func NewTagHandlers(tagService *services.TagService) *TagHandlers {
+	return &TagHandlers{
+		tagService: tagService,
+	}
+}
+
+// Autocomplete 标签自动补全
+// @Summary 标签自动补全
+// @Description 按 slug 或名称前缀匹配标签，内容数量多的在前；q 为空时返回最常用的标签
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Param q query string false "标签前缀"
+// @Param limit query int false "返回数量，最大50" default(10)
+// @Success 200 {object} response.Response[any] "标签列表"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/tags [get]
+func (h *TagHandlers) Autocomplete(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/tags", nil, "", nil)
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	tags, err := h.tagService.Autocomplete(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		bizLog.DatabaseError("select", "tags", "Autocomplete", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, tags, "获取标签成功")
+}
+
+// Trending 热门标签
+// @Summary 热门标签
+// @Description 按最近若干天内标签下内容获得的互动数（点赞、收藏、分享等）排名
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Param days query int false "统计天数，最大30" default(7)
+// @Param limit query int false "返回数量，最大50" default(10)
+// @Success 200 {object} response.Response[any] "热门标签"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/tags/trending [get]
+func (h *TagHandlers) Trending(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/tags/trending", nil, "", nil)
+
+	daysStr := c.DefaultQuery("days", "7")
+	days, err := strconv.Atoi(daysStr)
+	if err != nil || days < 1 || days > 30 {
+		bizLog.ValidationFailed("days", "天数必须在1到30之间", daysStr)
+		response.Fail(c, response.CodeInvalidParams, "days must be between 1 and 30")
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	tags, err := h.tagService.TrendingTags(c.Request.Context(), days, limit)
+	if err != nil {
+		bizLog.DatabaseError("select", "tags", "TrendingTags", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, tags, "获取热门标签成功")
+}
+
+// GetTag 获取标签详情
+// @Summary 获取标签详情
+// @Description 获取标签的名称、内容数量和关注人数
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Param slug path string true "标签 slug"
+// @Success 200 {object} response.Response[any] "标签详情"
+// @Failure 404 {object} response.Response[any] "标签不存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/tags/{slug} [get]
+func (h *TagHandlers) GetTag(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/tags/{slug}", nil, "", nil)
+
+	slug := c.Param("slug")
+	tag, followers, err := h.tagService.GetTag(c.Request.Context(), slug)
+	if err != nil {
+		h.fail(c, bizLog, slug, err)
+		return
+	}
+
+	response.OK(c, gin.H{
+		"tag":       tag,
+		"followers": followers,
+	}, "获取标签成功")
+}
+
+// Follow 关注标签
+// @Summary 关注标签
+// @Description 当前用户关注标签
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Param slug path string true "标签 slug"
+// @Success 200 {object} response.Response[any] "关注成功"
+// @Failure 401 {object} response.Response[any] "未认证"
+// @Failure 404 {object} response.Response[any] "标签不存在"
+// @Failure 409 {object} response.Response[any] "已关注"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/tags/{slug}/follow [post]
+// @Security BearerAuth
+func (h *TagHandlers) Follow(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/tags/{slug}/follow", nil, "", nil)
+
+	userID, _ := c.Get("user_id")
+	slug := c.Param("slug")
+	if err := h.tagService.FollowTag(c.Request.Context(), userID.(int64), slug); err != nil {
+		h.fail(c, bizLog, slug, err)
+		return
+	}
+
+	response.OK(c, gin.H{}, "关注标签成功")
+}
+
+// Unfollow 取消关注标签
+// @Summary 取消关注标签
+// @Description 当前用户取消关注标签
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Param slug path string true "标签 slug"
+// @Success 200 {object} response.Response[any] "取消关注成功"
+// @Failure 401 {object} response.Response[any] "未认证"
+// @Failure 404 {object} response.Response[any] "标签不存在或未关注"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/tags/{slug}/follow [delete]
+// @Security BearerAuth
+func (h *TagHandlers) Unfollow(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("DELETE", "/api/v1/tags/{slug}/follow", nil, "", nil)
+
+	userID, _ := c.Get("user_id")
+	slug := c.Param("slug")
+	if err := h.tagService.UnfollowTag(c.Request.Context(), userID.(int64), slug); err != nil {
+		h.fail(c, bizLog, slug, err)
+		return
+	}
+
+	response.OK(c, gin.H{}, "取消关注标签成功")
+}
+
+// ListFollowed 获取关注的标签
+// @Summary 获取关注的标签
+// @Description 获取当前用户关注的标签，最近关注的在前
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response[any] "关注的标签"
+// @Failure 401 {object} response.Response[any] "未认证"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/tags/following [get]
+// @Security BearerAuth
+func (h *TagHandlers) ListFollowed(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/tags/following", nil, "", nil)
+
+	userID, _ := c.Get("user_id")
+	follows, err := h.tagService.ListFollowedTags(c.Request.Context(), userID.(int64))
+	if err != nil {
+		bizLog.DatabaseError("select", "tag_follows", "ListFollowedTags", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, follows, "获取关注的标签成功")
+}
+
+// Rename 重命名标签
+// @Summary 重命名标签
+// @Description 修改标签名称，slug 随之更新；新名称对应的标签已存在时请使用合并（需要 tag_moderator 权限）
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Param slug path string true "标签 slug"
+// @Param request body dto.RenameTagRequest true "新名称"
+// @Success 200 {object} response.Response[any] "重命名成功"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 403 {object} response.Response[any] "无权限"
+// @Failure 404 {object} response.Response[any] "标签不存在"
+// @Failure 409 {object} response.Response[any] "标签已存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/tags/{slug} [put]
+// @Security BearerAuth
+func (h *TagHandlers) Rename(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("PUT", "/api/v1/tags/{slug}", nil, "", nil)
+
+	var req dto.RenameTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bizLog.ValidationFailed("request_body", "JSON格式错误", err.Error())
+		response.Fail(c, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	slug := c.Param("slug")
+	tag, err := h.tagService.RenameTag(c.Request.Context(), slug, req.Name)
+	if err != nil {
+		h.fail(c, bizLog, slug, err)
+		return
+	}
+
+	response.OK(c, tag, "重命名标签成功")
+}
+
+// Merge 合并标签
+// @Summary 合并标签
+// @Description 将路径中的标签并入目标标签：内容关联和关注者转移到目标标签，随后删除源标签（需要 tag_moderator 权限）
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Param slug path string true "源标签 slug"
+// @Param request body dto.MergeTagRequest true "目标标签"
+// @Success 200 {object} response.Response[any] "合并后的目标标签"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 403 {object} response.Response[any] "无权限"
+// @Failure 404 {object} response.Response[any] "标签不存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/tags/{slug}/merge [post]
+// @Security BearerAuth
+func (h *TagHandlers) Merge(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/tags/{slug}/merge", nil, "", nil)
+
+	var req dto.MergeTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bizLog.ValidationFailed("request_body", "JSON格式错误", err.Error())
+		response.Fail(c, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	slug := c.Param("slug")
+	tag, err := h.tagService.MergeTag(c.Request.Context(), slug, req.Target)
+	if err != nil {
+		h.fail(c, bizLog, slug, err)
+		return
+	}
+
+	response.OK(c, tag, "合并标签成功")
+}
+
+// fail 将标签服务错误映射为响应
+func (h *TagHandlers) fail(c *gin.Context, bizLog *loggerpkg.BusinessLogger, slug string, err error) {
+	switch err.Error() {
+	case "tag not found", "not following this tag":
+		response.Fail(c, response.CodeNotFound, err.Error())
+	case "already following this tag", "tag already exists":
+		response.Fail(c, response.CodeConflict, err.Error())
+	case "invalid tag", "cannot merge tag into itself":
+		bizLog.ValidationFailed("tag", err.Error(), slug)
+		response.Fail(c, response.CodeInvalidParams, err.Error())
+	default:
+		response.Fail(c, response.CodeInternalError, err.Error())
+	}
+}
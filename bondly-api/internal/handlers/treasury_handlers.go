@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/pkg/response"
+	"bondly-api/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TreasuryHandlers 金库处理器
+type TreasuryHandlers struct {
+	treasuryService *services.TreasuryService
+}
+
+func NewTreasuryHandlers(treasuryService *services.TreasuryService) *TreasuryHandlers {
+	return &TreasuryHandlers{
+		treasuryService: treasuryService,
+	}
+}
+
+// GetDashboard 获取金库面板
+// @Summary 获取金库面板
+// @Description 获取 BondlyTreasury 持有的 ETH 和 BOND 余额（wei）、已索引的累计流入流出，以及按预算类别汇总的拨款（投票中、已通过待执行、已执行）
+// @Tags 金库
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response[dto.TreasuryDashboardResponse] "金库面板"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/treasury [get]
+func (h *TreasuryHandlers) GetDashboard(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/treasury", nil, "", nil)
+
+	dashboard, err := h.treasuryService.GetDashboard(c.Request.Context())
+	if err != nil {
+		bizLog.DatabaseError("select", "treasury_flows", "GetDashboard", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, dashboard, "获取金库面板成功")
+}
+
+// ListFlows 获取金库资金流水
+// @Summary 获取金库资金流水
+// @Description 分页获取从 BondlyTreasury 事件索引的资金流入流出记录，最新的在前
+// @Tags 金库
+// @Accept json
+// @Produce json
+// @Param asset query string false "资产：ETH 或 BOND"
+// @Param direction query string false "流向：in 或 out"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量，最大100" default(20)
+// @Success 200 {object} response.Response[any] "资金流水"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/treasury/flows [get]
+func (h *TreasuryHandlers) ListFlows(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/treasury/flows", nil, "", nil)
+
+	asset := c.Query("asset")
+	if asset != "" && asset != "ETH" && asset != "BOND" {
+		bizLog.ValidationFailed("asset", "不支持的资产", asset)
+		response.Fail(c, response.CodeInvalidParams, "Invalid asset")
+		return
+	}
+	direction := c.Query("direction")
+	if direction != "" && direction != "in" && direction != "out" {
+		bizLog.ValidationFailed("direction", "不支持的流向", direction)
+		response.Fail(c, response.CodeInvalidParams, "Invalid direction")
+		return
+	}
+
+	page, limit := parseTreasuryPagination(c)
+
+	flows, total, err := h.treasuryService.ListFlows(c.Request.Context(), asset, direction, page, limit)
+	if err != nil {
+		bizLog.DatabaseError("select", "treasury_flows", "ListFlows", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	result := gin.H{
+		"flows": flows,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	}
+	response.OK(c, result, "获取金库资金流水成功")
+}
+
+// IndexFlows 索引金库资金事件
+// @Summary 索引金库资金事件
+// @Description 立即从上次索引的位置拉取 BondlyTreasury 资金事件，直到已有足够确认数（TREASURY_INDEX_CONFIRMATIONS）的最新区块，并根据提案执行事件更新登记了链上提案ID的拨款（需要管理员权限）
+// @Tags 金库
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response[dto.TreasuryIndexResult] "索引结果"
+// @Failure 400 {object} response.Response[any] "未配置金库合约"
+// @Failure 401 {object} response.Response[any] "权限不足"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/treasury/index [post]
+// @Security BearerAuth
+func (h *TreasuryHandlers) IndexFlows(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/treasury/index", nil, "", nil)
+
+	result, err := h.treasuryService.IndexFlows(c.Request.Context())
+	if err != nil {
+		if err.Error() == "treasury not available" {
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+			return
+		}
+		bizLog.ThirdPartyError("blockchain", "index_treasury_flows", nil, err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, result, "索引金库资金事件成功")
+}
+
+// CreateDisbursement 申请金库拨款
+// @Summary 申请金库拨款
+// @Description 为投票中的治理提案申请一笔金库拨款并指定预算类别，每个提案只能申请一笔；提案通过后拨款变为 approved，链上执行后变为 executed（仅提案发起人或管理员）
+// @Tags 金库
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateDisbursementRequest true "拨款申请"
+// @Success 200 {object} response.Response[models.TreasuryDisbursement] "拨款申请"
+// @Failure 400 {object} response.Response[any] "参数错误或提案不在投票中"
+// @Failure 401 {object} response.Response[any] "未认证"
+// @Failure 403 {object} response.Response[any] "不是提案发起人"
+// @Failure 404 {object} response.Response[any] "提案不存在"
+// @Failure 409 {object} response.Response[any] "提案已申请拨款"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/treasury/disbursements [post]
+// @Security BearerAuth
+func (h *TreasuryHandlers) CreateDisbursement(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/treasury/disbursements", nil, "", nil)
+
+	var req dto.CreateDisbursementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bizLog.ValidationFailed("request_body", "JSON格式错误", err.Error())
+		response.Fail(c, response.CodeRequestFormatError, response.MsgRequestFormatError)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	userRole, _ := c.Get("user_role")
+
+	disbursement, err := h.treasuryService.RequestDisbursement(c.Request.Context(), userID.(int64), userRole == "admin", &req)
+	if err != nil {
+		switch err.Error() {
+		case "proposal not found":
+			response.Fail(c, response.CodeNotFound, "Proposal not found")
+		case "not proposal proposer":
+			response.Fail(c, response.CodeForbidden, "Only the proposer can request a disbursement")
+		case "disbursement already requested":
+			response.Fail(c, response.CodeConflict, err.Error())
+		case "invalid budget category", "invalid amount", "invalid recipient address", "proposal not active":
+			bizLog.ValidationFailed("request_body", err.Error(), req.ProposalID)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+		default:
+			bizLog.DatabaseError("insert", "treasury_disbursements", "CreateDisbursement", err)
+			response.Fail(c, response.CodeInternalError, err.Error())
+		}
+		return
+	}
+
+	response.OK(c, disbursement, "申请金库拨款成功")
+}
+
+// ListDisbursements 获取金库拨款申请
+// @Summary 获取金库拨款申请
+// @Description 分页获取治理提案的金库拨款申请，最新的在前，状态为 requested、approved、rejected、executed、failed 或 mismatched
+// @Tags 金库
+// @Accept json
+// @Produce json
+// @Param category query string false "预算类别"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量，最大100" default(20)
+// @Success 200 {object} response.Response[any] "拨款申请"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/treasury/disbursements [get]
+func (h *TreasuryHandlers) ListDisbursements(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/treasury/disbursements", nil, "", nil)
+
+	category := c.Query("category")
+	if category != "" && !services.IsValidTreasuryCategory(category) {
+		bizLog.ValidationFailed("category", "不支持的预算类别", category)
+		response.Fail(c, response.CodeInvalidParams, "invalid budget category")
+		return
+	}
+
+	page, limit := parseTreasuryPagination(c)
+
+	disbursements, total, err := h.treasuryService.ListDisbursements(c.Request.Context(), category, page, limit)
+	if err != nil {
+		bizLog.DatabaseError("select", "treasury_disbursements", "ListDisbursements", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	result := gin.H{
+		"disbursements": disbursements,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	}
+	response.OK(c, result, "获取金库拨款申请成功")
+}
+
+// GetDisbursement 获取金库拨款申请详情
+// @Summary 获取金库拨款申请详情
+// @Description 获取单笔拨款申请及其提案
+// @Tags 金库
+// @Accept json
+// @Produce json
+// @Param id path int true "拨款申请ID"
+// @Success 200 {object} response.Response[models.TreasuryDisbursement] "拨款申请"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 404 {object} response.Response[any] "拨款申请不存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/treasury/disbursements/{id} [get]
+func (h *TreasuryHandlers) GetDisbursement(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/treasury/disbursements/{id}", nil, "", nil)
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		bizLog.ValidationFailed("id", "拨款申请ID格式错误", idStr)
+		response.Fail(c, response.CodeInvalidParams, "Invalid disbursement ID")
+		return
+	}
+
+	disbursement, err := h.treasuryService.GetDisbursement(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "disbursement not found" {
+			response.Fail(c, response.CodeNotFound, "Disbursement not found")
+			return
+		}
+		bizLog.DatabaseError("select", "treasury_disbursements", "GetDisbursement", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, disbursement, "获取金库拨款申请成功")
+}
+
+// LinkChainProposal 登记拨款对应的链上提案
+// @Summary 登记拨款对应的链上提案
+// @Description 提交金库合约提案后登记其链上提案ID。索引到该提案的执行事件时，校验资产、金额和收款地址与申请一致后标记为 executed，不一致时标记为 mismatched（需要管理员权限）
+// @Tags 金库
+// @Accept json
+// @Produce json
+// @Param id path int true "拨款申请ID"
+// @Param request body dto.LinkChainProposalRequest true "链上提案ID"
+// @Success 200 {object} response.Response[models.TreasuryDisbursement] "拨款申请"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 401 {object} response.Response[any] "权限不足"
+// @Failure 404 {object} response.Response[any] "拨款申请不存在"
+// @Failure 409 {object} response.Response[any] "拨款已执行或链上提案ID已被登记"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/treasury/disbursements/{id}/chain-proposal [post]
+// @Security BearerAuth
+func (h *TreasuryHandlers) LinkChainProposal(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/treasury/disbursements/{id}/chain-proposal", nil, "", nil)
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		bizLog.ValidationFailed("id", "拨款申请ID格式错误", idStr)
+		response.Fail(c, response.CodeInvalidParams, "Invalid disbursement ID")
+		return
+	}
+
+	var req dto.LinkChainProposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bizLog.ValidationFailed("request_body", "JSON格式错误", err.Error())
+		response.Fail(c, response.CodeRequestFormatError, response.MsgRequestFormatError)
+		return
+	}
+
+	disbursement, err := h.treasuryService.LinkChainProposal(c.Request.Context(), id, req.ChainProposalID)
+	if err != nil {
+		switch err.Error() {
+		case "disbursement not found":
+			response.Fail(c, response.CodeNotFound, "Disbursement not found")
+		case "invalid chain proposal id":
+			bizLog.ValidationFailed("chain_proposal_id", err.Error(), req.ChainProposalID)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+		case "disbursement already executed", "chain proposal already linked":
+			response.Fail(c, response.CodeConflict, err.Error())
+		default:
+			bizLog.DatabaseError("update", "treasury_disbursements", "LinkChainProposal", err)
+			response.Fail(c, response.CodeInternalError, err.Error())
+		}
+		return
+	}
+
+	response.OK(c, disbursement, "登记链上提案成功")
+}
+
+// parseTreasuryPagination 解析分页参数
+func parseTreasuryPagination(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return page, limit
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/pkg/response"
+	"bondly-api/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserBlockHandlers 用户屏蔽处理器
+type UserBlockHandlers struct {
+	userBlockService *services.UserBlockService
+}
+
+func NewUserBlockHandlers(userBlockService *services.UserBlockService) *UserBlockHandlers {
+	return &UserBlockHandlers{
+		userBlockService: userBlockService,
+	}
+}
+
+// BlockUser 屏蔽用户
+// @Summary 屏蔽用户
+// @Description 屏蔽指定用户，被屏蔽的用户 @ 提及当前用户时不再产生通知，反之亦然
+// @Tags 粉丝管理
+// @Accept json
+// @Produce json
+// @Param user_id path int true "要屏蔽的用户ID"
+// @Success 200 {object} response.ResponseAny
+// @Failure 400 {object} response.ResponseAny
+// @Failure 401 {object} response.ResponseAny
+// @Failure 404 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/blocks/{user_id} [post]
+// @Security BearerAuth
+func (h *UserBlockHandlers) BlockUser(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/blocks/{user_id}", nil, "", nil)
+
+	blockedID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		bizLog.ValidationFailed("user_id", "无效的用户ID", c.Param("user_id"))
+		response.Fail(c, response.CodeInvalidParams, "Invalid user ID")
+		return
+	}
+	blockerID, _ := c.Get("user_id")
+
+	if err := h.userBlockService.BlockUser(c.Request.Context(), blockerID.(int64), blockedID); err != nil {
+		switch err.Error() {
+		case "cannot block yourself":
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+		case "user not found":
+			response.Fail(c, response.CodeNotFound, err.Error())
+		default:
+			bizLog.ThirdPartyError("user_block_service", "block_user", map[string]interface{}{
+				"blocker_id": blockerID,
+				"blocked_id": blockedID,
+			}, err)
+			response.Fail(c, response.CodeInternalError, err.Error())
+		}
+		return
+	}
+
+	bizLog.BusinessLogic("屏蔽用户成功", map[string]interface{}{
+		"blocker_id": blockerID,
+		"blocked_id": blockedID,
+	})
+	response.OK(c, gin.H{}, "User blocked successfully")
+}
+
+// UnblockUser 取消屏蔽用户
+// @Summary 取消屏蔽用户
+// @Description 取消屏蔽指定用户
+// @Tags 粉丝管理
+// @Accept json
+// @Produce json
+// @Param user_id path int true "要取消屏蔽的用户ID"
+// @Success 200 {object} response.ResponseAny
+// @Failure 400 {object} response.ResponseAny
+// @Failure 401 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/blocks/{user_id} [delete]
+// @Security BearerAuth
+func (h *UserBlockHandlers) UnblockUser(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("DELETE", "/api/v1/blocks/{user_id}", nil, "", nil)
+
+	blockedID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		bizLog.ValidationFailed("user_id", "无效的用户ID", c.Param("user_id"))
+		response.Fail(c, response.CodeInvalidParams, "Invalid user ID")
+		return
+	}
+	blockerID, _ := c.Get("user_id")
+
+	if err := h.userBlockService.UnblockUser(c.Request.Context(), blockerID.(int64), blockedID); err != nil {
+		bizLog.ThirdPartyError("user_block_service", "unblock_user", map[string]interface{}{
+			"blocker_id": blockerID,
+			"blocked_id": blockedID,
+		}, err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, gin.H{}, "User unblocked successfully")
+}
+
+// ListBlocked 获取屏蔽列表
+// @Summary 获取屏蔽列表
+// @Description 分页获取当前用户屏蔽的人
+// @Tags 粉丝管理
+// @Accept json
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Success 200 {object} response.ResponseAny
+// @Failure 401 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/blocks [get]
+// @Security BearerAuth
+func (h *UserBlockHandlers) ListBlocked(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/blocks", nil, "", nil)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	userID, _ := c.Get("user_id")
+
+	blocks, total, err := h.userBlockService.ListBlocked(c.Request.Context(), userID.(int64), page, limit)
+	if err != nil {
+		bizLog.ThirdPartyError("user_block_service", "list_blocked", map[string]interface{}{"user_id": userID}, err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	response.OK(c, gin.H{
+		"blocks": blocks,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	}, "Blocked users retrieved successfully")
+}
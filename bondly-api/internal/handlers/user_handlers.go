@@ -23,7 +23,7 @@ func NewUserHandlers(userService *services.UserService) *UserHandlers {
 
 // CreateUser 创建用户接口
 // @Summary 创建新用户
-// @Description 创建新用户，支持Web2和Web3用户信息
+// @Description 创建新用户，支持Web2和Web3用户信息；新用户角色固定为 user
 // @Tags 用户管理
 // @Accept json
 // @Produce json
@@ -53,18 +53,17 @@ func (h *UserHandlers) CreateUser(c *gin.Context) {
 		"wallet_address": req.WalletAddress,
 		"has_avatar":     req.AvatarURL != nil,
 		"has_bio":        req.Bio != nil,
-		"role":           req.Role,
 	})
 
-	// 构建用户模型
+	// 构建用户模型，公开注册一律为普通用户，角色只能由管理员通过更新接口调整；
+	// 声誉分是流水的投影，初始为 0，不接受请求方指定
 	user := &models.User{
 		WalletAddress:        req.WalletAddress,
 		Email:                req.Email,
 		Nickname:             req.Nickname,
 		AvatarURL:            req.AvatarURL,
 		Bio:                  req.Bio,
-		Role:                 req.Role,
-		ReputationScore:      req.ReputationScore,
+		Role:                 "user",
 		CustodyWalletAddress: req.CustodyWalletAddress,
 		EncryptedPrivateKey:  req.EncryptedPrivateKey,
 	}
@@ -232,7 +231,7 @@ func (h *UserHandlers) GetUserByEmail(c *gin.Context) {
 
 // UpdateUser 更新用户接口
 // @Summary 更新用户信息
-// @Description 更新指定用户的个人信息（部分更新）
+// @Description 更新指定用户的个人信息（部分更新），需要本人或管理员；role 只有管理员可以修改
 // @Tags 用户管理
 // @Accept json
 // @Produce json
@@ -241,6 +240,7 @@ func (h *UserHandlers) GetUserByEmail(c *gin.Context) {
 // @Success 200 {object} response.Response[dto.UserResponse] "用户更新成功"
 // @Failure 200 {object} response.Response[any] "用户不存在或更新失败"
 // @Router /api/v1/users/{id} [post]
+// @Security BearerAuth
 func (h *UserHandlers) UpdateUser(c *gin.Context) {
 	// 创建业务日志工具
 	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
@@ -273,6 +273,22 @@ func (h *UserHandlers) UpdateUser(c *gin.Context) {
 		"has_reputation_score": req.ReputationScore != nil,
 	})
 
+	// 角色决定管理员权限，只有管理员可以修改，避免用户给自己提权
+	if req.Role != nil {
+		if role, _ := c.Get("user_role"); role != "admin" {
+			bizLog.ValidationFailed("role", "只有管理员可以修改角色", *req.Role)
+			response.Fail(c, response.CodeForbidden, "only admins can change role")
+			return
+		}
+	}
+
+	// 声誉分数由声誉账本维护，不允许直接修改
+	if req.ReputationScore != nil {
+		bizLog.ValidationFailed("reputation_score", "声誉分数只能通过声誉接口调整", *req.ReputationScore)
+		response.Fail(c, response.CodeInvalidParams, "reputation_score can only be changed via /api/v1/reputation/add or /api/v1/reputation/subtract")
+		return
+	}
+
 	// 获取现有用户
 	user, err := h.userService.GetUserByID(c.Request.Context(), int64(id))
 	if err != nil {
@@ -301,10 +317,6 @@ func (h *UserHandlers) UpdateUser(c *gin.Context) {
 		user.Role = *req.Role
 		updatedFields = append(updatedFields, "role")
 	}
-	if req.ReputationScore != nil {
-		user.ReputationScore = *req.ReputationScore
-		updatedFields = append(updatedFields, "reputation_score")
-	}
 	if req.CustodyWalletAddress != nil {
 		user.CustodyWalletAddress = req.CustodyWalletAddress
 		updatedFields = append(updatedFields, "custody_wallet_address")
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/pkg/response"
+	"bondly-api/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserPermissionHandlers 用户权限处理器
+type UserPermissionHandlers struct {
+	permissionService *services.UserPermissionService
+}
+
+func NewUserPermissionHandlers(permissionService *services.UserPermissionService) *UserPermissionHandlers {
+	return &UserPermissionHandlers{
+		permissionService: permissionService,
+	}
+}
+
+// ListPermissions 获取用户权限
+// @Summary 获取用户权限
+// @Description 获取用户已被授予的权限列表（需要管理员权限）
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} response.Response[[]models.UserPermission] "权限列表"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 401 {object} response.Response[any] "权限不足"
+// @Failure 404 {object} response.Response[any] "用户不存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/users/{id}/permissions [get]
+// @Security BearerAuth
+func (h *UserPermissionHandlers) ListPermissions(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/users/{id}/permissions", nil, "", nil)
+
+	userID, ok := parseUserIDParam(c, bizLog)
+	if !ok {
+		return
+	}
+
+	permissions, err := h.permissionService.ListPermissions(c.Request.Context(), userID)
+	if err != nil {
+		h.fail(c, bizLog, userID, err)
+		return
+	}
+
+	response.OK(c, permissions, "获取用户权限成功")
+}
+
+// GrantPermission 授予用户权限
+// @Summary 授予用户权限
+// @Description 授予用户指定权限，如 reputation_manager、tag_moderator（需要管理员权限）
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param request body dto.GrantPermissionRequest true "权限"
+// @Success 200 {object} response.Response[any] "授予成功"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 401 {object} response.Response[any] "权限不足"
+// @Failure 404 {object} response.Response[any] "用户不存在"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/users/{id}/permissions [post]
+// @Security BearerAuth
+func (h *UserPermissionHandlers) GrantPermission(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/users/{id}/permissions", nil, "", nil)
+
+	userID, ok := parseUserIDParam(c, bizLog)
+	if !ok {
+		return
+	}
+
+	var req dto.GrantPermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bizLog.ValidationFailed("request_body", "JSON格式错误", err.Error())
+		response.Fail(c, response.CodeRequestFormatError, response.MsgRequestFormatError)
+		return
+	}
+
+	adminID, _ := c.Get("user_id")
+	if err := h.permissionService.GrantPermission(c.Request.Context(), userID, req.Permission, adminID.(int64)); err != nil {
+		h.fail(c, bizLog, userID, err)
+		return
+	}
+
+	response.OK(c, map[string]interface{}{}, "授予用户权限成功")
+}
+
+// RevokePermission 撤销用户权限
+// @Summary 撤销用户权限
+// @Description 撤销用户的指定权限（需要管理员权限）
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param permission path string true "权限" Enums(reputation_manager, tag_moderator)
+// @Success 200 {object} response.Response[any] "撤销成功"
+// @Failure 400 {object} response.Response[any] "参数错误"
+// @Failure 401 {object} response.Response[any] "权限不足"
+// @Failure 404 {object} response.Response[any] "权限未授予"
+// @Failure 500 {object} response.Response[any] "服务器错误"
+// @Router /api/v1/users/{id}/permissions/{permission} [delete]
+// @Security BearerAuth
+func (h *UserPermissionHandlers) RevokePermission(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("DELETE", "/api/v1/users/{id}/permissions/{permission}", nil, "", nil)
+
+	userID, ok := parseUserIDParam(c, bizLog)
+	if !ok {
+		return
+	}
+
+	adminID, _ := c.Get("user_id")
+	if err := h.permissionService.RevokePermission(c.Request.Context(), userID, c.Param("permission"), adminID.(int64)); err != nil {
+		h.fail(c, bizLog, userID, err)
+		return
+	}
+
+	response.OK(c, map[string]interface{}{}, "撤销用户权限成功")
+}
+
+// fail 将权限服务错误映射为响应
+func (h *UserPermissionHandlers) fail(c *gin.Context, bizLog *loggerpkg.BusinessLogger, userID int64, err error) {
+	switch err.Error() {
+	case "user not found":
+		bizLog.UserNotFound("user_id", userID)
+		response.Fail(c, response.CodeUserNotFound, response.MsgUserNotFound)
+	case "unknown permission":
+		response.Fail(c, response.CodeInvalidParams, "Unknown permission")
+	case "permission not granted":
+		response.Fail(c, response.CodeNotFound, "Permission not granted")
+	default:
+		bizLog.DatabaseError("update", "user_permissions", "UserPermission", err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+	}
+}
+
+// parseUserIDParam 解析路径中的用户ID，失败时直接写入响应
+func parseUserIDParam(c *gin.Context, bizLog *loggerpkg.BusinessLogger) (int64, bool) {
+	idStr := c.Param("id")
+	userID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		bizLog.ValidationFailed("user_id", "用户ID格式错误", idStr)
+		response.Fail(c, response.CodeUserIDInvalid, response.MsgUserIDInvalid)
+		return 0, false
+	}
+	return userID, true
+}
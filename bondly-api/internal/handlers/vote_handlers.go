@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/pkg/response"
+	"bondly-api/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VoteHandlers 提案投票处理器
+type VoteHandlers struct {
+	voteService *services.VoteService
+}
+
+func NewVoteHandlers(voteService *services.VoteService) *VoteHandlers {
+	return &VoteHandlers{
+		voteService: voteService,
+	}
+}
+
+// CastVote 对提案投票
+// @Summary 对提案投票
+// @Description 对进行中的提案投票，权重由提案的投票策略计算（一人一票、声誉线性、声誉/质押二次方、信念投票），并计入快照时的委托投票权；委托人直接投票会覆盖其委托
+// @Tags 提案管理
+// @Accept json
+// @Produce json
+// @Param id path int true "提案ID"
+// @Param request body dto.CastVoteRequest true "投票选项"
+// @Success 200 {object} response.ResponseAny
+// @Failure 400 {object} response.ResponseAny
+// @Failure 401 {object} response.ResponseAny
+// @Failure 404 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/proposals/{id}/vote [post]
+// @Security BearerAuth
+func (h *VoteHandlers) CastVote(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("POST", "/api/v1/proposals/{id}/vote", nil, "", nil)
+
+	proposalID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		bizLog.ValidationFailed("proposal_id", "无效的提案ID", c.Param("id"))
+		response.Fail(c, response.CodeInvalidParams, "Invalid proposal ID")
+		return
+	}
+
+	var req dto.CastVoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bizLog.ValidationFailed("request_body", "JSON格式错误", err.Error())
+		response.Fail(c, response.CodeInvalidParams, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		bizLog.ValidationFailed("user_id", "用户未认证", "")
+		response.Fail(c, response.CodeUnauthorized, "User not authenticated")
+		return
+	}
+
+	vote, err := h.voteService.CastVote(c.Request.Context(), proposalID, userID.(int64), *req.Vote)
+	if err != nil {
+		switch err.Error() {
+		case "proposal not found":
+			bizLog.ValidationFailed("proposal_id", "提案不存在", proposalID)
+			response.Fail(c, response.CodeNotFound, "Proposal not found")
+		case "proposal is not open for voting", "already voted", "voting strategy not available":
+			bizLog.ValidationFailed("proposal_id", err.Error(), proposalID)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+		default:
+			bizLog.ThirdPartyError("vote_service", "cast_vote", map[string]interface{}{
+				"proposal_id": proposalID,
+				"voter_id":    userID,
+			}, err)
+			response.Fail(c, response.CodeInternalError, err.Error())
+		}
+		return
+	}
+
+	response.OK(c, vote, response.MsgVoteSubmitted)
+}
+
+// ListVotes 获取提案投票列表
+// @Summary 获取提案投票列表
+// @Description 分页获取提案的投票记录，包含每票的总权重与委托权重
+// @Tags 提案管理
+// @Accept json
+// @Produce json
+// @Param id path int true "提案ID"
+// @Param page query int false "页码" default(1)
+// @Param limit query int false "每页数量" default(10)
+// @Success 200 {object} response.ResponseAny
+// @Failure 400 {object} response.ResponseAny
+// @Failure 404 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/proposals/{id}/votes [get]
+func (h *VoteHandlers) ListVotes(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/proposals/{id}/votes", nil, "", nil)
+
+	proposalID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		bizLog.ValidationFailed("proposal_id", "无效的提案ID", c.Param("id"))
+		response.Fail(c, response.CodeInvalidParams, "Invalid proposal ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	votes, total, err := h.voteService.ListVotes(c.Request.Context(), proposalID, page, limit)
+	if err != nil {
+		if err.Error() == "proposal not found" {
+			bizLog.ValidationFailed("proposal_id", "提案不存在", proposalID)
+			response.Fail(c, response.CodeNotFound, "Proposal not found")
+			return
+		}
+		bizLog.ThirdPartyError("vote_service", "list_votes", map[string]interface{}{"proposal_id": proposalID}, err)
+		response.Fail(c, response.CodeInternalError, err.Error())
+		return
+	}
+
+	result := gin.H{
+		"votes": votes,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	}
+	response.OK(c, result, "Vote list retrieved successfully")
+}
+
+// GetResult 获取提案计票结果
+// @Summary 获取提案计票结果
+// @Description 获取提案按投票策略计算的赞成/反对票数、投票人数和是否通过；信念投票的提案由投票和定时任务重新累积权重，tallied_at 为最近一次计票时间
+// @Tags 提案管理
+// @Accept json
+// @Produce json
+// @Param id path int true "提案ID"
+// @Success 200 {object} response.ResponseAny{data=dto.ProposalResultResponse}
+// @Failure 400 {object} response.ResponseAny
+// @Failure 404 {object} response.ResponseAny
+// @Failure 500 {object} response.ResponseAny
+// @Router /api/v1/proposals/{id}/result [get]
+func (h *VoteHandlers) GetResult(c *gin.Context) {
+	bizLog := loggerpkg.NewBusinessLogger(c.Request.Context())
+	bizLog.StartAPI("GET", "/api/v1/proposals/{id}/result", nil, "", nil)
+
+	proposalID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		bizLog.ValidationFailed("proposal_id", "无效的提案ID", c.Param("id"))
+		response.Fail(c, response.CodeInvalidParams, "Invalid proposal ID")
+		return
+	}
+
+	result, err := h.voteService.GetResult(c.Request.Context(), proposalID)
+	if err != nil {
+		switch err.Error() {
+		case "proposal not found":
+			bizLog.ValidationFailed("proposal_id", "提案不存在", proposalID)
+			response.Fail(c, response.CodeNotFound, "Proposal not found")
+		case "voting strategy not available":
+			bizLog.ValidationFailed("proposal_id", err.Error(), proposalID)
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+		default:
+			bizLog.ThirdPartyError("vote_service", "get_result", map[string]interface{}{"proposal_id": proposalID}, err)
+			response.Fail(c, response.CodeInternalError, err.Error())
+		}
+		return
+	}
+
+	response.OK(c, result, "Proposal result retrieved successfully")
+}
@@ -80,6 +80,42 @@ func AdminOnly() gin.HandlerFunc {
 	}
 }
 
+// RequirePermission 要求拥有指定权限的中间件，管理员默认拥有全部权限
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Authentication required",
+			})
+			c.Abort()
+			return
+		}
+
+		userRole, _ := c.Get("user_role")
+		if userRole == "admin" {
+			c.Next()
+			return
+		}
+
+		var count int64
+		err := database.GetDB().Model(&models.UserPermission{}).
+			Where("user_id = ? AND permission = ?", userID, permission).
+			Count(&count).Error
+		if err != nil || count == 0 {
+			c.JSON(http.StatusForbidden, gin.H{
+				"status":  "error",
+				"message": "Permission required: " + permission,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // AdminOrOwner 管理员或资源所有者访问中间件
 func AdminOrOwner(resourceType string) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -212,3 +248,45 @@ func OptionalAuth() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// StreamAuth 长连接认证中间件，浏览器的 WebSocket 和 EventSource 无法设置请求头，因此也接受 access_token 查询参数
+func StreamAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Query("access_token")
+		if authHeader := c.GetHeader("Authorization"); authHeader != "" {
+			tokenParts := strings.Split(authHeader, " ")
+			if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"status":  "error",
+					"message": "Invalid authorization header format",
+				})
+				c.Abort()
+				return
+			}
+			token = tokenParts[1]
+		}
+		if token == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Authorization header or access_token required",
+			})
+			c.Abort()
+			return
+		}
+
+		claims, err := utils.ValidateJWT(token)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Invalid or expired token",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", claims.UserID)
+		c.Set("user_role", claims.Role)
+		c.Set("wallet_address", claims.WalletAddress)
+		c.Next()
+	}
+}
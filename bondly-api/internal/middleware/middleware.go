@@ -3,6 +3,8 @@ package middleware
 import (
 	"bondly-api/config"
 	loggerpkg "bondly-api/internal/logger"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -38,6 +40,9 @@ func TraceIDMiddleware() gin.HandlerFunc {
 	}
 }
 
+// sensitiveQueryParams 访问日志中需要隐去值的查询参数：长连接的 access_token 和退订链接的 token
+var sensitiveQueryParams = []string{"access_token", "token"}
+
 // Logger 日志中间件
 func Logger(log *logrus.Logger) gin.HandlerFunc {
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
@@ -46,19 +51,42 @@ func Logger(log *logrus.Logger) gin.HandlerFunc {
 			"latency":    param.Latency,
 			"client_ip":  param.ClientIP,
 			"method":     param.Method,
-			"path":       param.Path,
+			"path":       redactPath(param.Path),
 			"user_agent": param.Request.UserAgent(),
 		}).Info("HTTP Request")
 		return ""
 	})
 }
 
+// redactPath 隐去路径中敏感查询参数的值；查询串无法解析时整体隐去
+func redactPath(path string) string {
+	base, rawQuery, ok := strings.Cut(path, "?")
+	if !ok {
+		return path
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return base + "?REDACTED"
+	}
+	redacted := false
+	for _, name := range sensitiveQueryParams {
+		if _, ok := query[name]; ok {
+			query.Set(name, "REDACTED")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return path
+	}
+	return base + "?" + query.Encode()
+}
+
 // CORS 跨域中间件
 func CORS(cfg config.CORSConfig) gin.HandlerFunc {
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowOrigins = cfg.AllowedOrigins
 	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "Cache-Control", "Pragma", "Expires"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "Cache-Control", "Pragma", "Expires", "Idempotency-Key"}
 	corsConfig.AllowCredentials = true
 	return cors.New(corsConfig)
 }
@@ -14,6 +14,7 @@ type User struct {
 	Bio                  *string    `json:"bio" gorm:"type:text" comment:"用户个人简介（如：自我介绍、个性签名），可为空"`
 	Role                 string     `json:"role" gorm:"size:32;default:user;not null;check:role IN ('user', 'admin', 'moderator')" comment:"用户角色，默认 user，可扩展为 admin、moderator 等"`
 	ReputationScore      int        `json:"reputation_score" gorm:"default:0;not null;check:reputation_score >= 0" comment:"声誉积分，默认 0，用于表示用户活跃度、贡献度、治理投票权等"`
+	ReputationDecayedAt  *time.Time `json:"-" comment:"声誉分数最近一次衰减的时间，作为下一次衰减的起点"`
 	CustodyWalletAddress *string    `json:"custody_wallet_address" gorm:"size:42;check:char_length(custody_wallet_address) = 42" comment:"托管钱包地址，用于平台托管用户资产，允许为空"`
 	EncryptedPrivateKey  *string    `json:"encrypted_private_key" gorm:"type:text" comment:"加密的私钥，用于托管钱包操作，允许为空"`
 	HasReceivedAirdrop   bool       `json:"has_received_airdrop" gorm:"default:false;not null" comment:"是否已获得新用户空投"`
@@ -40,52 +41,91 @@ type Post struct {
 
 // Content 内容模型（兼容旧版）
 type Content struct {
-	ID                 int64     `json:"id" gorm:"primaryKey"`
-	AuthorID           int64     `json:"author_id"`
-	Title              string    `json:"title"`
-	Content            string    `json:"content"`
-	Type               string    `json:"type"`                        // article, post, comment
-	Status             string    `json:"status" gorm:"default:draft"` // draft, published, archived
-	CoverImageURL      *string   `json:"cover_image_url" gorm:"type:text" comment:"封面图片URL"`
-	NFTTokenID         *int64    `json:"nft_token_id" gorm:"column:nft_token_id;comment:NFT Token ID，如果内容已铸造为NFT"`
-	NFTContractAddress *string   `json:"nft_contract_address" gorm:"column:nft_contract_address;comment:NFT合约地址"`
-	IPFSHash           *string   `json:"ip_fs_hash" gorm:"column:ip_fs_hash;comment:IPFS内容哈希"`
-	MetadataHash       *string   `json:"metadata_hash" gorm:"column:metadata_hash;comment:IPFS元数据哈希"`
-	Likes              int64     `json:"likes" gorm:"default:0"`
-	Dislikes           int64     `json:"dislikes" gorm:"default:0"`
-	Views              int64     `json:"views" gorm:"default:0"`
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
-	Author             User      `json:"author" gorm:"foreignKey:AuthorID"`
+	ID                 int64            `json:"id" gorm:"primaryKey"`
+	AuthorID           int64            `json:"author_id"`
+	Title              string           `json:"title"`
+	Content            string           `json:"content"`                                          // Markdown 源文
+	ContentHTML        string           `json:"content_html" gorm:"type:text"`                    // 由 Markdown 渲染并按白名单净化的 HTML
+	Excerpt            string           `json:"excerpt" gorm:"type:text"`                         // 纯文本摘要
+	WordCount          int              `json:"word_count" gorm:"default:0"`                      // 字数，中文按字、其他语言按词计
+	ReadingTime        int              `json:"reading_time" gorm:"default:0"`                    // 预计阅读分钟数
+	TOC                []ContentHeading `json:"toc" gorm:"column:toc;serializer:json;type:jsonb"` // 由标题生成的目录
+	Type               string           `json:"type"`                                             // article, post, comment
+	Status             string           `json:"status" gorm:"default:draft"`                      // draft, in_review, scheduled, published, archived
+	CoverImageURL      *string          `json:"cover_image_url" gorm:"type:text" comment:"封面图片URL"`
+	NFTTokenID         *int64           `json:"nft_token_id" gorm:"column:nft_token_id;comment:NFT Token ID，如果内容已铸造为NFT"`
+	NFTContractAddress *string          `json:"nft_contract_address" gorm:"column:nft_contract_address;comment:NFT合约地址"`
+	IPFSHash           *string          `json:"ip_fs_hash" gorm:"column:ip_fs_hash;comment:IPFS内容哈希"`
+	MetadataHash       *string          `json:"metadata_hash" gorm:"column:metadata_hash;comment:IPFS元数据哈希"`
+	Likes              int64            `json:"likes" gorm:"default:0"`
+	Dislikes           int64            `json:"dislikes" gorm:"default:0"`
+	Views              int64            `json:"views" gorm:"default:0"`
+	PublishedAt        *time.Time       `json:"published_at" gorm:"index"` // 首次发布时间，用于信息流排序
+	PublishAt          *time.Time       `json:"publish_at" gorm:"index"`   // 定时发布时间，status 为 scheduled 时到期自动发布
+	CreatedAt          time.Time        `json:"created_at"`
+	UpdatedAt          time.Time        `json:"updated_at"`
+	Author             User             `json:"author" gorm:"foreignKey:AuthorID"`
+	Tags               []Tag            `json:"tags" gorm:"-"` // 由服务层从 content_tags 填充
+}
+
+// ContentHeading 内容目录中的一个标题，ID 为渲染后 HTML 中的锚点
+type ContentHeading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	ID    string `json:"id"`
 }
 
 // Proposal 提案模型
 type Proposal struct {
-	ID           int64     `json:"id" gorm:"primaryKey"`
-	Title        string    `json:"title"`
-	Description  string    `json:"description"`
-	ProposerID   int64     `json:"proposer_id"`
-	Status       string    `json:"status" gorm:"default:active"` // active, passed, rejected, executed
-	VotesFor     int64     `json:"votes_for" gorm:"default:0"`
-	VotesAgainst int64     `json:"votes_against" gorm:"default:0"`
-	StartTime    time.Time `json:"start_time"`
-	EndTime      time.Time `json:"end_time"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
-	Proposer     User      `json:"proposer" gorm:"foreignKey:ProposerID"`
+	ID             int64     `json:"id" gorm:"primaryKey"`
+	Title          string    `json:"title"`
+	Description    string    `json:"description"`
+	ProposerID     int64     `json:"proposer_id"`
+	Topic          string    `json:"topic" gorm:"size:64;default:''"`               // 提案主题，用于匹配按主题的投票委托
+	VotingStrategy string    `json:"voting_strategy" gorm:"size:32;default:linear"` // simple, linear, quadratic, quadratic_stake, conviction
+	Status         string    `json:"status" gorm:"default:active"`                  // active, passed, rejected, executed
+	VotesFor       int64     `json:"votes_for" gorm:"default:0"`
+	VotesAgainst   int64     `json:"votes_against" gorm:"default:0"`
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	Proposer       User      `json:"proposer" gorm:"foreignKey:ProposerID"`
 }
 
 // Vote 投票模型
 type Vote struct {
-	ID         int64     `json:"id" gorm:"primaryKey"`
-	ProposalID int64     `json:"proposal_id"`
-	VoterID    int64     `json:"voter_id"`
-	Vote       bool      `json:"vote"` // true for yes, false for no
-	Weight     int64     `json:"weight"`
+	ID              int64     `json:"id" gorm:"primaryKey"`
+	ProposalID      int64     `json:"proposal_id" gorm:"uniqueIndex:idx_votes_proposal_voter,priority:1"`
+	VoterID         int64     `json:"voter_id" gorm:"uniqueIndex:idx_votes_proposal_voter,priority:2"` // 每个用户对同一提案只能投一票
+	Vote            bool      `json:"vote"`                                                            // true for yes, false for no
+	Weight          int64     `json:"weight"`
+	DelegatedWeight int64     `json:"delegated_weight" gorm:"default:0"` // Weight 中来自委托的部分
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	Proposal        Proposal  `json:"proposal" gorm:"foreignKey:ProposalID"`
+	Voter           User      `json:"voter" gorm:"foreignKey:VoterID"`
+}
+
+// ProposalPowerSnapshot 提案的投票权快照，读取链上质押的投票策略在用户首次参与计票时保存，之后的计票都使用该值
+type ProposalPowerSnapshot struct {
+	ProposalID int64     `json:"proposal_id" gorm:"primaryKey"`
+	UserID     int64     `json:"user_id" gorm:"primaryKey"`
+	Power      int64     `json:"power" gorm:"not null;default:0"`
 	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
-	Proposal   Proposal  `json:"proposal" gorm:"foreignKey:ProposalID"`
-	Voter      User      `json:"voter" gorm:"foreignKey:VoterID"`
+}
+
+// VoteDelegation 投票委托模型
+type VoteDelegation struct {
+	ID          int64      `json:"id" gorm:"primaryKey"`
+	DelegatorID int64      `json:"delegator_id" gorm:"not null;index"`
+	DelegateID  int64      `json:"delegate_id" gorm:"not null;index"`
+	Topic       string     `json:"topic" gorm:"size:64;default:'';not null"` // 为空表示全局委托，否则仅对该主题的提案生效
+	RevokedAt   *time.Time `json:"revoked_at"`                               // 撤销时间，为空表示仍然有效
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Delegator   User       `json:"delegator" gorm:"foreignKey:DelegatorID"`
+	Delegate    User       `json:"delegate" gorm:"foreignKey:DelegateID"`
 }
 
 // Transaction 交易模型
@@ -105,20 +145,92 @@ type Transaction struct {
 
 // Comment 评论模型
 type Comment struct {
+	ID              int64      `json:"id" gorm:"primaryKey"`
+	PostID          *int64     `json:"post_id"`    // 关联posts表，可为空
+	ContentID       *int64     `json:"content_id"` // 关联contents表，可为空
+	AuthorID        int64      `json:"author_id"`
+	Content         string     `json:"content"`
+	ParentCommentID *int64     `json:"parent_comment_id"`
+	Path            string     `json:"-" gorm:"type:text"`           // 物化路径：从根评论到本评论的ID，以 / 分隔并结尾，如 12/34/
+	Depth           int        `json:"depth" gorm:"default:0"`       // 嵌套层级，顶层评论为 0
+	ReplyCount      int        `json:"reply_count" gorm:"default:0"` // 直接回复数
+	Likes           int        `json:"likes" gorm:"default:0"`
+	Status          string     `json:"status" gorm:"type:varchar(20);default:'visible';index"` // visible, hidden（被审核隐藏）, deleted（作者删除，保留节点以展示回复）
+	Pinned          bool       `json:"pinned" gorm:"default:false"`                            // 置顶，仅顶层评论
+	Locked          bool       `json:"locked" gorm:"default:false"`                            // 锁定后本评论及其子树不能再回复
+	EditCount       int        `json:"edit_count" gorm:"default:0"`
+	EditedAt        *time.Time `json:"edited_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	Author          User       `json:"author" gorm:"foreignKey:AuthorID"`
+	Post            *Post      `json:"post,omitempty" gorm:"foreignKey:PostID"`
+	ContentRef      *Content   `json:"content_ref,omitempty" gorm:"foreignKey:ContentID"`
+	ParentComment   *Comment   `json:"parent_comment,omitempty" gorm:"foreignKey:ParentCommentID"`
+	ChildComments   []Comment  `json:"child_comments,omitempty" gorm:"foreignKey:ParentCommentID"`
+	Liked           bool       `json:"liked" gorm:"-"` // 当前用户是否已点赞，由服务层填充
+}
+
+// CommentLike 用户对评论的点赞，每个用户对每条评论最多一条
+type CommentLike struct {
+	CommentID int64     `json:"comment_id" gorm:"primaryKey"`
+	UserID    int64     `json:"user_id" gorm:"primaryKey;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CommentEdit 评论编辑历史，记录每次编辑前的内容
+type CommentEdit struct {
+	ID        int64     `json:"id" gorm:"primaryKey"`
+	CommentID int64     `json:"comment_id" gorm:"index;not null"`
+	Content   string    `json:"content"`
+	EditedAt  time.Time `json:"edited_at"`
+}
+
+// UserBlock 用户屏蔽关系，被屏蔽的用户无法再向屏蔽者发送提及等通知
+type UserBlock struct {
+	BlockerID int64     `json:"blocker_id" gorm:"primaryKey"`
+	BlockedID int64     `json:"blocked_id" gorm:"primaryKey;index"`
+	CreatedAt time.Time `json:"created_at"`
+	Blocked   User      `json:"blocked,omitempty" gorm:"foreignKey:BlockedID"`
+}
+
+// Mention 评论或内容中对用户的 @ 提及，同一来源对同一用户只记录一次
+type Mention struct {
 	ID              int64     `json:"id" gorm:"primaryKey"`
-	PostID          *int64    `json:"post_id"`    // 关联posts表，可为空
-	ContentID       *int64    `json:"content_id"` // 关联contents表，可为空
-	AuthorID        int64     `json:"author_id"`
-	Content         string    `json:"content"`
-	ParentCommentID *int64    `json:"parent_comment_id"`
-	Likes           int       `json:"likes" gorm:"default:0"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	SourceType      string    `json:"source_type" gorm:"size:16;not null;uniqueIndex:idx_mentions_source_user,priority:1"` // comment, content
+	SourceID        int64     `json:"source_id" gorm:"not null;uniqueIndex:idx_mentions_source_user,priority:2"`
+	MentionedUserID int64     `json:"mentioned_user_id" gorm:"not null;uniqueIndex:idx_mentions_source_user,priority:3;index:idx_mentions_user_created,priority:1"`
+	AuthorID        int64     `json:"author_id" gorm:"not null"` // 发出提及的用户
+	CreatedAt       time.Time `json:"created_at" gorm:"index:idx_mentions_user_created,priority:2"`
 	Author          User      `json:"author" gorm:"foreignKey:AuthorID"`
-	Post            *Post     `json:"post,omitempty" gorm:"foreignKey:PostID"`
-	ContentRef      *Content  `json:"content_ref,omitempty" gorm:"foreignKey:ContentID"`
-	ParentComment   *Comment  `json:"parent_comment,omitempty" gorm:"foreignKey:ParentCommentID"`
-	ChildComments   []Comment `json:"child_comments,omitempty" gorm:"foreignKey:ParentCommentID"`
+}
+
+// Notification 站内通知
+type Notification struct {
+	ID          int64             `json:"id" gorm:"primaryKey"`
+	UserID      int64             `json:"user_id" gorm:"not null;index:idx_notifications_user_created,priority:1"` // 接收者
+	Type        string            `json:"type" gorm:"size:32;not null"`
+	ActorID     int64             `json:"actor_id"`                                         // 触发通知的用户，系统通知为 0
+	SubjectType string            `json:"subject_type" gorm:"size:32"`                      // 通知关联的对象类型，如 comment、content、user、proposal、airdrop
+	SubjectID   int64             `json:"subject_id"`                                       // 通知关联的对象ID
+	Data        map[string]string `json:"data,omitempty" gorm:"serializer:json;type:jsonb"` // 展示通知所需的附加信息，如提案标题、空投状态
+	ReadAt      *time.Time        `json:"read_at"`                                          // 为空表示未读
+	CreatedAt   time.Time         `json:"created_at" gorm:"index:idx_notifications_user_created,priority:2"`
+}
+
+// NotificationPreference 用户对某类通知的接收方式，没有记录时使用默认方式
+type NotificationPreference struct {
+	UserID    int64     `json:"-" gorm:"primaryKey"`
+	Type      string    `json:"type" gorm:"primaryKey;size:32"`
+	Channel   string    `json:"channel" gorm:"size:16;not null"` // in_app、email 或 off
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NotificationSetting 用户的邮件摘要设置，没有记录时使用默认设置
+type NotificationSetting struct {
+	UserID          int64      `json:"-" gorm:"primaryKey"`
+	DigestFrequency string     `json:"digest_frequency" gorm:"size:16;not null"` // daily、weekly 或 off
+	LastDigestAt    *time.Time `json:"last_digest_at"`                           // 上次发送摘要的时间，下一期摘要从这里开始统计
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
 // UserFollower 用户关注关系模型
@@ -164,3 +276,304 @@ type AirdropRecord struct {
 	UpdatedAt     time.Time `json:"updated_at"`
 	User          User      `json:"user" gorm:"foreignKey:UserID"`
 }
+
+// ReputationEvent 声誉变更事件（只追加），users.reputation_score 为其 delta 之和的投影
+type ReputationEvent struct {
+	ID          int64     `json:"id" gorm:"primaryKey"`
+	UserID      int64     `json:"user_id" gorm:"not null;index:idx_reputation_events_user_created,priority:1"`
+	Delta       int       `json:"delta" gorm:"not null"`                            // 实际生效的变更值，扣减不会使分数低于 0
+	ScoreAfter  int       `json:"score_after" gorm:"not null"`                      // 变更后的声誉分数
+	Reason      string    `json:"reason" gorm:"size:255;not null;index"`            // 变更原因
+	Source      string    `json:"source" gorm:"size:32;not null"`                   // manual, chain_sync, opening_balance 等
+	RelatedType string    `json:"related_type,omitempty" gorm:"size:32;default:''"` // 关联实体类型，如 content、comment、proposal
+	RelatedID   *int64    `json:"related_id,omitempty"`                             // 关联实体ID
+	TxHash      string    `json:"tx_hash,omitempty" gorm:"size:66;default:''"`      // 链上交易哈希
+	CreatedAt   time.Time `json:"created_at" gorm:"index:idx_reputation_events_user_created,priority:2"`
+}
+
+// ReputationRule 声誉自动奖惩规则
+type ReputationRule struct {
+	ID          int64     `json:"id" gorm:"primaryKey"`
+	Event       string    `json:"event" gorm:"size:32;uniqueIndex;not null"` // 触发事件，如 content_published、content_liked
+	Delta       int       `json:"delta" gorm:"not null"`                     // 每次触发的声誉变更值，负数为扣减
+	DailyCap    int       `json:"daily_cap" gorm:"default:0;not null"`       // 每个用户每天通过该规则变更的绝对值上限，0 表示不限
+	Enabled     bool      `json:"enabled" gorm:"default:true;not null"`
+	Description string    `json:"description" gorm:"size:255"` // 记入声誉事件的原因
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ReputationRuleHit 规则命中记录，用于去重、每日上限统计和链上批量同步
+type ReputationRuleHit struct {
+	ID          int64      `json:"id" gorm:"primaryKey"`
+	Event       string     `json:"event" gorm:"size:32;not null;uniqueIndex:idx_reputation_rule_hits_dedupe,priority:1;index:idx_reputation_rule_hits_user_day,priority:2"`
+	UserID      int64      `json:"user_id" gorm:"not null;uniqueIndex:idx_reputation_rule_hits_dedupe,priority:2;index:idx_reputation_rule_hits_user_day,priority:1"` // 获得声誉变更的用户
+	ActorID     int64      `json:"actor_id" gorm:"not null;uniqueIndex:idx_reputation_rule_hits_dedupe,priority:3"`                                                   // 触发事件的用户
+	RelatedType string     `json:"related_type" gorm:"size:32;not null;uniqueIndex:idx_reputation_rule_hits_dedupe,priority:4"`
+	RelatedID   int64      `json:"related_id" gorm:"not null;uniqueIndex:idx_reputation_rule_hits_dedupe,priority:5"`
+	Delta       int        `json:"delta" gorm:"not null"`
+	EventID     *int64     `json:"event_id"`                                                   // 对应的 reputation_events 记录
+	ChainStatus string     `json:"chain_status" gorm:"size:16;default:pending;not null;index"` // pending, submitted, skipped, failed
+	TxHash      string     `json:"tx_hash" gorm:"size:66;default:''"`
+	SubmittedAt *time.Time `json:"submitted_at"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"index:idx_reputation_rule_hits_user_day,priority:3"`
+}
+
+// ReputationSeason 声誉赛季，结束时冻结排行榜归档到 reputation_season_standings
+type ReputationSeason struct {
+	ID          int64      `json:"id" gorm:"primaryKey"`
+	Name        string     `json:"name" gorm:"size:100;not null"`
+	Status      string     `json:"status" gorm:"size:16;default:active;not null;index"` // active, archived
+	ResetScores bool       `json:"reset_scores" gorm:"default:false;not null"`          // 赛季开始时是否将当前声誉分数清零
+	StartedAt   time.Time  `json:"started_at" gorm:"not null"`
+	EndedAt     *time.Time `json:"ended_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ReputationSeasonStanding 赛季结束时冻结的排行榜记录
+type ReputationSeasonStanding struct {
+	ID        int64     `json:"id" gorm:"primaryKey"`
+	SeasonID  int64     `json:"season_id" gorm:"not null;uniqueIndex:idx_reputation_season_standings_user,priority:1;index:idx_reputation_season_standings_rank,priority:1"`
+	UserID    int64     `json:"user_id" gorm:"not null;uniqueIndex:idx_reputation_season_standings_user,priority:2;index"`
+	Rank      int       `json:"rank" gorm:"not null;index:idx_reputation_season_standings_rank,priority:2"`
+	Score     int64     `json:"score" gorm:"not null"` // 赛季内获得的声誉
+	CreatedAt time.Time `json:"created_at"`
+	User      User      `json:"user" gorm:"foreignKey:UserID"`
+}
+
+// 可授予用户的权限
+const (
+	PermissionReputationManager = "reputation_manager" // 手动调整和同步用户声誉
+	PermissionTagModerator      = "tag_moderator"      // 重命名和合并标签
+	PermissionContentModerator  = "content_moderator"  // 审核需要审核的内容
+	PermissionCommentModerator  = "comment_moderator"  // 隐藏、锁定和置顶评论
+)
+
+// UserPermission 用户权限授予记录，管理员默认拥有全部权限
+type UserPermission struct {
+	ID         int64     `json:"id" gorm:"primaryKey"`
+	UserID     int64     `json:"user_id" gorm:"not null;uniqueIndex:idx_user_permissions_user_permission,priority:1"`
+	Permission string    `json:"permission" gorm:"size:64;not null;uniqueIndex:idx_user_permissions_user_permission,priority:2"`
+	GrantedBy  int64     `json:"granted_by" gorm:"not null"` // 授权的管理员
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ReputationAuditLog 声誉手动变更审计记录，同时用于幂等键去重
+type ReputationAuditLog struct {
+	ID             int64     `json:"id" gorm:"primaryKey"`
+	IdempotencyKey string    `json:"idempotency_key" gorm:"size:128;uniqueIndex;not null"`
+	RequestHash    string    `json:"-" gorm:"size:64;not null"`            // 操作人、动作、目标、数量和原因的哈希，用于识别幂等键被复用于不同请求
+	Action         string    `json:"action" gorm:"size:16;not null;index"` // add, subtract, sync
+	ActorID        int64     `json:"actor_id" gorm:"not null;index"`       // 操作人
+	TargetUserID   int64     `json:"target_user_id" gorm:"not null;index"` // 被调整的用户
+	Amount         int       `json:"amount" gorm:"not null;default:0"`
+	Reason         string    `json:"reason" gorm:"size:255"`
+	ScoreBefore    int       `json:"score_before" gorm:"not null"`
+	ScoreAfter     int       `json:"score_after" gorm:"not null"`
+	Status         string    `json:"status" gorm:"size:16;default:pending;not null"` // pending, succeeded, failed
+	Error          string    `json:"error,omitempty" gorm:"type:text"`
+	TraceID        string    `json:"trace_id" gorm:"size:64;index"`
+	CreatedAt      time.Time `json:"created_at" gorm:"index"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ReputationReconcileRun 数据库与 ReputationVault 声誉对账任务记录
+type ReputationReconcileRun struct {
+	ID           int64      `json:"id" gorm:"primaryKey"`
+	Trigger      string     `json:"trigger" gorm:"size:16;not null"`   // scheduled, manual
+	Authority    string     `json:"authority" gorm:"size:16;not null"` // database, chain
+	DryRun       bool       `json:"dry_run" gorm:"not null"`
+	Status       string     `json:"status" gorm:"size:16;default:running;not null;index"` // running, completed, failed
+	UsersChecked int        `json:"users_checked" gorm:"default:0;not null"`
+	UsersDrifted int        `json:"users_drifted" gorm:"default:0;not null"`
+	TotalDrift   int64      `json:"total_drift" gorm:"default:0;not null"` // 差额绝对值之和
+	MaxDrift     int64      `json:"max_drift" gorm:"default:0;not null"`   // 单个用户最大差额绝对值
+	Corrected    int        `json:"corrected" gorm:"default:0;not null"`
+	Failed       int        `json:"failed" gorm:"default:0;not null"`
+	Error        string     `json:"error,omitempty" gorm:"type:text"`
+	StartedAt    time.Time  `json:"started_at" gorm:"not null;index"`
+	FinishedAt   *time.Time `json:"finished_at"`
+}
+
+// ReputationDrift 对账发现的单个用户声誉差异
+type ReputationDrift struct {
+	ID            int64     `json:"id" gorm:"primaryKey"`
+	RunID         int64     `json:"run_id" gorm:"not null;index"`
+	UserID        int64     `json:"user_id" gorm:"not null;index"`
+	WalletAddress string    `json:"wallet_address" gorm:"size:42;not null"`
+	DBScore       int       `json:"db_score" gorm:"not null"`
+	PendingDelta  int       `json:"pending_delta" gorm:"not null;default:0"` // 尚未批量上链的规则声誉变更
+	ChainScore    int       `json:"chain_score" gorm:"not null"`
+	Drift         int       `json:"drift" gorm:"not null"`                       // 链上分数减去期望的链上分数（数据库分数减去待上链变更）
+	Action        string    `json:"action" gorm:"size:16;not null"`              // dry_run, db_corrected, chain_pushed, failed
+	TxHash        string    `json:"tx_hash,omitempty" gorm:"size:66;default:''"` // 写入链上的交易哈希
+	Error         string    `json:"error,omitempty" gorm:"type:text"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// 成就统计指标
+const (
+	AchievementMetricPostsPublished  = "posts_published"  // 已发布的内容数
+	AchievementMetricLikesReceived   = "likes_received"   // 内容获得他人的点赞数
+	AchievementMetricCommentsCreated = "comments_created" // 发表的评论数
+	AchievementMetricFollowers       = "followers"        // 粉丝数
+	AchievementMetricProposalVotes   = "proposal_votes"   // 参与投票的提案数
+	AchievementMetricProposalsPassed = "proposals_passed" // 通过的提案数
+)
+
+// Achievement 成就定义，用户在某项指标上达到阈值时授予
+type Achievement struct {
+	ID                 int64     `json:"id" gorm:"primaryKey"`
+	Code               string    `json:"code" gorm:"size:64;uniqueIndex;not null"` // 成就标识，如 first_post
+	Name               string    `json:"name" gorm:"size:100;not null"`
+	Description        string    `json:"description" gorm:"size:255"`
+	Metric             string    `json:"metric" gorm:"size:32;not null;index"` // posts_published, likes_received, comments_created, followers, proposal_votes, proposals_passed
+	Threshold          int64     `json:"threshold" gorm:"not null"`
+	ChainAchievementID int64     `json:"chain_achievement_id" gorm:"default:0;not null"` // AchievementNFT 合约中的 achievementId，0 表示不铸造 NFT
+	TokenURI           string    `json:"token_uri" gorm:"type:text"`                     // NFT 元数据链接
+	Enabled            bool      `json:"enabled" gorm:"default:true;not null"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// UserAchievement 用户获得的成就，同一成就每个用户只授予一次
+type UserAchievement struct {
+	ID            int64       `json:"id" gorm:"primaryKey"`
+	UserID        int64       `json:"user_id" gorm:"not null;uniqueIndex:idx_user_achievements_user_achievement,priority:1"`
+	AchievementID int64       `json:"achievement_id" gorm:"not null;uniqueIndex:idx_user_achievements_user_achievement,priority:2;index"`
+	AwardedAt     time.Time   `json:"awarded_at" gorm:"not null"`
+	MintStatus    string      `json:"mint_status" gorm:"size:16;default:none;not null;index"` // none, pending, minted, failed
+	MintTxHash    string      `json:"mint_tx_hash,omitempty" gorm:"size:66;default:''"`
+	MintError     string      `json:"mint_error,omitempty" gorm:"type:text"`
+	MintAttempts  int         `json:"mint_attempts" gorm:"not null;default:0"` // 失败的铸造次数，达到上限后为 failed
+	NextMintAt    *time.Time  `json:"next_mint_at,omitempty" gorm:"index"`     // 失败后下次重试的时间
+	MintedAt      *time.Time  `json:"minted_at"`
+	Achievement   Achievement `json:"achievement" gorm:"foreignKey:AchievementID"`
+}
+
+// CreatorRewardClaim 创作者通过托管钱包领取 InteractionStaking 互动奖励的记录
+type CreatorRewardClaim struct {
+	ID              int64     `json:"id" gorm:"primaryKey"`
+	UserID          int64     `json:"user_id" gorm:"not null;index"`
+	ContentID       int64     `json:"content_id" gorm:"not null;index;uniqueIndex:idx_creator_reward_claims_active,priority:1,where:status <> 'failed'"` // 每个内容的每种互动类型只能有一条进行中或已确认的领取
+	TokenID         int64     `json:"token_id" gorm:"not null"`                                                                                          // 内容 NFT ID
+	InteractionType string    `json:"interaction_type" gorm:"size:16;not null;uniqueIndex:idx_creator_reward_claims_active,priority:2"`                  // like, comment, favorite
+	Amount          string    `json:"amount" gorm:"size:78;not null"`                                                                                    // 领取的 BOND 数量（wei）
+	WalletAddress   string    `json:"wallet_address" gorm:"size:42;not null"`                                                                            // 发起领取的托管钱包
+	TxHash          string    `json:"tx_hash,omitempty" gorm:"size:66;default:''"`
+	Status          string    `json:"status" gorm:"size:16;not null;index"` // pending（已发起，等待回执）, confirmed, failed
+	Error           string    `json:"error,omitempty" gorm:"type:text"`
+	CreatedAt       time.Time `json:"created_at" gorm:"index"`
+}
+
+// ChainIndexCursor 链上事件索引进度，按索引器名称记录已处理到的区块
+type ChainIndexCursor struct {
+	Name      string    `json:"name" gorm:"primaryKey;size:64"`
+	LastBlock int64     `json:"last_block" gorm:"not null;default:0"` // 已处理完成的最高区块
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TreasuryFlow 从 BondlyTreasury 事件索引的资金流入或流出
+type TreasuryFlow struct {
+	ID              int64     `json:"id" gorm:"primaryKey"`
+	TxHash          string    `json:"tx_hash" gorm:"size:66;not null;uniqueIndex:idx_treasury_flows_log,priority:1"`
+	LogIndex        int64     `json:"log_index" gorm:"not null;uniqueIndex:idx_treasury_flows_log,priority:2"`
+	BlockNumber     int64     `json:"block_number" gorm:"not null;index"`
+	BlockTime       time.Time `json:"block_time" gorm:"index"`
+	Event           string    `json:"event" gorm:"size:32;not null"`                         // EthFundsReceived, BondFundsWithdrawn, EthProposalExecuted 等
+	Asset           string    `json:"asset" gorm:"size:8;not null;index"`                    // ETH, BOND
+	Direction       string    `json:"direction" gorm:"size:8;not null;index"`                // in, out
+	Counterparty    string    `json:"counterparty" gorm:"size:42;not null"`                  // 转入方或接收方
+	Amount          string    `json:"amount" gorm:"size:78;not null"`                        // wei
+	ChainProposalID string    `json:"chain_proposal_id,omitempty" gorm:"size:78;default:''"` // 仅提案执行事件，合约中的提案ID
+	ProposalID      *int64    `json:"proposal_id,omitempty" gorm:"index"`                    // 仅提案执行事件，按拨款登记的链上提案ID对应的数据库提案
+	Reason          string    `json:"reason,omitempty" gorm:"type:text"`                     // 仅提取事件
+	Success         bool      `json:"success" gorm:"not null;default:true"`                  // 提案执行失败时资金退回，不计入流出
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// 金库预算类别
+const (
+	TreasuryCategoryDevelopment = "development" // 开发
+	TreasuryCategoryMarketing   = "marketing"   // 市场推广
+	TreasuryCategoryCommunity   = "community"   // 社区活动
+	TreasuryCategoryGrants      = "grants"      // 生态资助
+	TreasuryCategoryOperations  = "operations"  // 运营
+	TreasuryCategorySecurity    = "security"    // 安全审计
+)
+
+// TreasuryDisbursement 治理提案申请的金库拨款，每个提案最多申请一笔
+type TreasuryDisbursement struct {
+	ID              int64      `json:"id" gorm:"primaryKey"`
+	ProposalID      int64      `json:"proposal_id" gorm:"not null;uniqueIndex"`
+	RequesterID     int64      `json:"requester_id" gorm:"not null;index"`
+	Asset           string     `json:"asset" gorm:"size:8;not null"`                           // ETH, BOND
+	Amount          string     `json:"amount" gorm:"size:78;not null"`                         // wei
+	Recipient       string     `json:"recipient" gorm:"size:42;not null"`                      // 收款地址
+	Category        string     `json:"category" gorm:"size:32;not null;index"`                 // 预算类别
+	Purpose         string     `json:"purpose" gorm:"type:text"`                               // 用途说明
+	ChainProposalID *string    `json:"chain_proposal_id,omitempty" gorm:"size:78;uniqueIndex"` // 金库合约中的提案ID，由管理员在提交链上提案后登记
+	Status          string     `json:"status" gorm:"size:16;not null;default:requested;index"` // requested, executed, failed, mismatched；approved、rejected 由提案状态推导
+	TxHash          string     `json:"tx_hash,omitempty" gorm:"size:66;default:''"`            // 链上执行交易
+	ExecutedAt      *time.Time `json:"executed_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	Proposal        Proposal   `json:"proposal" gorm:"foreignKey:ProposalID"`
+}
+
+// PlatformDailyStat 平台每日统计快照，由聚合任务定期刷新当天的数据
+type PlatformDailyStat struct {
+	ID               int64     `json:"-" gorm:"primaryKey"`
+	Date             time.Time `json:"date" gorm:"type:date;not null;uniqueIndex"`
+	TotalUsers       int64     `json:"total_users" gorm:"not null;default:0"`
+	TotalContent     int64     `json:"total_content" gorm:"not null;default:0"`
+	TotalProposals   int64     `json:"total_proposals" gorm:"not null;default:0"`
+	NewUsers         int64     `json:"new_users" gorm:"not null;default:0"`
+	NewContent       int64     `json:"new_content" gorm:"not null;default:0"`
+	NewProposals     int64     `json:"new_proposals" gorm:"not null;default:0"`
+	ActiveStakers    int64     `json:"active_stakers" gorm:"not null;default:0"`             // 在 GeneralStaking 有质押的钱包用户数
+	TotalValueLocked string    `json:"total_value_locked" gorm:"size:78;not null;default:0"` // GeneralStaking 总质押量（wei）
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Tag 内容标签，slug 为规范化后的唯一标识
+type Tag struct {
+	ID           int64     `json:"id" gorm:"primaryKey"`
+	Name         string    `json:"name" gorm:"size:64;not null"`             // 展示名称
+	Slug         string    `json:"slug" gorm:"size:64;not null;uniqueIndex"` // 小写、空白替换为连字符
+	ContentCount int64     `json:"content_count" gorm:"->;-:migration"`      // 查询时统计的内容数量
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ContentTag 内容与标签的关联
+type ContentTag struct {
+	ContentID int64     `json:"content_id" gorm:"primaryKey"`
+	TagID     int64     `json:"tag_id" gorm:"primaryKey;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TagFollow 用户关注的标签
+type TagFollow struct {
+	UserID    int64     `json:"user_id" gorm:"primaryKey"`
+	TagID     int64     `json:"tag_id" gorm:"primaryKey;index"`
+	CreatedAt time.Time `json:"created_at"`
+	Tag       Tag       `json:"tag" gorm:"foreignKey:TagID"`
+}
+
+// ContentRevision 内容修订记录，创建内容及每次修改标题或正文时生成一条
+type ContentRevision struct {
+	ID               int64     `json:"id" gorm:"primaryKey"`
+	ContentID        int64     `json:"content_id" gorm:"not null;uniqueIndex:idx_content_revision"`
+	Revision         int       `json:"revision" gorm:"not null;uniqueIndex:idx_content_revision"` // 内容内从 1 递增的修订号
+	EditorID         int64     `json:"editor_id" gorm:"not null"`
+	Title            string    `json:"title"`
+	Content          string    `json:"content" gorm:"type:text"`
+	Checksum         string    `json:"checksum" gorm:"size:64;not null"` // 标题与正文的 sha256，用于比较修订是否相同
+	MintedIPFSHash   *string   `json:"minted_ipfs_hash"`                 // 该修订被铸造为 NFT 时上传的 IPFS 哈希
+	RestoredFrom     *int      `json:"restored_from"`                    // 由哪个修订恢复而来
+	CreatedAt        time.Time `json:"created_at"`
+	Editor           User      `json:"editor" gorm:"foreignKey:EditorID"`
+	DivergesFromMint bool      `json:"diverges_from_mint" gorm:"-"` // 与已铸造的 IPFS 快照内容不一致
+}
@@ -11,6 +11,7 @@ const (
 	CodeUnauthorized  = 1401
 	CodeForbidden     = 1403
 	CodeNotFound      = 1404
+	CodeConflict      = 1409
 	CodeInternalError = 1500
 	CodeUnknownError  = 1501
 )
@@ -80,6 +81,7 @@ var ErrorCodeToHTTPStatus = map[int]int{
 	CodeUnauthorized:  401, // Unauthorized
 	CodeForbidden:     403, // Forbidden
 	CodeNotFound:      404, // Not Found
+	CodeConflict:      409, // Conflict
 	CodeInternalError: 500, // Internal Server Error
 	CodeUnknownError:  500, // Internal Server Error
 
@@ -177,6 +179,7 @@ const (
 	MsgUnauthorized  = "未授权访问"
 	MsgForbidden     = "禁止访问"
 	MsgNotFound      = "资源不存在"
+	MsgConflict      = "请求冲突"
 	MsgInternalError = "服务器内部错误"
 	MsgUnknownError  = "未知错误"
 
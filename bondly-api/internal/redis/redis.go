@@ -177,6 +177,16 @@ func (r *RedisClient) ZRangeWithScores(ctx context.Context, key string, start, s
 	return r.client.ZRangeWithScores(ctx, key, start, stop).Result()
 }
 
+// ZRevRangeByScoreWithScores 按分数从高到低获取有序集合的成员及分数
+func (r *RedisClient) ZRevRangeByScoreWithScores(ctx context.Context, key string, opt *redis.ZRangeBy) ([]redis.Z, error) {
+	return r.client.ZRevRangeByScoreWithScores(ctx, key, opt).Result()
+}
+
+// ZCard 获取有序集合的成员数量
+func (r *RedisClient) ZCard(ctx context.Context, key string) (int64, error) {
+	return r.client.ZCard(ctx, key).Result()
+}
+
 // ZRem 从有序集合移除成员
 func (r *RedisClient) ZRem(ctx context.Context, key string, members ...interface{}) error {
 	return r.client.ZRem(ctx, key, members...).Err()
@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AchievementRepository struct {
+	db *gorm.DB
+}
+
+func NewAchievementRepository(db *gorm.DB) *AchievementRepository {
+	return &AchievementRepository{db: db}
+}
+
+// EnsureAchievements 插入尚不存在的成就定义，已存在的保持不变
+func (r *AchievementRepository) EnsureAchievements(achievements []models.Achievement) error {
+	if len(achievements) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoNothing: true,
+	}).Create(&achievements).Error
+}
+
+// List 获取成就定义，enabledOnly 为 true 时只返回启用的成就
+func (r *AchievementRepository) List(enabledOnly bool) ([]models.Achievement, error) {
+	var achievements []models.Achievement
+	query := r.db.Model(&models.Achievement{})
+	if enabledOnly {
+		query = query.Where("enabled = ?", true)
+	}
+	err := query.Order("metric ASC, threshold ASC").Find(&achievements).Error
+	return achievements, err
+}
+
+// ListByMetrics 获取指定指标下启用的成就定义
+func (r *AchievementRepository) ListByMetrics(metrics []string) ([]models.Achievement, error) {
+	var achievements []models.Achievement
+	err := r.db.Where("enabled = ? AND metric IN ?", true, metrics).
+		Order("threshold ASC").
+		Find(&achievements).Error
+	return achievements, err
+}
+
+// GetByCode 根据标识获取成就定义
+func (r *AchievementRepository) GetByCode(code string) (*models.Achievement, error) {
+	var achievement models.Achievement
+	err := r.db.Where("code = ?", code).First(&achievement).Error
+	if err != nil {
+		return nil, err
+	}
+	return &achievement, nil
+}
+
+// Create 创建成就定义
+func (r *AchievementRepository) Create(achievement *models.Achievement) error {
+	return r.db.Create(achievement).Error
+}
+
+// Update 更新成就定义
+func (r *AchievementRepository) Update(achievement *models.Achievement) error {
+	return r.db.Save(achievement).Error
+}
+
+// Award 授予用户成就，用户已获得该成就时返回 false
+func (r *AchievementRepository) Award(userAchievement *models.UserAchievement) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(userAchievement)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListByUser 获取用户获得的成就，最近获得的在前
+func (r *AchievementRepository) ListByUser(userID int64) ([]models.UserAchievement, error) {
+	var userAchievements []models.UserAchievement
+	err := r.db.Preload("Achievement").
+		Where("user_id = ?", userID).
+		Order("awarded_at DESC, id DESC").
+		Find(&userAchievements).Error
+	return userAchievements, err
+}
+
+// ListPendingMints 获取等待铸造、已到重试时间且用户已绑定钱包的成就，按应铸造的时间先后排序，
+// 失败退避中的记录不会挤占新授予的成就
+func (r *AchievementRepository) ListPendingMints(now time.Time, limit int) ([]models.UserAchievement, error) {
+	var userAchievements []models.UserAchievement
+	err := r.db.Preload("Achievement").
+		Joins("JOIN users ON users.id = user_achievements.user_id").
+		Where("user_achievements.mint_status = ? AND users.wallet_address IS NOT NULL", "pending").
+		Where("user_achievements.next_mint_at IS NULL OR user_achievements.next_mint_at <= ?", now).
+		Order("COALESCE(user_achievements.next_mint_at, user_achievements.awarded_at) ASC, user_achievements.id ASC").
+		Limit(limit).
+		Find(&userAchievements).Error
+	return userAchievements, err
+}
+
+// MarkMint 更新成就的铸造状态
+func (r *AchievementRepository) MarkMint(id int64, status, txHash, mintError string, mintedAt *time.Time) error {
+	return r.db.Model(&models.UserAchievement{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"mint_status":  status,
+			"mint_tx_hash": txHash,
+			"mint_error":   mintError,
+			"minted_at":    mintedAt,
+		}).Error
+}
+
+// MarkMintAttemptFailed 记录一次失败的铸造：status 为 pending 时在 nextMintAt 重试，为 failed 时不再重试
+func (r *AchievementRepository) MarkMintAttemptFailed(id int64, status string, attempts int, txHash, mintError string, nextMintAt *time.Time) error {
+	return r.db.Model(&models.UserAchievement{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"mint_status":   status,
+			"mint_attempts": attempts,
+			"mint_tx_hash":  txHash,
+			"mint_error":    mintError,
+			"next_mint_at":  nextMintAt,
+		}).Error
+}
+
+// CountMetric 统计用户在指定成就指标上的当前数值
+func (r *AchievementRepository) CountMetric(userID int64, metric string) (int64, error) {
+	var count int64
+	var err error
+	switch metric {
+	case models.AchievementMetricPostsPublished:
+		err = r.db.Model(&models.Content{}).
+			Where("author_id = ? AND status = ?", userID, "published").
+			Count(&count).Error
+	case models.AchievementMetricLikesReceived:
+		// 不计自己给自己的点赞
+		err = r.db.Model(&models.ContentInteraction{}).
+			Joins("JOIN contents ON contents.id = content_interactions.content_id").
+			Where("contents.author_id = ? AND content_interactions.interaction_type = ? AND content_interactions.user_id <> ?", userID, "like", userID).
+			Count(&count).Error
+	case models.AchievementMetricCommentsCreated:
+		err = r.db.Model(&models.Comment{}).
+			Where("author_id = ?", userID).
+			Count(&count).Error
+	case models.AchievementMetricFollowers:
+		err = r.db.Model(&models.UserFollower{}).
+			Where("followed_id = ?", userID).
+			Count(&count).Error
+	case models.AchievementMetricProposalVotes:
+		err = r.db.Model(&models.Vote{}).
+			Where("voter_id = ?", userID).
+			Distinct("proposal_id").
+			Count(&count).Error
+	case models.AchievementMetricProposalsPassed:
+		err = r.db.Model(&models.Proposal{}).
+			Where("proposer_id = ? AND status IN ?", userID, []string{"passed", "executed"}).
+			Count(&count).Error
+	default:
+		return 0, fmt.Errorf("unknown achievement metric: %s", metric)
+	}
+	return count, err
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Postgres advisory lock 的键，跨进程互斥的任务各用一个
+const (
+	// LockReputationChainSync 声誉规则批量上链与声誉对账共用，避免对账在读取链上分数和待上链变更之间插入一次上链
+	LockReputationChainSync int64 = 0x62726570 // "brep"
+	// LockReputationDecay 声誉衰减任务，每次只由一个实例执行
+	LockReputationDecay int64 = 0x62646563 // "bdec"
+	// LockVoteDelegation 投票委托的环路检查和写入，全局委托影响所有主题的委托图，因此所有主题共用一把锁
+	LockVoteDelegation int64 = 0x62646c67 // "bdlg"
+	// LockReputationSeason 开启和结束声誉赛季，归档、清零和创建新赛季在所有实例之间串行执行
+	LockReputationSeason int64 = 0x62736561 // "bsea"
+)
+
+// advisoryUnlockTimeout 释放锁的超时，调用方的 ctx 可能已取消
+const advisoryUnlockTimeout = 5 * time.Second
+
+// AdvisoryLock 持有 Postgres 会话级 advisory lock 的连接，多个 API 实例之间互斥
+type AdvisoryLock struct {
+	conn *sql.Conn
+	key  int64
+}
+
+// TryAdvisoryLock 在独立连接上尝试获取 advisory lock，已被其他会话持有时返回 nil
+func TryAdvisoryLock(ctx context.Context, db *gorm.DB, key int64) (*AdvisoryLock, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	// 会话级锁绑定在连接上，必须在同一连接上加锁和释放
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !acquired {
+		conn.Close()
+		return nil, nil
+	}
+	return &AdvisoryLock{conn: conn, key: key}, nil
+}
+
+// Release 释放锁并归还连接；释放失败时关闭连接，会话结束后锁随之释放
+func (l *AdvisoryLock) Release() error {
+	ctx, cancel := context.WithTimeout(context.Background(), advisoryUnlockTimeout)
+	defer cancel()
+	if _, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key); err != nil {
+		// 返回 ErrBadConn 让连接池丢弃该连接，不把仍持有锁的会话放回连接池
+		_ = l.conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		l.conn.Close()
+		return err
+	}
+	return l.conn.Close()
+}
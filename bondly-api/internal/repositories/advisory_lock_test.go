@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTryAdvisoryLock_AcquireAndRelease(t *testing.T) {
+	db, mock := newMockDB(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_try_advisory_lock($1)")).
+		WithArgs(LockReputationChainSync).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).
+		WithArgs(LockReputationChainSync).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	lock, err := TryAdvisoryLock(context.Background(), db, LockReputationChainSync)
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+	assert.NoError(t, lock.Release())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTryAdvisoryLock_HeldElsewhere(t *testing.T) {
+	db, mock := newMockDB(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_try_advisory_lock($1)")).
+		WithArgs(LockReputationChainSync).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+
+	lock, err := TryAdvisoryLock(context.Background(), db, LockReputationChainSync)
+	require.NoError(t, err)
+	assert.Nil(t, lock, "其他会话持有锁时不阻塞，返回 nil")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,10 +2,32 @@ package repositories
 
 import (
 	"bondly-api/internal/models"
+	"strconv"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// CommentPage 评论树的键集分页条件
+type CommentPage struct {
+	Sort       string    // oldest, newest, top
+	AfterID    int64     // 上一页最后一条评论的ID，为 0 表示第一页
+	AfterTime  time.Time // 上一页最后一条评论的创建时间，oldest/newest 使用
+	AfterLikes int       // 上一页最后一条评论的点赞数，top 使用
+	Limit      int
+}
+
+// commentOrders 各排序方式的排序子句，均以 id 作为次序键保证稳定
+var commentOrders = map[string]string{
+	"oldest": "created_at ASC, id ASC",
+	"newest": "created_at DESC, id DESC",
+	"top":    "likes DESC, id DESC",
+}
+
+// listableComment 列表中保留的评论：已删除且没有回复的评论不再展示
+const listableComment = "NOT (status = 'deleted' AND reply_count = 0)"
+
 type CommentRepository struct {
 	db *gorm.DB
 }
@@ -14,8 +36,27 @@ func NewCommentRepository(db *gorm.DB) *CommentRepository {
 	return &CommentRepository{db: db}
 }
 
+// Create 创建评论并写入物化路径，回复时同时增加父评论的回复数
 func (r *CommentRepository) Create(comment *models.Comment) error {
-	return r.db.Create(comment).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		parentPath := ""
+		if comment.ParentCommentID != nil {
+			var parent models.Comment
+			if err := tx.Select("id", "path", "depth").First(&parent, *comment.ParentCommentID).Error; err != nil {
+				return err
+			}
+			parentPath = parent.Path
+			comment.Depth = parent.Depth + 1
+			if err := tx.Model(&parent).UpdateColumn("reply_count", gorm.Expr("reply_count + 1")).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		comment.Path = parentPath + strconv.FormatInt(comment.ID, 10) + "/"
+		return tx.Model(comment).UpdateColumn("path", comment.Path).Error
+	})
 }
 
 func (r *CommentRepository) GetByID(id int64) (*models.Comment, error) {
@@ -45,24 +86,140 @@ func (r *CommentRepository) List(postID int64, contentID int64, parentCommentID
 		query = query.Where("parent_comment_id IS NULL")
 	}
 
+	query = query.Where(listableComment)
+
 	query.Count(&total)
-	err := query.Preload("Author").Preload("ChildComments").Order("created_at asc").Offset(offset).Limit(limit).Find(&comments).Error
+	err := query.Preload("Author").Preload("ChildComments").Order("pinned desc, created_at asc").Offset(offset).Limit(limit).Find(&comments).Error
 	return comments, total, err
 }
 
-func (r *CommentRepository) Delete(id int64, authorID int64) error {
-	return r.db.Where("id = ? AND author_id = ?", id, authorID).Delete(&models.Comment{}).Error
+// Edit 修改评论内容，并在同一事务中记录编辑前的内容
+func (r *CommentRepository) Edit(id int64, content string, editedAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var comment models.Comment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "content").First(&comment, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.CommentEdit{CommentID: id, Content: comment.Content, EditedAt: editedAt}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&comment).UpdateColumns(map[string]interface{}{
+			"content":    content,
+			"edit_count": gorm.Expr("edit_count + 1"),
+			"edited_at":  editedAt,
+			"updated_at": editedAt,
+		}).Error
+	})
+}
+
+// ListEdits 获取评论的编辑历史，最近的在前
+func (r *CommentRepository) ListEdits(commentID int64) ([]models.CommentEdit, error) {
+	var edits []models.CommentEdit
+	err := r.db.Where("comment_id = ?", commentID).Order("edited_at DESC, id DESC").Find(&edits).Error
+	return edits, err
+}
+
+// SoftDelete 将评论标记为已删除并清空内容和编辑历史，保留节点使回复仍可展示
+func (r *CommentRepository) SoftDelete(id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Comment{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+			"status":     "deleted",
+			"content":    "",
+			"pinned":     false,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Where("comment_id = ?", id).Delete(&models.CommentEdit{}).Error
+	})
+}
+
+// UpdateModeration 更新评论的审核字段（status、pinned、locked）
+func (r *CommentRepository) UpdateModeration(id int64, updates map[string]interface{}) error {
+	updates["updated_at"] = time.Now()
+	return r.db.Model(&models.Comment{}).Where("id = ?", id).UpdateColumns(updates).Error
+}
+
+// HasLocked 判断给定评论中是否有被锁定的
+func (r *CommentRepository) HasLocked(ids []int64) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Comment{}).Where("id IN ? AND locked = ?", ids, true).Count(&count).Error
+	return count > 0, err
+}
+
+// ListPinned 获取内容下置顶的顶层评论，最近发布的在前
+func (r *CommentRepository) ListPinned(postID int64, contentID int64) ([]models.Comment, error) {
+	query := r.db.Model(&models.Comment{}).Where("parent_comment_id IS NULL AND pinned = ?", true)
+	if postID > 0 {
+		query = query.Where("post_id = ?", postID)
+	} else {
+		query = query.Where("content_id = ?", contentID)
+	}
+
+	var comments []models.Comment
+	err := query.Preload("Author").Order("created_at DESC, id DESC").Find(&comments).Error
+	return comments, err
+}
+
+// Like 记录用户点赞并在同一事务中增加点赞数，已点赞时不变；返回最新点赞数
+func (r *CommentRepository) Like(id int64, userID int64) (int, error) {
+	var likes int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var comment models.Comment
+		if err := tx.Select("id").First(&comment, id).Error; err != nil {
+			return err
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.CommentLike{CommentID: id, UserID: userID, CreatedAt: time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			if err := tx.Model(&comment).UpdateColumn("likes", gorm.Expr("likes + 1")).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Comment{}).Where("id = ?", id).Pluck("likes", &likes).Error
+	})
+	return likes, err
 }
 
-func (r *CommentRepository) Like(id int64) error {
-	return r.db.Model(&models.Comment{}).Where("id = ?", id).UpdateColumn("likes", gorm.Expr("likes + 1")).Error
+// Unlike 删除用户点赞并在同一事务中减少点赞数，未点赞时不变；返回最新点赞数
+func (r *CommentRepository) Unlike(id int64, userID int64) (int, error) {
+	var likes int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var comment models.Comment
+		if err := tx.Select("id").First(&comment, id).Error; err != nil {
+			return err
+		}
+		result := tx.Where("comment_id = ? AND user_id = ?", id, userID).Delete(&models.CommentLike{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			if err := tx.Model(&comment).Where("likes > 0").UpdateColumn("likes", gorm.Expr("likes - 1")).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Comment{}).Where("id = ?", id).Pluck("likes", &likes).Error
+	})
+	return likes, err
 }
 
-func (r *CommentRepository) Unlike(id int64) error {
-	return r.db.Model(&models.Comment{}).Where("id = ? AND likes > 0", id).UpdateColumn("likes", gorm.Expr("likes - 1")).Error
+// ListLikedIDs 获取用户点赞过的评论ID
+func (r *CommentRepository) ListLikedIDs(userID int64, commentIDs []int64) (map[int64]bool, error) {
+	var ids []int64
+	err := r.db.Model(&models.CommentLike{}).
+		Where("user_id = ? AND comment_id IN ?", userID, commentIDs).
+		Pluck("comment_id", &ids).Error
+	liked := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		liked[id] = true
+	}
+	return liked, err
 }
 
-// GetCommentCount 获取指定内容的评论数量
+// GetCommentCount 获取指定内容正常展示的评论数量，不含已删除和被隐藏的评论
 func (r *CommentRepository) GetCommentCount(postID int64, contentID int64) (int64, error) {
 	var count int64
 	query := r.db.Model(&models.Comment{})
@@ -73,6 +230,103 @@ func (r *CommentRepository) GetCommentCount(postID int64, contentID int64) (int6
 		query = query.Where("content_id = ?", contentID)
 	}
 
-	err := query.Count(&count).Error
+	err := query.Where("status = ?", "visible").Count(&count).Error
 	return count, err
 }
+
+// ListThreadPage 按排序方式分页获取某一层评论：parentID 为空时为内容下未置顶的顶层评论，否则为该评论的直接回复
+func (r *CommentRepository) ListThreadPage(postID int64, contentID int64, parentID *int64, page CommentPage) ([]models.Comment, error) {
+	query := r.db.Model(&models.Comment{}).Where(listableComment)
+	if parentID != nil {
+		query = query.Where("parent_comment_id = ?", *parentID)
+	} else {
+		if postID > 0 {
+			query = query.Where("post_id = ?", postID)
+		} else {
+			query = query.Where("content_id = ?", contentID)
+		}
+		query = query.Where("parent_comment_id IS NULL AND pinned = ?", false)
+	}
+
+	if page.AfterID > 0 {
+		switch page.Sort {
+		case "newest":
+			query = query.Where("(created_at, id) < (?, ?)", page.AfterTime, page.AfterID)
+		case "top":
+			query = query.Where("(likes, id) < (?, ?)", page.AfterLikes, page.AfterID)
+		default:
+			query = query.Where("(created_at, id) > (?, ?)", page.AfterTime, page.AfterID)
+		}
+	}
+
+	var comments []models.Comment
+	err := query.Preload("Author").Order(commentOrder(page.Sort)).Limit(page.Limit).Find(&comments).Error
+	return comments, err
+}
+
+// ListDescendants 获取若干评论的子树中层级不超过 maxDepth 的回复，每条评论最多取 perParent 条直接回复
+func (r *CommentRepository) ListDescendants(roots []models.Comment, maxDepth int, sort string, perParent int) ([]models.Comment, error) {
+	if len(roots) == 0 {
+		return []models.Comment{}, nil
+	}
+	// 各子树的路径前缀以 OR 连接；切片参数会被展开为行构造器，不能直接用于 LIKE ANY (ARRAY[?])
+	inSubtrees := r.db.Where("path LIKE ?", roots[0].Path+"%")
+	minDepth := roots[0].Depth
+	for _, root := range roots[1:] {
+		inSubtrees = inSubtrees.Or("path LIKE ?", root.Path+"%")
+		if root.Depth < minDepth {
+			minDepth = root.Depth
+		}
+	}
+
+	order := commentOrder(sort)
+	ranked := r.db.Model(&models.Comment{}).
+		Select("comments.*, ROW_NUMBER() OVER (PARTITION BY parent_comment_id ORDER BY "+order+") AS sibling_rank").
+		Where(inSubtrees).
+		Where("depth > ? AND depth <= ?", minDepth, maxDepth).
+		Where(listableComment)
+
+	var comments []models.Comment
+	err := r.db.Table("(?) AS comments", ranked).
+		Where("sibling_rank <= ?", perParent).
+		Preload("Author").Order("depth ASC, " + order).Find(&comments).Error
+	return comments, err
+}
+
+// EnsureTreeIndexes 创建按物化路径前缀查询子树所需的索引
+func (r *CommentRepository) EnsureTreeIndexes() error {
+	return r.db.Exec("CREATE INDEX IF NOT EXISTS idx_comments_path ON comments (path text_pattern_ops)").Error
+}
+
+// BackfillTree 为物化路径上线前的评论补齐路径、层级和回复数
+func (r *CommentRepository) BackfillTree() (int64, error) {
+	result := r.db.Exec(`WITH RECURSIVE tree AS (
+		SELECT id, id::text || '/' AS path, 0 AS depth FROM comments WHERE parent_comment_id IS NULL
+		UNION ALL
+		SELECT c.id, tree.path || c.id::text || '/', tree.depth + 1 FROM comments c JOIN tree ON c.parent_comment_id = tree.id
+	)
+	UPDATE comments SET path = tree.path, depth = tree.depth
+	FROM tree WHERE comments.id = tree.id AND (comments.path IS NULL OR comments.path = '')`)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	err := r.db.Exec(`UPDATE comments SET reply_count = (
+		SELECT COUNT(*) FROM comments replies WHERE replies.parent_comment_id = comments.id
+	)`).Error
+	return result.RowsAffected, err
+}
+
+// commentOrder 排序方式对应的排序子句，未知排序按 oldest 处理
+func commentOrder(sort string) string {
+	if order, ok := commentOrders[sort]; ok {
+		return order
+	}
+	return commentOrders["oldest"]
+}
+
+// GetContentAuthorID 获取评论所属内容的作者ID
+func (r *CommentRepository) GetContentAuthorID(contentID int64) (int64, error) {
+	var content models.Content
+	err := r.db.Select("id", "author_id").First(&content, contentID).Error
+	return content.AuthorID, err
+}
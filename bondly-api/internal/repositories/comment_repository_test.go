@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentRepository_ListDescendants(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewCommentRepository(db)
+
+	// 每个子树一个 LIKE 条件，参数逐个绑定
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM (SELECT comments.*, ROW_NUMBER() OVER (PARTITION BY parent_comment_id ORDER BY created_at ASC, id ASC) AS sibling_rank FROM "comments" WHERE (path LIKE $1 OR path LIKE $2) AND (depth > $3 AND depth <= $4) AND (NOT (status = 'deleted' AND reply_count = 0))) AS comments WHERE sibling_rank <= $5 ORDER BY depth ASC, created_at ASC, id ASC`)).
+		WithArgs("12/%", "15/%", 0, 2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author_id", "parent_comment_id", "path", "depth"}).
+			AddRow(20, 7, 12, "12/20/", 1).
+			AddRow(21, 8, 15, "15/21/", 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE "users"."id" IN ($1,$2)`)).
+		WithArgs(int64(7), int64(8)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "nickname"}).AddRow(7, "alice").AddRow(8, "bob"))
+
+	roots := []models.Comment{{ID: 12, Path: "12/", Depth: 0}, {ID: 15, Path: "15/", Depth: 0}}
+	comments, err := repo.ListDescendants(roots, 2, "oldest", 3)
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	assert.Equal(t, int64(20), comments[0].ID)
+	assert.Equal(t, "alice", comments[0].Author.Nickname)
+	assert.Equal(t, "bob", comments[1].Author.Nickname)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCommentRepository_ListDescendants_NoRoots(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewCommentRepository(db)
+
+	comments, err := repo.ListDescendants(nil, 2, "oldest", 3)
+	require.NoError(t, err)
+	assert.Empty(t, comments)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
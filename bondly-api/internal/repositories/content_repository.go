@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"bondly-api/internal/models"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -36,6 +37,27 @@ func (r *ContentRepository) Update(content *models.Content) error {
 	return r.db.Save(content).Error
 }
 
+// UpdateWithRevision 在同一事务中保存内容并记录修订：revision 非空时追加新修订，否则 mintedHash 非空时将最新修订标记为已铸造
+func (r *ContentRepository) UpdateWithRevision(content *models.Content, revision *models.ContentRevision, mintedHash *string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(content).Error; err != nil {
+			return err
+		}
+		if revision != nil {
+			return createRevision(tx, revision)
+		}
+		if mintedHash == nil {
+			return nil
+		}
+		latest := tx.Model(&models.ContentRevision{}).
+			Select("id").
+			Where("content_id = ?", content.ID).
+			Order("revision DESC").
+			Limit(1)
+		return tx.Model(&models.ContentRevision{}).Where("id = (?)", latest).Update("minted_ipfs_hash", *mintedHash).Error
+	})
+}
+
 // Delete 删除内容
 func (r *ContentRepository) Delete(id int64) error {
 	return r.db.Delete(&models.Content{}, id).Error
@@ -48,6 +70,29 @@ func (r *ContentRepository) List(offset, limit int) ([]models.Content, error) {
 	return contents, err
 }
 
+// ListByIDs 根据ID列表获取内容，按给定ID顺序返回
+func (r *ContentRepository) ListByIDs(ids []int64) ([]models.Content, error) {
+	var contents []models.Content
+	if len(ids) == 0 {
+		return contents, nil
+	}
+	if err := r.db.Preload("Author").Where("id IN ?", ids).Find(&contents).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]models.Content, len(contents))
+	for _, content := range contents {
+		byID[content.ID] = content
+	}
+	ordered := make([]models.Content, 0, len(contents))
+	for _, id := range ids {
+		if content, ok := byID[id]; ok {
+			ordered = append(ordered, content)
+		}
+	}
+	return ordered, nil
+}
+
 // ListWithStatus 根据状态获取内容列表
 func (r *ContentRepository) ListWithStatus(offset, limit int, status string) ([]models.Content, error) {
 	var contents []models.Content
@@ -68,6 +113,13 @@ func (r *ContentRepository) Count() (int64, error) {
 	return count, err
 }
 
+// CountCreatedBefore 获取指定时间之前创建的内容数量
+func (r *ContentRepository) CountCreatedBefore(t time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Content{}).Where("created_at < ?", t).Count(&count).Error
+	return count, err
+}
+
 // CountWithStatus 根据状态获取内容总数
 func (r *ContentRepository) CountWithStatus(status string) (int64, error) {
 	var count int64
@@ -95,6 +147,13 @@ func (r *ContentRepository) CountByAuthorID(authorID int64) (int64, error) {
 	return count, err
 }
 
+// ListMintedByAuthor 获取作者已铸造为 NFT 的内容
+func (r *ContentRepository) ListMintedByAuthor(authorID int64) ([]models.Content, error) {
+	var contents []models.Content
+	err := r.db.Where("author_id = ? AND nft_token_id IS NOT NULL", authorID).Order("created_at DESC").Find(&contents).Error
+	return contents, err
+}
+
 // IncrementViews 增加浏览量
 func (r *ContentRepository) IncrementViews(id int64) error {
 	return r.db.Model(&models.Content{}).Where("id = ?", id).UpdateColumn("views", gorm.Expr("views + 1")).Error
@@ -114,3 +173,148 @@ func (r *ContentRepository) GetInteractionStats(contentID int64) (likes int64, d
 
 	return likes, dislikes, nil
 }
+
+// InteractionCounts 内容的点赞和点踩数量
+type InteractionCounts struct {
+	ContentID int64
+	Likes     int64
+	Dislikes  int64
+}
+
+// ListInteractionStats 一次查询批量统计内容的点赞和点踩数量，没有互动的内容不在结果中
+func (r *ContentRepository) ListInteractionStats(contentIDs []int64) (map[int64]InteractionCounts, error) {
+	result := make(map[int64]InteractionCounts, len(contentIDs))
+	if len(contentIDs) == 0 {
+		return result, nil
+	}
+	var rows []InteractionCounts
+	err := r.db.Model(&models.ContentInteraction{}).
+		Select("content_id, COUNT(*) FILTER (WHERE interaction_type = ?) AS likes, COUNT(*) FILTER (WHERE interaction_type = ?) AS dislikes", "like", "dislike").
+		Where("content_id IN ? AND interaction_type IN ?", contentIDs, []string{"like", "dislike"}).
+		Group("content_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.ContentID] = row
+	}
+	return result, nil
+}
+
+// FeedEntry 信息流条目
+type FeedEntry struct {
+	ID          int64
+	PublishedAt time.Time
+}
+
+// ListFeedEntries 获取作者们已发布的内容，按发布时间倒序；beforeTime 非零时只返回 (beforeTime, beforeID) 之前的内容
+func (r *ContentRepository) ListFeedEntries(authorIDs []int64, beforeTime time.Time, beforeID int64, limit int) ([]FeedEntry, error) {
+	var entries []FeedEntry
+	if len(authorIDs) == 0 {
+		return entries, nil
+	}
+	query := r.db.Model(&models.Content{}).
+		Select("id, published_at").
+		Where("author_id IN ? AND status = ? AND published_at IS NOT NULL", authorIDs, "published")
+	if !beforeTime.IsZero() {
+		query = query.Where("(published_at, id) < (?, ?)", beforeTime, beforeID)
+	}
+	err := query.Order("published_at DESC, id DESC").Limit(limit).Scan(&entries).Error
+	return entries, err
+}
+
+// ListPublishedBetween 获取作者们在 [since, until) 期间发布的内容及作者，最近的在前
+func (r *ContentRepository) ListPublishedBetween(authorIDs []int64, since, until time.Time, limit int) ([]models.Content, error) {
+	var contents []models.Content
+	if len(authorIDs) == 0 {
+		return contents, nil
+	}
+	err := r.db.Preload("Author").
+		Where("author_id IN ? AND status = ? AND published_at >= ? AND published_at < ?", authorIDs, "published", since, until).
+		Order("published_at DESC, id DESC").
+		Limit(limit).
+		Find(&contents).Error
+	return contents, err
+}
+
+// BackfillPublishedAt 为缺少发布时间的已发布内容以创建时间（精确到毫秒）补齐
+func (r *ContentRepository) BackfillPublishedAt() (int64, error) {
+	result := r.db.Model(&models.Content{}).
+		Where("status = ? AND published_at IS NULL", "published").
+		UpdateColumn("published_at", gorm.Expr("date_trunc('milliseconds', created_at)"))
+	return result.RowsAffected, result.Error
+}
+
+// ListDueScheduled 获取到期待发布的定时内容
+func (r *ContentRepository) ListDueScheduled(now time.Time, limit int) ([]models.Content, error) {
+	var contents []models.Content
+	err := r.db.Preload("Author").
+		Where("status = ? AND publish_at <= ?", "scheduled", now).
+		Order("publish_at ASC, id ASC").Limit(limit).Find(&contents).Error
+	return contents, err
+}
+
+// PublishScheduled 将到期的定时内容改为已发布，返回是否更新；内容已被改为其他状态或已被其他实例发布时不更新
+func (r *ContentRepository) PublishScheduled(id int64, now, publishedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.Content{}).
+		Where("id = ? AND status = ? AND publish_at <= ?", id, "scheduled", now).
+		Updates(map[string]interface{}{
+			"status":       "published",
+			"published_at": gorm.Expr("COALESCE(published_at, ?)", publishedAt),
+			"updated_at":   now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListUnrendered 按ID升序获取尚未渲染正文的内容
+func (r *ContentRepository) ListUnrendered(afterID int64, limit int) ([]models.Content, error) {
+	var contents []models.Content
+	err := r.db.Where("id > ? AND (content_html IS NULL OR content_html = '') AND content <> ''", afterID).
+		Order("id ASC").Limit(limit).Find(&contents).Error
+	return contents, err
+}
+
+// SaveRendered 保存内容的渲染结果，不更新修改时间
+func (r *ContentRepository) SaveRendered(content *models.Content) error {
+	return r.db.Model(content).
+		Select("content_html", "excerpt", "word_count", "reading_time", "toc").
+		UpdateColumns(content).Error
+}
+
+// ContentRankingStats 计算排行分数所需的内容统计
+type ContentRankingStats struct {
+	ID          int64
+	Status      string
+	Likes       int64
+	Dislikes    int64
+	Views       int64
+	Comments    int64
+	PublishedAt *time.Time
+}
+
+// rankingStatsSelect 点赞、点踩按互动记录统计，评论数按 comments 表中正常展示的评论统计
+const rankingStatsSelect = `contents.id, contents.status, contents.views, contents.published_at,
+	(SELECT COUNT(*) FROM content_interactions ci WHERE ci.content_id = contents.id AND ci.interaction_type = 'like') AS likes,
+	(SELECT COUNT(*) FROM content_interactions ci WHERE ci.content_id = contents.id AND ci.interaction_type = 'dislike') AS dislikes,
+	(SELECT COUNT(*) FROM comments c WHERE c.content_id = contents.id AND c.status = 'visible') AS comments`
+
+// GetRankingStats 获取单个内容的排行统计
+func (r *ContentRepository) GetRankingStats(id int64) (*ContentRankingStats, error) {
+	var stats ContentRankingStats
+	err := r.db.Model(&models.Content{}).Select(rankingStatsSelect).Where("contents.id = ?", id).Take(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// ListRankingStats 获取 since 之后发布的全部内容的排行统计
+func (r *ContentRepository) ListRankingStats(since time.Time) ([]ContentRankingStats, error) {
+	var stats []ContentRankingStats
+	err := r.db.Model(&models.Content{}).
+		Select(rankingStatsSelect).
+		Where("contents.status = ? AND contents.published_at >= ?", "published", since).
+		Scan(&stats).Error
+	return stats, err
+}
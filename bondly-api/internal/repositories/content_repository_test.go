@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentRepository_ListInteractionStats(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewContentRepository(db)
+
+	// 一次分组查询统计所有内容
+	mock.ExpectQuery(`SELECT content_id, COUNT\(\*\) FILTER \(WHERE interaction_type = \$1\) AS likes, COUNT\(\*\) FILTER \(WHERE interaction_type = \$2\) AS dislikes FROM "content_interactions" WHERE content_id IN \(\$3,\$4,\$5\) AND interaction_type IN \(\$6,\$7\) GROUP BY "content_id"`).
+		WithArgs("like", "dislike", int64(1), int64(2), int64(3), "like", "dislike").
+		WillReturnRows(sqlmock.NewRows([]string{"content_id", "likes", "dislikes"}).
+			AddRow(1, 5, 1).
+			AddRow(3, 0, 2))
+
+	stats, err := repo.ListInteractionStats([]int64{1, 2, 3})
+	require.NoError(t, err)
+	assert.Equal(t, InteractionCounts{ContentID: 1, Likes: 5, Dislikes: 1}, stats[1])
+	assert.Equal(t, InteractionCounts{ContentID: 3, Likes: 0, Dislikes: 2}, stats[3])
+	_, ok := stats[2]
+	assert.False(t, ok)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestContentRepository_ListInteractionStats_Empty(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewContentRepository(db)
+
+	stats, err := repo.ListInteractionStats(nil)
+	require.NoError(t, err)
+	assert.Empty(t, stats)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
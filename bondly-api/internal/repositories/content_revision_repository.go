@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type ContentRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewContentRevisionRepository(db *gorm.DB) *ContentRevisionRepository {
+	return &ContentRevisionRepository{db: db}
+}
+
+// revisionCreateAttempts 并发写入同一修订号时的最大尝试次数
+const revisionCreateAttempts = 3
+
+// Create 创建修订记录，修订号为该内容当前最大修订号加一
+func (r *ContentRevisionRepository) Create(revision *models.ContentRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createRevision(tx, revision)
+	})
+}
+
+// createRevision 在事务中以当前最大修订号加一插入修订；修订号唯一冲突时回滚到保存点后重新读取并重试
+func createRevision(tx *gorm.DB, revision *models.ContentRevision) error {
+	var err error
+	for attempt := 0; attempt < revisionCreateAttempts; attempt++ {
+		err = tx.Transaction(func(sp *gorm.DB) error {
+			var latest int
+			err := sp.Model(&models.ContentRevision{}).
+				Where("content_id = ?", revision.ContentID).
+				Select("COALESCE(MAX(revision), 0)").
+				Scan(&latest).Error
+			if err != nil {
+				return err
+			}
+			revision.Revision = latest + 1
+			return sp.Create(revision).Error
+		})
+		if err == nil || !isUniqueViolation(err) {
+			return err
+		}
+		revision.ID = 0
+	}
+	return err
+}
+
+// isUniqueViolation 判断是否为 Postgres 唯一约束冲突
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// CountByContent 获取内容的修订数量
+func (r *ContentRevisionRepository) CountByContent(contentID int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ContentRevision{}).Where("content_id = ?", contentID).Count(&count).Error
+	return count, err
+}
+
+// ListByContent 分页获取内容的修订记录，最新的在前
+func (r *ContentRevisionRepository) ListByContent(contentID int64, offset, limit int) ([]models.ContentRevision, error) {
+	var revisions []models.ContentRevision
+	err := r.db.Preload("Editor").
+		Where("content_id = ?", contentID).
+		Order("revision DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&revisions).Error
+	return revisions, err
+}
+
+// GetByRevision 根据修订号获取修订记录
+func (r *ContentRevisionRepository) GetByRevision(contentID int64, revision int) (*models.ContentRevision, error) {
+	var rev models.ContentRevision
+	err := r.db.Preload("Editor").Where("content_id = ? AND revision = ?", contentID, revision).First(&rev).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// GetMinted 获取以给定 IPFS 哈希铸造的最新修订
+func (r *ContentRevisionRepository) GetMinted(contentID int64, ipfsHash string) (*models.ContentRevision, error) {
+	var rev models.ContentRevision
+	err := r.db.Where("content_id = ? AND minted_ipfs_hash = ?", contentID, ipfsHash).Order("revision DESC").First(&rev).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
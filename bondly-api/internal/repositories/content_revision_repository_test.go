@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expectRevisionInsert 期望在保存点内读取最大修订号并插入修订
+func expectRevisionInsert(mock sqlmock.Sqlmock, contentID int64, latest int, insertErr error) {
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(revision\), 0\) FROM "content_revisions" WHERE content_id = \$1`).
+		WithArgs(contentID).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(latest))
+	insert := mock.ExpectQuery(`INSERT INTO "content_revisions"`)
+	if insertErr != nil {
+		insert.WillReturnError(insertErr)
+		mock.ExpectExec(`ROLLBACK TO SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+		return
+	}
+	insert.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+}
+
+func TestContentRevisionRepository_CreateRetriesOnConflict(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewContentRevisionRepository(db)
+
+	mock.ExpectBegin()
+	// 并发写入抢先占用了修订号 3，重新读取后写入 4
+	expectRevisionInsert(mock, 7, 2, &pgconn.PgError{Code: "23505"})
+	expectRevisionInsert(mock, 7, 3, nil)
+	mock.ExpectCommit()
+
+	revision := &models.ContentRevision{ContentID: 7, EditorID: 1, Title: "t", Content: "c"}
+	require.NoError(t, repo.Create(revision))
+	assert.Equal(t, 4, revision.Revision)
+	assert.Equal(t, int64(10), revision.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestContentRepository_UpdateWithRevision(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewContentRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "contents" SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectRevisionInsert(mock, 7, 1, nil)
+	mock.ExpectCommit()
+
+	content := &models.Content{ID: 7, Title: "t", Content: "c", Status: "draft"}
+	revision := &models.ContentRevision{ContentID: 7, EditorID: 1, Title: "t", Content: "c"}
+	require.NoError(t, repo.UpdateWithRevision(content, revision, nil))
+	assert.Equal(t, 2, revision.Revision)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestContentRepository_UpdateWithRevision_RollsBackOnRevisionError(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewContentRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "contents" SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectRevisionInsert(mock, 7, 1, &pgconn.PgError{Code: "23503"})
+	// 非唯一冲突不重试，内容修改随事务回滚
+	mock.ExpectRollback()
+
+	content := &models.Content{ID: 7, Title: "t", Content: "c", Status: "draft"}
+	revision := &models.ContentRevision{ContentID: 7, EditorID: 1, Title: "t", Content: "c"}
+	assert.Error(t, repo.UpdateWithRevision(content, revision, nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
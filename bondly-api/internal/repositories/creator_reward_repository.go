@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CreatorRewardRepository struct {
+	db *gorm.DB
+}
+
+func NewCreatorRewardRepository(db *gorm.DB) *CreatorRewardRepository {
+	return &CreatorRewardRepository{db: db}
+}
+
+// CreateClaim 创建奖励领取记录，该内容的该互动类型已有进行中或已确认的领取时返回 false
+func (r *CreatorRewardRepository) CreateClaim(claim *models.CreatorRewardClaim) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(claim)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetActiveClaim 获取内容某互动类型进行中或已确认的领取记录
+func (r *CreatorRewardRepository) GetActiveClaim(contentID int64, interactionType string) (*models.CreatorRewardClaim, error) {
+	var claim models.CreatorRewardClaim
+	err := r.db.Where("content_id = ? AND interaction_type = ? AND status <> ?", contentID, interactionType, "failed").
+		First(&claim).Error
+	if err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+// UpdateClaim 更新领取记录的状态、交易哈希和错误
+func (r *CreatorRewardRepository) UpdateClaim(claim *models.CreatorRewardClaim) error {
+	return r.db.Model(claim).Updates(map[string]interface{}{
+		"status":  claim.Status,
+		"tx_hash": claim.TxHash,
+		"error":   claim.Error,
+	}).Error
+}
+
+// ListClaimsByUser 获取用户的奖励领取记录，最新的在前
+func (r *CreatorRewardRepository) ListClaimsByUser(userID int64, offset, limit int) ([]models.CreatorRewardClaim, error) {
+	var claims []models.CreatorRewardClaim
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&claims).Error
+	return claims, err
+}
+
+// CountClaimsByUser 获取用户的奖励领取记录数量
+func (r *CreatorRewardRepository) CountClaimsByUser(userID int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.CreatorRewardClaim{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// ListClaimedAmounts 获取用户已在链上确认的领取金额（wei）
+func (r *CreatorRewardRepository) ListClaimedAmounts(userID int64) ([]string, error) {
+	var amounts []string
+	err := r.db.Model(&models.CreatorRewardClaim{}).
+		Where("user_id = ? AND status = ?", userID, "confirmed").
+		Pluck("amount", &amounts).Error
+	return amounts, err
+}
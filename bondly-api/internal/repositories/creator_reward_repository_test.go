@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatorRewardRepository_CreateClaimConflict(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewCreatorRewardRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "creator_reward_claims"`) + `.*ON CONFLICT DO NOTHING`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	claim := &models.CreatorRewardClaim{UserID: 1, ContentID: 2, TokenID: 3, InteractionType: "like", Amount: "10", WalletAddress: "0xabc", Status: "pending", CreatedAt: time.Now()}
+	created, err := repo.CreateClaim(claim)
+	require.NoError(t, err)
+	assert.False(t, created, "已有进行中的领取时不创建")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DelegationRepository struct {
+	db *gorm.DB
+}
+
+func NewDelegationRepository(db *gorm.DB) *DelegationRepository {
+	return &DelegationRepository{
+		db: db,
+	}
+}
+
+// WithDelegationLock 在事务中获取投票委托的事务级 advisory lock 后执行 fn，fn 收到的仓库使用同一事务
+// 环路检查、撤销旧委托和创建新委托因此在所有实例之间串行执行
+func (r *DelegationRepository) WithDelegationLock(fn func(tx *DelegationRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", LockVoteDelegation).Error; err != nil {
+			return err
+		}
+		return fn(&DelegationRepository{db: tx})
+	})
+}
+
+// EnsureActiveIndex 撤销同一委托人在同一主题下重复的有效委托（保留最新一条）后创建唯一索引，每个主题只能有一条有效委托
+func (r *DelegationRepository) EnsureActiveIndex() (int64, error) {
+	result := r.db.Exec(`UPDATE vote_delegations d SET revoked_at = later.created_at
+		FROM vote_delegations later
+		WHERE d.revoked_at IS NULL AND later.revoked_at IS NULL
+		AND d.delegator_id = later.delegator_id AND d.topic = later.topic
+		AND d.id < later.id`)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	err := r.db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_vote_delegations_active
+		ON vote_delegations (delegator_id, topic) WHERE revoked_at IS NULL`).Error
+	return result.RowsAffected, err
+}
+
+// Create 创建委托记录
+func (r *DelegationRepository) Create(delegation *models.VoteDelegation) error {
+	return r.db.Create(delegation).Error
+}
+
+// GetActive 获取委托人在指定主题下的有效委托
+func (r *DelegationRepository) GetActive(delegatorID int64, topic string) (*models.VoteDelegation, error) {
+	var delegation models.VoteDelegation
+	err := r.db.Preload("Delegate").
+		Where("delegator_id = ? AND topic = ? AND revoked_at IS NULL", delegatorID, topic).
+		First(&delegation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delegation, nil
+}
+
+// Revoke 撤销委托人在指定主题下的有效委托
+func (r *DelegationRepository) Revoke(delegatorID int64, topic string, revokedAt time.Time) (int64, error) {
+	result := r.db.Model(&models.VoteDelegation{}).
+		Where("delegator_id = ? AND topic = ? AND revoked_at IS NULL", delegatorID, topic).
+		Update("revoked_at", revokedAt)
+	return result.RowsAffected, result.Error
+}
+
+// ListActiveAt 获取指定时间点有效的委托（包含全局委托和指定主题的委托）
+func (r *DelegationRepository) ListActiveAt(topic string, at time.Time) ([]models.VoteDelegation, error) {
+	var delegations []models.VoteDelegation
+	query := r.db.Where("created_at <= ? AND (revoked_at IS NULL OR revoked_at > ?)", at, at)
+	if topic != "" {
+		query = query.Where("topic IN ?", []string{"", topic})
+	} else {
+		query = query.Where("topic = ?", "")
+	}
+	err := query.Order("id ASC").Find(&delegations).Error
+	return delegations, err
+}
+
+// ListActive 获取当前所有有效的委托
+func (r *DelegationRepository) ListActive() ([]models.VoteDelegation, error) {
+	var delegations []models.VoteDelegation
+	err := r.db.Where("revoked_at IS NULL").Order("id ASC").Find(&delegations).Error
+	return delegations, err
+}
+
+// ListByDelegator 获取用户发出的有效委托
+func (r *DelegationRepository) ListByDelegator(delegatorID int64) ([]models.VoteDelegation, error) {
+	var delegations []models.VoteDelegation
+	err := r.db.Preload("Delegate").Where("delegator_id = ? AND revoked_at IS NULL", delegatorID).Order("created_at DESC").Find(&delegations).Error
+	return delegations, err
+}
+
+// ListByDelegate 获取用户收到的有效委托
+func (r *DelegationRepository) ListByDelegate(delegateID int64) ([]models.VoteDelegation, error) {
+	var delegations []models.VoteDelegation
+	err := r.db.Preload("Delegator").Where("delegate_id = ? AND revoked_at IS NULL", delegateID).Order("created_at DESC").Find(&delegations).Error
+	return delegations, err
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelegationRepository_WithDelegationLock(t *testing.T) {
+	t.Run("加锁后在同一事务中执行", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewDelegationRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1)")).
+			WithArgs(LockVoteDelegation).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "vote_delegations" SET "revoked_at"=$1,"updated_at"=$2 WHERE delegator_id = $3 AND topic = $4 AND revoked_at IS NULL`)).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), int64(1), "").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.WithDelegationLock(func(tx *DelegationRepository) error {
+			_, err := tx.Revoke(1, "", time.Now())
+			return err
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("出错时回滚", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewDelegationRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1)")).
+			WithArgs(LockVoteDelegation).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.WithDelegationLock(func(tx *DelegationRepository) error {
+			return errors.New("delegation cycle detected")
+		})
+		assert.EqualError(t, err, "delegation cycle detected")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
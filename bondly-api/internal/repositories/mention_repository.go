@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MentionRepository struct {
+	db *gorm.DB
+}
+
+func NewMentionRepository(db *gorm.DB) *MentionRepository {
+	return &MentionRepository{db: db}
+}
+
+// CreateBatch 批量记录提及，同一来源对同一用户已记录时跳过
+func (r *MentionRepository) CreateBatch(mentions []models.Mention) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&mentions).Error
+}
+
+// ListMentionedUserIDs 获取某个来源已记录提及的用户ID
+func (r *MentionRepository) ListMentionedUserIDs(sourceType string, sourceID int64) ([]int64, error) {
+	var ids []int64
+	err := r.db.Model(&models.Mention{}).
+		Where("source_type = ? AND source_id = ?", sourceType, sourceID).
+		Pluck("mentioned_user_id", &ids).Error
+	return ids, err
+}
+
+// ListByUser 分页获取提及某用户的记录，最近的在前；与提及者之间存在任一方向屏蔽的记录不返回，屏蔽解除后恢复展示
+func (r *MentionRepository) ListByUser(userID int64, offset, limit int) ([]models.Mention, int64, error) {
+	var mentions []models.Mention
+	var total int64
+	query := r.db.Model(&models.Mention{}).
+		Where("mentioned_user_id = ?", userID).
+		Where(`NOT EXISTS (SELECT 1 FROM user_blocks ub WHERE (ub.blocker_id = mentions.mentioned_user_id AND ub.blocked_id = mentions.author_id) OR (ub.blocker_id = mentions.author_id AND ub.blocked_id = mentions.mentioned_user_id))`)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Preload("Author").Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&mentions).Error
+	return mentions, total, err
+}
@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMentionRepository_ListByUserExcludesBlocked(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewMentionRepository(db)
+
+	// 计数与分页查询都排除任一方向屏蔽的提及者
+	blocked := `NOT EXISTS (SELECT 1 FROM user_blocks ub WHERE (ub.blocker_id = mentions.mentioned_user_id AND ub.blocked_id = mentions.author_id) OR (ub.blocker_id = mentions.author_id AND ub.blocked_id = mentions.mentioned_user_id))`
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "mentions" WHERE mentioned_user_id = $1 AND (` + blocked + `)`)).
+		WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "mentions" WHERE mentioned_user_id = $1 AND (` + blocked + `) ORDER BY created_at DESC, id DESC LIMIT 20`)).
+		WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "mentioned_user_id", "author_id"}).AddRow(1, 3, 5))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE "users"."id" = $1`)).
+		WithArgs(int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "nickname"}).AddRow(5, "alice"))
+
+	mentions, total, err := repo.ListByUser(3, 0, 20)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, mentions, 1)
+	assert.Equal(t, "alice", mentions[0].Author.Nickname)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DigestRecipient 需要发送邮件摘要的用户
+type DigestRecipient struct {
+	UserID       int64
+	Email        string
+	Nickname     string
+	LastDigestAt *time.Time
+}
+
+type NotificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationPreferenceRepository(db *gorm.DB) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{db: db}
+}
+
+// ListByUser 获取用户设置过的通知偏好
+func (r *NotificationPreferenceRepository) ListByUser(userID int64) ([]models.NotificationPreference, error) {
+	var preferences []models.NotificationPreference
+	err := r.db.Where("user_id = ?", userID).Find(&preferences).Error
+	return preferences, err
+}
+
+// ListByType 获取多个用户对某类通知设置过的偏好
+func (r *NotificationPreferenceRepository) ListByType(userIDs []int64, notificationType string) ([]models.NotificationPreference, error) {
+	var preferences []models.NotificationPreference
+	if len(userIDs) == 0 {
+		return preferences, nil
+	}
+	err := r.db.Where("user_id IN ? AND type = ?", userIDs, notificationType).Find(&preferences).Error
+	return preferences, err
+}
+
+// Upsert 批量保存通知偏好
+func (r *NotificationPreferenceRepository) Upsert(preferences []models.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"channel", "updated_at"}),
+	}).Create(&preferences).Error
+}
+
+// GetSetting 获取用户的邮件摘要设置
+func (r *NotificationPreferenceRepository) GetSetting(userID int64) (*models.NotificationSetting, error) {
+	var setting models.NotificationSetting
+	err := r.db.Where("user_id = ?", userID).First(&setting).Error
+	if err != nil {
+		return nil, err
+	}
+	return &setting, nil
+}
+
+// SetDigestFrequency 设置用户的邮件摘要频率
+func (r *NotificationPreferenceRepository) SetDigestFrequency(userID int64, frequency string, now time.Time) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"digest_frequency", "updated_at"}),
+	}).Create(&models.NotificationSetting{UserID: userID, DigestFrequency: frequency, UpdatedAt: now}).Error
+}
+
+// ClaimDigest 在发送前把上次发送时间设为 at，只有上次发送仍不晚于 sentBefore 时才更新，没有设置记录时以当前频率创建
+// 返回是否由本次调用认领，多个实例同时检查时每期摘要只有一个实例发送
+func (r *NotificationPreferenceRepository) ClaimDigest(userID int64, frequency string, sentBefore, at time.Time) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_digest_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "notification_settings.last_digest_at IS NULL OR notification_settings.last_digest_at <= ?", Vars: []interface{}{sentBefore}},
+		}},
+	}).Create(&models.NotificationSetting{UserID: userID, DigestFrequency: frequency, LastDigestAt: &at, UpdatedAt: at})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReleaseDigest 发送失败时把上次发送时间恢复为 previous，仍为本次认领的 at 时才恢复，下次检查时重试
+func (r *NotificationPreferenceRepository) ReleaseDigest(userID int64, previous *time.Time, at time.Time) error {
+	return r.db.Model(&models.NotificationSetting{}).
+		Where("user_id = ? AND last_digest_at = ?", userID, at).
+		Update("last_digest_at", previous).Error
+}
+
+// ListDigestRecipients 按用户ID顺序获取有邮箱、摘要频率为 frequency 且上次发送不晚于 sentBefore 的用户；没有设置记录的用户按 defaultFrequency 处理
+func (r *NotificationPreferenceRepository) ListDigestRecipients(frequency, defaultFrequency string, sentBefore time.Time, afterUserID int64, limit int) ([]DigestRecipient, error) {
+	var recipients []DigestRecipient
+	err := r.db.Table("users").
+		Select("users.id AS user_id, users.email, users.nickname, notification_settings.last_digest_at").
+		Joins("LEFT JOIN notification_settings ON notification_settings.user_id = users.id").
+		Where("users.email IS NOT NULL AND users.email <> ''").
+		Where("COALESCE(notification_settings.digest_frequency, ?) = ?", defaultFrequency, frequency).
+		Where("notification_settings.last_digest_at IS NULL OR notification_settings.last_digest_at <= ?", sentBefore).
+		Where("users.id > ?", afterUserID).
+		Order("users.id").
+		Limit(limit).
+		Scan(&recipients).Error
+	return recipients, err
+}
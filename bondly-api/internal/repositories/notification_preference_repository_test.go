@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationPreferenceRepository_ClaimDigest(t *testing.T) {
+	sentBefore := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := sentBefore.Add(24 * time.Hour)
+	claim := regexp.QuoteMeta(`INSERT INTO "notification_settings"`) +
+		`.*ON CONFLICT \("user_id"\) DO UPDATE SET "last_digest_at"="excluded"."last_digest_at" ` +
+		regexp.QuoteMeta(`WHERE notification_settings.last_digest_at IS NULL OR notification_settings.last_digest_at <= $5`)
+
+	t.Run("上次发送已到期时认领", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewNotificationPreferenceRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(claim).
+			WithArgs("daily", at, at, int64(7), sentBefore).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(7))
+		mock.ExpectCommit()
+
+		claimed, err := repo.ClaimDigest(7, "daily", sentBefore, at)
+		require.NoError(t, err)
+		assert.True(t, claimed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("已被其他实例认领时不重复发送", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewNotificationPreferenceRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(claim).
+			WithArgs("daily", at, at, int64(7), sentBefore).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+		mock.ExpectCommit()
+
+		claimed, err := repo.ClaimDigest(7, "daily", sentBefore, at)
+		require.NoError(t, err)
+		assert.False(t, claimed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
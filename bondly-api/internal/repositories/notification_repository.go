@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// notificationBatchSize 批量创建通知时每批写入的条数
+const notificationBatchSize = 500
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// CreateBatch 批量创建通知
+func (r *NotificationRepository) CreateBatch(notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(&notifications, notificationBatchSize).Error
+}
+
+// CreateOnce 创建通知，同一触发者对同一对象已有同类通知时跳过（由 EnsureDedupIndex 创建的唯一索引保证），返回是否创建
+func (r *NotificationRepository) CreateOnce(notification *models.Notification) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(notification)
+	return result.RowsAffected > 0, result.Error
+}
+
+// EnsureDedupIndex 删除重复的点赞通知（保留最早一条）后创建唯一索引，取消点赞再点赞不会重复通知
+func (r *NotificationRepository) EnsureDedupIndex() (int64, error) {
+	result := r.db.Exec(`DELETE FROM notifications n USING notifications earlier
+		WHERE n.type = 'like' AND earlier.type = 'like'
+		AND n.user_id = earlier.user_id AND n.actor_id = earlier.actor_id
+		AND n.subject_type = earlier.subject_type AND n.subject_id = earlier.subject_id
+		AND n.id > earlier.id`)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	err := r.db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_like_once
+		ON notifications (user_id, actor_id, subject_type, subject_id) WHERE type = 'like'`).Error
+	return result.RowsAffected, err
+}
+
+// GetByID 获取用户自己的通知
+func (r *NotificationRepository) GetByID(userID, id int64) (*models.Notification, error) {
+	var notification models.Notification
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&notification).Error
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// List 分页获取用户的通知，最近的在前；unreadOnly 为 true 时只返回未读通知
+func (r *NotificationRepository) List(userID int64, unreadOnly bool, offset, limit int) ([]models.Notification, int64, error) {
+	var notifications []models.Notification
+	var total int64
+	query := r.db.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&notifications).Error
+	return notifications, total, err
+}
+
+// CountUnread 统计用户的未读通知数
+func (r *NotificationRepository) CountUnread(userID int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+// MarkRead 将用户的一条未读通知标记为已读，返回实际更新的条数
+func (r *NotificationRepository) MarkRead(userID, id int64, readAt time.Time) (int64, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", id, userID).
+		UpdateColumn("read_at", readAt)
+	return result.RowsAffected, result.Error
+}
+
+// MarkAllRead 将用户的全部未读通知标记为已读，返回实际更新的条数
+func (r *NotificationRepository) MarkAllRead(userID int64, readAt time.Time) (int64, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		UpdateColumn("read_at", readAt)
+	return result.RowsAffected, result.Error
+}
+
+// ListBetween 获取用户在 [since, until) 期间收到的指定类型通知，最近的在前，同时返回总数
+func (r *NotificationRepository) ListBetween(userID int64, types []string, since, until time.Time, limit int) ([]models.Notification, int64, error) {
+	var notifications []models.Notification
+	var total int64
+	if len(types) == 0 {
+		return notifications, 0, nil
+	}
+	query := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND type IN ? AND created_at >= ? AND created_at < ?", userID, types, since, until)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&notifications).Error
+	return notifications, total, err
+}
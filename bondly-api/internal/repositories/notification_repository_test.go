@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationRepository_CreateOnce(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewNotificationRepository(db)
+	notification := &models.Notification{UserID: 1, Type: "like", ActorID: 2, SubjectType: "content", SubjectID: 3, CreatedAt: time.Now()}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "notifications"`) + `.*ON CONFLICT DO NOTHING RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+	mock.ExpectCommit()
+
+	created, err := repo.CreateOnce(notification)
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	// 唯一索引冲突时不插入任何行
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "notifications"`) + `.*ON CONFLICT DO NOTHING RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	duplicate := *notification
+	duplicate.ID = 0
+	created, err = repo.CreateOnce(&duplicate)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PlatformStatsRepository struct {
+	db *gorm.DB
+}
+
+func NewPlatformStatsRepository(db *gorm.DB) *PlatformStatsRepository {
+	return &PlatformStatsRepository{db: db}
+}
+
+// UpsertDaily 写入某日的统计快照，当天已有记录时覆盖
+func (r *PlatformStatsRepository) UpsertDaily(stat *models.PlatformDailyStat) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"total_users", "total_content", "total_proposals",
+			"new_users", "new_content", "new_proposals",
+			"active_stakers", "total_value_locked", "updated_at",
+		}),
+	}).Create(stat).Error
+}
+
+// GetLatest 获取最近一天的统计快照，没有记录时返回 nil
+func (r *PlatformStatsRepository) GetLatest() (*models.PlatformDailyStat, error) {
+	var stat models.PlatformDailyStat
+	err := r.db.Order("date DESC").First(&stat).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stat, nil
+}
+
+// GetLatestBefore 获取指定日期之前最近一天的统计快照，没有记录时返回 nil
+func (r *PlatformStatsRepository) GetLatestBefore(date time.Time) (*models.PlatformDailyStat, error) {
+	var stat models.PlatformDailyStat
+	err := r.db.Where("date < ?", date).Order("date DESC").First(&stat).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stat, nil
+}
+
+// ListDaily 获取日期范围内（含两端）的统计快照，按日期升序
+func (r *PlatformStatsRepository) ListDaily(from, to time.Time) ([]models.PlatformDailyStat, error) {
+	var stats []models.PlatformDailyStat
+	err := r.db.Where("date >= ? AND date <= ?", from, to).
+		Order("date ASC").
+		Find(&stats).Error
+	return stats, err
+}
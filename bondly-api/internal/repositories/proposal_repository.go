@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"bondly-api/internal/models"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -55,6 +56,13 @@ func (r *ProposalRepository) Count() (int64, error) {
 	return count, err
 }
 
+// CountCreatedBefore 获取指定时间之前创建的提案数量
+func (r *ProposalRepository) CountCreatedBefore(t time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Proposal{}).Where("created_at < ?", t).Count(&count).Error
+	return count, err
+}
+
 // GetByProposerID 根据提案人ID获取提案列表
 func (r *ProposalRepository) GetByProposerID(proposerID int64, offset, limit int) ([]models.Proposal, error) {
 	var proposals []models.Proposal
@@ -83,8 +91,27 @@ func (r *ProposalRepository) CountByStatus(status string) (int64, error) {
 	return count, err
 }
 
+// ListTallyDue 获取使用指定投票策略、已开始且需要重新计票的提案：仍在投票期内，或结束后尚未按结束时间计票
+func (r *ProposalRepository) ListTallyDue(strategy string, now time.Time) ([]models.Proposal, error) {
+	var proposals []models.Proposal
+	err := r.db.Where("voting_strategy = ? AND start_time <= ?", strategy, now).
+		Where("end_time >= ? OR updated_at < end_time", now).
+		Order("id ASC").
+		Find(&proposals).Error
+	return proposals, err
+}
+
+// ListEndedActive 获取已到结束时间但仍为 active 的提案
+func (r *ProposalRepository) ListEndedActive(now time.Time) ([]models.Proposal, error) {
+	var proposals []models.Proposal
+	err := r.db.Where("status = ? AND end_time <= ?", "active", now).
+		Order("end_time ASC").
+		Find(&proposals).Error
+	return proposals, err
+}
+
 // UpdateVotes 更新投票数
-func (r *ProposalRepository) UpdateVotes(id int64, votesFor, votesAgainst int) error {
+func (r *ProposalRepository) UpdateVotes(id int64, votesFor, votesAgainst int64) error {
 	return r.db.Model(&models.Proposal{}).Where("id = ?", id).Updates(map[string]interface{}{
 		"votes_for":     votesFor,
 		"votes_against": votesAgainst,
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReputationAuditFilter 审计记录过滤条件，零值表示不过滤
+type ReputationAuditFilter struct {
+	ActorID      int64
+	TargetUserID int64
+	Action       string
+}
+
+type ReputationAuditRepository struct {
+	db *gorm.DB
+}
+
+func NewReputationAuditRepository(db *gorm.DB) *ReputationAuditRepository {
+	return &ReputationAuditRepository{db: db}
+}
+
+// Create 创建审计记录，幂等键已存在时返回 false
+func (r *ReputationAuditRepository) Create(log *models.ReputationAuditLog) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "idempotency_key"}},
+		DoNothing: true,
+	}).Create(log)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetByIdempotencyKey 根据幂等键获取审计记录
+func (r *ReputationAuditRepository) GetByIdempotencyKey(key string) (*models.ReputationAuditLog, error) {
+	var log models.ReputationAuditLog
+	err := r.db.Where("idempotency_key = ?", key).First(&log).Error
+	if err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+// Complete 更新审计记录的执行结果
+func (r *ReputationAuditRepository) Complete(log *models.ReputationAuditLog) error {
+	return r.db.Model(log).Updates(map[string]interface{}{
+		"score_before": log.ScoreBefore,
+		"score_after":  log.ScoreAfter,
+		"status":       log.Status,
+		"error":        log.Error,
+	}).Error
+}
+
+// List 分页获取审计记录，最新的在前
+func (r *ReputationAuditRepository) List(filter ReputationAuditFilter, offset, limit int) ([]models.ReputationAuditLog, error) {
+	var logs []models.ReputationAuditLog
+	err := r.filtered(filter).
+		Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&logs).Error
+	return logs, err
+}
+
+// Count 统计审计记录数量
+func (r *ReputationAuditRepository) Count(filter ReputationAuditFilter) (int64, error) {
+	var count int64
+	err := r.filtered(filter).Count(&count).Error
+	return count, err
+}
+
+// filtered 构建过滤查询
+func (r *ReputationAuditRepository) filtered(filter ReputationAuditFilter) *gorm.DB {
+	query := r.db.Model(&models.ReputationAuditLog{})
+	if filter.ActorID > 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.TargetUserID > 0 {
+		query = query.Where("target_user_id = ?", filter.TargetUserID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	return query
+}
@@ -0,0 +1,273 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReputationReasonAggregate 按原因汇总的声誉变更
+type ReputationReasonAggregate struct {
+	Reason     string `json:"reason"`
+	Count      int64  `json:"count"`
+	TotalDelta int64  `json:"total_delta"`
+}
+
+type ReputationEventRepository struct {
+	db *gorm.DB
+}
+
+func NewReputationEventRepository(db *gorm.DB) *ReputationEventRepository {
+	return &ReputationEventRepository{db: db}
+}
+
+// Append 追加声誉事件并在同一事务中更新用户声誉分数投影
+// 扣减超过当前分数时按实际可扣减值记账，保证投影等于事件 delta 之和且不小于 0
+func (r *ReputationEventRepository) Append(event *models.ReputationEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return appendEvent(tx, event)
+	})
+}
+
+// AppendDecay 在同一事务中追加衰减事件并把衰减起点从 decayedAt 移到 at
+// 起点已被其他实例或上一次未完成的执行移动时不记账，返回 false，同一时间段只衰减一次
+func (r *ReputationEventRepository) AppendDecay(event *models.ReputationEvent, decayedAt, at time.Time) (bool, error) {
+	applied := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).
+			Where("id = ? AND reputation_decayed_at = ?", event.UserID, decayedAt).
+			Update("reputation_decayed_at", at)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := appendEvent(tx, event); err != nil {
+			return err
+		}
+		applied = true
+		return nil
+	})
+	return applied, err
+}
+
+// appendEvent 锁定用户行后写入事件并更新声誉分数投影，必须在事务中调用
+func appendEvent(tx *gorm.DB, event *models.ReputationEvent) error {
+	var user models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "reputation_score").
+		First(&user, event.UserID).Error; err != nil {
+		return err
+	}
+
+	if user.ReputationScore+event.Delta < 0 {
+		event.Delta = -user.ReputationScore
+	}
+	event.ScoreAfter = user.ReputationScore + event.Delta
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	if err := tx.Create(event).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&models.User{}).Where("id = ?", event.UserID).
+		Update("reputation_score", event.ScoreAfter).Error
+}
+
+// ListByUser 分页获取用户的声誉事件，reason 为空时不过滤
+func (r *ReputationEventRepository) ListByUser(userID int64, reason string, offset, limit int) ([]models.ReputationEvent, error) {
+	var events []models.ReputationEvent
+	err := r.byUser(userID, reason).
+		Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+// CountByUser 统计用户的声誉事件数量
+func (r *ReputationEventRepository) CountByUser(userID int64, reason string) (int64, error) {
+	var count int64
+	err := r.byUser(userID, reason).Count(&count).Error
+	return count, err
+}
+
+// AggregateByReason 按原因汇总用户的声誉变更
+func (r *ReputationEventRepository) AggregateByReason(userID int64) ([]ReputationReasonAggregate, error) {
+	var aggregates []ReputationReasonAggregate
+	err := r.db.Model(&models.ReputationEvent{}).
+		Select("reason, COUNT(*) AS count, COALESCE(SUM(delta), 0) AS total_delta").
+		Where("user_id = ?", userID).
+		Group("reason").
+		Order("total_delta DESC").
+		Scan(&aggregates).Error
+	return aggregates, err
+}
+
+// SumByUser 计算用户声誉事件的 delta 之和
+func (r *ReputationEventRepository) SumByUser(userID int64) (int, error) {
+	var sum int
+	err := r.db.Model(&models.ReputationEvent{}).
+		Select("COALESCE(SUM(delta), 0)").
+		Where("user_id = ?", userID).
+		Scan(&sum).Error
+	return sum, err
+}
+
+// RebuildScore 按事件重新计算用户声誉分数投影
+func (r *ReputationEventRepository) RebuildScore(userID int64) (int, error) {
+	sum, err := r.SumByUser(userID)
+	if err != nil {
+		return 0, err
+	}
+	if sum < 0 {
+		sum = 0
+	}
+	err = r.db.Model(&models.User{}).Where("id = ?", userID).Update("reputation_score", sum).Error
+	return sum, err
+}
+
+// BackfillOpeningBalances 为分数与事件之和不一致的用户补记一条期初事件，返回补记数量
+func (r *ReputationEventRepository) BackfillOpeningBalances(reason, source string) (int64, error) {
+	result := r.db.Exec(`
+		INSERT INTO reputation_events (user_id, delta, score_after, reason, source, related_type, tx_hash, created_at)
+		SELECT u.id, u.reputation_score - COALESCE(e.total, 0), u.reputation_score, ?, ?, '', '', NOW()
+		FROM users u
+		LEFT JOIN (
+			SELECT user_id, SUM(delta) AS total FROM reputation_events GROUP BY user_id
+		) e ON e.user_id = u.id
+		WHERE u.reputation_score <> COALESCE(e.total, 0)`, reason, source)
+	return result.RowsAffected, result.Error
+}
+
+// ResetScores 将所有声誉分数大于 0 的用户清零，并为每个用户记一条扣减全部分数的事件，返回清零的用户数
+// 锁定用户行后在一条语句中完成，与 appendEvent 的行锁互斥
+func (r *ReputationEventRepository) ResetScores(reason, source string, at time.Time) (int64, error) {
+	result := r.db.Exec(`
+		WITH reset AS (
+			UPDATE users u SET reputation_score = 0, updated_at = ?
+			FROM (SELECT id, reputation_score FROM users WHERE reputation_score > 0 FOR UPDATE) old
+			WHERE u.id = old.id
+			RETURNING u.id, old.reputation_score
+		)
+		INSERT INTO reputation_events (user_id, delta, score_after, reason, source, related_type, tx_hash, created_at)
+		SELECT id, -reputation_score, 0, ?, ?, '', '', ? FROM reset`, at, reason, source, at)
+	return result.RowsAffected, result.Error
+}
+
+// byUser 构建按用户和原因过滤的查询
+func (r *ReputationEventRepository) byUser(userID int64, reason string) *gorm.DB {
+	query := r.db.Model(&models.ReputationEvent{}).Where("user_id = ?", userID)
+	if reason != "" {
+		query = query.Where("reason = ?", reason)
+	}
+	return query
+}
+
+// UserScore 用户分数聚合结果
+type UserScore struct {
+	UserID int64 `json:"user_id"`
+	Score  int64 `json:"score"`
+}
+
+// SumByUsersBetween 汇总时间段内各用户的声誉变更（排除指定来源），只返回正分并按分数降序分页
+// start、end 为零值表示不限起止时间，limit <= 0 表示返回全部
+func (r *ReputationEventRepository) SumByUsersBetween(start, end time.Time, excludedSources []string, offset, limit int) ([]UserScore, error) {
+	var scores []UserScore
+	query := r.between(start, end, excludedSources).
+		Select("user_id, SUM(delta) AS score").
+		Group("user_id").
+		Having("SUM(delta) > 0").
+		Order("score DESC, user_id ASC").
+		Offset(offset)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Scan(&scores).Error
+	return scores, err
+}
+
+// CountUsersBetween 统计时间段内声誉变更为正的用户数
+func (r *ReputationEventRepository) CountUsersBetween(start, end time.Time, excludedSources []string) (int64, error) {
+	var count int64
+	sub := r.between(start, end, excludedSources).
+		Select("user_id").
+		Group("user_id").
+		Having("SUM(delta) > 0")
+	err := r.db.Table("(?) AS t", sub).Count(&count).Error
+	return count, err
+}
+
+// SumByUserExcluding 汇总用户的声誉变更（排除指定来源）
+func (r *ReputationEventRepository) SumByUserExcluding(userID int64, excludedSources []string) (int64, error) {
+	var sum int64
+	query := r.db.Model(&models.ReputationEvent{}).
+		Select("COALESCE(SUM(delta), 0)").
+		Where("user_id = ?", userID)
+	if len(excludedSources) > 0 {
+		query = query.Where("source NOT IN ?", excludedSources)
+	}
+	err := query.Scan(&sum).Error
+	return sum, err
+}
+
+// SumByUserAndSource 汇总用户指定来源的声誉变更
+func (r *ReputationEventRepository) SumByUserAndSource(userID int64, source string) (int64, error) {
+	var sum int64
+	err := r.db.Model(&models.ReputationEvent{}).
+		Select("COALESCE(SUM(delta), 0)").
+		Where("user_id = ? AND source = ?", userID, source).
+		Scan(&sum).Error
+	return sum, err
+}
+
+// SumByUsersAfter 汇总各用户在 after 之后的声誉变更（排除指定来源），用于回推历史时间点的分数
+func (r *ReputationEventRepository) SumByUsersAfter(userIDs []int64, after time.Time, excludedSources []string) (map[int64]int, error) {
+	sums := make(map[int64]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return sums, nil
+	}
+	var rows []UserScore
+	query := r.db.Model(&models.ReputationEvent{}).
+		Select("user_id, SUM(delta) AS score").
+		Where("user_id IN ? AND created_at > ?", userIDs, after)
+	if len(excludedSources) > 0 {
+		query = query.Where("source NOT IN ?", excludedSources)
+	}
+	if err := query.Group("user_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		sums[row.UserID] = int(row.Score)
+	}
+	return sums, nil
+}
+
+// SumByUserSources 汇总用户属于指定来源的声誉变更
+func (r *ReputationEventRepository) SumByUserSources(userID int64, sources []string) (int64, error) {
+	var sum int64
+	err := r.db.Model(&models.ReputationEvent{}).
+		Select("COALESCE(SUM(delta), 0)").
+		Where("user_id = ? AND source IN ?", userID, sources).
+		Scan(&sum).Error
+	return sum, err
+}
+
+// between 构建时间段和来源过滤的查询
+func (r *ReputationEventRepository) between(start, end time.Time, excludedSources []string) *gorm.DB {
+	query := r.db.Model(&models.ReputationEvent{})
+	if !start.IsZero() {
+		query = query.Where("created_at >= ?", start)
+	}
+	if !end.IsZero() {
+		query = query.Where("created_at < ?", end)
+	}
+	if len(excludedSources) > 0 {
+		query = query.Where("source NOT IN ?", excludedSources)
+	}
+	return query
+}
@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newMockDB 创建基于 sqlmock 的 Postgres 连接，用于校验仓库生成的 SQL
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	return db, mock
+}
+
+// expectLockedScore 期望账本在事务中加锁读取用户当前分数
+func expectLockedScore(mock sqlmock.Sqlmock, userID int64, score int) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT "id","reputation_score" FROM "users" WHERE "users"."id" = \$1 .*FOR UPDATE`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "reputation_score"}).AddRow(userID, score))
+}
+
+func TestReputationEventRepository_Append(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewReputationEventRepository(db)
+
+	expectLockedScore(mock, 7, 50)
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "reputation_events"`)).
+		WithArgs(int64(7), 10, 60, "发布内容", "rule", "", nil, "", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "reputation_score"=$1`)).
+		WithArgs(60, sqlmock.AnyArg(), int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	event := &models.ReputationEvent{UserID: 7, Delta: 10, Reason: "发布内容", Source: "rule"}
+	require.NoError(t, repo.Append(event))
+	assert.Equal(t, 10, event.Delta)
+	assert.Equal(t, 60, event.ScoreAfter, "投影分数等于变更前分数加 delta")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReputationEventRepository_AppendClampsDeduction(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewReputationEventRepository(db)
+
+	expectLockedScore(mock, 7, 30)
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "reputation_events"`)).
+		WithArgs(int64(7), -30, 0, "违规", "manual", "", nil, "", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "reputation_score"=$1`)).
+		WithArgs(0, sqlmock.AnyArg(), int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	event := &models.ReputationEvent{UserID: 7, Delta: -100, Reason: "违规", Source: "manual"}
+	require.NoError(t, repo.Append(event))
+	assert.Equal(t, -30, event.Delta, "扣减超过当前分数时按实际可扣减值记账")
+	assert.Equal(t, 0, event.ScoreAfter)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReputationEventRepository_AppendRollsBackForMissingUser(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewReputationEventRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT "id","reputation_score" FROM "users"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "reputation_score"}))
+	mock.ExpectRollback()
+
+	err := repo.Append(&models.ReputationEvent{UserID: 404, Delta: 5, Reason: "发布内容", Source: "rule"})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet(), "用户不存在时不写入事件")
+}
+
+func TestReputationEventRepository_RebuildScore(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewReputationEventRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(SUM(delta), 0) FROM "reputation_events" WHERE user_id = $1`)).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(35))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "reputation_score"=$1`)).
+		WithArgs(35, sqlmock.AnyArg(), int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	score, err := repo.RebuildScore(7)
+	require.NoError(t, err)
+	assert.Equal(t, 35, score, "余额等于账本 delta 之和")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReputationEventRepository_AppendDecay(t *testing.T) {
+	decayedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := decayedAt.Add(24 * time.Hour)
+
+	t.Run("移动起点后记账", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewReputationEventRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "reputation_decayed_at"=$1,"updated_at"=$2 WHERE id = $3 AND reputation_decayed_at = $4`)).
+			WithArgs(at, sqlmock.AnyArg(), int64(7), decayedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT "id","reputation_score" FROM "users" WHERE "users"."id" = \$1 .*FOR UPDATE`).
+			WithArgs(int64(7)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "reputation_score"}).AddRow(7, 100))
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "reputation_events"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "reputation_score"=$1`)).
+			WithArgs(96, sqlmock.AnyArg(), int64(7)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		event := &models.ReputationEvent{UserID: 7, Delta: -4, Reason: "声誉衰减", Source: "decay"}
+		applied, err := repo.AppendDecay(event, decayedAt, at)
+		require.NoError(t, err)
+		assert.True(t, applied)
+		assert.Equal(t, 96, event.ScoreAfter)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("起点已被移动时不重复衰减", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewReputationEventRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "reputation_decayed_at"=$1`)).
+			WithArgs(at, sqlmock.AnyArg(), int64(7), decayedAt).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		event := &models.ReputationEvent{UserID: 7, Delta: -4, Reason: "声誉衰减", Source: "decay"}
+		applied, err := repo.AppendDecay(event, decayedAt, at)
+		require.NoError(t, err)
+		assert.False(t, applied)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ReputationReconcileRepository struct {
+	db *gorm.DB
+}
+
+func NewReputationReconcileRepository(db *gorm.DB) *ReputationReconcileRepository {
+	return &ReputationReconcileRepository{db: db}
+}
+
+// CreateRun 创建对账任务记录
+func (r *ReputationReconcileRepository) CreateRun(run *models.ReputationReconcileRun) error {
+	return r.db.Create(run).Error
+}
+
+// UpdateRun 更新对账任务记录
+func (r *ReputationReconcileRepository) UpdateRun(run *models.ReputationReconcileRun) error {
+	return r.db.Save(run).Error
+}
+
+// GetRun 根据ID获取对账任务记录
+func (r *ReputationReconcileRepository) GetRun(id int64) (*models.ReputationReconcileRun, error) {
+	var run models.ReputationReconcileRun
+	err := r.db.First(&run, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// ListRuns 分页获取对账任务记录，最新的在前
+func (r *ReputationReconcileRepository) ListRuns(offset, limit int) ([]models.ReputationReconcileRun, error) {
+	var runs []models.ReputationReconcileRun
+	err := r.db.Order("started_at DESC, id DESC").Offset(offset).Limit(limit).Find(&runs).Error
+	return runs, err
+}
+
+// CountRuns 统计对账任务数量
+func (r *ReputationReconcileRepository) CountRuns() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ReputationReconcileRun{}).Count(&count).Error
+	return count, err
+}
+
+// CreateDrift 记录用户声誉差异
+func (r *ReputationReconcileRepository) CreateDrift(drift *models.ReputationDrift) error {
+	return r.db.Create(drift).Error
+}
+
+// ListDrifts 分页获取对账任务发现的差异，差额绝对值大的在前
+func (r *ReputationReconcileRepository) ListDrifts(runID int64, offset, limit int) ([]models.ReputationDrift, error) {
+	var drifts []models.ReputationDrift
+	err := r.db.Where("run_id = ?", runID).
+		Order("ABS(drift) DESC, id ASC").
+		Offset(offset).Limit(limit).
+		Find(&drifts).Error
+	return drifts, err
+}
+
+// CountDrifts 统计对账任务发现的差异数量
+func (r *ReputationReconcileRepository) CountDrifts(runID int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ReputationDrift{}).Where("run_id = ?", runID).Count(&count).Error
+	return count, err
+}
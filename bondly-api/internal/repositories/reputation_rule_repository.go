@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReputationRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewReputationRuleRepository(db *gorm.DB) *ReputationRuleRepository {
+	return &ReputationRuleRepository{db: db}
+}
+
+// ListRules 获取全部规则
+func (r *ReputationRuleRepository) ListRules() ([]models.ReputationRule, error) {
+	var rules []models.ReputationRule
+	err := r.db.Order("event ASC").Find(&rules).Error
+	return rules, err
+}
+
+// GetRule 根据事件获取规则
+func (r *ReputationRuleRepository) GetRule(event string) (*models.ReputationRule, error) {
+	var rule models.ReputationRule
+	err := r.db.Where("event = ?", event).First(&rule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// UpdateRule 更新规则
+func (r *ReputationRuleRepository) UpdateRule(rule *models.ReputationRule) error {
+	return r.db.Save(rule).Error
+}
+
+// EnsureRules 插入尚不存在的规则，已存在的规则保持不变
+func (r *ReputationRuleRepository) EnsureRules(rules []models.ReputationRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event"}},
+		DoNothing: true,
+	}).Create(&rules).Error
+}
+
+// CreateHit 创建规则命中记录，相同的命中已存在时返回 false
+func (r *ReputationRuleRepository) CreateHit(hit *models.ReputationRuleHit) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(hit)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// SetHitEvent 关联命中记录与声誉事件
+func (r *ReputationRuleRepository) SetHitEvent(hitID, eventID int64, delta int) error {
+	return r.db.Model(&models.ReputationRuleHit{}).Where("id = ?", hitID).
+		Updates(map[string]interface{}{"event_id": eventID, "delta": delta}).Error
+}
+
+// DeleteHit 删除命中记录
+func (r *ReputationRuleRepository) DeleteHit(hitID int64) error {
+	return r.db.Delete(&models.ReputationRuleHit{}, hitID).Error
+}
+
+// SumDailyDelta 统计用户自 since 起通过指定规则变更的声誉绝对值之和
+func (r *ReputationRuleRepository) SumDailyDelta(userID int64, event string, since time.Time) (int, error) {
+	var sum int
+	err := r.db.Model(&models.ReputationRuleHit{}).
+		Select("COALESCE(SUM(ABS(delta)), 0)").
+		Where("user_id = ? AND event = ? AND created_at >= ?", userID, event, since).
+		Scan(&sum).Error
+	return sum, err
+}
+
+// ListPendingHits 获取待同步到链上的命中记录
+func (r *ReputationRuleRepository) ListPendingHits(limit int) ([]models.ReputationRuleHit, error) {
+	var hits []models.ReputationRuleHit
+	err := r.db.Where("chain_status = ? AND event_id IS NOT NULL", "pending").
+		Order("id ASC").Limit(limit).
+		Find(&hits).Error
+	return hits, err
+}
+
+// MarkHits 批量更新命中记录的链上同步状态
+func (r *ReputationRuleRepository) MarkHits(ids []int64, status, txHash string, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.ReputationRuleHit{}).Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"chain_status": status,
+			"tx_hash":      txHash,
+			"submitted_at": at,
+		}).Error
+}
+
+// SumUnsyncedByUser 汇总用户未写入链上的规则声誉变更：待同步、跳过（未开启批量同步或没有钱包）和失败的命中
+func (r *ReputationRuleRepository) SumUnsyncedByUser(userID int64) (int, error) {
+	var sum int
+	err := r.db.Model(&models.ReputationRuleHit{}).
+		Select("COALESCE(SUM(delta), 0)").
+		Where("user_id = ? AND chain_status IN ? AND event_id IS NOT NULL", userID, []string{"pending", "skipped", "failed"}).
+		Scan(&sum).Error
+	return sum, err
+}
+
+// TryLockChainSync 获取声誉上链的跨进程锁，批量上链与对账共用；锁被占用时返回 nil
+func (r *ReputationRuleRepository) TryLockChainSync(ctx context.Context) (*AdvisoryLock, error) {
+	return TryAdvisoryLock(ctx, r.db, LockReputationChainSync)
+}
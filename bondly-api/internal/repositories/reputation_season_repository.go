@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReputationSeasonRepository struct {
+	db *gorm.DB
+}
+
+func NewReputationSeasonRepository(db *gorm.DB) *ReputationSeasonRepository {
+	return &ReputationSeasonRepository{db: db}
+}
+
+// WithSeasonLock 在事务中获取赛季的事务级 advisory lock 后执行 fn，fn 收到的仓库使用同一事务
+// 归档旧赛季、清零声誉和创建新赛季因此要么全部生效要么全部回滚，且不会与其他实例交错
+func (r *ReputationSeasonRepository) WithSeasonLock(fn func(seasons *ReputationSeasonRepository, events *ReputationEventRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", LockReputationSeason).Error; err != nil {
+			return err
+		}
+		return fn(&ReputationSeasonRepository{db: tx}, &ReputationEventRepository{db: tx})
+	})
+}
+
+// EnsureActiveIndex 将重复的进行中赛季归档（保留最新一条，不生成排行榜）后创建唯一索引，同一时间只有一个进行中的赛季
+func (r *ReputationSeasonRepository) EnsureActiveIndex() (int64, error) {
+	result := r.db.Exec(`UPDATE reputation_seasons s SET status = 'archived', ended_at = later.started_at
+		FROM reputation_seasons later
+		WHERE s.status = 'active' AND later.status = 'active' AND s.id < later.id`)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	err := r.db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_reputation_seasons_active
+		ON reputation_seasons (status) WHERE status = 'active'`).Error
+	return result.RowsAffected, err
+}
+
+// Create 创建赛季
+func (r *ReputationSeasonRepository) Create(season *models.ReputationSeason) error {
+	return r.db.Create(season).Error
+}
+
+// GetByID 根据ID获取赛季
+func (r *ReputationSeasonRepository) GetByID(id int64) (*models.ReputationSeason, error) {
+	var season models.ReputationSeason
+	err := r.db.First(&season, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &season, nil
+}
+
+// GetActive 获取当前进行中的赛季，不存在时返回 nil
+func (r *ReputationSeasonRepository) GetActive() (*models.ReputationSeason, error) {
+	var season models.ReputationSeason
+	err := r.db.Where("status = ?", "active").Order("started_at DESC").First(&season).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &season, nil
+}
+
+// List 获取全部赛季，最新的在前
+func (r *ReputationSeasonRepository) List() ([]models.ReputationSeason, error) {
+	var seasons []models.ReputationSeason
+	err := r.db.Order("started_at DESC").Find(&seasons).Error
+	return seasons, err
+}
+
+// Archive 在同一事务中写入冻结的排行榜并将赛季标记为已归档
+func (r *ReputationSeasonRepository) Archive(season *models.ReputationSeason, standings []models.ReputationSeasonStanding) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(standings) > 0 {
+			if err := tx.Omit(clause.Associations).CreateInBatches(&standings, 500).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(season).Updates(map[string]interface{}{
+			"status":   season.Status,
+			"ended_at": season.EndedAt,
+		}).Error
+	})
+}
+
+// ListStandings 分页获取赛季排行榜
+func (r *ReputationSeasonRepository) ListStandings(seasonID int64, offset, limit int) ([]models.ReputationSeasonStanding, error) {
+	var standings []models.ReputationSeasonStanding
+	err := r.db.Preload("User").
+		Where("season_id = ?", seasonID).
+		Order("rank ASC").
+		Offset(offset).Limit(limit).
+		Find(&standings).Error
+	return standings, err
+}
+
+// CountStandings 统计赛季排行榜人数
+func (r *ReputationSeasonRepository) CountStandings(seasonID int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ReputationSeasonStanding{}).Where("season_id = ?", seasonID).Count(&count).Error
+	return count, err
+}
+
+// ListStandingsByUser 获取用户在各个已归档赛季中的排名
+func (r *ReputationSeasonRepository) ListStandingsByUser(userID int64) ([]models.ReputationSeasonStanding, error) {
+	var standings []models.ReputationSeasonStanding
+	err := r.db.Where("user_id = ?", userID).Order("season_id DESC").Find(&standings).Error
+	return standings, err
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReputationSeasonRepository_WithSeasonLock(t *testing.T) {
+	t.Run("清零和创建赛季在同一事务中执行", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewReputationSeasonRepository(db)
+		at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1)")).
+			WithArgs(LockReputationSeason).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`WITH reset AS .*INSERT INTO reputation_events`).
+			WithArgs(at, "赛季重置", "season_reset", at).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectCommit()
+
+		var reset int64
+		err := repo.WithSeasonLock(func(seasons *ReputationSeasonRepository, events *ReputationEventRepository) error {
+			var err error
+			reset, err = events.ResetScores("赛季重置", "season_reset", at)
+			return err
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), reset)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("出错时回滚", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewReputationSeasonRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1)")).
+			WithArgs(LockReputationSeason).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.WithSeasonLock(func(seasons *ReputationSeasonRepository, events *ReputationEventRepository) error {
+			return errors.New("no active season")
+		})
+		assert.EqualError(t, err, "no active season")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
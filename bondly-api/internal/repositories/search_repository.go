@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 搜索结果类型
+const (
+	SearchTypeContent  = "content"
+	SearchTypeUser     = "user"
+	SearchTypeProposal = "proposal"
+)
+
+// 搜索片段中匹配词的起止标记，由服务层转义后替换为 <mark>
+const (
+	SearchHighlightStart = "[[hl]]"
+	SearchHighlightStop  = "[[/hl]]"
+)
+
+// searchTarget 可搜索的表及其检索文本
+type searchTarget struct {
+	table  string
+	title  string // 结果标题
+	body   string // 生成片段的正文
+	filter string // 额外过滤条件
+}
+
+// searchTargets 各搜索类型对应的表，document 为 title 与 body 拼接，须与 EnsureSearchIndexes 创建的索引表达式一致
+var searchTargets = map[string]searchTarget{
+	SearchTypeContent:  {table: "contents", title: "coalesce(title, '')", body: "coalesce(content, '')", filter: "status = 'published'"},
+	SearchTypeUser:     {table: "users", title: "coalesce(nickname, '')", body: "coalesce(bio, '')"},
+	SearchTypeProposal: {table: "proposals", title: "coalesce(title, '')", body: "coalesce(description, '')"},
+}
+
+// SearchQuery 搜索参数，TextSearchConfig 须为合法的标识符
+type SearchQuery struct {
+	Keyword          string
+	Types            []string
+	TextSearchConfig string
+	TrigramFallback  bool
+	Offset           int
+	Limit            int
+}
+
+// SearchHit 搜索命中的记录
+type SearchHit struct {
+	Type      string
+	ID        int64
+	Title     string
+	Body      string
+	Headline  string // ts_headline 生成的片段，匹配词以 SearchHighlightStart/Stop 包裹
+	Rank      float64
+	CreatedAt time.Time
+}
+
+type SearchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// EnsureSearchIndexes 创建全文检索 GIN 索引，启用子串匹配时同时创建 pg_trgm 索引
+func (r *SearchRepository) EnsureSearchIndexes(textSearchConfig string, trigram bool) error {
+	if trigram {
+		if err := r.db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+			return fmt.Errorf("failed to enable pg_trgm: %w", err)
+		}
+	}
+
+	for _, name := range []string{SearchTypeContent, SearchTypeUser, SearchTypeProposal} {
+		target := searchTargets[name]
+		statements := []string{fmt.Sprintf(
+			"CREATE INDEX IF NOT EXISTS idx_%s_search_%s ON %s USING GIN (%s)",
+			target.table, textSearchConfig, target.table, searchVector(target, textSearchConfig),
+		)}
+		if trigram {
+			statements = append(statements, fmt.Sprintf(
+				"CREATE INDEX IF NOT EXISTS idx_%s_search_trgm ON %s USING GIN ((%s) gin_trgm_ops)",
+				target.table, target.table, searchDocument(target),
+			))
+		}
+		for _, statement := range statements {
+			if err := r.db.Exec(statement).Error; err != nil {
+				return fmt.Errorf("failed to create search index on %s: %w", target.table, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Search 按相关度搜索，返回当前页的命中记录以及各类型的命中数量
+func (r *SearchRepository) Search(query SearchQuery) ([]SearchHit, map[string]int64, error) {
+	union := r.unionQuery(query)
+	args := map[string]interface{}{
+		"keyword": query.Keyword,
+		"pattern": "%" + escapeLike(query.Keyword) + "%",
+		"offset":  query.Offset,
+		"limit":   query.Limit,
+	}
+
+	var counts []struct {
+		Type  string
+		Total int64
+	}
+	err := r.db.Raw(fmt.Sprintf("SELECT type, COUNT(*) AS total FROM (%s) AS results GROUP BY type", union), args).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	totals := make(map[string]int64, len(query.Types))
+	for _, name := range query.Types {
+		totals[name] = 0
+	}
+	for _, count := range counts {
+		totals[count.Type] = count.Total
+	}
+
+	headline := fmt.Sprintf(
+		"ts_headline('%s'::regconfig, body, websearch_to_tsquery('%s'::regconfig, @keyword), 'StartSel=\"%s\", StopSel=\"%s\", MaxWords=35, MinWords=15, MaxFragments=2')",
+		query.TextSearchConfig, query.TextSearchConfig, SearchHighlightStart, SearchHighlightStop,
+	)
+	var hits []SearchHit
+	err = r.db.Raw(fmt.Sprintf(
+		"SELECT type, id, title, body, %s AS headline, rank, created_at FROM (%s) AS results ORDER BY rank DESC, created_at DESC, id DESC OFFSET @offset LIMIT @limit",
+		headline, union,
+	), args).Scan(&hits).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return hits, totals, nil
+}
+
+// unionQuery 拼接各类型的检索子查询
+func (r *SearchRepository) unionQuery(query SearchQuery) string {
+	parts := make([]string, 0, len(query.Types))
+	for _, name := range query.Types {
+		target, ok := searchTargets[name]
+		if !ok {
+			continue
+		}
+
+		vector := searchVector(target, query.TextSearchConfig)
+		tsQuery := fmt.Sprintf("websearch_to_tsquery('%s'::regconfig, @keyword)", query.TextSearchConfig)
+		rank := fmt.Sprintf("ts_rank(%s, %s)", vector, tsQuery)
+		match := fmt.Sprintf("%s @@ %s", vector, tsQuery)
+		if query.TrigramFallback {
+			rank = fmt.Sprintf("%s + word_similarity(@keyword, %s)", rank, searchDocument(target))
+			match = fmt.Sprintf("(%s OR %s ILIKE @pattern)", match, searchDocument(target))
+		}
+		where := match
+		if target.filter != "" {
+			where = target.filter + " AND " + match
+		}
+
+		parts = append(parts, fmt.Sprintf(
+			"SELECT '%s' AS type, id, %s AS title, %s AS body, (%s)::float8 AS rank, created_at FROM %s WHERE %s",
+			name, target.title, target.body, rank, target.table, where,
+		))
+	}
+	return strings.Join(parts, " UNION ALL ")
+}
+
+// searchDocument 检索文本表达式
+func searchDocument(target searchTarget) string {
+	return fmt.Sprintf("%s || ' ' || %s", target.title, target.body)
+}
+
+// searchVector tsvector 表达式
+func searchVector(target searchTarget, textSearchConfig string) string {
+	return fmt.Sprintf("to_tsvector('%s'::regconfig, %s)", textSearchConfig, searchDocument(target))
+}
+
+// escapeLike 转义 LIKE 模式中的通配符
+func escapeLike(keyword string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(keyword)
+}
@@ -0,0 +1,236 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tagWithCount 查询标签并统计关联的内容数量
+const tagWithCount = "tags.*, (SELECT COUNT(*) FROM content_tags ct WHERE ct.tag_id = tags.id) AS content_count"
+
+// TrendingTag 近期互动量排名的标签
+type TrendingTag struct {
+	models.Tag
+	Interactions int64 `json:"interactions"` // 统计窗口内标签下内容获得的互动数
+}
+
+type TagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+// GetBySlug 根据 slug 获取标签及其内容数量
+func (r *TagRepository) GetBySlug(slug string) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.db.Select(tagWithCount).Where("slug = ?", slug).First(&tag).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// EnsureTags 按 slug 获取标签，不存在的标签以给定名称创建
+func (r *TagRepository) EnsureTags(tags []models.Tag) ([]models.Tag, error) {
+	if len(tags) == 0 {
+		return []models.Tag{}, nil
+	}
+
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	slugs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		slugs = append(slugs, tag.Slug)
+	}
+	var existing []models.Tag
+	if err := r.db.Where("slug IN ?", slugs).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+
+	// 保持调用方给定的顺序
+	bySlug := make(map[string]models.Tag, len(existing))
+	for _, tag := range existing {
+		bySlug[tag.Slug] = tag
+	}
+	result := make([]models.Tag, 0, len(slugs))
+	for _, slug := range slugs {
+		if tag, ok := bySlug[slug]; ok {
+			result = append(result, tag)
+		}
+	}
+	return result, nil
+}
+
+// ReplaceContentTags 以给定标签替换内容的全部标签
+func (r *TagRepository) ReplaceContentTags(contentID int64, tagIDs []int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("content_id = ?", contentID).Delete(&models.ContentTag{}).Error; err != nil {
+			return err
+		}
+		if len(tagIDs) == 0 {
+			return nil
+		}
+		now := time.Now()
+		links := make([]models.ContentTag, 0, len(tagIDs))
+		for _, tagID := range tagIDs {
+			links = append(links, models.ContentTag{ContentID: contentID, TagID: tagID, CreatedAt: now})
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+	})
+}
+
+// ListByContentIDs 获取多个内容的标签，按内容ID分组
+func (r *TagRepository) ListByContentIDs(contentIDs []int64) (map[int64][]models.Tag, error) {
+	result := make(map[int64][]models.Tag, len(contentIDs))
+	if len(contentIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		ContentID int64
+		models.Tag
+	}
+	err := r.db.Table("content_tags").
+		Select("content_tags.content_id, tags.*").
+		Joins("JOIN tags ON tags.id = content_tags.tag_id").
+		Where("content_tags.content_id IN ?", contentIDs).
+		Order("content_tags.created_at ASC, tags.id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.ContentID] = append(result[row.ContentID], row.Tag)
+	}
+	return result, nil
+}
+
+// Search 按 slug 或名称前缀匹配标签，内容多的在前，prefix 为空时返回最常用的标签
+func (r *TagRepository) Search(prefix string, limit int) ([]models.Tag, error) {
+	var tags []models.Tag
+	query := r.db.Select(tagWithCount)
+	if prefix != "" {
+		pattern := escapeLike(prefix) + "%"
+		query = query.Where("slug LIKE ? OR name ILIKE ?", pattern, pattern)
+	}
+	err := query.Order("content_count DESC, slug ASC").Limit(limit).Find(&tags).Error
+	return tags, err
+}
+
+// ListTrending 按统计窗口内标签下内容获得的互动数排名
+func (r *TagRepository) ListTrending(since time.Time, limit int) ([]TrendingTag, error) {
+	var tags []TrendingTag
+	err := r.db.Model(&models.Tag{}).
+		Select("tags.*, COUNT(ci.id) AS interactions, (SELECT COUNT(*) FROM content_tags c2 WHERE c2.tag_id = tags.id) AS content_count").
+		Joins("JOIN content_tags ct ON ct.tag_id = tags.id").
+		Joins("JOIN content_interactions ci ON ci.content_id = ct.content_id").
+		Where("ci.created_at >= ?", since).
+		Group("tags.id").
+		Order("interactions DESC, tags.slug ASC").
+		Limit(limit).
+		Scan(&tags).Error
+	return tags, err
+}
+
+// ListContentIDsByTag 分页获取标签下的内容ID，最新的在前，status 为空时不过滤
+func (r *TagRepository) ListContentIDsByTag(tagID int64, status string, offset, limit int) ([]int64, error) {
+	var ids []int64
+	err := r.contentByTagQuery(tagID, status).
+		Order("contents.created_at DESC, contents.id DESC").
+		Offset(offset).
+		Limit(limit).
+		Pluck("contents.id", &ids).Error
+	return ids, err
+}
+
+// CountContentsByTag 获取标签下的内容数量
+func (r *TagRepository) CountContentsByTag(tagID int64, status string) (int64, error) {
+	var count int64
+	err := r.contentByTagQuery(tagID, status).Count(&count).Error
+	return count, err
+}
+
+// Follow 关注标签，已关注时返回 false
+func (r *TagRepository) Follow(userID, tagID int64) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.TagFollow{UserID: userID, TagID: tagID, CreatedAt: time.Now()})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Unfollow 取消关注标签，未关注时返回 false
+func (r *TagRepository) Unfollow(userID, tagID int64) (bool, error) {
+	result := r.db.Where("user_id = ? AND tag_id = ?", userID, tagID).Delete(&models.TagFollow{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListFollowed 获取用户关注的标签，最近关注的在前
+func (r *TagRepository) ListFollowed(userID int64) ([]models.TagFollow, error) {
+	var follows []models.TagFollow
+	err := r.db.Preload("Tag").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&follows).Error
+	return follows, err
+}
+
+// CountFollowers 获取标签的关注人数
+func (r *TagRepository) CountFollowers(tagID int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.TagFollow{}).Where("tag_id = ?", tagID).Count(&count).Error
+	return count, err
+}
+
+// Rename 修改标签名称和 slug
+func (r *TagRepository) Rename(tagID int64, name, slug string) error {
+	return r.db.Model(&models.Tag{}).Where("id = ?", tagID).
+		Updates(map[string]interface{}{"name": name, "slug": slug, "updated_at": time.Now()}).Error
+}
+
+// Merge 将源标签的内容关联和关注者并入目标标签，并删除源标签
+func (r *TagRepository) Merge(sourceID, targetID int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			`INSERT INTO content_tags (content_id, tag_id, created_at)
+			 SELECT content_id, ?, created_at FROM content_tags WHERE tag_id = ?
+			 ON CONFLICT DO NOTHING`,
+			`INSERT INTO tag_follows (user_id, tag_id, created_at)
+			 SELECT user_id, ?, created_at FROM tag_follows WHERE tag_id = ?
+			 ON CONFLICT DO NOTHING`,
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement, targetID, sourceID).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("tag_id = ?", sourceID).Delete(&models.ContentTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("tag_id = ?", sourceID).Delete(&models.TagFollow{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Tag{}, sourceID).Error
+	})
+}
+
+func (r *TagRepository) contentByTagQuery(tagID int64, status string) *gorm.DB {
+	query := r.db.Model(&models.Content{}).
+		Joins("JOIN content_tags ON content_tags.content_id = contents.id").
+		Where("content_tags.tag_id = ?", tagID)
+	if status != "" {
+		query = query.Where("contents.status = ?", status)
+	}
+	return query
+}
@@ -0,0 +1,207 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TreasuryFlowTotal 按资产和流向汇总的金额（wei）
+type TreasuryFlowTotal struct {
+	Asset     string
+	Direction string
+	Total     string
+}
+
+// TreasuryDisbursementTotal 按类别、资产、拨款状态和提案状态汇总的拨款金额（wei）
+type TreasuryDisbursementTotal struct {
+	Category       string
+	Asset          string
+	Status         string
+	ProposalStatus string
+	Total          string
+}
+
+type TreasuryRepository struct {
+	db *gorm.DB
+}
+
+func NewTreasuryRepository(db *gorm.DB) *TreasuryRepository {
+	return &TreasuryRepository{db: db}
+}
+
+// GetCursor 获取索引器已处理到的区块，尚未索引时 found 为 false
+func (r *TreasuryRepository) GetCursor(name string) (lastBlock int64, found bool, err error) {
+	var cursor models.ChainIndexCursor
+	err = r.db.Where("name = ?", name).First(&cursor).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return cursor.LastBlock, true, nil
+}
+
+// SaveCursor 保存索引器已处理到的区块
+func (r *TreasuryRepository) SaveCursor(name string, lastBlock int64) error {
+	cursor := models.ChainIndexCursor{Name: name, LastBlock: lastBlock, UpdatedAt: time.Now()}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_block", "updated_at"}),
+	}).Create(&cursor).Error
+}
+
+// CreateFlow 记录资金流水，同一日志重复索引时忽略
+func (r *TreasuryRepository) CreateFlow(flow *models.TreasuryFlow) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(flow)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListFlows 分页获取资金流水，最新的在前，asset、direction 为空时不过滤
+func (r *TreasuryRepository) ListFlows(asset, direction string, offset, limit int) ([]models.TreasuryFlow, error) {
+	var flows []models.TreasuryFlow
+	err := r.flowQuery(asset, direction).
+		Order("block_number DESC, log_index DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&flows).Error
+	return flows, err
+}
+
+// CountFlows 获取资金流水数量
+func (r *TreasuryRepository) CountFlows(asset, direction string) (int64, error) {
+	var count int64
+	err := r.flowQuery(asset, direction).Count(&count).Error
+	return count, err
+}
+
+// SumFlows 按资产和流向汇总成功的资金流水
+func (r *TreasuryRepository) SumFlows() ([]TreasuryFlowTotal, error) {
+	var totals []TreasuryFlowTotal
+	err := r.db.Model(&models.TreasuryFlow{}).
+		Select("asset, direction, COALESCE(SUM(CAST(amount AS NUMERIC)), 0)::text AS total").
+		Where("success = ?", true).
+		Group("asset, direction").
+		Scan(&totals).Error
+	return totals, err
+}
+
+// CreateDisbursement 创建拨款申请，提案已有拨款申请时返回 false
+func (r *TreasuryRepository) CreateDisbursement(disbursement *models.TreasuryDisbursement) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(disbursement)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetDisbursement 获取拨款申请及其提案
+func (r *TreasuryRepository) GetDisbursement(id int64) (*models.TreasuryDisbursement, error) {
+	var disbursement models.TreasuryDisbursement
+	err := r.db.Preload("Proposal").First(&disbursement, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &disbursement, nil
+}
+
+// ListDisbursements 分页获取拨款申请，最新的在前，category 为空时不过滤
+func (r *TreasuryRepository) ListDisbursements(category string, offset, limit int) ([]models.TreasuryDisbursement, error) {
+	var disbursements []models.TreasuryDisbursement
+	err := r.disbursementQuery(category).
+		Preload("Proposal").
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&disbursements).Error
+	return disbursements, err
+}
+
+// CountDisbursements 获取拨款申请数量
+func (r *TreasuryRepository) CountDisbursements(category string) (int64, error) {
+	var count int64
+	err := r.disbursementQuery(category).Count(&count).Error
+	return count, err
+}
+
+// SumDisbursements 按类别、资产、拨款状态和提案状态汇总拨款金额
+func (r *TreasuryRepository) SumDisbursements() ([]TreasuryDisbursementTotal, error) {
+	var totals []TreasuryDisbursementTotal
+	err := r.db.Table("treasury_disbursements AS d").
+		Select("d.category, d.asset, d.status, p.status AS proposal_status, COALESCE(SUM(CAST(d.amount AS NUMERIC)), 0)::text AS total").
+		Joins("JOIN proposals p ON p.id = d.proposal_id").
+		Group("d.category, d.asset, d.status, p.status").
+		Scan(&totals).Error
+	return totals, err
+}
+
+// GetDisbursementByChainProposal 根据登记的链上提案ID获取拨款申请
+func (r *TreasuryRepository) GetDisbursementByChainProposal(chainProposalID string) (*models.TreasuryDisbursement, error) {
+	var disbursement models.TreasuryDisbursement
+	err := r.db.Where("chain_proposal_id = ?", chainProposalID).First(&disbursement).Error
+	if err != nil {
+		return nil, err
+	}
+	return &disbursement, nil
+}
+
+// LinkChainProposal 为待执行的拨款登记链上提案ID，拨款已执行或链上提案ID已被其他拨款使用时返回 false
+func (r *TreasuryRepository) LinkChainProposal(id int64, chainProposalID string) (bool, error) {
+	var linked bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.TreasuryDisbursement{}).
+			Where("chain_proposal_id = ? AND id <> ?", chainProposalID, id).
+			Count(&count).Error; err != nil || count > 0 {
+			return err
+		}
+		result := tx.Model(&models.TreasuryDisbursement{}).
+			Where("id = ? AND status = ?", id, "requested").
+			Update("chain_proposal_id", chainProposalID)
+		linked = result.RowsAffected > 0
+		return result.Error
+	})
+	return linked, err
+}
+
+// MarkDisbursementExecuted 根据链上提案执行结果更新待执行或执行失败的拨款，返回是否有记录被更新
+// BondlyTreasury 执行失败后提案可以重新执行，因此 failed 不是终态，重试成功后更新为 executed
+func (r *TreasuryRepository) MarkDisbursementExecuted(id int64, status, txHash string, executedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.TreasuryDisbursement{}).
+		Where("id = ? AND status IN ?", id, []string{"requested", "failed"}).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"tx_hash":     txHash,
+			"executed_at": executedAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *TreasuryRepository) flowQuery(asset, direction string) *gorm.DB {
+	query := r.db.Model(&models.TreasuryFlow{})
+	if asset != "" {
+		query = query.Where("asset = ?", asset)
+	}
+	if direction != "" {
+		query = query.Where("direction = ?", direction)
+	}
+	return query
+}
+
+func (r *TreasuryRepository) disbursementQuery(category string) *gorm.DB {
+	query := r.db.Model(&models.TreasuryDisbursement{})
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	return query
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreasuryRepository_MarkDisbursementExecuted(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewTreasuryRepository(db)
+	executedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// 执行失败的拨款在重新执行成功后同样更新
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "treasury_disbursements" SET "executed_at"=$1,"status"=$2,"tx_hash"=$3,"updated_at"=$4 WHERE id = $5 AND status IN ($6,$7)`)).
+		WithArgs(executedAt, "executed", "0xabc", sqlmock.AnyArg(), int64(3), "requested", "failed").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	updated, err := repo.MarkDisbursementExecuted(3, "executed", "0xabc", executedAt)
+	require.NoError(t, err)
+	assert.True(t, updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
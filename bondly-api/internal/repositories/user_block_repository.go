@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserBlockRepository struct {
+	db *gorm.DB
+}
+
+func NewUserBlockRepository(db *gorm.DB) *UserBlockRepository {
+	return &UserBlockRepository{db: db}
+}
+
+// Create 创建屏蔽关系，已存在时不变
+func (r *UserBlockRepository) Create(block *models.UserBlock) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(block).Error
+}
+
+// Delete 删除屏蔽关系
+func (r *UserBlockRepository) Delete(blockerID, blockedID int64) error {
+	return r.db.Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).Delete(&models.UserBlock{}).Error
+}
+
+// ExistsEither 判断两个用户之间是否存在任一方向的屏蔽
+func (r *UserBlockRepository) ExistsEither(userID, otherID int64) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.UserBlock{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", userID, otherID, otherID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ListBlocked 获取用户屏蔽的人列表
+func (r *UserBlockRepository) ListBlocked(blockerID int64, offset, limit int) ([]models.UserBlock, error) {
+	var blocks []models.UserBlock
+	err := r.db.Preload("Blocked").Where("blocker_id = ?", blockerID).Order("created_at DESC").Offset(offset).Limit(limit).Find(&blocks).Error
+	return blocks, err
+}
+
+// CountBlocked 获取用户屏蔽的人数
+func (r *UserBlockRepository) CountBlocked(blockerID int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.UserBlock{}).Where("blocker_id = ?", blockerID).Count(&count).Error
+	return count, err
+}
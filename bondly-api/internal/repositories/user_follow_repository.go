@@ -70,3 +70,31 @@ func (r *UserFollowRepository) CountFollowing(userID int64) (int64, error) {
 	err := r.db.Model(&models.UserFollower{}).Where("follower_id = ?", userID).Count(&count).Error
 	return count, err
 }
+
+// ListFollowerIDs 获取用户全部粉丝的ID
+func (r *UserFollowRepository) ListFollowerIDs(userID int64) ([]int64, error) {
+	var ids []int64
+	err := r.db.Model(&models.UserFollower{}).Where("followed_id = ?", userID).Pluck("follower_id", &ids).Error
+	return ids, err
+}
+
+// ListFollowingIDs 获取用户关注的全部用户ID
+func (r *UserFollowRepository) ListFollowingIDs(userID int64) ([]int64, error) {
+	var ids []int64
+	err := r.db.Model(&models.UserFollower{}).Where("follower_id = ?", userID).Pluck("followed_id", &ids).Error
+	return ids, err
+}
+
+// ListPopularIDs 在给定用户中筛选粉丝数不少于 threshold 的用户ID
+func (r *UserFollowRepository) ListPopularIDs(userIDs []int64, threshold int64) ([]int64, error) {
+	var ids []int64
+	if len(userIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&models.UserFollower{}).
+		Where("followed_id IN ?", userIDs).
+		Group("followed_id").
+		Having("COUNT(*) >= ?", threshold).
+		Pluck("followed_id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserPermissionRepository struct {
+	db *gorm.DB
+}
+
+func NewUserPermissionRepository(db *gorm.DB) *UserPermissionRepository {
+	return &UserPermissionRepository{db: db}
+}
+
+// Grant 授予权限，已授予时保持不变
+func (r *UserPermissionRepository) Grant(permission *models.UserPermission) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(permission).Error
+}
+
+// Revoke 撤销权限，返回是否存在该授权
+func (r *UserPermissionRepository) Revoke(userID int64, permission string) (bool, error) {
+	result := r.db.Where("user_id = ? AND permission = ?", userID, permission).Delete(&models.UserPermission{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListByUser 获取用户已授予的权限
+func (r *UserPermissionRepository) ListByUser(userID int64) ([]models.UserPermission, error) {
+	var permissions []models.UserPermission
+	err := r.db.Where("user_id = ?", userID).Order("permission ASC").Find(&permissions).Error
+	return permissions, err
+}
+
+// Has 判断用户是否拥有权限
+func (r *UserPermissionRepository) Has(userID int64, permission string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.UserPermission{}).
+		Where("user_id = ? AND permission = ?", userID, permission).
+		Count(&count).Error
+	return count > 0, err
+}
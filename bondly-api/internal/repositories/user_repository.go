@@ -2,6 +2,8 @@ package repositories
 
 import (
 	"bondly-api/internal/models"
+	"context"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -54,7 +56,8 @@ func (r *UserRepository) Create(user *models.User) error {
 
 // Update 更新用户
 func (r *UserRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
+	// 声誉分数是声誉事件的投影，只能通过声誉账本修改；衰减时间由衰减任务维护
+	return r.db.Omit("reputation_score", "reputation_decayed_at").Save(user).Error
 }
 
 // UpdateLastLogin 更新最后登录时间
@@ -62,11 +65,6 @@ func (r *UserRepository) UpdateLastLogin(id int64) error {
 	return r.db.Model(&models.User{}).Where("id = ?", id).Update("last_login_at", time.Now()).Error
 }
 
-// UpdateReputationScore 更新声誉积分
-func (r *UserRepository) UpdateReputationScore(id int64, score int) error {
-	return r.db.Model(&models.User{}).Where("id = ?", id).Update("reputation_score", score).Error
-}
-
 // List 获取用户列表
 func (r *UserRepository) List(offset, limit int) ([]models.User, error) {
 	var users []models.User
@@ -74,6 +72,39 @@ func (r *UserRepository) List(offset, limit int) ([]models.User, error) {
 	return users, err
 }
 
+// ListWithPositiveReputation 按ID游标分批获取声誉分数大于 0 的用户
+func (r *UserRepository) ListWithPositiveReputation(afterID int64, limit int) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Select("id", "reputation_score", "reputation_decayed_at").
+		Where("id > ? AND reputation_score > 0", afterID).
+		Order("id ASC").Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+// ListWithWallet 按ID游标分批获取绑定了钱包地址的用户
+func (r *UserRepository) ListWithWallet(afterID int64, limit int) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Select("id", "wallet_address", "reputation_score").
+		Where("id > ? AND wallet_address IS NOT NULL", afterID).
+		Order("id ASC").Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+// AnchorReputationDecay 为声誉为 0 或尚未设置衰减起点的用户设置衰减起点，返回更新数量
+func (r *UserRepository) AnchorReputationDecay(at time.Time) (int64, error) {
+	result := r.db.Model(&models.User{}).
+		Where("reputation_score = 0 OR reputation_decayed_at IS NULL").
+		Update("reputation_decayed_at", at)
+	return result.RowsAffected, result.Error
+}
+
+// TryLockReputationDecay 获取声誉衰减任务的跨进程锁，锁被占用时返回 nil
+func (r *UserRepository) TryLockReputationDecay(ctx context.Context) (*AdvisoryLock, error) {
+	return TryAdvisoryLock(ctx, r.db, LockReputationDecay)
+}
+
 // ListByRole 根据角色获取用户列表
 func (r *UserRepository) ListByRole(role string, offset, limit int) ([]models.User, error) {
 	var users []models.User
@@ -88,6 +119,13 @@ func (r *UserRepository) Count() (int64, error) {
 	return count, err
 }
 
+// CountCreatedBefore 获取指定时间之前创建的用户数量
+func (r *UserRepository) CountCreatedBefore(t time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.User{}).Where("created_at < ?", t).Count(&count).Error
+	return count, err
+}
+
 // CountByRole 根据角色获取用户数量
 func (r *UserRepository) CountByRole(role string) (int64, error) {
 	var count int64
@@ -167,3 +205,27 @@ func (r *UserRepository) HasUserReceivedWalletAirdrop(userID int64) (bool, error
 	}
 	return count > 0, nil
 }
+
+// GetByIDs 根据ID列表批量获取用户
+func (r *UserRepository) GetByIDs(ids []int64) ([]models.User, error) {
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
+// ListByNicknames 按昵称查找用户，忽略大小写
+func (r *UserRepository) ListByNicknames(nicknames []string) ([]models.User, error) {
+	var users []models.User
+	if len(nicknames) == 0 {
+		return users, nil
+	}
+	lowered := make([]string, 0, len(nicknames))
+	for _, nickname := range nicknames {
+		lowered = append(lowered, strings.ToLower(nickname))
+	}
+	err := r.db.Where("LOWER(nickname) IN ?", lowered).Find(&users).Error
+	return users, err
+}
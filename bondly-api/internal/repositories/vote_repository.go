@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"bondly-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type VoteRepository struct {
+	db *gorm.DB
+}
+
+func NewVoteRepository(db *gorm.DB) *VoteRepository {
+	return &VoteRepository{
+		db: db,
+	}
+}
+
+// WithProposalLock 在事务中锁定提案行后执行 fn，fn 收到的仓库使用同一事务
+// 同一提案的投票和计票因此串行执行，不会重复投票或以过期的投票列表覆盖票数
+func (r *VoteRepository) WithProposalLock(proposalID int64, fn func(tx *VoteRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var proposal models.Proposal
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&proposal, proposalID).Error; err != nil {
+			return err
+		}
+		return fn(&VoteRepository{db: tx})
+	})
+}
+
+// UpdateTally 更新提案的赞成和反对票数
+func (r *VoteRepository) UpdateTally(proposalID int64, votesFor, votesAgainst int64) error {
+	return r.db.Model(&models.Proposal{}).Where("id = ?", proposalID).Updates(map[string]interface{}{
+		"votes_for":     votesFor,
+		"votes_against": votesAgainst,
+	}).Error
+}
+
+// FinalizeProposal 将仍为 active 的提案更新为结算状态，返回是否由本次更新完成结算
+func (r *VoteRepository) FinalizeProposal(proposalID int64, status string) (bool, error) {
+	result := r.db.Model(&models.Proposal{}).
+		Where("id = ? AND status = ?", proposalID, "active").
+		Update("status", status)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Create 创建投票
+func (r *VoteRepository) Create(vote *models.Vote) error {
+	return r.db.Create(vote).Error
+}
+
+// GetByProposalAndVoter 获取用户在提案上的投票
+func (r *VoteRepository) GetByProposalAndVoter(proposalID, voterID int64) (*models.Vote, error) {
+	var vote models.Vote
+	err := r.db.Where("proposal_id = ? AND voter_id = ?", proposalID, voterID).First(&vote).Error
+	if err != nil {
+		return nil, err
+	}
+	return &vote, nil
+}
+
+// ListAllByProposal 获取提案的全部投票（用于计票）
+func (r *VoteRepository) ListAllByProposal(proposalID int64) ([]models.Vote, error) {
+	var votes []models.Vote
+	err := r.db.Where("proposal_id = ?", proposalID).Order("id ASC").Find(&votes).Error
+	return votes, err
+}
+
+// ListByProposal 分页获取提案的投票列表
+func (r *VoteRepository) ListByProposal(proposalID int64, offset, limit int) ([]models.Vote, error) {
+	var votes []models.Vote
+	err := r.db.Preload("Voter").Where("proposal_id = ?", proposalID).Offset(offset).Limit(limit).Order("weight DESC, created_at ASC").Find(&votes).Error
+	return votes, err
+}
+
+// CountByProposal 获取提案的投票数量
+func (r *VoteRepository) CountByProposal(proposalID int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Vote{}).Where("proposal_id = ?", proposalID).Count(&count).Error
+	return count, err
+}
+
+// UpdateWeight 更新投票权重
+func (r *VoteRepository) UpdateWeight(id int64, weight, delegatedWeight int64) error {
+	return r.db.Model(&models.Vote{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"weight":           weight,
+		"delegated_weight": delegatedWeight,
+	}).Error
+}
+
+// ListPowerSnapshots 获取提案已保存的投票权快照，以用户ID为键
+func (r *VoteRepository) ListPowerSnapshots(proposalID int64) (map[int64]int64, error) {
+	var snapshots []models.ProposalPowerSnapshot
+	if err := r.db.Where("proposal_id = ?", proposalID).Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+	power := make(map[int64]int64, len(snapshots))
+	for _, snapshot := range snapshots {
+		power[snapshot.UserID] = snapshot.Power
+	}
+	return power, nil
+}
+
+// CreatePowerSnapshots 保存提案的投票权快照，已有快照的用户保留最早的值
+func (r *VoteRepository) CreatePowerSnapshots(proposalID int64, power map[int64]int64, at time.Time) error {
+	if len(power) == 0 {
+		return nil
+	}
+	snapshots := make([]models.ProposalPowerSnapshot, 0, len(power))
+	for userID, amount := range power {
+		snapshots = append(snapshots, models.ProposalPowerSnapshot{ProposalID: proposalID, UserID: userID, Power: amount, CreatedAt: at})
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&snapshots).Error
+}
+
+// ListVoterIDs 获取提案的全部投票人ID
+func (r *VoteRepository) ListVoterIDs(proposalID int64) ([]int64, error) {
+	var ids []int64
+	err := r.db.Model(&models.Vote{}).Where("proposal_id = ?", proposalID).Distinct().Pluck("voter_id", &ids).Error
+	return ids, err
+}
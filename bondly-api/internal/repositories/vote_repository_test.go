@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoteRepository_FinalizeProposal(t *testing.T) {
+	t.Run("仍为 active 时完成结算", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewVoteRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "proposals" SET "status"=\$1,"updated_at"=\$2 WHERE id = \$3 AND status = \$4`).
+			WithArgs("passed", sqlmock.AnyArg(), int64(5), "active").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		claimed, err := repo.FinalizeProposal(5, "passed")
+		require.NoError(t, err)
+		assert.True(t, claimed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("已被其他实例结算时不重复结算", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewVoteRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "proposals" SET "status"=\$1`).
+			WithArgs("passed", sqlmock.AnyArg(), int64(5), "active").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		claimed, err := repo.FinalizeProposal(5, "passed")
+		require.NoError(t, err)
+		assert.False(t, claimed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestVoteRepository_CreatePowerSnapshots(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewVoteRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "proposal_power_snapshots" ("proposal_id","user_id","power","created_at") VALUES ($1,$2,$3,$4) ON CONFLICT DO NOTHING`)).
+		WithArgs(int64(5), int64(7), int64(10), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.CreatePowerSnapshots(5, map[int64]int64{7: 10}, time.Now()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	loggerpkg "bondly-api/internal/logger"
+	"context"
+	"sync"
+	"time"
+)
+
+// JobFunc 定时任务函数
+type JobFunc func(ctx context.Context) error
+
+type job struct {
+	name     string
+	interval time.Duration
+	fn       JobFunc
+	atStart  bool // 启动时先执行一次
+}
+
+// Scheduler 简单的进程内定时任务调度器，每个任务在独立的 goroutine 中按固定间隔执行
+type Scheduler struct {
+	jobs   []job
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New 创建调度器
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every 注册按固定间隔执行的任务，需在 Start 之前调用
+func (s *Scheduler) Every(name string, interval time.Duration, fn JobFunc) {
+	if interval <= 0 {
+		loggerpkg.Log.Warnf("Scheduler job %s ignored: non-positive interval", name)
+		return
+	}
+	s.jobs = append(s.jobs, job{name: name, interval: interval, fn: fn})
+}
+
+// EveryFromStart 与 Every 相同，但启动后立即执行一次，用于接口依赖其结果的任务
+func (s *Scheduler) EveryFromStart(name string, interval time.Duration, fn JobFunc) {
+	if interval <= 0 {
+		loggerpkg.Log.Warnf("Scheduler job %s ignored: non-positive interval", name)
+		return
+	}
+	s.jobs = append(s.jobs, job{name: name, interval: interval, fn: fn, atStart: true})
+}
+
+// Start 启动所有任务
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.run(ctx, j)
+	}
+	loggerpkg.Log.Infof("Scheduler started with %d jobs", len(s.jobs))
+}
+
+// Stop 停止所有任务并等待正在执行的任务结束
+func (s *Scheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+	loggerpkg.Log.Info("Scheduler stopped")
+}
+
+// run 按间隔循环执行任务，单次执行失败只记录日志
+func (s *Scheduler) run(ctx context.Context, j job) {
+	defer s.wg.Done()
+
+	if j.atStart {
+		s.execute(ctx, j)
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.execute(ctx, j)
+		}
+	}
+}
+
+// execute 执行一次任务并记录结果
+func (s *Scheduler) execute(ctx context.Context, j job) {
+	start := time.Now()
+	if err := j.fn(ctx); err != nil {
+		loggerpkg.Log.Errorf("Scheduler job %s failed: %v", j.name, err)
+		return
+	}
+	loggerpkg.Log.Debugf("Scheduler job %s finished in %s", j.name, time.Since(start))
+}
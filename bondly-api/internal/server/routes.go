@@ -3,6 +3,7 @@ package server
 import (
 	"bondly-api/internal/handlers"
 	"bondly-api/internal/middleware"
+	"bondly-api/internal/models"
 	"context"
 	"net/http"
 	"time"
@@ -52,11 +53,18 @@ func (s *Server) setupRoutes() {
 		content := v1.Group("/content")
 		content.Use(middleware.NoCache()) // 禁用缓存
 		{
-			content.GET("", s.contentHandlers.ListContent)                                                                        // 获取内容列表
-			content.POST("", middleware.AuthMiddleware(), s.contentHandlers.CreateContent)                                        // 创建内容
-			content.GET("/:id", s.contentHandlers.GetContent)                                                                     // 获取内容详情
-			content.PUT("/:id", middleware.AuthMiddleware(), middleware.AdminOrOwner("content"), s.contentHandlers.UpdateContent) // 更新内容
-			content.DELETE("/:id", middleware.AuthMiddleware(), middleware.AdminOnly(), s.contentHandlers.DeleteContent)          // 删除内容
+			content.GET("", s.contentHandlers.ListContent)                                                                                                               // 获取内容列表
+			content.POST("", middleware.AuthMiddleware(), s.contentHandlers.CreateContent)                                                                               // 创建内容
+			content.POST("/preview", middleware.AuthMiddleware(), s.contentHandlers.PreviewContent)                                                                      // 预览 Markdown 渲染结果
+			content.GET("/:id/stakes", s.creatorRewardHandlers.GetContentStakes)                                                                                         // 获取内容互动质押
+			content.GET("/:id/revisions", middleware.AuthMiddleware(), middleware.AdminOrOwner("content"), s.contentRevisionHandlers.ListRevisions)                      // 获取内容修订历史
+			content.GET("/:id/revisions/diff", middleware.AuthMiddleware(), middleware.AdminOrOwner("content"), s.contentRevisionHandlers.DiffRevisions)                 // 对比两个修订
+			content.GET("/:id/revisions/:revision", middleware.AuthMiddleware(), middleware.AdminOrOwner("content"), s.contentRevisionHandlers.GetRevision)              // 获取单个修订
+			content.POST("/:id/revisions/:revision/restore", middleware.AuthMiddleware(), middleware.AdminOrOwner("content"), s.contentRevisionHandlers.RestoreRevision) // 恢复到指定修订
+			content.POST("/:id/review", middleware.AuthMiddleware(), middleware.RequirePermission(models.PermissionContentModerator), s.contentHandlers.ReviewContent)   // 审核内容
+			content.GET("/:id", middleware.OptionalAuth(), s.contentHandlers.GetContent)                                                                                 // 获取内容详情
+			content.PUT("/:id", middleware.AuthMiddleware(), middleware.AdminOrOwner("content"), s.contentHandlers.UpdateContent)                                        // 更新内容
+			content.DELETE("/:id", middleware.AuthMiddleware(), middleware.AdminOnly(), s.contentHandlers.DeleteContent)                                                 // 删除内容
 		}
 
 		// 内容互动相关路由
@@ -76,6 +84,19 @@ func (s *Server) setupRoutes() {
 			proposals.GET("/:id", s.proposalHandlers.GetProposal)                                                                      // 获取提案详情
 			proposals.PUT("/:id", middleware.AuthMiddleware(), middleware.AdminOrOwner("proposal"), s.proposalHandlers.UpdateProposal) // 更新提案
 			proposals.DELETE("/:id", middleware.AuthMiddleware(), middleware.AdminOnly(), s.proposalHandlers.DeleteProposal)           // 删除提案
+			proposals.POST("/:id/vote", middleware.AuthMiddleware(), s.voteHandlers.CastVote)                                          // 对提案投票
+			proposals.GET("/:id/votes", s.voteHandlers.ListVotes)                                                                      // 获取提案投票列表
+			proposals.GET("/:id/result", s.voteHandlers.GetResult)                                                                     // 获取提案计票结果
+		}
+
+		// 投票委托相关路由
+		delegations := v1.Group("/delegations")
+		{
+			delegations.POST("", middleware.AuthMiddleware(), s.delegationHandlers.Delegate)     // 委托投票权
+			delegations.DELETE("", middleware.AuthMiddleware(), s.delegationHandlers.Undelegate) // 撤销委托
+			delegations.GET("/graph", s.delegationHandlers.GetDelegationGraph)                   // 获取委托图
+			delegations.GET("/user/:id", s.delegationHandlers.GetUserDelegations)                // 获取用户委托关系
+			delegations.GET("/power/:id", s.delegationHandlers.GetVotingPower)                   // 获取用户投票权
 		}
 
 		// 交易相关路由 - 完整的CRUD
@@ -93,13 +114,18 @@ func (s *Server) setupRoutes() {
 		// 评论相关路由 - 完整的CRUD
 		comments := v1.Group("/comments")
 		{
-			comments.GET("", s.commentHandlers.ListComments)                                           // 获取评论列表
-			comments.GET("/count", s.commentHandlers.GetCommentCount)                                  // 获取评论数量
-			comments.POST("", middleware.AuthMiddleware(), s.commentHandlers.CreateComment)            // 创建评论
-			comments.GET("/:id", s.commentHandlers.GetComment)                                         // 获取评论详情
-			comments.DELETE("/:id", middleware.AuthMiddleware(), s.commentHandlers.DeleteComment)      // 删除评论
-			comments.POST("/:id/like", middleware.AuthMiddleware(), s.commentHandlers.LikeComment)     // 点赞评论
-			comments.POST("/:id/unlike", middleware.AuthMiddleware(), s.commentHandlers.UnlikeComment) // 取消点赞
+			comments.GET("", middleware.OptionalAuth(), s.commentHandlers.ListComments)                                                                                     // 获取评论列表
+			comments.GET("/tree", middleware.OptionalAuth(), s.commentHandlers.ListCommentTree)                                                                             // 获取评论树
+			comments.GET("/count", s.commentHandlers.GetCommentCount)                                                                                                       // 获取评论数量
+			comments.POST("", middleware.AuthMiddleware(), s.commentHandlers.CreateComment)                                                                                 // 创建评论
+			comments.GET("/:id/replies", middleware.OptionalAuth(), s.commentHandlers.ListReplies)                                                                          // 获取评论的回复树
+			comments.GET("/:id", middleware.OptionalAuth(), s.commentHandlers.GetComment)                                                                                   // 获取评论详情
+			comments.PUT("/:id", middleware.AuthMiddleware(), s.commentHandlers.EditComment)                                                                                // 编辑评论
+			comments.GET("/:id/edits", s.commentHandlers.ListCommentEdits)                                                                                                  // 获取评论编辑历史
+			comments.DELETE("/:id", middleware.AuthMiddleware(), s.commentHandlers.DeleteComment)                                                                           // 删除评论
+			comments.POST("/:id/like", middleware.AuthMiddleware(), s.commentHandlers.LikeComment)                                                                          // 点赞评论
+			comments.POST("/:id/unlike", middleware.AuthMiddleware(), s.commentHandlers.UnlikeComment)                                                                      // 取消点赞
+			comments.POST("/:id/moderate", middleware.AuthMiddleware(), middleware.RequirePermission(models.PermissionCommentModerator), s.commentHandlers.ModerateComment) // 隐藏、锁定或置顶评论
 		}
 
 		// 用户关注相关路由
@@ -109,19 +135,55 @@ func (s *Server) setupRoutes() {
 			follows.DELETE("/:followed_id", middleware.AuthMiddleware(), s.userFollowHandlers.UnfollowUser) // 取消关注
 		}
 
+		// 用户屏蔽相关路由
+		blocks := v1.Group("/blocks")
+		{
+			blocks.GET("", middleware.AuthMiddleware(), s.userBlockHandlers.ListBlocked)             // 获取屏蔽列表
+			blocks.POST("/:user_id", middleware.AuthMiddleware(), s.userBlockHandlers.BlockUser)     // 屏蔽用户
+			blocks.DELETE("/:user_id", middleware.AuthMiddleware(), s.userBlockHandlers.UnblockUser) // 取消屏蔽
+		}
+
+		// 提及相关路由
+		v1.GET("/mentions", middleware.AuthMiddleware(), s.mentionHandlers.ListMentions) // 获取提及我的记录
+
+		// 站内通知相关路由
+		notifications := v1.Group("/notifications")
+		{
+			notifications.GET("", middleware.AuthMiddleware(), s.notificationHandlers.ListNotifications)             // 获取通知列表
+			notifications.GET("/unread-count", middleware.AuthMiddleware(), s.notificationHandlers.GetUnreadCount)   // 获取未读通知数
+			notifications.POST("/read-all", middleware.AuthMiddleware(), s.notificationHandlers.MarkAllRead)         // 全部标记为已读
+			notifications.POST("/:id/read", middleware.AuthMiddleware(), s.notificationHandlers.MarkRead)            // 标记通知为已读
+			notifications.GET("/preferences", middleware.AuthMiddleware(), s.notificationHandlers.GetPreferences)    // 获取通知偏好
+			notifications.PUT("/preferences", middleware.AuthMiddleware(), s.notificationHandlers.UpdatePreferences) // 修改通知偏好
+			notifications.GET("/unsubscribe", s.notificationHandlers.UnsubscribePage)                                // 邮件摘要退订确认页
+			notifications.POST("/unsubscribe", s.notificationHandlers.Unsubscribe)                                   // 一键退订邮件摘要
+		}
+
+		// 实时推送相关路由
+		realtime := v1.Group("/realtime")
+		{
+			realtime.GET("/ws", middleware.StreamAuth(), s.realtimeHandlers.WebSocket) // WebSocket 推送
+			realtime.GET("/sse", middleware.StreamAuth(), s.realtimeHandlers.Stream)   // SSE 推送
+		}
+
 		// 用户相关路由 - 扩展关注功能
 		users := v1.Group("/users")
 		{
-			users.POST("/", s.userHandlers.CreateUser)                            // 创建用户
-			users.GET("/", s.userHandlers.ListUsers)                              // 获取用户列表
-			users.GET("/top", s.userHandlers.GetTopUsersByReputation)             // 获取声誉排行榜
-			users.GET("/wallet/:address", s.userHandlers.GetUserByWalletAddress)  // 根据钱包地址获取用户
-			users.GET("/email/:email", s.userHandlers.GetUserByEmail)             // 根据邮箱获取用户
-			users.GET("/:id/followers", s.userFollowHandlers.GetFollowers)        // 获取用户粉丝列表
-			users.GET("/:id/following", s.userFollowHandlers.GetFollowing)        // 获取用户关注列表
-			users.GET("/:id/custody-wallet", s.userHandlers.GetUserCustodyWallet) // 获取用户托管钱包信息
-			users.GET("/:id", s.userHandlers.GetUserByID)                         // 根据ID获取用户
-			users.POST("/:id", s.userHandlers.UpdateUser)                         // 更新用户
+			users.POST("/", s.userHandlers.CreateUser)                                                                                                   // 创建用户
+			users.GET("/", s.userHandlers.ListUsers)                                                                                                     // 获取用户列表
+			users.GET("/top", s.userHandlers.GetTopUsersByReputation)                                                                                    // 获取声誉排行榜
+			users.GET("/wallet/:address", s.userHandlers.GetUserByWalletAddress)                                                                         // 根据钱包地址获取用户
+			users.GET("/email/:email", s.userHandlers.GetUserByEmail)                                                                                    // 根据邮箱获取用户
+			users.GET("/:id/followers", s.userFollowHandlers.GetFollowers)                                                                               // 获取用户粉丝列表
+			users.GET("/:id/following", s.userFollowHandlers.GetFollowing)                                                                               // 获取用户关注列表
+			users.GET("/:id/achievements", s.achievementHandlers.GetUserAchievements)                                                                    // 获取用户成就
+			users.POST("/:id/achievements/evaluate", middleware.AuthMiddleware(), middleware.AdminOnly(), s.achievementHandlers.EvaluateUser)            // 重新评估用户成就（管理员）
+			users.GET("/:id/custody-wallet", s.userHandlers.GetUserCustodyWallet)                                                                        // 获取用户托管钱包信息
+			users.GET("/:id/permissions", middleware.AuthMiddleware(), middleware.AdminOnly(), s.userPermissionHandlers.ListPermissions)                 // 获取用户权限（管理员）
+			users.POST("/:id/permissions", middleware.AuthMiddleware(), middleware.AdminOnly(), s.userPermissionHandlers.GrantPermission)                // 授予用户权限（管理员）
+			users.DELETE("/:id/permissions/:permission", middleware.AuthMiddleware(), middleware.AdminOnly(), s.userPermissionHandlers.RevokePermission) // 撤销用户权限（管理员）
+			users.GET("/:id", s.userHandlers.GetUserByID)                                                                                                // 根据ID获取用户
+			users.POST("/:id", middleware.AuthMiddleware(), middleware.AdminOrOwner("user"), s.userHandlers.UpdateUser)                                  // 更新用户（本人或管理员）
 		}
 
 		// 钱包绑定相关路由 - 完整的CRUD
@@ -152,17 +214,81 @@ func (s *Server) setupRoutes() {
 		// 声誉系统相关路由
 		reputation := v1.Group("/reputation")
 		{
-			reputation.GET("/user/:id", s.reputationHandlers.GetUserReputation)                                // 获取用户声誉分数
-			reputation.GET("/address/:address", s.reputationHandlers.GetUserReputationByAddress)               // 根据钱包地址获取声誉分数
-			reputation.GET("/ranking", s.reputationHandlers.GetTopUsersByReputation)                           // 获取声誉排行榜
-			reputation.GET("/governance/eligible/:id", s.reputationHandlers.IsEligibleForGovernance)           // 检查治理资格
-			reputation.POST("/add", middleware.AuthMiddleware(), s.reputationHandlers.AddReputation)           // 增加声誉分数（需要权限）
-			reputation.POST("/subtract", middleware.AuthMiddleware(), s.reputationHandlers.SubtractReputation) // 减少声誉分数（需要权限）
-			reputation.POST("/sync/:id", s.reputationHandlers.SyncReputationFromChain)                         // 从链上同步声誉分数
+			reputation.GET("/user/:id", s.reputationHandlers.GetUserReputation)                                                                                                              // 获取用户声誉分数
+			reputation.GET("/user/:id/history", s.reputationHandlers.GetReputationHistory)                                                                                                   // 获取用户声誉变更历史
+			reputation.GET("/address/:address", s.reputationHandlers.GetUserReputationByAddress)                                                                                             // 根据钱包地址获取声誉分数
+			reputation.GET("/user/:id/lifetime", s.reputationSeasonHandlers.GetUserLifetime)                                                                                                 // 获取用户累计声誉及历史赛季排名
+			reputation.GET("/ranking", s.reputationHandlers.GetTopUsersByReputation)                                                                                                         // 获取声誉排行榜
+			reputation.GET("/governance/eligible/:id", s.reputationHandlers.IsEligibleForGovernance)                                                                                         // 检查治理资格
+			reputation.POST("/add", middleware.AuthMiddleware(), middleware.RequirePermission(models.PermissionReputationManager), s.reputationHandlers.AddReputation)                       // 增加声誉分数（需要声誉管理权限和幂等键）
+			reputation.POST("/subtract", middleware.AuthMiddleware(), middleware.RequirePermission(models.PermissionReputationManager), s.reputationHandlers.SubtractReputation)             // 减少声誉分数（需要声誉管理权限和幂等键）
+			reputation.POST("/sync/:id", middleware.AuthMiddleware(), middleware.RequirePermission(models.PermissionReputationManager), s.reputationHandlers.SyncReputationFromChain)        // 从链上同步声誉分数（需要声誉管理权限和幂等键）
+			reputation.GET("/audit", middleware.AuthMiddleware(), middleware.RequirePermission(models.PermissionReputationManager), s.reputationHandlers.ListAuditLogs)                      // 获取声誉变更审计记录（需要声誉管理权限）
+			reputation.POST("/reconcile", middleware.AuthMiddleware(), middleware.RequirePermission(models.PermissionReputationManager), s.reputationReconcileHandlers.StartReconcile)       // 开始数据库与链上声誉对账（需要声誉管理权限）
+			reputation.GET("/reconcile/runs", middleware.AuthMiddleware(), middleware.RequirePermission(models.PermissionReputationManager), s.reputationReconcileHandlers.ListRuns)         // 获取声誉对账任务列表（需要声誉管理权限）
+			reputation.GET("/reconcile/runs/:id", middleware.AuthMiddleware(), middleware.RequirePermission(models.PermissionReputationManager), s.reputationReconcileHandlers.GetRunReport) // 获取声誉对账报告（需要声誉管理权限）
+			reputation.GET("/rules", s.reputationRuleHandlers.ListRules)                                                                                                                     // 获取声誉规则列表
+			reputation.PUT("/rules/:event", middleware.AuthMiddleware(), middleware.AdminOnly(), s.reputationRuleHandlers.UpdateRule)                                                        // 更新声誉规则（管理员）
+			reputation.POST("/rules/flush", middleware.AuthMiddleware(), middleware.AdminOnly(), s.reputationRuleHandlers.FlushToChain)                                                      // 批量同步规则声誉到链上（管理员）
+			reputation.GET("/ranking/lifetime", s.reputationSeasonHandlers.GetLifetimeRanking)                                                                                               // 获取累计声誉排行榜
+			reputation.GET("/seasons", s.reputationSeasonHandlers.ListSeasons)                                                                                                               // 获取声誉赛季列表
+			reputation.GET("/seasons/:id/leaderboard", s.reputationSeasonHandlers.GetSeasonLeaderboard)                                                                                      // 获取赛季排行榜
+			reputation.POST("/seasons", middleware.AuthMiddleware(), middleware.AdminOnly(), s.reputationSeasonHandlers.StartSeason)                                                         // 开启新赛季（管理员）
+			reputation.POST("/seasons/end", middleware.AuthMiddleware(), middleware.AdminOnly(), s.reputationSeasonHandlers.EndSeason)                                                       // 结束当前赛季（管理员）
 		}
 
+		// 创作者收益相关路由
+		rewards := v1.Group("/rewards")
+		rewards.Use(middleware.AuthMiddleware())
+		{
+			rewards.GET("/earnings", s.creatorRewardHandlers.GetMyEarnings) // 获取创作者收益面板
+			rewards.GET("/claims", s.creatorRewardHandlers.ListMyClaims)    // 获取奖励领取记录
+			rewards.POST("/claim", s.creatorRewardHandlers.ClaimReward)     // 领取互动奖励
+		}
+
+		// 金库相关路由
+		treasury := v1.Group("/treasury")
+		{
+			treasury.GET("", s.treasuryHandlers.GetDashboard)                                                                                             // 获取金库面板
+			treasury.GET("/flows", s.treasuryHandlers.ListFlows)                                                                                          // 获取金库资金流水
+			treasury.POST("/index", middleware.AuthMiddleware(), middleware.AdminOnly(), s.treasuryHandlers.IndexFlows)                                   // 索引金库资金事件（管理员）
+			treasury.GET("/disbursements", s.treasuryHandlers.ListDisbursements)                                                                          // 获取金库拨款申请
+			treasury.GET("/disbursements/:id", s.treasuryHandlers.GetDisbursement)                                                                        // 获取金库拨款申请详情
+			treasury.POST("/disbursements", middleware.AuthMiddleware(), s.treasuryHandlers.CreateDisbursement)                                           // 提案申请金库拨款
+			treasury.POST("/disbursements/:id/chain-proposal", middleware.AuthMiddleware(), middleware.AdminOnly(), s.treasuryHandlers.LinkChainProposal) // 登记拨款对应的链上提案（管理员）
+		}
+
+		// 成就系统相关路由
+		achievements := v1.Group("/achievements")
+		{
+			achievements.GET("", s.achievementHandlers.ListAchievements)                                                             // 获取成就列表
+			achievements.POST("", middleware.AuthMiddleware(), middleware.AdminOnly(), s.achievementHandlers.CreateAchievement)      // 创建成就（管理员）
+			achievements.POST("/mint", middleware.AuthMiddleware(), middleware.AdminOnly(), s.achievementHandlers.MintPending)       // 铸造待发放的成就NFT（管理员）
+			achievements.PUT("/:code", middleware.AuthMiddleware(), middleware.AdminOnly(), s.achievementHandlers.UpdateAchievement) // 更新成就（管理员）
+		}
+
+		// 个性化信息流路由
+		v1.GET("/feed", middleware.AuthMiddleware(), s.feedHandlers.GetFeed) // 获取关注作者发布的内容
+
+		// 标签相关路由
+		tags := v1.Group("/tags")
+		{
+			tags.GET("", s.tagHandlers.Autocomplete)                                                                                                 // 标签自动补全
+			tags.GET("/trending", s.tagHandlers.Trending)                                                                                            // 热门标签
+			tags.GET("/following", middleware.AuthMiddleware(), s.tagHandlers.ListFollowed)                                                          // 获取关注的标签
+			tags.GET("/:slug", s.tagHandlers.GetTag)                                                                                                 // 获取标签详情
+			tags.POST("/:slug/follow", middleware.AuthMiddleware(), s.tagHandlers.Follow)                                                            // 关注标签
+			tags.DELETE("/:slug/follow", middleware.AuthMiddleware(), s.tagHandlers.Unfollow)                                                        // 取消关注标签
+			tags.PUT("/:slug", middleware.AuthMiddleware(), middleware.RequirePermission(models.PermissionTagModerator), s.tagHandlers.Rename)       // 重命名标签（需要标签管理权限）
+			tags.POST("/:slug/merge", middleware.AuthMiddleware(), middleware.RequirePermission(models.PermissionTagModerator), s.tagHandlers.Merge) // 合并标签（需要标签管理权限）
+		}
+
+		// 全文搜索路由
+		v1.GET("/search", s.searchHandlers.Search) // 搜索内容、用户和提案
+
 		// 统计信息路由
-		v1.GET("/stats", handlers.GetStats)
+		v1.GET("/stats", s.statsHandlers.GetStats)            // 获取平台统计
+		v1.GET("/stats/daily", s.statsHandlers.GetDailyStats) // 获取每日统计
 
 		// 缓存管理路由（开发环境）
 		if s.config.Logging.Level == "debug" {
@@ -10,6 +10,7 @@ import (
 	"bondly-api/internal/middleware"
 	"bondly-api/internal/redis"
 	"bondly-api/internal/repositories"
+	"bondly-api/internal/scheduler"
 	"bondly-api/internal/services"
 	"bondly-api/internal/utils"
 	"context"
@@ -27,20 +28,40 @@ type Server struct {
 	cacheService cache.CacheService
 	router       *gin.Engine
 	server       *http.Server
+	scheduler    *scheduler.Scheduler
+	realtime     *services.RealtimeService
 
 	// 依赖注入
-	userHandlers               *handlers.UserHandlers
-	authHandlers               *handlers.AuthHandlers
-	uploadHandlers             *handlers.UploadHandlers
-	walletHandlers             *handlers.WalletHandlers
-	contentHandlers            *handlers.ContentHandlers
-	contentInteractionHandlers *handlers.ContentInteractionHandlers
-	proposalHandlers           *handlers.ProposalHandlers
-	transactionHandlers        *handlers.TransactionHandlers
-	commentHandlers            *handlers.CommentHandlers
-	userFollowHandlers         *handlers.UserFollowHandlers
-	walletBindingHandlers      *handlers.WalletBindingHandlers
-	reputationHandlers         *handlers.ReputationHandlers
+	userHandlers                *handlers.UserHandlers
+	authHandlers                *handlers.AuthHandlers
+	uploadHandlers              *handlers.UploadHandlers
+	walletHandlers              *handlers.WalletHandlers
+	contentHandlers             *handlers.ContentHandlers
+	contentInteractionHandlers  *handlers.ContentInteractionHandlers
+	proposalHandlers            *handlers.ProposalHandlers
+	transactionHandlers         *handlers.TransactionHandlers
+	commentHandlers             *handlers.CommentHandlers
+	userFollowHandlers          *handlers.UserFollowHandlers
+	walletBindingHandlers       *handlers.WalletBindingHandlers
+	reputationHandlers          *handlers.ReputationHandlers
+	delegationHandlers          *handlers.DelegationHandlers
+	voteHandlers                *handlers.VoteHandlers
+	reputationRuleHandlers      *handlers.ReputationRuleHandlers
+	reputationSeasonHandlers    *handlers.ReputationSeasonHandlers
+	userPermissionHandlers      *handlers.UserPermissionHandlers
+	reputationReconcileHandlers *handlers.ReputationReconcileHandlers
+	achievementHandlers         *handlers.AchievementHandlers
+	creatorRewardHandlers       *handlers.CreatorRewardHandlers
+	treasuryHandlers            *handlers.TreasuryHandlers
+	statsHandlers               *handlers.StatsHandlers
+	searchHandlers              *handlers.SearchHandlers
+	tagHandlers                 *handlers.TagHandlers
+	feedHandlers                *handlers.FeedHandlers
+	contentRevisionHandlers     *handlers.ContentRevisionHandlers
+	userBlockHandlers           *handlers.UserBlockHandlers
+	mentionHandlers             *handlers.MentionHandlers
+	notificationHandlers        *handlers.NotificationHandlers
+	realtimeHandlers            *handlers.RealtimeHandlers
 }
 
 func NewServer(cfg *config.Config, db *gorm.DB) *Server {
@@ -59,6 +80,11 @@ func NewServer(cfg *config.Config, db *gorm.DB) *Server {
 	router.Use(middleware.Logger(loggerpkg.Log))
 	router.Use(middleware.CORS(cfg.CORS))
 
+	// 校验配置，启用摘要邮件时必须配置专用的退订链接签名密钥
+	if err := cfg.Digest.Validate(); err != nil {
+		loggerpkg.Log.Fatalf("Invalid digest config: %v", err)
+	}
+
 	// 初始化 Redis 客户端
 	redisClient, err := redis.NewRedisClient(cfg.Redis)
 	if err != nil {
@@ -87,13 +113,23 @@ func NewServer(cfg *config.Config, db *gorm.DB) *Server {
 	userRepo := repositories.NewUserRepository(db)
 	walletService := services.NewWalletService(cfg)
 
+	// 站内通知和实时推送被空投、关注、评论、互动和治理等服务共用，需先于它们初始化
+	contentRepo := repositories.NewContentRepository(db)
+	realtimeService := services.NewRealtimeService(redisClient, contentRepo, cfg.Realtime)
+	userBlockRepo := repositories.NewUserBlockRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	notificationPreferenceRepo := repositories.NewNotificationPreferenceRepository(db)
+	userBlockService := services.NewUserBlockService(userBlockRepo, userRepo)
+	notificationPreferenceService := services.NewNotificationPreferenceService(notificationPreferenceRepo, cfg.Digest)
+	notificationService := services.NewNotificationService(notificationRepo, notificationPreferenceService, userBlockService, realtimeService, redisClient)
+
 	// 初始化以太坊客户端和空投服务
 	ethClient, err := blockchain.NewEthereumClient(cfg.Ethereum)
 	if err != nil {
 		loggerpkg.Log.Warnf("Failed to initialize ethereum client: %v, airdrop service will be disabled", err)
 		ethClient = nil
 	}
-	airdropService := services.NewAirdropService(ethClient, userRepo, cfg)
+	airdropService := services.NewAirdropService(ethClient, userRepo, notificationService, cfg)
 
 	userService := services.NewUserService(userRepo, cacheService, walletService, airdropService)
 	userHandlers := handlers.NewUserHandlers(userService)
@@ -111,22 +147,89 @@ func NewServer(cfg *config.Config, db *gorm.DB) *Server {
 	uploadHandlers := handlers.NewUploadHandlers(uploadService)
 
 	// 初始化新的repositories
-	contentRepo := repositories.NewContentRepository(db)
 	proposalRepo := repositories.NewProposalRepository(db)
 	transactionRepo := repositories.NewTransactionRepository(db)
 	commentRepo := repositories.NewCommentRepository(db)
 	userFollowRepo := repositories.NewUserFollowRepository(db)
 	walletBindingRepo := repositories.NewWalletBindingRepository(db)
+	delegationRepo := repositories.NewDelegationRepository(db)
+	voteRepo := repositories.NewVoteRepository(db)
+	reputationEventRepo := repositories.NewReputationEventRepository(db)
+	reputationRuleRepo := repositories.NewReputationRuleRepository(db)
+	reputationSeasonRepo := repositories.NewReputationSeasonRepository(db)
+	reputationAuditRepo := repositories.NewReputationAuditRepository(db)
+	userPermissionRepo := repositories.NewUserPermissionRepository(db)
+	reputationReconcileRepo := repositories.NewReputationReconcileRepository(db)
+	achievementRepo := repositories.NewAchievementRepository(db)
+	creatorRewardRepo := repositories.NewCreatorRewardRepository(db)
+	treasuryRepo := repositories.NewTreasuryRepository(db)
+	platformStatsRepo := repositories.NewPlatformStatsRepository(db)
+	searchRepo := repositories.NewSearchRepository(db)
+	tagRepo := repositories.NewTagRepository(db)
+	contentRevisionRepo := repositories.NewContentRevisionRepository(db)
+	mentionRepo := repositories.NewMentionRepository(db)
 
 	// 初始化新的services
-	contentService := services.NewContentService(contentRepo)
-	contentInteractionService := services.NewContentInteractionService(db)
-	proposalService := services.NewProposalService(proposalRepo)
+	reputationService := services.NewReputationService(userRepo, reputationEventRepo, reputationRuleRepo, cfg.Ethereum)
+	reputationRuleService := services.NewReputationRuleService(reputationRuleRepo, userRepo, contentRepo, walletBindingRepo, reputationService, cfg.Reputation.ChainBatchEnabled)
+	if err := reputationRuleService.EnsureDefaultRules(context.Background()); err != nil {
+		loggerpkg.Log.Warnf("Failed to ensure default reputation rules: %v", err)
+	}
+	reputationAuditService := services.NewReputationAuditService(reputationAuditRepo, userRepo, reputationService)
+	userPermissionService := services.NewUserPermissionService(userPermissionRepo, userRepo)
+	reputationReconcileService := services.NewReputationReconcileService(reputationReconcileRepo, userRepo, reputationRuleRepo, reputationService, cfg.Reputation)
+	reputationDecayService := services.NewReputationDecayService(userRepo, reputationService, cfg.Reputation)
+	reputationSeasonService := services.NewReputationSeasonService(reputationSeasonRepo, reputationEventRepo, userRepo)
+	var achievementNFT *blockchain.AchievementNFT
+	if cfg.Achievement.MintEnabled {
+		if achievementNFT, err = blockchain.NewAchievementNFT(cfg.Ethereum); err != nil {
+			loggerpkg.Log.Warnf("Failed to initialize AchievementNFT contract: %v, achievement minting will be disabled", err)
+			achievementNFT = nil
+		}
+	}
+	achievementService := services.NewAchievementService(achievementRepo, userRepo, contentRepo, achievementNFT, cfg)
+	if err := achievementService.EnsureDefaultAchievements(context.Background()); err != nil {
+		loggerpkg.Log.Warnf("Failed to ensure default achievements: %v", err)
+	}
+	tagService := services.NewTagService(tagRepo)
+	rankingService := services.NewRankingService(redisClient, contentRepo, cfg.Ranking)
+	feedService := services.NewFeedService(redisClient, contentRepo, userFollowRepo, tagService, cfg.Feed)
+	contentRevisionService := services.NewContentRevisionService(contentRevisionRepo, contentRepo)
+	mentionService := services.NewMentionService(mentionRepo, userRepo, notificationService)
+	contentWorkflowService := services.NewContentWorkflowService(userRepo, userPermissionRepo, emailService, cfg.Content)
+	contentService := services.NewContentService(contentRepo, tagService, feedService, rankingService, contentRevisionService, contentWorkflowService, reputationRuleService, achievementService, mentionService)
+	contentInteractionService := services.NewContentInteractionService(db, rankingService, reputationRuleService, achievementService, notificationService)
+	proposalService := services.NewProposalService(proposalRepo, voteRepo, reputationRuleService, achievementService, notificationService)
 	transactionService := services.NewTransactionService(transactionRepo)
-	commentService := services.NewCommentService(commentRepo)
-	userFollowService := services.NewUserFollowService(userFollowRepo)
+	commentService := services.NewCommentService(commentRepo, rankingService, reputationRuleService, achievementService, mentionService, notificationService, realtimeService, cfg.Comment)
+	userFollowService := services.NewUserFollowService(userFollowRepo, feedService, reputationRuleService, achievementService, notificationService)
 	walletBindingService := services.NewWalletBindingService(walletBindingRepo)
-	reputationService := services.NewReputationService(userRepo, cfg.Ethereum)
+	delegationService := services.NewDelegationService(delegationRepo, userRepo, reputationEventRepo)
+	var stakeReader services.StakeReader
+	var generalStaking *blockchain.GeneralStaking
+	if generalStaking, err = blockchain.NewGeneralStaking(cfg.Ethereum); err != nil {
+		loggerpkg.Log.Warnf("Failed to initialize GeneralStaking contract: %v, quadratic stake voting and staking stats will be disabled", err)
+		generalStaking = nil
+	} else {
+		stakeReader = generalStaking
+	}
+	var interactionStaking *blockchain.InteractionStaking
+	if interactionStaking, err = blockchain.NewInteractionStaking(cfg.Ethereum); err != nil {
+		loggerpkg.Log.Warnf("Failed to initialize InteractionStaking contract: %v, creator rewards will be disabled", err)
+		interactionStaking = nil
+	}
+	creatorRewardService := services.NewCreatorRewardService(creatorRewardRepo, contentRepo, userRepo, walletService, interactionStaking)
+	var treasury *blockchain.BondlyTreasury
+	if treasury, err = blockchain.NewBondlyTreasury(cfg.Ethereum); err != nil {
+		loggerpkg.Log.Warnf("Failed to initialize BondlyTreasury contract: %v, treasury balances and indexing will be disabled", err)
+		treasury = nil
+	}
+	treasuryService := services.NewTreasuryService(treasuryRepo, proposalRepo, treasury, cfg.Treasury)
+	searchService := services.NewSearchService(searchRepo, cfg.Search)
+	platformStatsService := services.NewPlatformStatsService(platformStatsRepo, userRepo, contentRepo, proposalRepo, generalStaking, cacheService, cfg.Stats.AggregateInterval)
+	votingStrategies := services.NewVotingStrategies(stakeReader, cfg.Governance.ConvictionHalfLife)
+	notificationDigestService := services.NewNotificationDigestService(notificationPreferenceRepo, notificationRepo, userFollowRepo, contentRepo, userRepo, notificationPreferenceService, emailService, cfg.Digest)
+	voteService := services.NewVoteService(voteRepo, proposalRepo, proposalService, delegationService, votingStrategies, achievementService, realtimeService)
 
 	// 初始化新的handlers
 	contentHandlers := handlers.NewContentHandlers(contentService)
@@ -136,26 +239,117 @@ func NewServer(cfg *config.Config, db *gorm.DB) *Server {
 	commentHandlers := handlers.NewCommentHandlers(commentService)
 	userFollowHandlers := handlers.NewUserFollowHandlers(userFollowService)
 	walletBindingHandlers := handlers.NewWalletBindingHandlers(walletBindingService)
-	reputationHandlers := handlers.NewReputationHandlers(reputationService)
+	reputationHandlers := handlers.NewReputationHandlers(reputationService, reputationAuditService)
+	delegationHandlers := handlers.NewDelegationHandlers(delegationService)
+	voteHandlers := handlers.NewVoteHandlers(voteService)
+	reputationRuleHandlers := handlers.NewReputationRuleHandlers(reputationRuleService)
+	reputationSeasonHandlers := handlers.NewReputationSeasonHandlers(reputationSeasonService)
+	userPermissionHandlers := handlers.NewUserPermissionHandlers(userPermissionService)
+	reputationReconcileHandlers := handlers.NewReputationReconcileHandlers(reputationReconcileService)
+	achievementHandlers := handlers.NewAchievementHandlers(achievementService)
+	creatorRewardHandlers := handlers.NewCreatorRewardHandlers(creatorRewardService)
+	treasuryHandlers := handlers.NewTreasuryHandlers(treasuryService)
+	statsHandlers := handlers.NewStatsHandlers(platformStatsService)
+	searchHandlers := handlers.NewSearchHandlers(searchService)
+	tagHandlers := handlers.NewTagHandlers(tagService)
+	feedHandlers := handlers.NewFeedHandlers(feedService)
+	contentRevisionHandlers := handlers.NewContentRevisionHandlers(contentRevisionService, contentService)
+	userBlockHandlers := handlers.NewUserBlockHandlers(userBlockService)
+	mentionHandlers := handlers.NewMentionHandlers(mentionService)
+	notificationHandlers := handlers.NewNotificationHandlers(notificationService, notificationPreferenceService)
+	realtimeHandlers := handlers.NewRealtimeHandlers(realtimeService, cfg.CORS.AllowedOrigins)
+
+	// 初始化定时任务
+	jobs := scheduler.New()
+	jobs.EveryFromStart("platform_stats", cfg.Stats.AggregateInterval, func(ctx context.Context) error {
+		_, err := platformStatsService.Aggregate(ctx)
+		return err
+	})
+	jobs.EveryFromStart("content_ranking", cfg.Ranking.RebuildInterval, rankingService.Rebuild)
+	jobs.Every("conviction_tally", cfg.Governance.ConvictionTallyInterval, func(ctx context.Context) error {
+		_, err := voteService.TallyConviction(ctx)
+		return err
+	})
+	jobs.EveryFromStart("proposal_finalize", cfg.Governance.FinalizeInterval, func(ctx context.Context) error {
+		_, err := voteService.FinalizeEnded(ctx)
+		return err
+	})
+	jobs.Every("content_scheduled_publish", cfg.Content.SchedulerInterval, contentService.PublishScheduled)
+	if cfg.Digest.Enabled {
+		jobs.Every("notification_digest", cfg.Digest.CheckInterval, func(ctx context.Context) error {
+			_, err := notificationDigestService.SendDue(ctx)
+			return err
+		})
+	}
+	if cfg.Reputation.ChainBatchEnabled {
+		jobs.Every("reputation_chain_batch", cfg.Reputation.ChainBatchInterval, func(ctx context.Context) error {
+			_, err := reputationRuleService.FlushToChain(ctx)
+			return err
+		})
+	}
+	if cfg.Reputation.DecayEnabled {
+		jobs.Every("reputation_decay", cfg.Reputation.DecayInterval, func(ctx context.Context) error {
+			_, err := reputationDecayService.ApplyDecay(ctx)
+			return err
+		})
+	}
+	if cfg.Reputation.ReconcileEnabled {
+		jobs.Every("reputation_reconcile", cfg.Reputation.ReconcileInterval, func(ctx context.Context) error {
+			_, err := reputationReconcileService.Run(ctx, reputationReconcileService.DefaultOptions(services.ReconcileTriggerScheduled))
+			return err
+		})
+	}
+	if achievementNFT != nil {
+		jobs.Every("achievement_mint", cfg.Achievement.MintInterval, func(ctx context.Context) error {
+			_, err := achievementService.MintPending(ctx)
+			return err
+		})
+	}
+	if cfg.Treasury.IndexEnabled && treasury != nil {
+		jobs.Every("treasury_indexer", cfg.Treasury.IndexInterval, func(ctx context.Context) error {
+			_, err := treasuryService.IndexFlows(ctx)
+			return err
+		})
+	}
 
 	server := &Server{
-		config:                     cfg,
-		db:                         db,
-		redisClient:                redisClient,
-		cacheService:               cacheService,
-		router:                     router,
-		userHandlers:               userHandlers,
-		authHandlers:               authHandlers,
-		uploadHandlers:             uploadHandlers,
-		walletHandlers:             walletHandlers,
-		contentHandlers:            contentHandlers,
-		contentInteractionHandlers: contentInteractionHandlers,
-		proposalHandlers:           proposalHandlers,
-		transactionHandlers:        transactionHandlers,
-		commentHandlers:            commentHandlers,
-		userFollowHandlers:         userFollowHandlers,
-		walletBindingHandlers:      walletBindingHandlers,
-		reputationHandlers:         reputationHandlers,
+		config:                      cfg,
+		db:                          db,
+		redisClient:                 redisClient,
+		cacheService:                cacheService,
+		router:                      router,
+		scheduler:                   jobs,
+		realtime:                    realtimeService,
+		userHandlers:                userHandlers,
+		authHandlers:                authHandlers,
+		uploadHandlers:              uploadHandlers,
+		walletHandlers:              walletHandlers,
+		contentHandlers:             contentHandlers,
+		contentInteractionHandlers:  contentInteractionHandlers,
+		proposalHandlers:            proposalHandlers,
+		transactionHandlers:         transactionHandlers,
+		commentHandlers:             commentHandlers,
+		userFollowHandlers:          userFollowHandlers,
+		walletBindingHandlers:       walletBindingHandlers,
+		reputationHandlers:          reputationHandlers,
+		delegationHandlers:          delegationHandlers,
+		voteHandlers:                voteHandlers,
+		reputationRuleHandlers:      reputationRuleHandlers,
+		reputationSeasonHandlers:    reputationSeasonHandlers,
+		userPermissionHandlers:      userPermissionHandlers,
+		reputationReconcileHandlers: reputationReconcileHandlers,
+		achievementHandlers:         achievementHandlers,
+		creatorRewardHandlers:       creatorRewardHandlers,
+		treasuryHandlers:            treasuryHandlers,
+		statsHandlers:               statsHandlers,
+		searchHandlers:              searchHandlers,
+		tagHandlers:                 tagHandlers,
+		feedHandlers:                feedHandlers,
+		contentRevisionHandlers:     contentRevisionHandlers,
+		userBlockHandlers:           userBlockHandlers,
+		mentionHandlers:             mentionHandlers,
+		notificationHandlers:        notificationHandlers,
+		realtimeHandlers:            realtimeHandlers,
 	}
 
 	// 设置路由
@@ -172,12 +366,20 @@ func NewServer(cfg *config.Config, db *gorm.DB) *Server {
 
 func (s *Server) Start() error {
 	loggerpkg.Log.Infof("Server starting on %s:%s", s.config.Server.Host, s.config.Server.Port)
+	s.scheduler.Start()
+	s.realtime.Start()
 	return s.server.ListenAndServe()
 }
 
 func (s *Server) Shutdown(ctx context.Context) error {
 	loggerpkg.Log.Info("Server shutting down...")
 
+	// 停止定时任务
+	s.scheduler.Stop()
+
+	// 关闭实时推送连接，WebSocket 连接已被接管，不会被 http.Server.Shutdown 关闭
+	s.realtime.Stop()
+
 	// 关闭 Redis 连接
 	if s.redisClient != nil {
 		if err := s.redisClient.Close(); err != nil {
@@ -0,0 +1,427 @@
+package services
+
+import (
+	"bondly-api/config"
+	"bondly-api/internal/blockchain"
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 成就铸造参数
+const (
+	achievementMintBatchSize   = 100             // 单次铸造任务处理的成就数量
+	achievementMaxMintAttempts = 5               // 连续失败达到该次数后标记为 failed，不再重试
+	achievementMintRetryBase   = 5 * time.Minute // 第一次失败后的重试间隔，之后每次翻倍
+	achievementMintRetryMax    = 6 * time.Hour   // 重试间隔上限
+)
+
+// defaultAchievements 默认成就，启动时写入数据库，之后可由管理员调整
+var defaultAchievements = []models.Achievement{
+	{Code: "first_post", Name: "初次发声", Description: "发布第一篇内容", Metric: models.AchievementMetricPostsPublished, Threshold: 1, ChainAchievementID: 1, Enabled: true},
+	{Code: "prolific_author", Name: "活跃作者", Description: "发布 50 篇内容", Metric: models.AchievementMetricPostsPublished, Threshold: 50, ChainAchievementID: 2, Enabled: true},
+	{Code: "likes_100", Name: "点赞达人", Description: "内容累计获得 100 个点赞", Metric: models.AchievementMetricLikesReceived, Threshold: 100, ChainAchievementID: 3, Enabled: true},
+	{Code: "first_comment", Name: "初次互动", Description: "发表第一条评论", Metric: models.AchievementMetricCommentsCreated, Threshold: 1, ChainAchievementID: 4, Enabled: true},
+	{Code: "followers_100", Name: "小有名气", Description: "拥有 100 位粉丝", Metric: models.AchievementMetricFollowers, Threshold: 100, ChainAchievementID: 5, Enabled: true},
+	{Code: "voter_10", Name: "积极公民", Description: "参与 10 个提案的投票", Metric: models.AchievementMetricProposalVotes, Threshold: 10, ChainAchievementID: 6, Enabled: true},
+	{Code: "proposal_passed", Name: "社区推动者", Description: "发起的提案获得通过", Metric: models.AchievementMetricProposalsPassed, Threshold: 1, ChainAchievementID: 7, Enabled: true},
+}
+
+// achievementMetrics 支持的成就统计指标
+var achievementMetrics = []string{
+	models.AchievementMetricPostsPublished,
+	models.AchievementMetricLikesReceived,
+	models.AchievementMetricCommentsCreated,
+	models.AchievementMetricFollowers,
+	models.AchievementMetricProposalVotes,
+	models.AchievementMetricProposalsPassed,
+}
+
+// AchievementService 成就服务，监听平台活动并在用户指标达到阈值时授予成就，可选铸造 AchievementNFT
+// 活动钩子允许在 nil 接收者上调用，此时不做任何处理，便于独立工具复用业务服务
+type AchievementService struct {
+	achievementRepo *repositories.AchievementRepository
+	userRepo        *repositories.UserRepository
+	contentRepo     *repositories.ContentRepository
+	achievementNFT  *blockchain.AchievementNFT
+	relayWalletKey  string
+	mintEnabled     bool
+}
+
+// NewAchievementService 创建成就服务，achievementNFT 为 nil 时不铸造 NFT
+func NewAchievementService(
+	achievementRepo *repositories.AchievementRepository,
+	userRepo *repositories.UserRepository,
+	contentRepo *repositories.ContentRepository,
+	achievementNFT *blockchain.AchievementNFT,
+	cfg *config.Config,
+) *AchievementService {
+	return &AchievementService{
+		achievementRepo: achievementRepo,
+		userRepo:        userRepo,
+		contentRepo:     contentRepo,
+		achievementNFT:  achievementNFT,
+		relayWalletKey:  cfg.Ethereum.RelayWalletKey,
+		mintEnabled:     cfg.Achievement.MintEnabled && achievementNFT != nil,
+	}
+}
+
+// EnsureDefaultAchievements 写入缺失的默认成就
+func (s *AchievementService) EnsureDefaultAchievements(ctx context.Context) error {
+	achievements := make([]models.Achievement, len(defaultAchievements))
+	copy(achievements, defaultAchievements)
+	return s.achievementRepo.EnsureAchievements(achievements)
+}
+
+// ListAchievements 获取成就定义，includeDisabled 为 true 时包含已停用的成就
+func (s *AchievementService) ListAchievements(ctx context.Context, includeDisabled bool) ([]models.Achievement, error) {
+	return s.achievementRepo.List(!includeDisabled)
+}
+
+// CreateAchievement 创建成就定义
+func (s *AchievementService) CreateAchievement(ctx context.Context, req *dto.CreateAchievementRequest) (*models.Achievement, error) {
+	if !isAchievementMetric(req.Metric) {
+		return nil, errors.New("unknown achievement metric")
+	}
+	if _, err := s.achievementRepo.GetByCode(req.Code); err == nil {
+		return nil, errors.New("achievement already exists")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	achievement := &models.Achievement{
+		Code:               req.Code,
+		Name:               req.Name,
+		Description:        req.Description,
+		Metric:             req.Metric,
+		Threshold:          req.Threshold,
+		ChainAchievementID: req.ChainAchievementID,
+		TokenURI:           req.TokenURI,
+		Enabled:            true,
+	}
+	if err := s.achievementRepo.Create(achievement); err != nil {
+		return nil, err
+	}
+
+	loggerpkg.NewBusinessLogger(ctx).BusinessLogic("创建成就", map[string]interface{}{
+		"code":      achievement.Code,
+		"metric":    achievement.Metric,
+		"threshold": achievement.Threshold,
+	})
+
+	return achievement, nil
+}
+
+// UpdateAchievement 更新成就定义，已授予的成就不受影响
+func (s *AchievementService) UpdateAchievement(ctx context.Context, code string, req *dto.UpdateAchievementRequest) (*models.Achievement, error) {
+	achievement, err := s.achievementRepo.GetByCode(code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("achievement not found")
+		}
+		return nil, err
+	}
+
+	if req.Name != nil {
+		achievement.Name = *req.Name
+	}
+	if req.Description != nil {
+		achievement.Description = *req.Description
+	}
+	if req.Threshold != nil {
+		if *req.Threshold < 1 {
+			return nil, errors.New("threshold must be positive")
+		}
+		achievement.Threshold = *req.Threshold
+	}
+	if req.ChainAchievementID != nil {
+		if *req.ChainAchievementID < 0 {
+			return nil, errors.New("chain achievement id must not be negative")
+		}
+		achievement.ChainAchievementID = *req.ChainAchievementID
+	}
+	if req.TokenURI != nil {
+		achievement.TokenURI = *req.TokenURI
+	}
+	if req.Enabled != nil {
+		achievement.Enabled = *req.Enabled
+	}
+
+	if err := s.achievementRepo.Update(achievement); err != nil {
+		return nil, err
+	}
+
+	loggerpkg.NewBusinessLogger(ctx).BusinessLogic("更新成就", map[string]interface{}{
+		"code":      achievement.Code,
+		"threshold": achievement.Threshold,
+		"enabled":   achievement.Enabled,
+	})
+
+	return achievement, nil
+}
+
+// GetUserAchievements 获取用户获得的成就
+func (s *AchievementService) GetUserAchievements(ctx context.Context, userID int64) ([]models.UserAchievement, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	return s.achievementRepo.ListByUser(userID)
+}
+
+// EvaluateUser 按全部指标重新评估用户的成就，用于补发历史活动对应的成就
+func (s *AchievementService) EvaluateUser(ctx context.Context, userID int64) ([]models.UserAchievement, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	return s.evaluate(ctx, userID, achievementMetrics)
+}
+
+// OnContentPublished 内容发布
+func (s *AchievementService) OnContentPublished(ctx context.Context, content *models.Content) {
+	if s == nil {
+		return
+	}
+	s.Evaluate(ctx, content.AuthorID, models.AchievementMetricPostsPublished)
+}
+
+// OnContentInteraction 内容互动，点赞计入内容作者的成就
+func (s *AchievementService) OnContentInteraction(ctx context.Context, contentID int64, interactionType string) {
+	if s == nil || interactionType != "like" {
+		return
+	}
+
+	content, err := s.contentRepo.GetByID(contentID)
+	if err != nil {
+		loggerpkg.NewBusinessLogger(ctx).DatabaseError("select", "contents", "GetByID", err)
+		return
+	}
+	s.Evaluate(ctx, content.AuthorID, models.AchievementMetricLikesReceived)
+}
+
+// OnCommentCreated 发表评论
+func (s *AchievementService) OnCommentCreated(ctx context.Context, comment *models.Comment) {
+	if s == nil {
+		return
+	}
+	s.Evaluate(ctx, comment.AuthorID, models.AchievementMetricCommentsCreated)
+}
+
+// OnFollow 关注用户，计入被关注者的成就
+func (s *AchievementService) OnFollow(ctx context.Context, followedID int64) {
+	if s == nil {
+		return
+	}
+	s.Evaluate(ctx, followedID, models.AchievementMetricFollowers)
+}
+
+// OnVoteCast 对提案投票
+func (s *AchievementService) OnVoteCast(ctx context.Context, voterID int64) {
+	if s == nil {
+		return
+	}
+	s.Evaluate(ctx, voterID, models.AchievementMetricProposalVotes)
+}
+
+// OnProposalPassed 提案通过
+func (s *AchievementService) OnProposalPassed(ctx context.Context, proposal *models.Proposal) {
+	if s == nil {
+		return
+	}
+	s.Evaluate(ctx, proposal.ProposerID, models.AchievementMetricProposalsPassed)
+}
+
+// Evaluate 评估用户在指定指标上的成就，失败只记录日志，不影响触发方的主流程
+func (s *AchievementService) Evaluate(ctx context.Context, userID int64, metrics ...string) {
+	if s == nil {
+		return
+	}
+
+	if _, err := s.evaluate(ctx, userID, metrics); err != nil {
+		loggerpkg.NewBusinessLogger(ctx).ThirdPartyError("achievement", "evaluate", map[string]interface{}{
+			"user_id": userID,
+			"metrics": metrics,
+		}, err)
+	}
+}
+
+// evaluate 统计用户指标并授予达到阈值的成就，返回本次新获得的成就
+func (s *AchievementService) evaluate(ctx context.Context, userID int64, metrics []string) ([]models.UserAchievement, error) {
+	achievements, err := s.achievementRepo.ListByMetrics(metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	awarded := []models.UserAchievement{}
+	values := make(map[string]int64)
+	for _, achievement := range achievements {
+		value, ok := values[achievement.Metric]
+		if !ok {
+			value, err = s.achievementRepo.CountMetric(userID, achievement.Metric)
+			if err != nil {
+				return awarded, err
+			}
+			values[achievement.Metric] = value
+		}
+		if value < achievement.Threshold {
+			continue
+		}
+
+		userAchievement := models.UserAchievement{
+			UserID:        userID,
+			AchievementID: achievement.ID,
+			AwardedAt:     time.Now(),
+			MintStatus:    achievementMintStatus(&achievement, s.mintEnabled),
+		}
+		created, err := s.achievementRepo.Award(&userAchievement)
+		if err != nil {
+			return awarded, err
+		}
+		if !created {
+			continue
+		}
+
+		userAchievement.Achievement = achievement
+		awarded = append(awarded, userAchievement)
+
+		loggerpkg.NewBusinessLogger(ctx).BusinessLogic("授予成就", map[string]interface{}{
+			"user_id":     userID,
+			"achievement": achievement.Code,
+			"metric":      achievement.Metric,
+			"value":       value,
+			"mint_status": userAchievement.MintStatus,
+		})
+	}
+
+	return awarded, nil
+}
+
+// MintPending 将待铸造的成就铸造为 AchievementNFT 发放到用户钱包
+func (s *AchievementService) MintPending(ctx context.Context) (*dto.AchievementMintResult, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	if s.achievementNFT == nil {
+		return nil, errors.New("achievement nft not available")
+	}
+	if s.relayWalletKey == "" {
+		return nil, errors.New("relay wallet not configured")
+	}
+
+	pending, err := s.achievementRepo.ListPendingMints(time.Now(), achievementMintBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.AchievementMintResult{TxHashes: []string{}}
+	for _, userAchievement := range pending {
+		user, err := s.userRepo.GetByID(userAchievement.UserID)
+		if err != nil {
+			return result, err
+		}
+		if user.WalletAddress == nil {
+			continue
+		}
+
+		walletAddress := *user.WalletAddress
+		chainID := userAchievement.Achievement.ChainAchievementID
+
+		// 上次交易可能在等待回执超时后才上链，先查询链上是否已持有
+		owned, err := s.achievementNFT.HasAchievement(ctx, walletAddress, chainID)
+		if err != nil {
+			s.markMintFailed(ctx, &userAchievement, "hasAchievement", "", err)
+			result.Failed++
+			continue
+		}
+
+		now := time.Now()
+		if owned {
+			// 合约限制同一地址只能领取一次同一成就
+			if err := s.achievementRepo.MarkMint(userAchievement.ID, "minted", "", "", &now); err != nil {
+				return result, err
+			}
+			result.Existing++
+			continue
+		}
+
+		// 只有确认交易执行成功后才标记为已铸造
+		txHash, err := s.achievementNFT.MintAchievement(ctx, walletAddress, chainID, userAchievement.Achievement.TokenURI, s.relayWalletKey)
+		if err != nil {
+			s.markMintFailed(ctx, &userAchievement, "mintAchievement", txHash, err)
+			result.Failed++
+			continue
+		}
+
+		if err := s.achievementRepo.MarkMint(userAchievement.ID, "minted", txHash, "", &now); err != nil {
+			return result, err
+		}
+		result.Minted++
+		result.TxHashes = append(result.TxHashes, txHash)
+	}
+
+	bizLog.BusinessLogic("成就 NFT 批量铸造", map[string]interface{}{
+		"minted":   result.Minted,
+		"existing": result.Existing,
+		"failed":   result.Failed,
+	})
+
+	return result, nil
+}
+
+// markMintFailed 记录铸造错误和失败次数，按退避时间等待下一批次重试，达到上限后标记为 failed
+func (s *AchievementService) markMintFailed(ctx context.Context, userAchievement *models.UserAchievement, method, txHash string, err error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+	attempts := userAchievement.MintAttempts + 1
+	status, nextMintAt := achievementMintRetry(attempts, time.Now())
+	bizLog.ThirdPartyError("blockchain", method, map[string]interface{}{
+		"user_id":        userAchievement.UserID,
+		"achievement_id": userAchievement.AchievementID,
+		"attempts":       attempts,
+		"mint_status":    status,
+		"tx_hash":        txHash,
+	}, err)
+	if markErr := s.achievementRepo.MarkMintAttemptFailed(userAchievement.ID, status, attempts, txHash, err.Error(), nextMintAt); markErr != nil {
+		bizLog.DatabaseError("update", "user_achievements", "MarkMintAttemptFailed", markErr)
+	}
+}
+
+// achievementMintRetry 第 attempts 次失败后的铸造状态和下次重试时间：按指数退避重试，达到上限后为 failed
+func achievementMintRetry(attempts int, now time.Time) (string, *time.Time) {
+	if attempts >= achievementMaxMintAttempts {
+		return "failed", nil
+	}
+	delay := achievementMintRetryBase << (attempts - 1)
+	if delay > achievementMintRetryMax {
+		delay = achievementMintRetryMax
+	}
+	next := now.Add(delay)
+	return "pending", &next
+}
+
+// achievementMintStatus 新授予成就的铸造状态，未配置链上成就或未开启铸造时不铸造
+func achievementMintStatus(achievement *models.Achievement, mintEnabled bool) string {
+	if mintEnabled && achievement.ChainAchievementID > 0 {
+		return "pending"
+	}
+	return "none"
+}
+
+// isAchievementMetric 判断是否为支持的成就指标
+func isAchievementMetric(metric string) bool {
+	for _, m := range achievementMetrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
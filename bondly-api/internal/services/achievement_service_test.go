@@ -0,0 +1,41 @@
+package services
+
+import (
+	"bondly-api/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAchievementMintStatus(t *testing.T) {
+	onChain := &models.Achievement{ChainAchievementID: 1}
+	offChain := &models.Achievement{ChainAchievementID: 0}
+
+	assert.Equal(t, "pending", achievementMintStatus(onChain, true))
+	assert.Equal(t, "none", achievementMintStatus(onChain, false), "未开启铸造")
+	assert.Equal(t, "none", achievementMintStatus(offChain, true), "未配置链上成就")
+}
+
+func TestDefaultAchievementsUseKnownMetrics(t *testing.T) {
+	for _, achievement := range defaultAchievements {
+		assert.True(t, isAchievementMetric(achievement.Metric), achievement.Code)
+		assert.Positive(t, achievement.Threshold, achievement.Code)
+	}
+	assert.False(t, isAchievementMetric("unknown"))
+}
+
+func TestAchievementMintRetry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	status, next := achievementMintRetry(1, now)
+	assert.Equal(t, "pending", status)
+	assert.Equal(t, now.Add(achievementMintRetryBase), *next)
+
+	_, next = achievementMintRetry(3, now)
+	assert.Equal(t, now.Add(4*achievementMintRetryBase), *next, "每次失败后间隔翻倍")
+
+	status, next = achievementMintRetry(achievementMaxMintAttempts, now)
+	assert.Equal(t, "failed", status, "达到上限后不再重试")
+	assert.Nil(t, next)
+}
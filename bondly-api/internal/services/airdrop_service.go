@@ -13,16 +13,18 @@ import (
 )
 
 type AirdropService struct {
-	ethClient *blockchain.EthereumClient
-	userRepo  *repositories.UserRepository
-	config    *config.Config
+	ethClient     *blockchain.EthereumClient
+	userRepo      *repositories.UserRepository
+	notifications *NotificationService
+	config        *config.Config
 }
 
-func NewAirdropService(ethClient *blockchain.EthereumClient, userRepo *repositories.UserRepository, cfg *config.Config) *AirdropService {
+func NewAirdropService(ethClient *blockchain.EthereumClient, userRepo *repositories.UserRepository, notifications *NotificationService, cfg *config.Config) *AirdropService {
 	return &AirdropService{
-		ethClient: ethClient,
-		userRepo:  userRepo,
-		config:    cfg,
+		ethClient:     ethClient,
+		userRepo:      userRepo,
+		notifications: notifications,
+		config:        cfg,
 	}
 }
 
@@ -122,7 +124,7 @@ func (s *AirdropService) AirdropToNewUser(ctx context.Context, userID int64, wal
 	}
 
 	// 6. 异步等待交易确认
-	go s.waitForTransactionConfirmation(ctx, txHash, airdropRecord.ID)
+	go s.waitForTransactionConfirmation(ctx, txHash, airdropRecord.ID, userID)
 
 	bizLog.BusinessLogic("新用户空投BOND代币完成", map[string]interface{}{
 		"user_id":        userID,
@@ -134,8 +136,8 @@ func (s *AirdropService) AirdropToNewUser(ctx context.Context, userID int64, wal
 	return nil
 }
 
-// waitForTransactionConfirmation 异步等待交易确认
-func (s *AirdropService) waitForTransactionConfirmation(ctx context.Context, txHash string, recordID int64) {
+// waitForTransactionConfirmation 异步等待交易确认，确认结果通知空投用户
+func (s *AirdropService) waitForTransactionConfirmation(ctx context.Context, txHash string, recordID int64, userID int64) {
 	bizLog := logger.NewBusinessLogger(ctx)
 
 	bizLog.BusinessLogic("开始等待交易确认", map[string]interface{}{
@@ -155,6 +157,7 @@ func (s *AirdropService) waitForTransactionConfirmation(ctx context.Context, txH
 		if updateErr := s.userRepo.UpdateAirdropRecordStatus(recordID, "failed"); updateErr != nil {
 			bizLog.DatabaseError("update", "airdrop_records", "更新空投记录状态失败", updateErr)
 		}
+		s.notifyAirdrop(ctx, userID, recordID, txHash, "failed")
 		return
 	}
 
@@ -178,6 +181,18 @@ func (s *AirdropService) waitForTransactionConfirmation(ctx context.Context, txH
 	if err := s.userRepo.UpdateAirdropRecordStatus(recordID, status); err != nil {
 		bizLog.DatabaseError("update", "airdrop_records", "更新空投记录状态失败", err)
 	}
+	s.notifyAirdrop(ctx, userID, recordID, txHash, status)
+}
+
+// notifyAirdrop 通知用户空投结果；等待确认可能晚于请求结束，不随请求取消
+func (s *AirdropService) notifyAirdrop(ctx context.Context, userID, recordID int64, txHash, status string) {
+	s.notifications.Notify(context.WithoutCancel(ctx), &models.Notification{
+		UserID:      userID,
+		Type:        NotificationTypeAirdrop,
+		SubjectType: "airdrop",
+		SubjectID:   recordID,
+		Data:        map[string]string{"status": status, "tx_hash": txHash},
+	})
 }
 
 // GetAirdropStatus 获取用户空投状态
@@ -333,7 +348,7 @@ func (s *AirdropService) executeAirdrop(ctx context.Context, userID int64, targe
 	}
 
 	// 6. 异步等待交易确认
-	go s.waitForTransactionConfirmation(ctx, txHash, airdropRecord.ID)
+	go s.waitForTransactionConfirmation(ctx, txHash, airdropRecord.ID, userID)
 
 	bizLog.BusinessLogic("空投BOND代币完成", map[string]interface{}{
 		"user_id":        userID,
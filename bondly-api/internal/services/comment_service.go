@@ -1,58 +1,572 @@
 package services
 
 import (
+	"bondly-api/config"
 	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
 	"bondly-api/internal/models"
 	"bondly-api/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
+
+	"gorm.io/gorm"
+)
+
+// 评论状态
+const (
+	CommentStatusVisible = "visible"
+	CommentStatusHidden  = "hidden"  // 被审核员隐藏，保留节点
+	CommentStatusDeleted = "deleted" // 被作者删除，保留节点以展示回复
+)
+
+// 评论审核操作
+const (
+	CommentActionHide   = "hide"
+	CommentActionUnhide = "unhide"
+	CommentActionLock   = "lock"
+	CommentActionUnlock = "unlock"
+	CommentActionPin    = "pin"
+	CommentActionUnpin  = "unpin"
+)
+
+// 已删除和被隐藏评论在列表中展示的内容
+const (
+	deletedCommentPlaceholder = "[deleted]"
+	hiddenCommentPlaceholder  = "[hidden]"
+)
+
+// 评论树排序方式
+const (
+	CommentSortOldest = "oldest" // 按发布时间正序
+	CommentSortNewest = "newest" // 按发布时间倒序
+	CommentSortTop    = "top"    // 按点赞数倒序
+)
+
+// 评论树默认参数
+const (
+	defaultCommentTreeDepth  = 3  // 默认展开的回复层数
+	defaultRepliesPerComment = 5  // 每条评论默认展开的直接回复数
+	maxRepliesPerComment     = 50 // 每条评论最多展开的直接回复数
 )
 
+// CommentTreeOptions 评论树查询参数
+type CommentTreeOptions struct {
+	Sort         string // oldest, newest, top，默认 oldest
+	Cursor       string // 上一页返回的 next_cursor，第一页为空
+	Limit        int    // 本层评论数
+	Depth        int    // 在本层评论之下展开的回复层数，0 表示不展开
+	RepliesLimit int    // 每条评论展开的直接回复数
+}
+
 type CommentService struct {
-	repo *repositories.CommentRepository
+	repo            *repositories.CommentRepository
+	ranking         *RankingService
+	reputationRules *ReputationRuleService
+	achievements    *AchievementService
+	mentions        *MentionService
+	notifications   *NotificationService
+	realtime        *RealtimeService
+	maxTreeDepth    int
 }
 
-func NewCommentService(repo *repositories.CommentRepository) *CommentService {
-	return &CommentService{repo: repo}
+func NewCommentService(repo *repositories.CommentRepository, ranking *RankingService, reputationRules *ReputationRuleService, achievements *AchievementService, mentions *MentionService, notifications *NotificationService, realtime *RealtimeService, cfg config.CommentConfig) *CommentService {
+	return &CommentService{repo: repo, ranking: ranking, reputationRules: reputationRules, achievements: achievements, mentions: mentions, notifications: notifications, realtime: realtime, maxTreeDepth: cfg.MaxTreeDepth}
 }
 
 func (s *CommentService) CreateComment(req *dto.CreateCommentRequest, authorID int64) (*models.Comment, error) {
+	// 回复必须与父评论属于同一内容，未指定内容时沿用父评论的内容
+	var parent *models.Comment
+	if req.ParentCommentID != nil {
+		var err error
+		parent, err = s.repo.GetByID(*req.ParentCommentID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("parent comment not found")
+			}
+			return nil, err
+		}
+		if req.PostID == nil && req.ContentID == nil {
+			req.PostID, req.ContentID = parent.PostID, parent.ContentID
+		} else if !sameInt64(req.PostID, parent.PostID) || !sameInt64(req.ContentID, parent.ContentID) {
+			return nil, errors.New("parent comment belongs to another thread")
+		}
+		if parent.Status == CommentStatusDeleted {
+			return nil, errors.New("parent comment deleted")
+		}
+		// 父评论或其任一祖先被锁定时不能回复
+		locked, err := s.repo.HasLocked(commentPathIDs(parent.Path))
+		if err != nil {
+			return nil, err
+		}
+		if locked {
+			return nil, errors.New("thread locked")
+		}
+	}
+
 	comment := &models.Comment{
 		PostID:          req.PostID,
 		ContentID:       req.ContentID,
 		AuthorID:        authorID,
 		Content:         req.Content,
 		ParentCommentID: req.ParentCommentID,
+		Status:          CommentStatusVisible,
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
 	if err := s.repo.Create(comment); err != nil {
 		return nil, err
 	}
+	s.reputationRules.OnCommentCreated(context.Background(), comment)
+	s.achievements.OnCommentCreated(context.Background(), comment)
+	s.mentions.OnCommentSaved(context.Background(), comment)
+	s.notifyNewComment(context.Background(), comment, parent)
+	s.publishComment(context.Background(), RealtimeEventCommentCreated, comment)
+	if comment.ContentID != nil {
+		s.ranking.OnContentChanged(context.Background(), *comment.ContentID)
+	}
 	return comment, nil
 }
 
-func (s *CommentService) GetComment(id int64) (*models.Comment, error) {
-	return s.repo.GetByID(id)
+// notifyNewComment 通知被回复的评论作者和内容作者，二者为同一人时只发送回复通知
+func (s *CommentService) notifyNewComment(ctx context.Context, comment *models.Comment, parent *models.Comment) {
+	if s.notifications == nil {
+		return
+	}
+	var parentAuthorID int64
+	if parent != nil {
+		parentAuthorID = parent.AuthorID
+		s.notifications.Notify(ctx, &models.Notification{
+			UserID:      parentAuthorID,
+			Type:        NotificationTypeReply,
+			ActorID:     comment.AuthorID,
+			SubjectType: "comment",
+			SubjectID:   comment.ID,
+		})
+	}
+	if comment.ContentID == nil {
+		return
+	}
+	contentAuthorID, err := s.repo.GetContentAuthorID(*comment.ContentID)
+	if err != nil {
+		loggerpkg.NewBusinessLogger(ctx).DatabaseError("select", "contents", "GetContentAuthorID", err)
+		return
+	}
+	if contentAuthorID != parentAuthorID {
+		s.notifications.Notify(ctx, &models.Notification{
+			UserID:      contentAuthorID,
+			Type:        NotificationTypeComment,
+			ActorID:     comment.AuthorID,
+			SubjectType: "comment",
+			SubjectID:   comment.ID,
+		})
+	}
+}
+
+// GetComment 获取评论详情，viewerID 非 0 时标记该用户是否已点赞
+func (s *CommentService) GetComment(id int64, viewerID int64) (*models.Comment, error) {
+	comment, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	comments := []models.Comment{*comment}
+	s.markLiked(comments, viewerID)
+	redactComments(comments)
+	return &comments[0], nil
 }
 
-func (s *CommentService) ListComments(postID int64, contentID int64, parentCommentID *int64, page, limit int) ([]models.Comment, int64, error) {
+// ListComments 分页获取评论列表，viewerID 非 0 时标记该用户是否已点赞
+func (s *CommentService) ListComments(postID int64, contentID int64, parentCommentID *int64, page, limit int, viewerID int64) ([]models.Comment, int64, error) {
 	offset := (page - 1) * limit
-	return s.repo.List(postID, contentID, parentCommentID, offset, limit)
+	comments, total, err := s.repo.List(postID, contentID, parentCommentID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.markLiked(comments, viewerID)
+	redactComments(comments)
+	return comments, total, nil
+}
+
+// EditComment 作者修改评论内容，编辑前的内容记入编辑历史；内容未变化时不产生记录
+func (s *CommentService) EditComment(id int64, userID int64, content string) (*models.Comment, error) {
+	comment, err := s.getOwnComment(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Status == CommentStatusDeleted {
+		return nil, errors.New("comment deleted")
+	}
+	if content == comment.Content {
+		return s.GetComment(id, userID)
+	}
+	if err := s.repo.Edit(id, content, time.Now()); err != nil {
+		return nil, err
+	}
+	edited, err := s.GetComment(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	// 编辑中新增的提及同样通知
+	s.mentions.OnCommentSaved(context.Background(), edited)
+	s.publishComment(context.Background(), RealtimeEventCommentUpdated, edited)
+	return edited, nil
+}
+
+// ListCommentEdits 获取评论的编辑历史，已删除或被隐藏的评论不公开历史
+func (s *CommentService) ListCommentEdits(id int64) ([]models.CommentEdit, error) {
+	comment, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("comment not found")
+		}
+		return nil, err
+	}
+	if comment.Status != CommentStatusVisible {
+		return nil, errors.New("comment not found")
+	}
+	return s.repo.ListEdits(id)
 }
 
+// DeleteComment 作者软删除自己的评论，节点保留为 [deleted] 使回复仍可展示；重复删除不报错
 func (s *CommentService) DeleteComment(id int64, authorID int64) error {
-	return s.repo.Delete(id, authorID)
+	comment, err := s.getOwnComment(id, authorID)
+	if err != nil {
+		return err
+	}
+	if comment.Status == CommentStatusDeleted {
+		return nil
+	}
+	if err := s.repo.SoftDelete(id); err != nil {
+		return err
+	}
+	if comment.ContentID != nil {
+		s.ranking.OnContentChanged(context.Background(), *comment.ContentID)
+	}
+	s.publishComment(context.Background(), RealtimeEventCommentDeleted, comment)
+	return nil
+}
+
+// ModerateComment 审核员隐藏、锁定或置顶评论
+func (s *CommentService) ModerateComment(ctx context.Context, id int64, moderatorID int64, action string, reason string) (*models.Comment, error) {
+	comment, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("comment not found")
+		}
+		return nil, err
+	}
+	updates, err := commentModerationUpdates(comment, action)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateModeration(id, updates); err != nil {
+		return nil, err
+	}
+	if _, changed := updates["status"]; changed && comment.ContentID != nil {
+		s.ranking.OnContentChanged(ctx, *comment.ContentID)
+	}
+
+	loggerpkg.NewBusinessLogger(ctx).BusinessLogic("审核评论", map[string]interface{}{
+		"comment_id":   id,
+		"moderator_id": moderatorID,
+		"action":       action,
+		"reason":       reason,
+	})
+	moderated, err := s.GetComment(id, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+	s.publishComment(ctx, RealtimeEventCommentUpdated, moderated)
+	return moderated, nil
+}
+
+// publishComment 向内容评论主题推送评论变更，推送的是脱敏后的最新评论；只属于帖子的评论没有对应主题
+func (s *CommentService) publishComment(ctx context.Context, event string, comment *models.Comment) {
+	if s.realtime == nil || comment.ContentID == nil {
+		return
+	}
+	latest, err := s.GetComment(comment.ID, 0)
+	if err != nil {
+		loggerpkg.NewBusinessLogger(ctx).DatabaseError("select", "comments", "GetComment", err)
+		return
+	}
+	s.realtime.Publish(ctx, ContentCommentsTopic(*comment.ContentID), event, latest)
+}
+
+// getOwnComment 获取评论并校验是否为 userID 所发
+func (s *CommentService) getOwnComment(id int64, userID int64) (*models.Comment, error) {
+	comment, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("comment not found")
+		}
+		return nil, err
+	}
+	if comment.AuthorID != userID {
+		return nil, errors.New("forbidden")
+	}
+	return comment, nil
+}
+
+// commentModerationUpdates 计算审核操作需要更新的字段
+func commentModerationUpdates(comment *models.Comment, action string) (map[string]interface{}, error) {
+	switch action {
+	case CommentActionHide:
+		if comment.Status == CommentStatusDeleted {
+			return nil, errors.New("comment deleted")
+		}
+		return map[string]interface{}{"status": CommentStatusHidden}, nil
+	case CommentActionUnhide:
+		if comment.Status != CommentStatusHidden {
+			return map[string]interface{}{}, nil
+		}
+		return map[string]interface{}{"status": CommentStatusVisible}, nil
+	case CommentActionLock:
+		return map[string]interface{}{"locked": true}, nil
+	case CommentActionUnlock:
+		return map[string]interface{}{"locked": false}, nil
+	case CommentActionPin:
+		if comment.ParentCommentID != nil {
+			return nil, errors.New("only top-level comments can be pinned")
+		}
+		if comment.Status == CommentStatusDeleted {
+			return nil, errors.New("comment deleted")
+		}
+		return map[string]interface{}{"pinned": true}, nil
+	case CommentActionUnpin:
+		return map[string]interface{}{"pinned": false}, nil
+	default:
+		return nil, errors.New("invalid action")
+	}
+}
+
+// LikeComment 点赞评论，重复点赞不重复计数；返回最新点赞数
+func (s *CommentService) LikeComment(id int64, userID int64) (int, error) {
+	likes, err := s.repo.Like(id, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, errors.New("comment not found")
+	}
+	return likes, err
+}
+
+// UnlikeComment 取消点赞，未点赞时不变；返回最新点赞数
+func (s *CommentService) UnlikeComment(id int64, userID int64) (int, error) {
+	likes, err := s.repo.Unlike(id, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, errors.New("comment not found")
+	}
+	return likes, err
+}
+
+// markLiked 为评论及其已展开的回复标记 viewerID 是否已点赞，查询失败时保持未点赞
+func (s *CommentService) markLiked(comments []models.Comment, viewerID int64) {
+	if viewerID == 0 || len(comments) == 0 {
+		return
+	}
+	ids := collectCommentIDs(comments, nil)
+	liked, err := s.repo.ListLikedIDs(viewerID, ids)
+	if err != nil {
+		return
+	}
+	applyLiked(comments, liked)
 }
 
-func (s *CommentService) LikeComment(id int64) error {
-	return s.repo.Like(id)
+// collectCommentIDs 收集评论及其嵌套回复的ID
+func collectCommentIDs(comments []models.Comment, ids []int64) []int64 {
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+		ids = collectCommentIDs(comment.ChildComments, ids)
+	}
+	return ids
+}
+
+// applyLiked 按点赞集合设置评论及其嵌套回复的 Liked
+func applyLiked(comments []models.Comment, liked map[int64]bool) {
+	for i := range comments {
+		comments[i].Liked = liked[comments[i].ID]
+		applyLiked(comments[i].ChildComments, liked)
+	}
 }
 
-func (s *CommentService) UnlikeComment(id int64) error {
-	return s.repo.Unlike(id)
+// redactComments 将已删除和被隐藏的评论及其嵌套回复替换为占位内容并隐去作者
+func redactComments(comments []models.Comment) {
+	for i := range comments {
+		switch comments[i].Status {
+		case CommentStatusDeleted:
+			comments[i].Content = deletedCommentPlaceholder
+		case CommentStatusHidden:
+			comments[i].Content = hiddenCommentPlaceholder
+		default:
+			redactComments(comments[i].ChildComments)
+			continue
+		}
+		comments[i].AuthorID = 0
+		comments[i].Author = models.User{}
+		redactComments(comments[i].ChildComments)
+	}
+}
+
+// commentPathIDs 解析物化路径中从根评论到本评论的ID
+func commentPathIDs(path string) []int64 {
+	ids := []int64{}
+	for _, part := range strings.Split(path, "/") {
+		if id, err := strconv.ParseInt(part, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
 }
 
 // GetCommentCount 获取指定内容的评论数量
 func (s *CommentService) GetCommentCount(postID int64, contentID int64) (int64, error) {
 	return s.repo.GetCommentCount(postID, contentID)
 }
+
+// ListCommentTree 分页获取一层评论并展开其下若干层回复：parentID 为空时为内容下的顶层评论（第一页先返回置顶评论），否则为该评论的回复；viewerID 非 0 时标记是否已点赞
+// 返回本层评论（回复挂在 ChildComments 中）和下一页游标，游标为空表示没有更多
+func (s *CommentService) ListCommentTree(ctx context.Context, postID int64, contentID int64, parentID *int64, opts CommentTreeOptions, viewerID int64) ([]models.Comment, string, error) {
+	opts, err := s.normalizeTreeOptions(opts)
+	if err != nil {
+		return nil, "", err
+	}
+	page, err := ParseCommentCursor(opts.Sort, opts.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if parentID != nil {
+		if _, err := s.repo.GetByID(*parentID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, "", errors.New("comment not found")
+			}
+			return nil, "", err
+		}
+	}
+
+	// 多取一条用于判断是否还有下一页
+	page.Limit = opts.Limit + 1
+	comments, err := s.repo.ListThreadPage(postID, contentID, parentID, page)
+	if err != nil {
+		return nil, "", err
+	}
+	nextCursor := ""
+	if len(comments) > opts.Limit {
+		comments = comments[:opts.Limit]
+		nextCursor = EncodeCommentCursor(opts.Sort, comments[len(comments)-1])
+	}
+
+	// 置顶评论不参与分页，只在顶层第一页最前面返回
+	if parentID == nil && opts.Cursor == "" {
+		pinned, err := s.repo.ListPinned(postID, contentID)
+		if err != nil {
+			return nil, "", err
+		}
+		comments = append(pinned, comments...)
+	}
+
+	if opts.Depth > 0 && len(comments) > 0 {
+		descendants, err := s.repo.ListDescendants(comments, comments[0].Depth+opts.Depth, opts.Sort, opts.RepliesLimit)
+		if err != nil {
+			return nil, "", err
+		}
+		comments = buildCommentTree(comments, descendants)
+	}
+	s.markLiked(comments, viewerID)
+	redactComments(comments)
+	return comments, nextCursor, nil
+}
+
+// normalizeTreeOptions 校验排序方式并为层数和数量填充默认值、限制上限
+func (s *CommentService) normalizeTreeOptions(opts CommentTreeOptions) (CommentTreeOptions, error) {
+	switch opts.Sort {
+	case "":
+		opts.Sort = CommentSortOldest
+	case CommentSortOldest, CommentSortNewest, CommentSortTop:
+	default:
+		return opts, errors.New("invalid sort")
+	}
+	if opts.Limit < 1 || opts.Limit > 100 {
+		opts.Limit = 20
+	}
+	if opts.Depth < 0 {
+		opts.Depth = defaultCommentTreeDepth
+	}
+	if opts.Depth > s.maxTreeDepth {
+		opts.Depth = s.maxTreeDepth
+	}
+	if opts.RepliesLimit < 1 {
+		opts.RepliesLimit = defaultRepliesPerComment
+	}
+	if opts.RepliesLimit > maxRepliesPerComment {
+		opts.RepliesLimit = maxRepliesPerComment
+	}
+	return opts, nil
+}
+
+// EncodeCommentCursor 编码评论分页游标：top 排序为 点赞数_评论ID，其余为 微秒时间戳_评论ID
+func EncodeCommentCursor(sort string, last models.Comment) string {
+	if sort == CommentSortTop {
+		return fmt.Sprintf("%d_%d", last.Likes, last.ID)
+	}
+	return fmt.Sprintf("%d_%d", last.CreatedAt.UnixMicro(), last.ID)
+}
+
+// ParseCommentCursor 按排序方式解析评论分页游标，空字符串表示第一页
+func ParseCommentCursor(sort, value string) (repositories.CommentPage, error) {
+	page := repositories.CommentPage{Sort: sort}
+	if value == "" {
+		return page, nil
+	}
+	parts := strings.SplitN(value, "_", 2)
+	if len(parts) != 2 {
+		return page, errors.New("invalid cursor")
+	}
+	key, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || key < 0 {
+		return page, errors.New("invalid cursor")
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || id <= 0 {
+		return page, errors.New("invalid cursor")
+	}
+
+	page.AfterID = id
+	if sort == CommentSortTop {
+		page.AfterLikes = int(key)
+	} else {
+		page.AfterTime = time.UnixMicro(key)
+	}
+	return page, nil
+}
+
+// buildCommentTree 将回复挂到各自的父评论下，父评论不在结果中的回复被丢弃；descendants 须已按层级和排序方式排序
+func buildCommentTree(roots []models.Comment, descendants []models.Comment) []models.Comment {
+	children := make(map[int64][]models.Comment)
+	for _, reply := range descendants {
+		if reply.ParentCommentID != nil {
+			children[*reply.ParentCommentID] = append(children[*reply.ParentCommentID], reply)
+		}
+	}
+
+	var attach func(comment *models.Comment)
+	attach = func(comment *models.Comment) {
+		comment.ChildComments = children[comment.ID]
+		for i := range comment.ChildComments {
+			attach(&comment.ChildComments[i])
+		}
+	}
+	for i := range roots {
+		attach(&roots[i])
+	}
+	return roots
+}
+
+// sameInt64 判断两个可空ID是否相同
+func sameInt64(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
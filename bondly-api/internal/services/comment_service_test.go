@@ -0,0 +1,125 @@
+package services
+
+import (
+	"bondly-api/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentCursor(t *testing.T) {
+	createdAt := time.Date(2024, 6, 1, 8, 0, 0, 123456000, time.UTC)
+	last := models.Comment{ID: 42, Likes: 7, CreatedAt: createdAt}
+
+	page, err := ParseCommentCursor(CommentSortNewest, EncodeCommentCursor(CommentSortNewest, last))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), page.AfterID)
+	assert.True(t, page.AfterTime.Equal(createdAt), "游标保留微秒精度")
+
+	page, err = ParseCommentCursor(CommentSortTop, EncodeCommentCursor(CommentSortTop, last))
+	assert.NoError(t, err)
+	assert.Equal(t, 7, page.AfterLikes)
+
+	page, err = ParseCommentCursor(CommentSortOldest, "")
+	assert.NoError(t, err)
+	assert.Zero(t, page.AfterID)
+
+	_, err = ParseCommentCursor(CommentSortOldest, "abc")
+	assert.EqualError(t, err, "invalid cursor")
+}
+
+func TestBuildCommentTree(t *testing.T) {
+	id := func(v int64) *int64 { return &v }
+	roots := []models.Comment{{ID: 1}, {ID: 2}}
+	descendants := []models.Comment{
+		{ID: 3, ParentCommentID: id(1)},
+		{ID: 4, ParentCommentID: id(1)},
+		{ID: 5, ParentCommentID: id(3)},
+		{ID: 6, ParentCommentID: id(99)}, // 父评论未被展开
+	}
+
+	tree := buildCommentTree(roots, descendants)
+	assert.Len(t, tree[0].ChildComments, 2)
+	assert.Equal(t, int64(5), tree[0].ChildComments[0].ChildComments[0].ID)
+	assert.Empty(t, tree[1].ChildComments)
+}
+
+func TestNormalizeTreeOptions(t *testing.T) {
+	s := &CommentService{maxTreeDepth: 4}
+
+	opts, err := s.normalizeTreeOptions(CommentTreeOptions{Depth: -1})
+	assert.NoError(t, err)
+	assert.Equal(t, CommentSortOldest, opts.Sort)
+	assert.Equal(t, defaultCommentTreeDepth, opts.Depth)
+	assert.Equal(t, 20, opts.Limit)
+	assert.Equal(t, defaultRepliesPerComment, opts.RepliesLimit)
+
+	opts, err = s.normalizeTreeOptions(CommentTreeOptions{Sort: CommentSortTop, Depth: 10, RepliesLimit: 500})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, opts.Depth)
+	assert.Equal(t, maxRepliesPerComment, opts.RepliesLimit)
+
+	_, err = s.normalizeTreeOptions(CommentTreeOptions{Sort: "random"})
+	assert.EqualError(t, err, "invalid sort")
+}
+
+func TestApplyLiked(t *testing.T) {
+	comments := []models.Comment{
+		{ID: 1, ChildComments: []models.Comment{{ID: 3}, {ID: 4, ChildComments: []models.Comment{{ID: 5}}}}},
+		{ID: 2},
+	}
+
+	assert.Equal(t, []int64{1, 3, 4, 5, 2}, collectCommentIDs(comments, nil))
+
+	applyLiked(comments, map[int64]bool{1: true, 5: true})
+	assert.True(t, comments[0].Liked)
+	assert.False(t, comments[0].ChildComments[0].Liked)
+	assert.True(t, comments[0].ChildComments[1].ChildComments[0].Liked)
+	assert.False(t, comments[1].Liked)
+}
+
+func TestRedactComments(t *testing.T) {
+	comments := []models.Comment{
+		{ID: 1, AuthorID: 7, Content: "gone", Status: CommentStatusDeleted, Author: models.User{ID: 7},
+			ChildComments: []models.Comment{
+				{ID: 2, AuthorID: 8, Content: "reply", Status: CommentStatusVisible},
+				{ID: 3, AuthorID: 9, Content: "spam", Status: CommentStatusHidden},
+			}},
+	}
+
+	redactComments(comments)
+	assert.Equal(t, deletedCommentPlaceholder, comments[0].Content)
+	assert.Zero(t, comments[0].AuthorID)
+	assert.Zero(t, comments[0].Author.ID)
+	assert.Equal(t, "reply", comments[0].ChildComments[0].Content)
+	assert.Equal(t, int64(8), comments[0].ChildComments[0].AuthorID)
+	assert.Equal(t, hiddenCommentPlaceholder, comments[0].ChildComments[1].Content)
+}
+
+func TestCommentPathIDs(t *testing.T) {
+	assert.Equal(t, []int64{12, 34}, commentPathIDs("12/34/"))
+	assert.Empty(t, commentPathIDs(""))
+}
+
+func TestCommentModerationUpdates(t *testing.T) {
+	parentID := int64(1)
+	visible := &models.Comment{ID: 1, Status: CommentStatusVisible}
+
+	updates, err := commentModerationUpdates(visible, CommentActionHide)
+	assert.NoError(t, err)
+	assert.Equal(t, CommentStatusHidden, updates["status"])
+
+	updates, err = commentModerationUpdates(visible, CommentActionUnhide)
+	assert.NoError(t, err)
+	assert.Empty(t, updates)
+
+	_, err = commentModerationUpdates(&models.Comment{Status: CommentStatusDeleted}, CommentActionHide)
+	assert.EqualError(t, err, "comment deleted")
+
+	_, err = commentModerationUpdates(&models.Comment{ParentCommentID: &parentID}, CommentActionPin)
+	assert.EqualError(t, err, "only top-level comments can be pinned")
+
+	_, err = commentModerationUpdates(visible, "ban")
+	assert.EqualError(t, err, "invalid action")
+}
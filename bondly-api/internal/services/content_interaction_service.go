@@ -13,13 +13,21 @@ import (
 
 // ContentInteractionService 内容互动服务
 type ContentInteractionService struct {
-	db *gorm.DB
+	db              *gorm.DB
+	ranking         *RankingService
+	reputationRules *ReputationRuleService
+	achievements    *AchievementService
+	notifications   *NotificationService
 }
 
 // NewContentInteractionService 创建内容互动服务
-func NewContentInteractionService(db *gorm.DB) *ContentInteractionService {
+func NewContentInteractionService(db *gorm.DB, ranking *RankingService, reputationRules *ReputationRuleService, achievements *AchievementService, notifications *NotificationService) *ContentInteractionService {
 	return &ContentInteractionService{
-		db: db,
+		db:              db,
+		ranking:         ranking,
+		reputationRules: reputationRules,
+		achievements:    achievements,
+		notifications:   notifications,
 	}
 }
 
@@ -69,6 +77,23 @@ func (s *ContentInteractionService) CreateInteraction(req dto.CreateInteractionR
 		// TODO: 考虑使用事务来确保数据一致性
 	}
 
+	s.ranking.OnContentChanged(ctx, req.ContentID)
+
+	// 按规则奖惩内容作者的声誉
+	s.reputationRules.OnContentInteraction(ctx, req.ContentID, req.UserID, req.InteractionType)
+	s.achievements.OnContentInteraction(ctx, req.ContentID, req.InteractionType)
+
+	if req.InteractionType == "like" {
+		s.notifications.Notify(ctx, &models.Notification{
+			UserID:      content.AuthorID,
+			Type:        NotificationTypeLike,
+			ActorID:     req.UserID,
+			SubjectType: "content",
+			SubjectID:   content.ID,
+			Data:        map[string]string{"title": content.Title},
+		})
+	}
+
 	return s.convertToDTO(&interaction), nil
 }
 
@@ -94,6 +119,8 @@ func (s *ContentInteractionService) DeleteInteraction(contentID int64, userID in
 		// TODO: 考虑使用事务来确保数据一致性
 	}
 
+	s.ranking.OnContentChanged(ctx, contentID)
+
 	return nil
 }
 
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+// 内容渲染参数
+const (
+	excerptLength         = 200 // 摘要最大字符数
+	readingWordsPerMinute = 300 // 阅读速度，中文按字、其他语言按词计
+	renderBackfillBatch   = 200 // 补齐渲染结果时每批处理的内容数
+)
+
+// RenderedContent Markdown 渲染结果
+type RenderedContent struct {
+	HTML        string                  `json:"html"`
+	Excerpt     string                  `json:"excerpt"`
+	WordCount   int                     `json:"word_count"`
+	ReadingTime int                     `json:"reading_time"`
+	TOC         []models.ContentHeading `json:"toc"`
+}
+
+var (
+	// markdownRenderer 支持 GFM（表格、删除线、任务列表、自动链接），内嵌的 HTML 原样输出，由 contentPolicy 统一净化
+	markdownRenderer = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+	)
+	contentPolicy   = newContentPolicy()
+	plainTextPolicy = bluemonday.StrictPolicy()
+)
+
+// newContentPolicy 内容 HTML 白名单：在 UGC 策略基础上允许标题锚点、代码语言标记和任务列表复选框
+func newContentPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("id").Matching(regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+	policy.AddTargetBlankToFullyQualifiedLinks(true)
+	return policy
+}
+
+// RenderMarkdown 将 Markdown 渲染为净化后的 HTML，并生成摘要、字数、阅读时间和目录
+func RenderMarkdown(source string) (*RenderedContent, error) {
+	src := []byte(source)
+	doc := markdownRenderer.Parser().Parse(text.NewReader(src))
+	toc := assignHeadingIDs(doc, src)
+
+	var buf bytes.Buffer
+	if err := markdownRenderer.Renderer().Render(&buf, src, doc); err != nil {
+		return nil, fmt.Errorf("failed to render markdown: %w", err)
+	}
+	sanitized := contentPolicy.Sanitize(buf.String())
+
+	plain := plainText(sanitized)
+	words := countWords(plain)
+	return &RenderedContent{
+		HTML:        sanitized,
+		Excerpt:     excerpt(plain, excerptLength),
+		WordCount:   words,
+		ReadingTime: (words + readingWordsPerMinute - 1) / readingWordsPerMinute,
+		TOC:         toc,
+	}, nil
+}
+
+// renderContent 渲染内容正文并写入内容的渲染字段
+func renderContent(content *models.Content) error {
+	rendered, err := RenderMarkdown(content.Content)
+	if err != nil {
+		return err
+	}
+	content.ContentHTML = rendered.HTML
+	content.Excerpt = rendered.Excerpt
+	content.WordCount = rendered.WordCount
+	content.ReadingTime = rendered.ReadingTime
+	content.TOC = rendered.TOC
+	return nil
+}
+
+// BackfillRenderedContent 为渲染功能上线前的内容补齐渲染结果，返回处理的内容数
+func BackfillRenderedContent(contentRepo *repositories.ContentRepository) (int, error) {
+	total := 0
+	var afterID int64
+	for {
+		contents, err := contentRepo.ListUnrendered(afterID, renderBackfillBatch)
+		if err != nil {
+			return total, err
+		}
+		if len(contents) == 0 {
+			return total, nil
+		}
+		for i := range contents {
+			if err := renderContent(&contents[i]); err != nil {
+				return total, err
+			}
+			if err := contentRepo.SaveRendered(&contents[i]); err != nil {
+				return total, err
+			}
+			total++
+		}
+		afterID = contents[len(contents)-1].ID
+	}
+}
+
+// assignHeadingIDs 为标题生成唯一锚点并返回目录，没有文字的标题不计入目录
+func assignHeadingIDs(doc ast.Node, source []byte) []models.ContentHeading {
+	toc := []models.ContentHeading{}
+	used := make(map[string]int)
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := node.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		title := strings.Join(strings.Fields(plainText(string(heading.Text(source)))), " ")
+		if title == "" {
+			return ast.WalkSkipChildren, nil
+		}
+
+		id := headingSlug(title)
+		if n := used[id]; n > 0 {
+			used[id] = n + 1
+			id = fmt.Sprintf("%s-%d", id, n)
+		} else {
+			used[id] = 1
+		}
+		heading.SetAttributeString("id", []byte(id))
+		toc = append(toc, models.ContentHeading{Level: heading.Level, Text: title, ID: id})
+		return ast.WalkSkipChildren, nil
+	})
+	return toc
+}
+
+// headingSlug 由标题文字生成锚点：小写，保留字母、数字、- 和 _，空白替换为 -
+func headingSlug(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-':
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			b.WriteRune('-')
+		}
+	}
+	slug := strings.Trim(b.String(), "-")
+	if slug == "" {
+		return "section"
+	}
+	return slug
+}
+
+// plainText 去掉 HTML 标签并反转义实体
+func plainText(htmlText string) string {
+	return html.UnescapeString(plainTextPolicy.Sanitize(htmlText))
+}
+
+// countWords 统计字数：每个汉字计一个，连续的字母数字计一个词
+func countWords(text string) int {
+	count := 0
+	inWord := false
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			count++
+			inWord = false
+		case isWordRune(r):
+			if !inWord {
+				count++
+			}
+			inWord = true
+		default:
+			inWord = false
+		}
+	}
+	return count
+}
+
+// excerpt 合并空白后截取前 limit 个字符，被截断时以省略号结尾
+func excerpt(text string, limit int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	return strings.TrimSpace(string(runes[:limit])) + "…"
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"bondly-api/internal/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	source := "# 简介\n\nHello **world**, 你好。\n\n## Setup\n\n## Setup\n\n```go\nfmt.Println(1)\n```\n\n- [x] done\n"
+	rendered, err := RenderMarkdown(source)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []models.ContentHeading{
+		{Level: 1, Text: "简介", ID: "简介"},
+		{Level: 2, Text: "Setup", ID: "setup"},
+		{Level: 2, Text: "Setup", ID: "setup-1"},
+	}, rendered.TOC)
+	assert.Contains(t, rendered.HTML, `<h1 id="简介">简介</h1>`)
+	assert.Contains(t, rendered.HTML, `<h2 id="setup-1">`)
+	assert.Contains(t, rendered.HTML, `<strong>world</strong>`)
+	assert.Contains(t, rendered.HTML, `<code class="language-go">`)
+	assert.Contains(t, rendered.HTML, `type="checkbox"`)
+	assert.True(t, strings.HasPrefix(rendered.Excerpt, "简介 Hello world, 你好。"))
+	assert.Equal(t, 1, rendered.ReadingTime)
+}
+
+func TestRenderMarkdownSanitizes(t *testing.T) {
+	rendered, err := RenderMarkdown("<script>alert(1)</script>\n\n<a href=\"javascript:alert(1)\" onclick=\"x()\">link</a> [ok](https://example.com)")
+	assert.NoError(t, err)
+	assert.NotContains(t, rendered.HTML, "<script")
+	assert.NotContains(t, rendered.HTML, "javascript:")
+	assert.NotContains(t, rendered.HTML, "onclick")
+	assert.Contains(t, rendered.HTML, `href="https://example.com"`)
+	assert.Contains(t, rendered.HTML, `rel="nofollow noopener"`)
+}
+
+func TestCountWordsAndExcerpt(t *testing.T) {
+	assert.Equal(t, 7, countWords("Hello web3 world, 你好世界"))
+	assert.Equal(t, 0, countWords(""))
+
+	assert.Equal(t, "a b", excerpt(" a\n\n b ", 10))
+	assert.Equal(t, "你好…", excerpt("你好世界", 2))
+}
@@ -0,0 +1,387 @@
+package services
+
+import (
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// 修订对比粒度
+const (
+	DiffModeLine = "line"
+	DiffModeWord = "word"
+)
+
+// 对比片段类型
+const (
+	DiffOpEqual  = "equal"
+	DiffOpInsert = "insert"
+	DiffOpDelete = "delete"
+)
+
+// maxDiffCells 逐词对比的 LCS 表上限，超出时将差异部分整体视为删除后插入
+const maxDiffCells = 1000000
+
+// DiffOp 对比结果片段
+type DiffOp struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// RevisionDiff 两个修订之间的差异
+type RevisionDiff struct {
+	From       int      `json:"from"`
+	To         int      `json:"to"`
+	Mode       string   `json:"mode"`
+	Title      []DiffOp `json:"title"`
+	Content    []DiffOp `json:"content"`
+	Insertions int      `json:"insertions"` // 新增的行数或词数
+	Deletions  int      `json:"deletions"`  // 删除的行数或词数
+}
+
+// ContentRevisionService 内容修订历史服务
+type ContentRevisionService struct {
+	revisionRepo *repositories.ContentRevisionRepository
+	contentRepo  *repositories.ContentRepository
+}
+
+func NewContentRevisionService(revisionRepo *repositories.ContentRevisionRepository, contentRepo *repositories.ContentRepository) *ContentRevisionService {
+	return &ContentRevisionService{
+		revisionRepo: revisionRepo,
+		contentRepo:  contentRepo,
+	}
+}
+
+// OnContentCreated 为新内容记录第一个修订
+func (s *ContentRevisionService) OnContentCreated(ctx context.Context, content *models.Content) {
+	if s == nil {
+		return
+	}
+	s.record(ctx, content, content.AuthorID, content.IPFSHash, nil)
+}
+
+// EnsureBaseline 修订功能上线前创建的内容在首次修改前补记当前版本，已铸造的 IPFS 哈希视为对应该版本
+func (s *ContentRevisionService) EnsureBaseline(ctx context.Context, content *models.Content) {
+	if s == nil {
+		return
+	}
+	count, err := s.revisionRepo.CountByContent(content.ID)
+	if err != nil {
+		loggerpkg.NewBusinessLogger(ctx).DatabaseError("select", "content_revisions", "CountByContent", err)
+		return
+	}
+	if count == 0 {
+		s.record(ctx, content, content.AuthorID, content.IPFSHash, nil)
+	}
+}
+
+// PrepareRevision 根据修改前后的内容决定与内容保存在同一事务中记录的修订：
+// 标题或正文变化时返回新修订；仅更新 IPFS 哈希时返回需标记到最新修订的铸造哈希
+func (s *ContentRevisionService) PrepareRevision(before, after *models.Content, editorID int64, restoredFrom *int) (*models.ContentRevision, *string) {
+	if s == nil {
+		return nil, nil
+	}
+
+	var minted *string
+	if after.IPFSHash != nil && *after.IPFSHash != "" && (before.IPFSHash == nil || *before.IPFSHash != *after.IPFSHash) {
+		minted = after.IPFSHash
+	}
+
+	if before.Title != after.Title || before.Content != after.Content {
+		return newContentRevision(after, editorID, minted, restoredFrom), nil
+	}
+	return nil, minted
+}
+
+// ListRevisions 分页获取内容的修订，并标记与已铸造快照不一致的修订；返回已铸造的修订号，未铸造或无法确定时为 nil
+func (s *ContentRevisionService) ListRevisions(ctx context.Context, contentID int64, page, limit int) ([]models.ContentRevision, int64, *int, error) {
+	content, err := s.getContent(contentID)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	offset := (page - 1) * limit
+	revisions, err := s.revisionRepo.ListByContent(contentID, offset, limit)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	total, err := s.revisionRepo.CountByContent(contentID)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	minted := s.mintedRevision(content)
+	var mintedRevision *int
+	if minted != nil {
+		mintedRevision = &minted.Revision
+	}
+	for i := range revisions {
+		markDivergence(&revisions[i], minted)
+	}
+	return revisions, total, mintedRevision, nil
+}
+
+// GetRevision 获取单个修订
+func (s *ContentRevisionService) GetRevision(ctx context.Context, contentID int64, revision int) (*models.ContentRevision, error) {
+	content, err := s.getContent(contentID)
+	if err != nil {
+		return nil, err
+	}
+	rev, err := s.getRevision(contentID, revision)
+	if err != nil {
+		return nil, err
+	}
+	markDivergence(rev, s.mintedRevision(content))
+	return rev, nil
+}
+
+// DiffRevisions 对比两个修订的标题和正文
+func (s *ContentRevisionService) DiffRevisions(ctx context.Context, contentID int64, from, to int, mode string) (*RevisionDiff, error) {
+	if mode == "" {
+		mode = DiffModeLine
+	}
+	if mode != DiffModeLine && mode != DiffModeWord {
+		return nil, errors.New("invalid diff mode")
+	}
+	if _, err := s.getContent(contentID); err != nil {
+		return nil, err
+	}
+	fromRev, err := s.getRevision(contentID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := s.getRevision(contentID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &RevisionDiff{
+		From:  from,
+		To:    to,
+		Mode:  mode,
+		Title: diffTokens(tokenizeWords(fromRev.Title), tokenizeWords(toRev.Title)),
+	}
+	var ops []DiffOp
+	if mode == DiffModeWord {
+		ops = diffTokenOps(tokenizeWords(fromRev.Content), tokenizeWords(toRev.Content))
+	} else {
+		ops = diffTokenOps(tokenizeLines(fromRev.Content), tokenizeLines(toRev.Content))
+	}
+	diff.Content = mergeDiffOps(ops)
+	diff.Insertions, diff.Deletions = countDiffOps(ops)
+	return diff, nil
+}
+
+// getRevision 根据修订号获取修订
+func (s *ContentRevisionService) getRevision(contentID int64, revision int) (*models.ContentRevision, error) {
+	rev, err := s.revisionRepo.GetByRevision(contentID, revision)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("revision not found")
+		}
+		return nil, err
+	}
+	return rev, nil
+}
+
+// getContent 获取内容
+func (s *ContentRevisionService) getContent(contentID int64) (*models.Content, error) {
+	content, err := s.contentRepo.GetByID(contentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("content not found")
+		}
+		return nil, err
+	}
+	return content, nil
+}
+
+// mintedRevision 获取与内容当前 IPFS 哈希对应的修订，未铸造或找不到时返回 nil
+func (s *ContentRevisionService) mintedRevision(content *models.Content) *models.ContentRevision {
+	if content.IPFSHash == nil || *content.IPFSHash == "" {
+		return nil
+	}
+	minted, err := s.revisionRepo.GetMinted(content.ID, *content.IPFSHash)
+	if err != nil {
+		return nil
+	}
+	return minted
+}
+
+// record 记录内容当前的标题和正文为新修订
+func (s *ContentRevisionService) record(ctx context.Context, content *models.Content, editorID int64, minted *string, restoredFrom *int) {
+	if err := s.revisionRepo.Create(newContentRevision(content, editorID, minted, restoredFrom)); err != nil {
+		loggerpkg.NewBusinessLogger(ctx).DatabaseError("insert", "content_revisions", "Create", err)
+	}
+}
+
+// newContentRevision 以内容当前的标题和正文构造修订，修订号在写入时分配
+func newContentRevision(content *models.Content, editorID int64, minted *string, restoredFrom *int) *models.ContentRevision {
+	return &models.ContentRevision{
+		ContentID:      content.ID,
+		EditorID:       editorID,
+		Title:          content.Title,
+		Content:        content.Content,
+		Checksum:       revisionChecksum(content.Title, content.Content),
+		MintedIPFSHash: minted,
+		RestoredFrom:   restoredFrom,
+	}
+}
+
+// markDivergence 内容已铸造时标记修订是否与铸造快照不一致
+func markDivergence(revision *models.ContentRevision, minted *models.ContentRevision) {
+	revision.DivergesFromMint = minted != nil && revision.Checksum != minted.Checksum
+}
+
+// revisionChecksum 标题与正文的 sha256
+func revisionChecksum(title, content string) string {
+	sum := sha256.Sum256([]byte(title + "\x00" + content))
+	return hex.EncodeToString(sum[:])
+}
+
+// tokenizeLines 按行切分，每行保留结尾的换行符
+func tokenizeLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.SplitAfter(text, "\n")
+}
+
+// tokenizeWords 按词切分：连续的字母数字为一个词，连续空白为一个词，汉字和标点各自成词
+func tokenizeWords(text string) []string {
+	var tokens []string
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		j := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			for j < len(runes) && unicode.IsSpace(runes[j]) {
+				j++
+			}
+		case isWordRune(r):
+			for j < len(runes) && isWordRune(runes[j]) {
+				j++
+			}
+		}
+		tokens = append(tokens, string(runes[i:j]))
+		i = j
+	}
+	return tokens
+}
+
+// isWordRune 可与相邻字符组成一个词的字符，汉字不分词因此不计入
+func isWordRune(r rune) bool {
+	return (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') && !unicode.Is(unicode.Han, r)
+}
+
+// diffTokens 基于最长公共子序列对比两个词序列，合并相邻的同类片段
+func diffTokens(a, b []string) []DiffOp {
+	return mergeDiffOps(diffTokenOps(a, b))
+}
+
+// diffTokenOps 对比两个词序列，每个片段对应一个词
+func diffTokenOps(a, b []string) []DiffOp {
+	// 去掉公共前缀和后缀，缩小 LCS 表
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := tokenOps(nil, DiffOpEqual, a[:prefix])
+	ops = append(ops, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	return tokenOps(ops, DiffOpEqual, a[len(a)-suffix:])
+}
+
+// diffMiddle 对比去掉公共前后缀后的部分
+func diffMiddle(a, b []string) []DiffOp {
+	if len(a)*len(b) > maxDiffCells {
+		return tokenOps(tokenOps(nil, DiffOpDelete, a), DiffOpInsert, b)
+	}
+
+	// lcs[i][j] 为 a[i:] 与 b[j:] 的最长公共子序列长度
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []DiffOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, DiffOp{Op: DiffOpEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, DiffOp{Op: DiffOpDelete, Text: a[i]})
+			i++
+		default:
+			ops = append(ops, DiffOp{Op: DiffOpInsert, Text: b[j]})
+			j++
+		}
+	}
+	ops = tokenOps(ops, DiffOpDelete, a[i:])
+	return tokenOps(ops, DiffOpInsert, b[j:])
+}
+
+// tokenOps 为每个词追加一个片段
+func tokenOps(ops []DiffOp, op string, tokens []string) []DiffOp {
+	for _, token := range tokens {
+		ops = append(ops, DiffOp{Op: op, Text: token})
+	}
+	return ops
+}
+
+// mergeDiffOps 合并相邻的同类片段
+func mergeDiffOps(ops []DiffOp) []DiffOp {
+	merged := make([]DiffOp, 0, len(ops))
+	for _, op := range ops {
+		if n := len(merged); n > 0 && merged[n-1].Op == op.Op {
+			merged[n-1].Text += op.Text
+			continue
+		}
+		merged = append(merged, op)
+	}
+	return merged
+}
+
+// countDiffOps 统计新增和删除的词或行，不计空白
+func countDiffOps(ops []DiffOp) (int, int) {
+	insertions, deletions := 0, 0
+	for _, op := range ops {
+		if strings.TrimSpace(op.Text) == "" {
+			continue
+		}
+		switch op.Op {
+		case DiffOpInsert:
+			insertions++
+		case DiffOpDelete:
+			deletions++
+		}
+	}
+	return insertions, deletions
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"bondly-api/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenizeWords(t *testing.T) {
+	assert.Equal(t, []string{"Hello", ",", " ", "web3", " ", "世", "界", "!"}, tokenizeWords("Hello, web3 世界!"))
+	assert.Nil(t, tokenizeWords(""))
+	assert.Equal(t, []string{"a\n", "b"}, tokenizeLines("a\nb"))
+}
+
+func TestDiffTokens(t *testing.T) {
+	ops := diffTokenOps(tokenizeWords("the quick fox"), tokenizeWords("the slow fox"))
+	insertions, deletions := countDiffOps(ops)
+	assert.Equal(t, 1, insertions)
+	assert.Equal(t, 1, deletions)
+
+	assert.Equal(t, []DiffOp{
+		{Op: DiffOpEqual, Text: "the "},
+		{Op: DiffOpDelete, Text: "quick"},
+		{Op: DiffOpInsert, Text: "slow"},
+		{Op: DiffOpEqual, Text: " fox"},
+	}, diffTokens(tokenizeWords("the quick fox"), tokenizeWords("the slow fox")))
+
+	assert.Equal(t, []DiffOp{{Op: DiffOpInsert, Text: "a\nb"}}, diffTokens(nil, tokenizeLines("a\nb")))
+}
+
+func TestMarkDivergence(t *testing.T) {
+	minted := &models.ContentRevision{Checksum: revisionChecksum("标题", "正文")}
+	same := &models.ContentRevision{Checksum: revisionChecksum("标题", "正文")}
+	edited := &models.ContentRevision{Checksum: revisionChecksum("标题", "正文已修改")}
+
+	markDivergence(same, minted)
+	markDivergence(edited, minted)
+	assert.False(t, same.DivergesFromMint)
+	assert.True(t, edited.DivergesFromMint)
+
+	markDivergence(edited, nil)
+	assert.False(t, edited.DivergesFromMint, "未铸造的内容不标记")
+}
+
+func TestPrepareRevision(t *testing.T) {
+	s := &ContentRevisionService{}
+	hash := "QmHash"
+	before := &models.Content{ID: 1, Title: "t", Content: "old"}
+
+	// 正文变化时记录新修订，同时带上新铸造的哈希
+	after := &models.Content{ID: 1, Title: "t", Content: "new", IPFSHash: &hash}
+	revision, minted := s.PrepareRevision(before, after, 9, nil)
+	if assert.NotNil(t, revision) {
+		assert.Equal(t, "new", revision.Content)
+		assert.Equal(t, int64(9), revision.EditorID)
+		assert.Equal(t, &hash, revision.MintedIPFSHash)
+	}
+	assert.Nil(t, minted)
+
+	// 只更新 IPFS 哈希时标记最新修订
+	revision, minted = s.PrepareRevision(before, &models.Content{ID: 1, Title: "t", Content: "old", IPFSHash: &hash}, 9, nil)
+	assert.Nil(t, revision)
+	assert.Equal(t, &hash, minted)
+
+	var disabled *ContentRevisionService
+	revision, minted = disabled.PrepareRevision(before, after, 9, nil)
+	assert.Nil(t, revision)
+	assert.Nil(t, minted)
+}
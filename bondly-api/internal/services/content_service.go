@@ -1,21 +1,42 @@
 package services
 
 import (
+	loggerpkg "bondly-api/internal/logger"
 	"bondly-api/internal/models"
 	"bondly-api/internal/repositories"
 	"context"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// scheduledPublishBatchSize 每次定时任务最多发布的内容数，剩余的由下一次任务处理
+const scheduledPublishBatchSize = 100
+
 type ContentService struct {
-	contentRepo *repositories.ContentRepository
+	contentRepo     *repositories.ContentRepository
+	tags            *TagService
+	feed            *FeedService
+	ranking         *RankingService
+	revisions       *ContentRevisionService
+	workflow        *ContentWorkflowService
+	reputationRules *ReputationRuleService
+	achievements    *AchievementService
+	mentions        *MentionService
 }
 
-func NewContentService(contentRepo *repositories.ContentRepository) *ContentService {
+func NewContentService(contentRepo *repositories.ContentRepository, tags *TagService, feed *FeedService, ranking *RankingService, revisions *ContentRevisionService, workflow *ContentWorkflowService, reputationRules *ReputationRuleService, achievements *AchievementService, mentions *MentionService) *ContentService {
 	return &ContentService{
-		contentRepo: contentRepo,
+		contentRepo:     contentRepo,
+		tags:            tags,
+		feed:            feed,
+		ranking:         ranking,
+		revisions:       revisions,
+		workflow:        workflow,
+		reputationRules: reputationRules,
+		achievements:    achievements,
+		mentions:        mentions,
 	}
 }
 
@@ -26,14 +47,58 @@ func (s *ContentService) CreateContent(ctx context.Context, content *models.Cont
 		content.Type = "article"
 	}
 	if content.Status == "" {
-		content.Status = "draft"
+		content.Status = ContentStatusDraft
+	}
+	needsReview := content.Status != ContentStatusDraft && s.workflow.RequiresReview(ctx, content.Type, content.AuthorID)
+	status, err := resolveContentStatus(ContentStatusDraft, content.Status, content.PublishAt, content.PublishAt != nil, time.Now(), needsReview)
+	if err != nil {
+		return err
+	}
+	content.Status = status
+
+	// 先校验标签，避免内容创建后因标签不合法而失败
+	var tags []models.Tag
+	if content.Tags != nil {
+		normalized, err := normalizeTags(content.Tags)
+		if err != nil {
+			return err
+		}
+		tags = normalized
+	}
+
+	if err := renderContent(content); err != nil {
+		return err
+	}
+	if content.Status == ContentStatusPublished {
+		markPublished(content)
+	}
+
+	if err := s.contentRepo.Create(content); err != nil {
+		return err
+	}
+
+	s.revisions.OnContentCreated(ctx, content)
+
+	content.Tags = []models.Tag{}
+	if s.tags != nil && len(tags) > 0 {
+		saved, err := s.tags.SetContentTags(ctx, content.ID, tags)
+		if err != nil {
+			return err
+		}
+		content.Tags = saved
+	}
+
+	if content.Status == ContentStatusPublished {
+		s.onPublished(ctx, content)
+		s.ranking.OnContentChanged(ctx, content.ID)
 	}
 
-	return s.contentRepo.Create(content)
+	return nil
 }
 
-// GetContent 获取内容
-func (s *ContentService) GetContent(ctx context.Context, id int64) (*models.Content, error) {
+// GetContent 获取内容，未发布的内容仅作者、管理员和内容审核员可见，viewerID 为 0 表示未登录；
+// 对其他访问者与内容不存在一样返回 content not found，且不计浏览量
+func (s *ContentService) GetContent(ctx context.Context, id int64, viewerID int64) (*models.Content, error) {
 	content, err := s.contentRepo.GetByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -41,15 +106,117 @@ func (s *ContentService) GetContent(ctx context.Context, id int64) (*models.Cont
 		}
 		return nil, err
 	}
+	if content.Status != ContentStatusPublished && !s.canViewUnpublished(ctx, content, viewerID) {
+		return nil, errors.New("content not found")
+	}
 
 	// 增加浏览量
 	s.contentRepo.IncrementViews(id)
 
+	contents := []models.Content{*content}
+	s.tags.AttachTags(ctx, contents)
+	content.Tags = contents[0].Tags
+
 	return content, nil
 }
 
-// UpdateContent 更新内容
-func (s *ContentService) UpdateContent(ctx context.Context, id int64, updateData *models.Content) (*models.Content, error) {
+// canViewUnpublished 作者、管理员和内容审核员可以查看未发布的内容
+func (s *ContentService) canViewUnpublished(ctx context.Context, content *models.Content, viewerID int64) bool {
+	if viewerID == 0 {
+		return false
+	}
+	return content.AuthorID == viewerID || s.workflow.CanModerate(ctx, viewerID)
+}
+
+// updateOptions 更新内容的附加选项
+type updateOptions struct {
+	restoredFrom *int   // 由该修订恢复
+	note         string // 状态变更通知中附带的审核意见
+	bypassReview bool   // 审核通过时直接进入目标状态
+}
+
+// UpdateContent 更新内容，标题或正文变化时以 editorID 记录修订
+func (s *ContentService) UpdateContent(ctx context.Context, id int64, editorID int64, updateData *models.Content) (*models.Content, error) {
+	return s.updateContent(ctx, id, editorID, updateData, updateOptions{})
+}
+
+// ReviewContent 审核待审核的内容：通过时发布，publish_at 未到时转为定时发布；驳回时退回草稿并通知作者
+func (s *ContentService) ReviewContent(ctx context.Context, id int64, reviewerID int64, approve bool, note string) (*models.Content, error) {
+	content, err := s.contentRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("content not found")
+		}
+		return nil, err
+	}
+	if content.Status != ContentStatusInReview {
+		return nil, errors.New("content not in review")
+	}
+
+	status := ContentStatusDraft
+	if approve {
+		status = ContentStatusPublished
+		if content.PublishAt != nil && content.PublishAt.After(time.Now()) {
+			status = ContentStatusScheduled
+		}
+	}
+	return s.updateContent(ctx, id, reviewerID, &models.Content{Status: status}, updateOptions{note: note, bypassReview: true})
+}
+
+// PublishScheduled 发布 publish_at 已到期的定时内容，由定时任务调用
+func (s *ContentService) PublishScheduled(ctx context.Context) error {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+	now := time.Now()
+
+	due, err := s.contentRepo.ListDueScheduled(now, scheduledPublishBatchSize)
+	if err != nil {
+		bizLog.DatabaseError("select", "contents", "ListDueScheduled", err)
+		return err
+	}
+
+	published := 0
+	for i := range due {
+		content := &due[i]
+		publishedAt := now.Truncate(time.Millisecond)
+		updated, err := s.contentRepo.PublishScheduled(content.ID, now, publishedAt)
+		if err != nil {
+			bizLog.DatabaseError("update", "contents", "PublishScheduled", err)
+			continue
+		}
+		if !updated {
+			continue
+		}
+
+		content.Status = ContentStatusPublished
+		if content.PublishedAt == nil {
+			content.PublishedAt = &publishedAt
+		}
+		s.onPublished(ctx, content)
+		s.ranking.OnContentChanged(ctx, content.ID)
+		s.workflow.OnStatusChanged(ctx, content, ContentStatusScheduled, 0, "")
+		published++
+	}
+
+	if published > 0 {
+		bizLog.BusinessLogic("发布定时内容", map[string]interface{}{"published": published})
+	}
+	return nil
+}
+
+// RestoreRevision 将内容的标题和正文恢复为指定修订，并记录为新修订
+func (s *ContentService) RestoreRevision(ctx context.Context, id int64, revision int, editorID int64) (*models.Content, error) {
+	if s.revisions == nil {
+		return nil, errors.New("revision not found")
+	}
+	rev, err := s.revisions.GetRevision(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+	return s.updateContent(ctx, id, editorID, &models.Content{Title: rev.Title, Content: rev.Content}, updateOptions{restoredFrom: &rev.Revision})
+}
+
+// updateContent 更新内容并校验状态流转
+func (s *ContentService) updateContent(ctx context.Context, id int64, editorID int64, updateData *models.Content, opts updateOptions) (*models.Content, error) {
 	// 检查内容是否存在
 	existingContent, err := s.contentRepo.GetByID(id)
 	if err != nil {
@@ -59,6 +226,19 @@ func (s *ContentService) UpdateContent(ctx context.Context, id int64, updateData
 		return nil, err
 	}
 
+	previousStatus := existingContent.Status
+	s.revisions.EnsureBaseline(ctx, existingContent)
+	before := *existingContent
+
+	// Tags 为 nil 时保持不变，为空切片时清空标签
+	var tags []models.Tag
+	if updateData.Tags != nil {
+		tags, err = normalizeTags(updateData.Tags)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// 更新字段
 	if updateData.Title != "" {
 		existingContent.Title = updateData.Title
@@ -66,12 +246,31 @@ func (s *ContentService) UpdateContent(ctx context.Context, id int64, updateData
 	if updateData.Content != "" {
 		existingContent.Content = updateData.Content
 	}
+	// 正文变化或尚未渲染时重新渲染
+	if updateData.Content != "" || existingContent.ContentHTML == "" {
+		if err := renderContent(existingContent); err != nil {
+			return nil, err
+		}
+	}
+	if updateData.Type != "" && updateData.Type != before.Type && !opts.bypassReview &&
+		(previousStatus == ContentStatusPublished || previousStatus == ContentStatusScheduled) &&
+		s.workflow.RequiresReview(ctx, updateData.Type, editorID) {
+		// 已发布和定时发布的内容不会再经过发布状态变更，改为需要审核的类型会绕过审核
+		return nil, errors.New("content type change requires review")
+	}
 	if updateData.Type != "" {
 		existingContent.Type = updateData.Type
 	}
-	if updateData.Status != "" {
-		existingContent.Status = updateData.Status
+	if updateData.PublishAt != nil {
+		existingContent.PublishAt = updateData.PublishAt
 	}
+	needsReview := updateData.Status != "" && updateData.Status != previousStatus && !opts.bypassReview &&
+		s.workflow.RequiresReview(ctx, existingContent.Type, editorID)
+	status, err := resolveContentStatus(previousStatus, updateData.Status, existingContent.PublishAt, updateData.PublishAt != nil, time.Now(), needsReview)
+	if err != nil {
+		return nil, err
+	}
+	existingContent.Status = status
 	// 更新封面图片URL（允许设置为空值）
 	if updateData.CoverImageURL != nil {
 		existingContent.CoverImageURL = updateData.CoverImageURL
@@ -90,11 +289,36 @@ func (s *ContentService) UpdateContent(ctx context.Context, id int64, updateData
 		existingContent.MetadataHash = updateData.MetadataHash
 	}
 
-	err = s.contentRepo.Update(existingContent)
-	if err != nil {
+	if existingContent.Status == ContentStatusPublished {
+		markPublished(existingContent)
+	}
+
+	// 修订与内容在同一事务中保存，不会出现内容已修改而修订缺失
+	revision, minted := s.revisions.PrepareRevision(&before, existingContent, editorID, opts.restoredFrom)
+	if err := s.contentRepo.UpdateWithRevision(existingContent, revision, minted); err != nil {
 		return nil, err
 	}
 
+	if s.tags != nil && updateData.Tags != nil {
+		if _, err := s.tags.SetContentTags(ctx, id, tags); err != nil {
+			return nil, err
+		}
+	}
+	contents := []models.Content{*existingContent}
+	s.tags.AttachTags(ctx, contents)
+	existingContent.Tags = contents[0].Tags
+
+	if previousStatus != ContentStatusPublished && existingContent.Status == ContentStatusPublished {
+		s.onPublished(ctx, existingContent)
+	} else if existingContent.Status == ContentStatusPublished && updateData.Content != "" {
+		// 已发布内容修改正文时通知新增的提及
+		s.mentions.OnContentPublished(ctx, existingContent)
+	}
+	if previousStatus == ContentStatusPublished || existingContent.Status == ContentStatusPublished {
+		s.ranking.OnContentChanged(ctx, existingContent.ID)
+	}
+	s.workflow.OnStatusChanged(ctx, existingContent, previousStatus, editorID, opts.note)
+
 	return existingContent, nil
 }
 
@@ -109,7 +333,12 @@ func (s *ContentService) DeleteContent(ctx context.Context, id int64) error {
 		return err
 	}
 
-	return s.contentRepo.Delete(id)
+	if err := s.contentRepo.Delete(id); err != nil {
+		return err
+	}
+
+	s.ranking.OnContentChanged(ctx, id)
+	return nil
 }
 
 // ListContent 获取内容列表
@@ -121,16 +350,10 @@ func (s *ContentService) ListContent(ctx context.Context, page, limit int, statu
 		return nil, 0, err
 	}
 
-	// 为每个内容更新真实的点赞和点踩数量
-	for i := range contents {
-		likes, dislikes, err := s.contentRepo.GetInteractionStats(contents[i].ID)
-		if err != nil {
-			// 如果获取失败，保持默认值
-			continue
-		}
-		contents[i].Likes = likes
-		contents[i].Dislikes = dislikes
-	}
+	// 批量更新真实的点赞和点踩数量
+	attachInteractionStats(s.contentRepo, contents)
+
+	s.tags.AttachTags(ctx, contents)
 
 	total, err := s.contentRepo.CountWithStatus(status)
 	if err != nil {
@@ -149,16 +372,10 @@ func (s *ContentService) GetContentByAuthor(ctx context.Context, authorID int64,
 		return nil, 0, err
 	}
 
-	// 为每个内容更新真实的点赞和点踩数量
-	for i := range contents {
-		likes, dislikes, err := s.contentRepo.GetInteractionStats(contents[i].ID)
-		if err != nil {
-			// 如果获取失败，保持默认值
-			continue
-		}
-		contents[i].Likes = likes
-		contents[i].Dislikes = dislikes
-	}
+	// 批量更新真实的点赞和点踩数量
+	attachInteractionStats(s.contentRepo, contents)
+
+	s.tags.AttachTags(ctx, contents)
 
 	total, err := s.contentRepo.CountByAuthorID(authorID)
 	if err != nil {
@@ -167,3 +384,100 @@ func (s *ContentService) GetContentByAuthor(ctx context.Context, authorID int64,
 
 	return contents, total, nil
 }
+
+// ListContentByTag 获取标签下的内容列表，status 为空时不过滤状态
+func (s *ContentService) ListContentByTag(ctx context.Context, slug string, page, limit int, status string) ([]models.Content, int64, error) {
+	if s.tags == nil {
+		return nil, 0, errors.New("tag not found")
+	}
+	tag, err := s.tags.getBySlug(slug)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	ids, err := s.tags.tagRepo.ListContentIDsByTag(tag.ID, status, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	contents, err := s.contentRepo.ListByIDs(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 批量更新真实的点赞和点踩数量
+	attachInteractionStats(s.contentRepo, contents)
+
+	s.tags.AttachTags(ctx, contents)
+
+	total, err := s.tags.tagRepo.CountContentsByTag(tag.ID, status)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return contents, total, nil
+}
+
+// onPublished 内容进入已发布状态后触发声誉、成就、信息流和提及通知
+func (s *ContentService) onPublished(ctx context.Context, content *models.Content) {
+	s.reputationRules.OnContentPublished(ctx, content)
+	s.achievements.OnContentPublished(ctx, content)
+	s.feed.OnContentPublished(ctx, content)
+	s.mentions.OnContentPublished(ctx, content)
+}
+
+// attachInteractionStats 一次查询填充内容的点赞和点踩数量，查询失败时保持默认值
+func attachInteractionStats(contentRepo *repositories.ContentRepository, contents []models.Content) {
+	if len(contents) == 0 {
+		return
+	}
+	ids := make([]int64, 0, len(contents))
+	for _, content := range contents {
+		ids = append(ids, content.ID)
+	}
+	stats, err := contentRepo.ListInteractionStats(ids)
+	if err != nil {
+		return
+	}
+	for i := range contents {
+		counts := stats[contents[i].ID]
+		contents[i].Likes = counts.Likes
+		contents[i].Dislikes = counts.Dislikes
+	}
+}
+
+// markPublished 记录首次发布时间，精确到毫秒以便与信息流游标比较
+func markPublished(content *models.Content) {
+	if content.PublishedAt == nil {
+		now := time.Now().Truncate(time.Millisecond)
+		content.PublishedAt = &now
+	}
+}
+
+// ListRankedContent 按热度、好评或争议排行获取已发布内容
+func (s *ContentService) ListRankedContent(ctx context.Context, sort, window string, page, limit int) ([]models.Content, int64, error) {
+	if s.ranking == nil {
+		return nil, 0, errors.New("ranking unavailable")
+	}
+	ids, total, err := s.ranking.ListRankedIDs(ctx, sort, window, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	contents, err := s.contentRepo.ListByIDs(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 排行更新前被撤回的内容不再展示
+	published := make([]models.Content, 0, len(contents))
+	for _, content := range contents {
+		if content.Status != "published" {
+			continue
+		}
+		published = append(published, content)
+	}
+	attachInteractionStats(s.contentRepo, published)
+	s.tags.AttachTags(ctx, published)
+
+	return published, total, nil
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"bondly-api/config"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"context"
+	"errors"
+	"time"
+)
+
+// 内容状态
+const (
+	ContentStatusDraft     = "draft"
+	ContentStatusInReview  = "in_review" // 需要审核的类型提交发布后等待审核
+	ContentStatusScheduled = "scheduled" // 到达 publish_at 后由定时任务发布
+	ContentStatusPublished = "published"
+	ContentStatusArchived  = "archived"
+)
+
+// contentStatusTransitions 允许的状态流转，状态不变始终允许
+var contentStatusTransitions = map[string][]string{
+	ContentStatusDraft:     {ContentStatusInReview, ContentStatusScheduled, ContentStatusPublished, ContentStatusArchived},
+	ContentStatusInReview:  {ContentStatusDraft, ContentStatusScheduled, ContentStatusPublished, ContentStatusArchived},
+	ContentStatusScheduled: {ContentStatusDraft, ContentStatusPublished, ContentStatusArchived},
+	ContentStatusPublished: {ContentStatusArchived},
+	ContentStatusArchived:  {ContentStatusInReview, ContentStatusPublished},
+}
+
+// ValidateContentStatusTransition 校验内容状态流转
+func ValidateContentStatusTransition(from, to string) error {
+	if _, ok := contentStatusTransitions[to]; !ok {
+		return errors.New("invalid status")
+	}
+	if from == to {
+		return nil
+	}
+	for _, next := range contentStatusTransitions[from] {
+		if next == to {
+			return nil
+		}
+	}
+	return errors.New("invalid status transition")
+}
+
+// resolveContentStatus 根据请求的状态计算内容实际进入的状态，publishAt 为生效的定时发布时间，rescheduled 表示本次修改了定时发布时间
+// 需要审核时发布和定时发布请求转为 in_review，审核通过后再按 publish_at 发布
+func resolveContentStatus(from, requested string, publishAt *time.Time, rescheduled bool, now time.Time, needsReview bool) (string, error) {
+	if requested == "" {
+		requested = from
+	}
+	if requested == ContentStatusScheduled && (from != ContentStatusScheduled || rescheduled) {
+		if publishAt == nil || !publishAt.After(now) {
+			return "", errors.New("publish_at must be in the future")
+		}
+	}
+
+	status := requested
+	if needsReview && requested != from && (requested == ContentStatusPublished || requested == ContentStatusScheduled) {
+		status = ContentStatusInReview
+	}
+	if err := ValidateContentStatusTransition(from, status); err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+// ContentWorkflowService 内容发布流程：审核要求和状态变更通知
+type ContentWorkflowService struct {
+	userRepo       *repositories.UserRepository
+	permissionRepo *repositories.UserPermissionRepository
+	emailService   *EmailService
+	reviewTypes    map[string]bool
+}
+
+func NewContentWorkflowService(userRepo *repositories.UserRepository, permissionRepo *repositories.UserPermissionRepository, emailService *EmailService, cfg config.ContentConfig) *ContentWorkflowService {
+	reviewTypes := make(map[string]bool, len(cfg.ReviewTypes))
+	for _, contentType := range cfg.ReviewTypes {
+		reviewTypes[contentType] = true
+	}
+	return &ContentWorkflowService{
+		userRepo:       userRepo,
+		permissionRepo: permissionRepo,
+		emailService:   emailService,
+		reviewTypes:    reviewTypes,
+	}
+}
+
+// RequiresReview 判断 editorID 发布该类型内容是否需要审核，管理员和内容审核员无需审核
+func (s *ContentWorkflowService) RequiresReview(ctx context.Context, contentType string, editorID int64) bool {
+	if s == nil || !s.reviewTypes[contentType] {
+		return false
+	}
+	return !s.CanModerate(ctx, editorID)
+}
+
+// CanModerate 判断用户是否可以审核内容，查询失败时按无权限处理
+func (s *ContentWorkflowService) CanModerate(ctx context.Context, userID int64) bool {
+	if s == nil {
+		return false
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return false
+	}
+	if user.Role == "admin" {
+		return true
+	}
+	granted, err := s.permissionRepo.Has(userID, models.PermissionContentModerator)
+	if err != nil {
+		loggerpkg.NewBusinessLogger(ctx).DatabaseError("select", "user_permissions", "Has", err)
+		return false
+	}
+	return granted
+}
+
+// OnStatusChanged 内容状态被他人或定时任务变更时邮件通知作者，actorID 为 0 表示系统；作者自己修改时不通知
+func (s *ContentWorkflowService) OnStatusChanged(ctx context.Context, content *models.Content, from string, actorID int64, note string) {
+	if s == nil || s.emailService == nil || content.Status == from || actorID == content.AuthorID {
+		return
+	}
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	author, err := s.userRepo.GetByID(content.AuthorID)
+	if err != nil {
+		bizLog.DatabaseError("select", "users", "GetByID", err)
+		return
+	}
+	if author.Email == nil || *author.Email == "" {
+		return
+	}
+	if err := s.emailService.SendContentStatusEmail(ctx, *author.Email, author.Nickname, content.Title, content.Status, note); err != nil {
+		bizLog.ThirdPartyError("email", "content_status", map[string]interface{}{
+			"content_id": content.ID,
+			"status":     content.Status,
+		}, err)
+	}
+}
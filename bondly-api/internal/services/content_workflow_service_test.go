@@ -0,0 +1,44 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateContentStatusTransition(t *testing.T) {
+	assert.NoError(t, ValidateContentStatusTransition(ContentStatusDraft, ContentStatusScheduled))
+	assert.NoError(t, ValidateContentStatusTransition(ContentStatusArchived, ContentStatusPublished))
+	assert.NoError(t, ValidateContentStatusTransition(ContentStatusPublished, ContentStatusPublished))
+	assert.EqualError(t, ValidateContentStatusTransition(ContentStatusArchived, ContentStatusDraft), "invalid status transition")
+	assert.EqualError(t, ValidateContentStatusTransition(ContentStatusPublished, ContentStatusScheduled), "invalid status transition")
+	assert.EqualError(t, ValidateContentStatusTransition(ContentStatusDraft, "deleted"), "invalid status")
+}
+
+func TestResolveContentStatus(t *testing.T) {
+	now := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	future := now.Add(time.Hour)
+	past := now.Add(-time.Hour)
+
+	status, err := resolveContentStatus(ContentStatusDraft, "", nil, false, now, false)
+	assert.NoError(t, err)
+	assert.Equal(t, ContentStatusDraft, status)
+
+	status, err = resolveContentStatus(ContentStatusDraft, ContentStatusScheduled, &future, true, now, false)
+	assert.NoError(t, err)
+	assert.Equal(t, ContentStatusScheduled, status)
+
+	_, err = resolveContentStatus(ContentStatusDraft, ContentStatusScheduled, &past, true, now, false)
+	assert.EqualError(t, err, "publish_at must be in the future")
+	_, err = resolveContentStatus(ContentStatusScheduled, "", &future, true, future.Add(time.Minute), false)
+	assert.EqualError(t, err, "publish_at must be in the future", "改期也必须晚于当前时间")
+
+	status, err = resolveContentStatus(ContentStatusDraft, ContentStatusPublished, nil, false, now, true)
+	assert.NoError(t, err)
+	assert.Equal(t, ContentStatusInReview, status, "需要审核时发布请求转为审核中")
+
+	status, err = resolveContentStatus(ContentStatusPublished, ContentStatusPublished, nil, false, now, true)
+	assert.NoError(t, err)
+	assert.Equal(t, ContentStatusPublished, status, "已发布的内容编辑时无需重新审核")
+}
@@ -0,0 +1,361 @@
+package services
+
+import (
+	"bondly-api/internal/blockchain"
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// creatorClaimConfirmTimeout 领取交易超过该时间仍未在链上确认时，允许重新发起领取
+const creatorClaimConfirmTimeout = 30 * time.Minute
+
+// stakingInteractionTypes InteractionStaking 合约支持的互动类型，按合约枚举顺序排列
+var stakingInteractionTypes = []string{"like", "comment", "favorite"}
+
+// stakingInteractionEnum 互动类型到合约枚举值的映射
+var stakingInteractionEnum = map[string]uint8{
+	"like":     blockchain.InteractionTypeLike,
+	"comment":  blockchain.InteractionTypeComment,
+	"favorite": blockchain.InteractionTypeFavorite,
+}
+
+// CreatorRewardService 创作者互动质押收益服务
+// 从 InteractionStaking 读取内容 NFT 的质押情况，并以创作者托管钱包领取奖励
+type CreatorRewardService struct {
+	rewardRepo    *repositories.CreatorRewardRepository
+	contentRepo   *repositories.ContentRepository
+	userRepo      *repositories.UserRepository
+	walletService *WalletService
+	staking       *blockchain.InteractionStaking
+}
+
+// NewCreatorRewardService 创建创作者收益服务，staking 为 nil 时链上查询和领取不可用
+func NewCreatorRewardService(rewardRepo *repositories.CreatorRewardRepository, contentRepo *repositories.ContentRepository, userRepo *repositories.UserRepository, walletService *WalletService, staking *blockchain.InteractionStaking) *CreatorRewardService {
+	return &CreatorRewardService{
+		rewardRepo:    rewardRepo,
+		contentRepo:   contentRepo,
+		userRepo:      userRepo,
+		walletService: walletService,
+		staking:       staking,
+	}
+}
+
+// GetContentStakes 获取内容 NFT 各互动类型的质押情况
+func (s *CreatorRewardService) GetContentStakes(ctx context.Context, contentID int64) (*dto.ContentStakesResponse, error) {
+	if s.staking == nil {
+		return nil, errors.New("interaction staking not available")
+	}
+
+	content, err := s.contentRepo.GetByID(contentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("content not found")
+		}
+		return nil, err
+	}
+	if content.NFTTokenID == nil {
+		return nil, errors.New("content not minted")
+	}
+
+	stakes, _, err := s.contentStakes(ctx, content)
+	return stakes, err
+}
+
+// GetCreatorEarnings 获取创作者收益面板：各内容 NFT 的可领取质押及已领取总额
+func (s *CreatorRewardService) GetCreatorEarnings(ctx context.Context, userID int64) (*dto.CreatorEarningsResponse, error) {
+	if s.staking == nil {
+		return nil, errors.New("interaction staking not available")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	contents, err := s.contentRepo.ListMintedByAuthor(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	earnings := &dto.CreatorEarningsResponse{
+		UserID:   userID,
+		Contents: make([]dto.ContentStakesResponse, 0, len(contents)),
+	}
+	if user.CustodyWalletAddress != nil {
+		earnings.WalletAddress = *user.CustodyWalletAddress
+	}
+
+	claimable := new(big.Int)
+	for i := range contents {
+		stakes, unclaimed, err := s.contentStakes(ctx, &contents[i])
+		if err != nil {
+			return nil, err
+		}
+		claimable.Add(claimable, unclaimed)
+		earnings.Contents = append(earnings.Contents, *stakes)
+	}
+	earnings.TotalClaimable = claimable.String()
+
+	amounts, err := s.rewardRepo.ListClaimedAmounts(userID)
+	if err != nil {
+		return nil, err
+	}
+	earnings.TotalClaimed = sumWei(amounts).String()
+
+	return earnings, nil
+}
+
+// ListClaims 分页获取创作者的奖励领取记录
+func (s *CreatorRewardService) ListClaims(ctx context.Context, userID int64, page, limit int) ([]models.CreatorRewardClaim, int64, error) {
+	offset := (page - 1) * limit
+	claims, err := s.rewardRepo.ListClaimsByUser(userID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.rewardRepo.CountClaimsByUser(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return claims, total, nil
+}
+
+// ClaimReward 以创作者托管钱包领取内容 NFT 某互动类型的奖励
+func (s *CreatorRewardService) ClaimReward(ctx context.Context, userID int64, req *dto.ClaimCreatorRewardRequest) (*models.CreatorRewardClaim, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	if s.staking == nil {
+		return nil, errors.New("interaction staking not available")
+	}
+	interactionType, ok := stakingInteractionEnum[req.InteractionType]
+	if !ok {
+		return nil, errors.New("invalid interaction type")
+	}
+
+	content, err := s.contentRepo.GetByID(req.ContentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("content not found")
+		}
+		return nil, err
+	}
+	if content.AuthorID != userID {
+		return nil, errors.New("not content creator")
+	}
+	if content.NFTTokenID == nil {
+		return nil, errors.New("content not minted")
+	}
+	tokenID := *content.NFTTokenID
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.CustodyWalletAddress == nil || user.EncryptedPrivateKey == nil {
+		return nil, errors.New("custody wallet not found")
+	}
+
+	// 合约只允许内容 NFT 元数据中的创作者领取，托管钱包不一致时交易必然回滚
+	creator, err := s.staking.ContentCreator(ctx, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(creator, *user.CustodyWalletAddress) {
+		return nil, errors.New("custody wallet is not content creator")
+	}
+
+	claimed, err := s.staking.RewardClaimed(ctx, tokenID, interactionType)
+	if err != nil {
+		return nil, err
+	}
+	if claimed {
+		s.confirmActiveClaim(ctx, content.ID, req.InteractionType)
+		return nil, errors.New("reward already claimed")
+	}
+	amount, err := s.staking.GetTotalStaked(ctx, tokenID, interactionType)
+	if err != nil {
+		return nil, err
+	}
+	if amount.Sign() <= 0 {
+		return nil, errors.New("no reward to claim")
+	}
+
+	privateKey, err := s.walletService.DecryptPrivateKey(ctx, *user.EncryptedPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先占位再发送交易，唯一索引保证同一内容的同一互动类型同时只有一笔领取
+	claim := &models.CreatorRewardClaim{
+		UserID:          userID,
+		ContentID:       content.ID,
+		TokenID:         tokenID,
+		InteractionType: req.InteractionType,
+		Amount:          amount.String(),
+		WalletAddress:   *user.CustodyWalletAddress,
+		Status:          "pending",
+		CreatedAt:       time.Now(),
+	}
+	if err := s.reserveClaim(claim); err != nil {
+		return nil, err
+	}
+
+	txHash, claimErr := s.staking.ClaimReward(ctx, tokenID, interactionType, privateKey)
+	claim.TxHash = txHash
+	switch {
+	case claimErr == nil:
+		claim.Status = "confirmed"
+	case txHash != "" && !errors.Is(claimErr, blockchain.ErrTransactionReverted):
+		// 交易已广播但未等到回执，保持 pending，之后按链上的领取状态确认
+		claim.Error = claimErr.Error()
+	default:
+		claim.Status = "failed"
+		claim.Error = claimErr.Error()
+	}
+	if claimErr != nil {
+		bizLog.ThirdPartyError("blockchain", "claimReward", map[string]interface{}{
+			"user_id":          userID,
+			"content_id":       content.ID,
+			"interaction_type": req.InteractionType,
+			"tx_hash":          txHash,
+			"status":           claim.Status,
+		}, claimErr)
+	}
+
+	if err := s.rewardRepo.UpdateClaim(claim); err != nil {
+		bizLog.DatabaseError("update", "creator_reward_claims", "UpdateClaim", err)
+		if claimErr == nil {
+			return nil, err
+		}
+	}
+	if claim.Status == "failed" {
+		return nil, claimErr
+	}
+
+	bizLog.BusinessLogic("创作者领取互动奖励", map[string]interface{}{
+		"user_id":          userID,
+		"content_id":       content.ID,
+		"token_id":         tokenID,
+		"interaction_type": req.InteractionType,
+		"amount":           claim.Amount,
+		"tx_hash":          txHash,
+		"status":           claim.Status,
+	})
+
+	return claim, nil
+}
+
+// reserveClaim 创建进行中的领取记录。已有领取时：超过确认时限仍未上链的标记为失败后重新创建，否则返回领取进行中
+func (s *CreatorRewardService) reserveClaim(claim *models.CreatorRewardClaim) error {
+	created, err := s.rewardRepo.CreateClaim(claim)
+	if err != nil || created {
+		return err
+	}
+
+	active, err := s.rewardRepo.GetActiveClaim(claim.ContentID, claim.InteractionType)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// 旧记录刚好被标记为失败
+		return s.retryReserveClaim(claim)
+	}
+	if err != nil {
+		return err
+	}
+	if active.Status == "confirmed" {
+		return errors.New("reward already claimed")
+	}
+	if time.Since(active.CreatedAt) < creatorClaimConfirmTimeout {
+		return errors.New("reward claim in progress")
+	}
+
+	// 调用方已确认链上尚未领取，超时的旧交易视为未上链
+	active.Status = "failed"
+	active.Error = "not confirmed on chain"
+	if err := s.rewardRepo.UpdateClaim(active); err != nil {
+		return err
+	}
+	return s.retryReserveClaim(claim)
+}
+
+// retryReserveClaim 释放旧记录后再次创建领取记录，仍冲突说明有并发请求
+func (s *CreatorRewardService) retryReserveClaim(claim *models.CreatorRewardClaim) error {
+	created, err := s.rewardRepo.CreateClaim(claim)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return errors.New("reward claim in progress")
+	}
+	return nil
+}
+
+// confirmActiveClaim 链上已领取时，将等待回执的领取记录标记为已确认
+func (s *CreatorRewardService) confirmActiveClaim(ctx context.Context, contentID int64, interactionType string) {
+	active, err := s.rewardRepo.GetActiveClaim(contentID, interactionType)
+	if err != nil || active.Status != "pending" {
+		return
+	}
+	active.Status = "confirmed"
+	active.Error = ""
+	if err := s.rewardRepo.UpdateClaim(active); err != nil {
+		loggerpkg.NewBusinessLogger(ctx).DatabaseError("update", "creator_reward_claims", "UpdateClaim", err)
+	}
+}
+
+// contentStakes 查询内容 NFT 各互动类型的质押，返回汇总以及尚未领取的合计
+func (s *CreatorRewardService) contentStakes(ctx context.Context, content *models.Content) (*dto.ContentStakesResponse, *big.Int, error) {
+	tokenID := *content.NFTTokenID
+	result := &dto.ContentStakesResponse{
+		ContentID: content.ID,
+		TokenID:   tokenID,
+		Title:     content.Title,
+		Stakes:    make([]dto.InteractionStakeItem, 0, len(stakingInteractionTypes)),
+	}
+
+	total := new(big.Int)
+	unclaimed := new(big.Int)
+	for _, name := range stakingInteractionTypes {
+		staked, err := s.staking.GetTotalStaked(ctx, tokenID, stakingInteractionEnum[name])
+		if err != nil {
+			return nil, nil, err
+		}
+		claimed, err := s.staking.RewardClaimed(ctx, tokenID, stakingInteractionEnum[name])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		total.Add(total, staked)
+		if !claimed {
+			unclaimed.Add(unclaimed, staked)
+		}
+		result.Stakes = append(result.Stakes, dto.InteractionStakeItem{
+			InteractionType: name,
+			TotalStaked:     staked.String(),
+			RewardClaimed:   claimed,
+		})
+	}
+	result.TotalStaked = total.String()
+
+	return result, unclaimed, nil
+}
+
+// sumWei 累加以十进制字符串保存的 wei 数量，无法解析的值忽略
+func sumWei(amounts []string) *big.Int {
+	sum := new(big.Int)
+	for _, amount := range amounts {
+		if value, ok := new(big.Int).SetString(amount, 10); ok {
+			sum.Add(sum, value)
+		}
+	}
+	return sum
+}
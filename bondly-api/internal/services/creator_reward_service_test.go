@@ -0,0 +1,19 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSumWei(t *testing.T) {
+	assert.Equal(t, "0", sumWei(nil).String())
+	assert.Equal(t, "4000000000000000000", sumWei([]string{"1000000000000000000", "3000000000000000000"}).String())
+	assert.Equal(t, "5", sumWei([]string{"2", "invalid", "3"}).String(), "无法解析的值忽略")
+}
+
+func TestStakingInteractionTypesMatchEnum(t *testing.T) {
+	for i, name := range stakingInteractionTypes {
+		assert.Equal(t, uint8(i), stakingInteractionEnum[name], name)
+	}
+}
@@ -0,0 +1,451 @@
+package services
+
+import (
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// maxDelegationTopicLength 委托主题最大长度，与 models.VoteDelegation.Topic 字段保持一致
+const maxDelegationTopicLength = 64
+
+// DelegationService 投票委托服务
+type DelegationService struct {
+	delegationRepo *repositories.DelegationRepository
+	userRepo       *repositories.UserRepository
+	eventRepo      *repositories.ReputationEventRepository
+}
+
+// NewDelegationService 创建投票委托服务
+func NewDelegationService(delegationRepo *repositories.DelegationRepository, userRepo *repositories.UserRepository, eventRepo *repositories.ReputationEventRepository) *DelegationService {
+	return &DelegationService{
+		delegationRepo: delegationRepo,
+		userRepo:       userRepo,
+		eventRepo:      eventRepo,
+	}
+}
+
+// Delegate 将投票权委托给其他用户，同一主题下的旧委托会被撤销
+// 环路检查、撤销和创建在持有委托锁的同一事务中完成，并发请求不会留下两条有效委托或漏检环路
+func (s *DelegationService) Delegate(ctx context.Context, delegatorID, delegateID int64, topic string) (*models.VoteDelegation, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	topic = normalizeTopic(topic)
+	if len(topic) > maxDelegationTopicLength {
+		return nil, errors.New("topic too long")
+	}
+	if delegatorID == delegateID {
+		return nil, errors.New("cannot delegate to yourself")
+	}
+
+	if _, err := s.userRepo.GetByID(delegateID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("delegate not found")
+		}
+		return nil, err
+	}
+
+	var delegation *models.VoteDelegation
+	err := s.delegationRepo.WithDelegationLock(func(tx *repositories.DelegationRepository) error {
+		active, err := tx.ListActive()
+		if err != nil {
+			bizLog.DatabaseError("select", "vote_delegations", "ListActive", err)
+			return err
+		}
+		if createsDelegationCycle(active, delegatorID, delegateID, topic) {
+			bizLog.ValidationFailed("delegate_id", "委托关系形成环路", delegateID)
+			return errors.New("delegation cycle detected")
+		}
+
+		now := time.Now()
+		if _, err := tx.Revoke(delegatorID, topic, now); err != nil {
+			bizLog.DatabaseError("update", "vote_delegations", "Revoke", err)
+			return err
+		}
+
+		delegation = &models.VoteDelegation{
+			DelegatorID: delegatorID,
+			DelegateID:  delegateID,
+			Topic:       topic,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := tx.Create(delegation); err != nil {
+			bizLog.DatabaseError("insert", "vote_delegations", "Create", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bizLog.BusinessLogic("创建投票委托", map[string]interface{}{
+		"delegator_id": delegatorID,
+		"delegate_id":  delegateID,
+		"topic":        topic,
+	})
+
+	return delegation, nil
+}
+
+// Undelegate 撤销指定主题下的委托
+func (s *DelegationService) Undelegate(ctx context.Context, delegatorID int64, topic string) error {
+	topic = normalizeTopic(topic)
+
+	affected, err := s.delegationRepo.Revoke(delegatorID, topic, time.Now())
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("delegation not found")
+	}
+
+	loggerpkg.NewBusinessLogger(ctx).BusinessLogic("撤销投票委托", map[string]interface{}{
+		"delegator_id": delegatorID,
+		"topic":        topic,
+	})
+
+	return nil
+}
+
+// GetUserDelegations 获取用户发出和收到的有效委托
+func (s *DelegationService) GetUserDelegations(ctx context.Context, userID int64) ([]models.VoteDelegation, []models.VoteDelegation, error) {
+	outgoing, err := s.delegationRepo.ListByDelegator(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	incoming, err := s.delegationRepo.ListByDelegate(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return outgoing, incoming, nil
+}
+
+// GetVotingPower 获取用户当前在指定主题下的投票权（自有声誉 + 可获得的委托投票权）
+func (s *DelegationService) GetVotingPower(ctx context.Context, userID int64, topic string) (*dto.VotingPowerResponse, error) {
+	topic = normalizeTopic(topic)
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	active, err := s.delegationRepo.ListActive()
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := resolveDelegations(active, topic)
+	power, err := s.loadPower(ctx, delegationUserIDs(resolved, nil), linearStrategy{})
+	if err != nil {
+		return nil, err
+	}
+
+	delegated := potentialDelegatedPower(resolved, power)[userID]
+	own := int64(user.ReputationScore)
+
+	return &dto.VotingPowerResponse{
+		UserID:         userID,
+		Topic:          topic,
+		OwnPower:       own,
+		DelegatedPower: delegated,
+		TotalPower:     own + delegated,
+	}, nil
+}
+
+// GetDelegationGraph 获取指定主题下当前有效的委托图
+func (s *DelegationService) GetDelegationGraph(ctx context.Context, topic string) (*dto.DelegationGraphResponse, error) {
+	topic = normalizeTopic(topic)
+
+	active, err := s.delegationRepo.ListActive()
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := resolveDelegations(active, topic)
+	userIDs := delegationUserIDs(resolved, nil)
+
+	users, err := s.userRepo.GetByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	power := make(map[int64]int64, len(users))
+	for _, user := range users {
+		power[user.ID] = int64(user.ReputationScore)
+	}
+	delegated := potentialDelegatedPower(resolved, power)
+
+	graph := &dto.DelegationGraphResponse{
+		Topic: topic,
+		Nodes: make([]dto.DelegationGraphNode, 0, len(users)),
+		Edges: make([]dto.DelegationGraphEdge, 0, len(resolved)),
+	}
+	for _, user := range users {
+		graph.Nodes = append(graph.Nodes, dto.DelegationGraphNode{
+			UserID:         user.ID,
+			Nickname:       user.Nickname,
+			OwnPower:       power[user.ID],
+			DelegatedPower: delegated[user.ID],
+		})
+	}
+	for _, delegation := range resolved {
+		graph.Edges = append(graph.Edges, dto.DelegationGraphEdge{
+			From:  delegation.DelegatorID,
+			To:    delegation.DelegateID,
+			Topic: delegation.Topic,
+		})
+	}
+
+	sort.Slice(graph.Nodes, func(i, j int) bool { return graph.Nodes[i].UserID < graph.Nodes[j].UserID })
+	sort.Slice(graph.Edges, func(i, j int) bool { return graph.Edges[i].From < graph.Edges[j].From })
+
+	return graph, nil
+}
+
+// ComputeVoteWeights 按投票策略计算投票人在快照时间点的自有投票权和委托投票权
+// 委托关系和声誉分数都取快照时间点的值，委托人若已直接投票，其投票权不再沿委托链传递，从而覆盖委托
+// savedPower 不为空时使用已保存的投票权快照，不再按策略读取，未保存的用户投票权为 0
+func (s *DelegationService) ComputeVoteWeights(ctx context.Context, topic string, snapshot time.Time, voterIDs []int64, strategy VotingStrategy, savedPower map[int64]int64) (map[int64]int64, map[int64]int64, error) {
+	delegations, err := s.delegationRepo.ListActiveAt(normalizeTopic(topic), snapshot)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resolved := resolveDelegations(delegations, normalizeTopic(topic))
+	power := savedPower
+	if power == nil {
+		if power, err = s.loadPowerAt(ctx, delegationUserIDs(resolved, voterIDs), strategy, snapshot); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	voters := make(map[int64]bool, len(voterIDs))
+	own := make(map[int64]int64, len(voterIDs))
+	for _, voterID := range voterIDs {
+		voters[voterID] = true
+		own[voterID] = power[voterID]
+	}
+
+	return own, allocateVotingPower(resolved, power, voters), nil
+}
+
+// VotingPowerAt 按投票策略读取快照时间点委托图中的用户和 voterIDs 中尚未保存快照的投票权，用于保存投票权快照
+func (s *DelegationService) VotingPowerAt(ctx context.Context, topic string, snapshot time.Time, voterIDs []int64, strategy VotingStrategy, saved map[int64]int64) (map[int64]int64, error) {
+	delegations, err := s.delegationRepo.ListActiveAt(normalizeTopic(topic), snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []int64
+	for _, userID := range delegationUserIDs(resolveDelegations(delegations, normalizeTopic(topic)), voterIDs) {
+		if _, ok := saved[userID]; !ok {
+			missing = append(missing, userID)
+		}
+	}
+	if len(missing) == 0 {
+		return map[int64]int64{}, nil
+	}
+
+	power, err := s.loadPowerAt(ctx, missing, strategy, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	// 没有钱包等原因未返回投票权的用户同样保存为 0，不在之后的投票中重新读取
+	for _, userID := range missing {
+		if _, ok := power[userID]; !ok {
+			power[userID] = 0
+		}
+	}
+	return power, nil
+}
+
+// loadPower 按投票策略加载用户的自有投票权
+func (s *DelegationService) loadPower(ctx context.Context, userIDs []int64, strategy VotingStrategy) (map[int64]int64, error) {
+	users, err := s.userRepo.GetByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return strategy.Power(ctx, users)
+}
+
+// loadPowerAt 按投票策略加载用户在快照时间点的自有投票权，声誉分数由账本回推
+func (s *DelegationService) loadPowerAt(ctx context.Context, userIDs []int64, strategy VotingStrategy, snapshot time.Time) (map[int64]int64, error) {
+	users, err := s.userRepo.GetByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 期初余额是账本上线前已有的分数，不从快照中扣除
+	later, err := s.eventRepo.SumByUsersAfter(userIDs, snapshot, []string{ReputationSourceOpeningBalance})
+	if err != nil {
+		return nil, err
+	}
+	snapshotReputation(users, later)
+
+	return strategy.Power(ctx, users)
+}
+
+// snapshotReputation 将用户声誉分数回推为当前分数减去快照之后的变更，不低于 0
+func snapshotReputation(users []models.User, later map[int64]int) {
+	for i := range users {
+		score := users[i].ReputationScore - later[users[i].ID]
+		if score < 0 {
+			score = 0
+		}
+		users[i].ReputationScore = score
+	}
+}
+
+// normalizeTopic 统一主题格式
+func normalizeTopic(topic string) string {
+	return strings.ToLower(strings.TrimSpace(topic))
+}
+
+// resolveDelegations 解析每个委托人在指定主题下生效的委托，主题委托优先于全局委托
+func resolveDelegations(delegations []models.VoteDelegation, topic string) map[int64]models.VoteDelegation {
+	resolved := make(map[int64]models.VoteDelegation)
+	for _, delegation := range delegations {
+		if delegation.Topic == "" {
+			if _, exists := resolved[delegation.DelegatorID]; !exists {
+				resolved[delegation.DelegatorID] = delegation
+			}
+		}
+	}
+	if topic != "" {
+		for _, delegation := range delegations {
+			if delegation.Topic == topic {
+				resolved[delegation.DelegatorID] = delegation
+			}
+		}
+	}
+	return resolved
+}
+
+// delegationUserIDs 汇总委托图中涉及的全部用户ID
+func delegationUserIDs(resolved map[int64]models.VoteDelegation, extra []int64) []int64 {
+	seen := make(map[int64]bool)
+	var ids []int64
+	add := func(id int64) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, delegation := range resolved {
+		add(delegation.DelegatorID)
+		add(delegation.DelegateID)
+	}
+	for _, id := range extra {
+		add(id)
+	}
+	return ids
+}
+
+// allocateVotingPower 将未投票用户的投票权沿委托链传递给链上第一个已投票的用户
+// 委托链末端用户未投票或链路成环时，该部分投票权不计入
+func allocateVotingPower(resolved map[int64]models.VoteDelegation, power map[int64]int64, voters map[int64]bool) map[int64]int64 {
+	received := make(map[int64]int64)
+	for userID, amount := range power {
+		if amount <= 0 || voters[userID] {
+			continue
+		}
+
+		visited := map[int64]bool{userID: true}
+		current := userID
+		for {
+			delegation, ok := resolved[current]
+			if !ok || visited[delegation.DelegateID] {
+				break
+			}
+			next := delegation.DelegateID
+			if voters[next] {
+				received[next] += amount
+				break
+			}
+			visited[next] = true
+			current = next
+		}
+	}
+	return received
+}
+
+// potentialDelegatedPower 计算每个用户在自己投票、上游用户均未投票时可获得的委托投票权
+func potentialDelegatedPower(resolved map[int64]models.VoteDelegation, power map[int64]int64) map[int64]int64 {
+	received := make(map[int64]int64)
+	for userID, amount := range power {
+		if amount <= 0 {
+			continue
+		}
+
+		visited := map[int64]bool{userID: true}
+		current := userID
+		for {
+			delegation, ok := resolved[current]
+			if !ok || visited[delegation.DelegateID] {
+				break
+			}
+			current = delegation.DelegateID
+			visited[current] = true
+			received[current] += amount
+		}
+	}
+	return received
+}
+
+// createsDelegationCycle 检查新增委托后是否会在任一主题的委托图中形成环路
+func createsDelegationCycle(active []models.VoteDelegation, delegatorID, delegateID int64, topic string) bool {
+	candidates := make([]models.VoteDelegation, 0, len(active)+1)
+	topics := map[string]bool{topic: true}
+	for _, delegation := range active {
+		if delegation.DelegatorID == delegatorID && delegation.Topic == topic {
+			continue
+		}
+		candidates = append(candidates, delegation)
+		if topic == "" {
+			// 全局委托会影响所有主题的解析结果
+			topics[delegation.Topic] = true
+		}
+	}
+	candidates = append(candidates, models.VoteDelegation{
+		DelegatorID: delegatorID,
+		DelegateID:  delegateID,
+		Topic:       topic,
+	})
+
+	for t := range topics {
+		resolved := resolveDelegations(candidates, t)
+		visited := map[int64]bool{}
+		current := delegatorID
+		for {
+			delegation, ok := resolved[current]
+			if !ok {
+				break
+			}
+			if delegation.DelegateID == delegatorID {
+				return true
+			}
+			if visited[delegation.DelegateID] {
+				break
+			}
+			visited[delegation.DelegateID] = true
+			current = delegation.DelegateID
+		}
+	}
+	return false
+}
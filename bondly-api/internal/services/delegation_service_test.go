@@ -0,0 +1,105 @@
+package services
+
+import (
+	"bondly-api/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func delegation(from, to int64, topic string) models.VoteDelegation {
+	return models.VoteDelegation{DelegatorID: from, DelegateID: to, Topic: topic}
+}
+
+func TestResolveDelegations_TopicOverridesGlobal(t *testing.T) {
+	delegations := []models.VoteDelegation{
+		delegation(1, 2, ""),
+		delegation(1, 3, "treasury"),
+		delegation(4, 2, "grants"),
+	}
+
+	global := resolveDelegations(delegations, "")
+	assert.Len(t, global, 1)
+	assert.Equal(t, int64(2), global[1].DelegateID)
+
+	treasury := resolveDelegations(delegations, "treasury")
+	assert.Len(t, treasury, 1)
+	assert.Equal(t, int64(3), treasury[1].DelegateID)
+
+	grants := resolveDelegations(delegations, "grants")
+	assert.Len(t, grants, 2)
+	assert.Equal(t, int64(2), grants[1].DelegateID)
+	assert.Equal(t, int64(2), grants[4].DelegateID)
+}
+
+func TestAllocateVotingPower(t *testing.T) {
+	// 1 -> 2 -> 3，4 -> 3
+	resolved := resolveDelegations([]models.VoteDelegation{
+		delegation(1, 2, ""),
+		delegation(2, 3, ""),
+		delegation(4, 3, ""),
+	}, "")
+	power := map[int64]int64{1: 10, 2: 20, 3: 30, 4: 40}
+
+	t.Run("委托链末端投票获得全部委托", func(t *testing.T) {
+		received := allocateVotingPower(resolved, power, map[int64]bool{3: true})
+		assert.Equal(t, int64(70), received[3])
+	})
+
+	t.Run("委托人直接投票覆盖委托", func(t *testing.T) {
+		received := allocateVotingPower(resolved, power, map[int64]bool{2: true, 3: true})
+		assert.Equal(t, int64(10), received[2])
+		assert.Equal(t, int64(40), received[3])
+	})
+
+	t.Run("链末端未投票时委托不计入", func(t *testing.T) {
+		received := allocateVotingPower(resolved, power, map[int64]bool{4: true})
+		assert.Empty(t, received)
+	})
+}
+
+func TestPotentialDelegatedPower(t *testing.T) {
+	resolved := resolveDelegations([]models.VoteDelegation{
+		delegation(1, 2, ""),
+		delegation(2, 3, ""),
+	}, "")
+	power := map[int64]int64{1: 10, 2: 20, 3: 30}
+
+	received := potentialDelegatedPower(resolved, power)
+	assert.Equal(t, int64(10), received[2])
+	assert.Equal(t, int64(30), received[3])
+	assert.Zero(t, received[1])
+}
+
+func TestCreatesDelegationCycle(t *testing.T) {
+	active := []models.VoteDelegation{
+		delegation(1, 2, ""),
+		delegation(2, 3, ""),
+		delegation(5, 1, "treasury"),
+	}
+
+	assert.True(t, createsDelegationCycle(active, 3, 1, ""), "3 -> 1 -> 2 -> 3 应形成环路")
+	assert.False(t, createsDelegationCycle(active, 4, 1, ""))
+	assert.True(t, createsDelegationCycle(active, 1, 5, "treasury"), "treasury 主题下 1 -> 5 -> 1 应形成环路")
+	assert.True(t, createsDelegationCycle(active, 1, 5, ""), "全局委托同样会影响 treasury 主题")
+	assert.False(t, createsDelegationCycle(active, 1, 4, ""), "替换 1 的旧委托不应误判为环路")
+}
+
+func TestSnapshotReputation(t *testing.T) {
+	users := []models.User{
+		{ID: 1, ReputationScore: 120},
+		{ID: 2, ReputationScore: 30},
+		{ID: 3, ReputationScore: 50},
+	}
+
+	// 用户 1 快照后获得 20 分，用户 2 快照后被扣 10 分，用户 3 快照后没有变更
+	snapshotReputation(users, map[int64]int{1: 20, 2: -10})
+	assert.Equal(t, 100, users[0].ReputationScore)
+	assert.Equal(t, 40, users[1].ReputationScore)
+	assert.Equal(t, 50, users[2].ReputationScore)
+
+	// 快照后的变更超过当前分数时按 0 处理
+	late := []models.User{{ID: 4, ReputationScore: 5}}
+	snapshotReputation(late, map[int64]int{4: 8})
+	assert.Equal(t, 0, late[0].ReputationScore)
+}
@@ -4,6 +4,7 @@ import (
 	"bondly-api/internal/email"
 	"context"
 	"fmt"
+	"html"
 
 	"github.com/sirupsen/logrus"
 )
@@ -163,3 +164,92 @@ func (s *EmailService) SendWelcomeEmail(ctx context.Context, toEmail, nickname s
 	log.Info("欢迎邮件发送成功")
 	return nil
 }
+
+// contentStatusLabels 内容状态在邮件中的中文名称
+var contentStatusLabels = map[string]string{
+	ContentStatusDraft:     "草稿",
+	ContentStatusInReview:  "审核中",
+	ContentStatusScheduled: "定时发布",
+	ContentStatusPublished: "已发布",
+	ContentStatusArchived:  "已归档",
+}
+
+// SendContentStatusEmail 发送内容状态变更通知邮件，note 为审核意见，可为空
+func (s *EmailService) SendContentStatusEmail(ctx context.Context, toEmail, nickname, title, status, note string) error {
+	log := s.logger.WithFields(logrus.Fields{
+		"to_email": toEmail,
+		"status":   status,
+		"action":   "send_content_status_email",
+	})
+
+	label := contentStatusLabels[status]
+	if label == "" {
+		label = status
+	}
+	subject := fmt.Sprintf("您的内容状态已变更为「%s」 - Bondly", label)
+
+	noteHTML := ""
+	if note != "" {
+		noteHTML = fmt.Sprintf("<p>审核意见：%s</p>", html.EscapeString(note))
+	}
+	body := fmt.Sprintf(`
+<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+    <meta charset="UTF-8">
+    <title>内容状态变更 - Bondly</title>
+</head>
+<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; line-height: 1.6; color: #333;">
+    <p>亲爱的 <strong>%s</strong>，</p>
+    <p>您的内容《%s》状态已变更为 <strong>%s</strong>。</p>
+    %s
+    <p style="color: #64748b; font-size: 12px;">此邮件由系统自动发送，请勿回复</p>
+</body>
+</html>`, html.EscapeString(nickname), html.EscapeString(title), label, noteHTML)
+
+	if err := s.emailSender.Send(toEmail, subject, body); err != nil {
+		log.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Error("内容状态通知邮件发送失败")
+		return fmt.Errorf("failed to send content status email: %w", err)
+	}
+
+	log.Info("内容状态通知邮件发送成功")
+	return nil
+}
+
+// SendDigestEmail 发送通知摘要邮件，发送器支持时附带 RFC 8058 一键退订邮件头
+func (s *EmailService) SendDigestEmail(ctx context.Context, toEmail string, data email.DigestEmailData) error {
+	log := s.logger.WithFields(logrus.Fields{
+		"to_email": toEmail,
+		"period":   data.PeriodLabel,
+		"action":   "send_digest_email",
+	})
+
+	body, err := email.NewDigestEmailTemplate().Render(data)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Error("邮件模板渲染失败")
+		return fmt.Errorf("failed to render email template: %w", err)
+	}
+	subject := fmt.Sprintf("您的%s动态摘要 - %s", data.PeriodLabel, data.ServiceName)
+
+	if sender, ok := s.emailSender.(email.HeaderEmailSender); ok {
+		err = sender.SendWithHeaders(toEmail, subject, body, map[string]string{
+			"List-Unsubscribe":      "<" + data.UnsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		})
+	} else {
+		err = s.emailSender.Send(toEmail, subject, body)
+	}
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Error("摘要邮件发送失败")
+		return fmt.Errorf("failed to send digest email: %w", err)
+	}
+
+	log.Info("摘要邮件发送成功")
+	return nil
+}
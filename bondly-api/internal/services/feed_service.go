@@ -0,0 +1,451 @@
+package services
+
+import (
+	"bondly-api/config"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/redis"
+	"bondly-api/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// feedSentinel 信息流占位成员，分数为0，使没有内容的信息流也能被缓存；读取时按分数排除
+const feedSentinel = "0"
+
+// feedTieAllowance 按游标读取 Redis 时额外多取的条数，用于跳过与游标同一毫秒的已读条目
+const feedTieAllowance = 10
+
+// feedPushScript 仅在用户信息流已构建时写入新内容，并裁剪到最大条数；未构建的信息流在读取时从数据库重建
+var feedPushScript = goredis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2])
+	redis.call('ZREMRANGEBYRANK', KEYS[1], 0, -(tonumber(ARGV[3]) + 1))
+end
+return 0
+`)
+
+// FeedCursor 信息流分页游标，指向上一页最后一条内容
+type FeedCursor struct {
+	PublishedAt time.Time
+	ID          int64
+}
+
+// FeedService 个性化信息流服务
+// 普通作者发布内容时写扩散到粉丝的 Redis 有序集合；粉丝数达到阈值的作者在读取时从数据库拉取
+type FeedService struct {
+	redisClient     *redis.RedisClient
+	contentRepo     *repositories.ContentRepository
+	userFollowRepo  *repositories.UserFollowRepository
+	tags            *TagService
+	fanoutThreshold int64
+	maxItems        int64
+	ttl             time.Duration
+}
+
+func NewFeedService(redisClient *redis.RedisClient, contentRepo *repositories.ContentRepository, userFollowRepo *repositories.UserFollowRepository, tags *TagService, cfg config.FeedConfig) *FeedService {
+	return &FeedService{
+		redisClient:     redisClient,
+		contentRepo:     contentRepo,
+		userFollowRepo:  userFollowRepo,
+		tags:            tags,
+		fanoutThreshold: cfg.FanoutThreshold,
+		maxItems:        cfg.MaxItems,
+		ttl:             cfg.TTL,
+	}
+}
+
+// GetFeed 获取用户关注作者发布的内容，按发布时间倒序；返回下一页游标，没有更多时为空
+func (s *FeedService) GetFeed(ctx context.Context, userID int64, cursorStr string, limit int) ([]models.Content, string, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	cursor, err := ParseFeedCursor(cursorStr)
+	if err != nil {
+		return nil, "", err
+	}
+
+	followingIDs, err := s.userFollowRepo.ListFollowingIDs(userID)
+	if err != nil {
+		bizLog.DatabaseError("select", "user_followers", "ListFollowingIDs", err)
+		return nil, "", err
+	}
+	if len(followingIDs) == 0 {
+		return []models.Content{}, "", nil
+	}
+	popularIDs, err := s.userFollowRepo.ListPopularIDs(followingIDs, s.fanoutThreshold)
+	if err != nil {
+		bizLog.DatabaseError("select", "user_followers", "ListPopularIDs", err)
+		return nil, "", err
+	}
+	regularIDs := excludeIDs(followingIDs, popularIDs)
+	s.markPopular(ctx, popularIDs)
+
+	// 多取一条用于判断是否还有下一页
+	need := limit + 1
+	pushed, err := s.readPushed(ctx, userID, regularIDs, cursor, need)
+	if err != nil {
+		return nil, "", err
+	}
+	pulled, err := s.contentRepo.ListFeedEntries(popularIDs, cursor.PublishedAt, cursor.ID, need)
+	if err != nil {
+		bizLog.DatabaseError("select", "contents", "ListFeedEntries", err)
+		return nil, "", err
+	}
+
+	entries := mergeFeedEntries(pushed, pulled, need)
+	nextCursor := ""
+	if len(entries) > limit {
+		entries = entries[:limit]
+		last := entries[len(entries)-1]
+		nextCursor = EncodeFeedCursor(FeedCursor{PublishedAt: last.PublishedAt, ID: last.ID})
+	}
+
+	ids := make([]int64, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+	contents, err := s.contentRepo.ListByIDs(ids)
+	if err != nil {
+		bizLog.DatabaseError("select", "contents", "ListByIDs", err)
+		return nil, "", err
+	}
+
+	// 写入信息流后被撤回或删除的内容不再展示
+	visible := make([]models.Content, 0, len(contents))
+	for _, content := range contents {
+		if content.Status != "published" {
+			continue
+		}
+		visible = append(visible, content)
+	}
+	attachInteractionStats(s.contentRepo, visible)
+	s.tags.AttachTags(ctx, visible)
+
+	return visible, nextCursor, nil
+}
+
+// OnContentPublished 内容发布时写扩散到粉丝已构建的信息流，粉丝数达到阈值的作者跳过
+func (s *FeedService) OnContentPublished(ctx context.Context, content *models.Content) {
+	if s == nil || content.PublishedAt == nil {
+		return
+	}
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	followers, err := s.userFollowRepo.CountFollowers(content.AuthorID)
+	if err != nil {
+		bizLog.DatabaseError("select", "user_followers", "CountFollowers", err)
+		return
+	}
+	if followers >= s.fanoutThreshold {
+		s.markPopular(ctx, []int64{content.AuthorID})
+		return
+	}
+	if followers == 0 {
+		return
+	}
+	followerIDs, err := s.userFollowRepo.ListFollowerIDs(content.AuthorID)
+	if err != nil {
+		bizLog.DatabaseError("select", "user_followers", "ListFollowerIDs", err)
+		return
+	}
+
+	score := feedScore(*content.PublishedAt)
+	_, err = s.redisClient.GetClient().Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for _, followerID := range followerIDs {
+			feedPushScript.Eval(ctx, pipe, []string{feedKey(followerID)}, score, content.ID, s.maxItems)
+		}
+		return nil
+	})
+	if err != nil {
+		bizLog.ThirdPartyError("redis", "feed_fanout", map[string]interface{}{
+			"content_id": content.ID,
+			"followers":  len(followerIDs),
+		}, err)
+		return
+	}
+
+	bizLog.BusinessLogic("信息流写扩散", map[string]interface{}{
+		"content_id": content.ID,
+		"author_id":  content.AuthorID,
+		"followers":  len(followerIDs),
+	})
+}
+
+// InvalidateFeed 关注关系变化时删除用户的信息流缓存，下次读取时重建
+func (s *FeedService) InvalidateFeed(ctx context.Context, userID int64) {
+	if s == nil {
+		return
+	}
+	if err := s.redisClient.Del(ctx, feedKey(userID)); err != nil {
+		loggerpkg.NewBusinessLogger(ctx).ThirdPartyError("redis", "feed_invalidate", map[string]interface{}{
+			"user_id": userID,
+		}, err)
+	}
+}
+
+// OnUnfollow 取消关注时删除该用户的信息流缓存
+// 作者粉丝数降到阈值以下后改为写扩散，读扩散期间发布的内容不在粉丝已构建的信息流中，因此同时删除其所有粉丝的信息流缓存
+func (s *FeedService) OnUnfollow(ctx context.Context, followerID, followedID int64) {
+	if s == nil {
+		return
+	}
+	s.InvalidateFeed(ctx, followerID)
+
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+	followers, err := s.userFollowRepo.CountFollowers(followedID)
+	if err != nil {
+		bizLog.DatabaseError("select", "user_followers", "CountFollowers", err)
+		return
+	}
+	if followers >= s.fanoutThreshold {
+		return
+	}
+	// 只有删除到标记的请求负责清理，并发的取消关注不会重复删除
+	deleted, err := s.redisClient.GetClient().Del(ctx, feedPopularKey(followedID)).Result()
+	if err != nil {
+		bizLog.ThirdPartyError("redis", "feed_popular_unmark", map[string]interface{}{"author_id": followedID}, err)
+		return
+	}
+	if deleted == 0 {
+		return
+	}
+
+	followerIDs, err := s.userFollowRepo.ListFollowerIDs(followedID)
+	if err != nil {
+		bizLog.DatabaseError("select", "user_followers", "ListFollowerIDs", err)
+		s.markPopular(ctx, []int64{followedID})
+		return
+	}
+	_, err = s.redisClient.GetClient().Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for _, id := range followerIDs {
+			pipe.Del(ctx, feedKey(id))
+		}
+		return nil
+	})
+	if err != nil {
+		bizLog.ThirdPartyError("redis", "feed_invalidate_followers", map[string]interface{}{
+			"author_id": followedID,
+			"followers": len(followerIDs),
+		}, err)
+		// 恢复标记，下次取消关注时重试
+		s.markPopular(ctx, []int64{followedID})
+		return
+	}
+
+	bizLog.BusinessLogic("作者恢复写扩散，删除粉丝信息流缓存", map[string]interface{}{
+		"author_id": followedID,
+		"followers": len(followerIDs),
+	})
+}
+
+// markPopular 标记作者正在读扩散，有效期与信息流缓存相同：期间构建的信息流都不包含其内容
+func (s *FeedService) markPopular(ctx context.Context, authorIDs []int64) {
+	if len(authorIDs) == 0 {
+		return
+	}
+	_, err := s.redisClient.GetClient().Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for _, id := range authorIDs {
+			pipe.Set(ctx, feedPopularKey(id), 1, s.ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		loggerpkg.NewBusinessLogger(ctx).ThirdPartyError("redis", "feed_popular_mark", map[string]interface{}{
+			"authors": len(authorIDs),
+		}, err)
+	}
+}
+
+// readPushed 从 Redis 读取写扩散部分，信息流未构建时先从数据库重建；Redis 不可用或翻页超出缓存范围时回退到数据库
+func (s *FeedService) readPushed(ctx context.Context, userID int64, authorIDs []int64, cursor FeedCursor, need int) ([]repositories.FeedEntry, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+	if len(authorIDs) == 0 {
+		return nil, nil
+	}
+
+	entries, full, err := s.readCached(ctx, userID, authorIDs, cursor, need)
+	if err != nil {
+		bizLog.ThirdPartyError("redis", "feed_read", map[string]interface{}{"user_id": userID}, err)
+		return s.contentRepo.ListFeedEntries(authorIDs, cursor.PublishedAt, cursor.ID, need)
+	}
+	if len(entries) >= need || !full {
+		return entries, nil
+	}
+
+	// 缓存已被裁剪，更早的内容从数据库补齐
+	from := cursor
+	if len(entries) > 0 {
+		last := entries[len(entries)-1]
+		from = FeedCursor{PublishedAt: last.PublishedAt, ID: last.ID}
+	}
+	older, err := s.contentRepo.ListFeedEntries(authorIDs, from.PublishedAt, from.ID, need-len(entries))
+	if err != nil {
+		return nil, err
+	}
+	return append(entries, older...), nil
+}
+
+// readCached 读取 Redis 中游标之前的条目，full 表示缓存已达到最大条数
+func (s *FeedService) readCached(ctx context.Context, userID int64, authorIDs []int64, cursor FeedCursor, need int) ([]repositories.FeedEntry, bool, error) {
+	key := feedKey(userID)
+	exists, err := s.redisClient.Exists(ctx, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if exists == 0 {
+		if err := s.rebuild(ctx, key, authorIDs); err != nil {
+			return nil, false, err
+		}
+	}
+
+	max := "+inf"
+	if !cursor.PublishedAt.IsZero() {
+		max = strconv.FormatFloat(feedScore(cursor.PublishedAt), 'f', 0, 64)
+	}
+	members, err := s.redisClient.ZRevRangeByScoreWithScores(ctx, key, &goredis.ZRangeBy{
+		Min:   "(0",
+		Max:   max,
+		Count: int64(need + feedTieAllowance),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	size, err := s.redisClient.ZCard(ctx, key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	entries := make([]repositories.FeedEntry, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseInt(fmt.Sprint(member.Member), 10, 64)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, repositories.FeedEntry{ID: id, PublishedAt: time.UnixMilli(int64(member.Score))})
+	}
+	entries = mergeFeedEntries(filterBeforeCursor(entries, cursor), nil, need)
+	return entries, size >= s.maxItems, nil
+}
+
+// rebuild 从数据库重建用户的信息流缓存
+func (s *FeedService) rebuild(ctx context.Context, key string, authorIDs []int64) error {
+	entries, err := s.contentRepo.ListFeedEntries(authorIDs, time.Time{}, 0, int(s.maxItems))
+	if err != nil {
+		return err
+	}
+
+	members := make([]goredis.Z, 0, len(entries)+1)
+	members = append(members, goredis.Z{Score: 0, Member: feedSentinel})
+	for _, entry := range entries {
+		members = append(members, goredis.Z{Score: feedScore(entry.PublishedAt), Member: entry.ID})
+	}
+	_, err = s.redisClient.GetClient().TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.ZAdd(ctx, key, members...)
+		pipe.Expire(ctx, key, s.ttl)
+		return nil
+	})
+	return err
+}
+
+// feedKey 用户信息流的 Redis 键
+func feedKey(userID int64) string {
+	return fmt.Sprintf("feed:user:%d", userID)
+}
+
+// feedPopularKey 作者近期处于读扩散的标记键
+func feedPopularKey(authorID int64) string {
+	return fmt.Sprintf("feed:popular:%d", authorID)
+}
+
+// feedScore 信息流分数为发布时间的毫秒时间戳
+func feedScore(t time.Time) float64 {
+	return float64(t.UnixMilli())
+}
+
+// EncodeFeedCursor 编码分页游标，格式为 毫秒时间戳_内容ID
+func EncodeFeedCursor(cursor FeedCursor) string {
+	return fmt.Sprintf("%d_%d", cursor.PublishedAt.UnixMilli(), cursor.ID)
+}
+
+// ParseFeedCursor 解析分页游标，空字符串表示第一页
+func ParseFeedCursor(value string) (FeedCursor, error) {
+	if value == "" {
+		return FeedCursor{}, nil
+	}
+	parts := strings.SplitN(value, "_", 2)
+	if len(parts) != 2 {
+		return FeedCursor{}, errors.New("invalid cursor")
+	}
+	millis, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || millis <= 0 {
+		return FeedCursor{}, errors.New("invalid cursor")
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || id <= 0 {
+		return FeedCursor{}, errors.New("invalid cursor")
+	}
+	return FeedCursor{PublishedAt: time.UnixMilli(millis), ID: id}, nil
+}
+
+// filterBeforeCursor 保留排在游标之后（更早）的条目
+func filterBeforeCursor(entries []repositories.FeedEntry, cursor FeedCursor) []repositories.FeedEntry {
+	if cursor.PublishedAt.IsZero() {
+		return entries
+	}
+	result := make([]repositories.FeedEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.PublishedAt.Before(cursor.PublishedAt) ||
+			(entry.PublishedAt.Equal(cursor.PublishedAt) && entry.ID < cursor.ID) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// mergeFeedEntries 合并写扩散与读扩散的条目，按内容ID去重，按发布时间和ID倒序，最多返回 limit 条
+func mergeFeedEntries(a, b []repositories.FeedEntry, limit int) []repositories.FeedEntry {
+	seen := make(map[int64]bool, len(a)+len(b))
+	merged := make([]repositories.FeedEntry, 0, len(a)+len(b))
+	for _, entries := range [][]repositories.FeedEntry{a, b} {
+		for _, entry := range entries {
+			if seen[entry.ID] {
+				continue
+			}
+			seen[entry.ID] = true
+			merged = append(merged, entry)
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		if !merged[i].PublishedAt.Equal(merged[j].PublishedAt) {
+			return merged[i].PublishedAt.After(merged[j].PublishedAt)
+		}
+		return merged[i].ID > merged[j].ID
+	})
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged
+}
+
+// excludeIDs 返回 ids 中不在 excluded 内的ID
+func excludeIDs(ids, excluded []int64) []int64 {
+	skip := make(map[int64]bool, len(excluded))
+	for _, id := range excluded {
+		skip[id] = true
+	}
+	result := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !skip[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
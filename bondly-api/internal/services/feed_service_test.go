@@ -0,0 +1,56 @@
+package services
+
+import (
+	"bondly-api/internal/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedCursorRoundTrip(t *testing.T) {
+	cursor := FeedCursor{PublishedAt: time.UnixMilli(1700000000123), ID: 42}
+
+	parsed, err := ParseFeedCursor(EncodeFeedCursor(cursor))
+	assert.NoError(t, err)
+	assert.True(t, parsed.PublishedAt.Equal(cursor.PublishedAt))
+	assert.Equal(t, int64(42), parsed.ID)
+
+	first, err := ParseFeedCursor("")
+	assert.NoError(t, err)
+	assert.True(t, first.PublishedAt.IsZero(), "空游标表示第一页")
+
+	for _, invalid := range []string{"abc", "123", "123_x", "-1_5", "123_0"} {
+		_, err := ParseFeedCursor(invalid)
+		assert.EqualError(t, err, "invalid cursor", invalid)
+	}
+}
+
+func TestMergeFeedEntries(t *testing.T) {
+	at := func(ms int64) time.Time { return time.UnixMilli(ms) }
+	pushed := []repositories.FeedEntry{{ID: 5, PublishedAt: at(500)}, {ID: 3, PublishedAt: at(300)}}
+	pulled := []repositories.FeedEntry{{ID: 4, PublishedAt: at(300)}, {ID: 5, PublishedAt: at(500)}, {ID: 1, PublishedAt: at(100)}}
+
+	merged := mergeFeedEntries(pushed, pulled, 3)
+	ids := make([]int64, 0, len(merged))
+	for _, entry := range merged {
+		ids = append(ids, entry.ID)
+	}
+	assert.Equal(t, []int64{5, 4, 3}, ids, "去重并按发布时间、ID倒序")
+}
+
+func TestFilterBeforeCursor(t *testing.T) {
+	at := func(ms int64) time.Time { return time.UnixMilli(ms) }
+	entries := []repositories.FeedEntry{{ID: 9, PublishedAt: at(300)}, {ID: 7, PublishedAt: at(300)}, {ID: 6, PublishedAt: at(200)}}
+
+	filtered := filterBeforeCursor(entries, FeedCursor{PublishedAt: at(300), ID: 8})
+	assert.Len(t, filtered, 2)
+	assert.Equal(t, int64(7), filtered[0].ID, "同一毫秒内ID更小的条目保留")
+
+	assert.Equal(t, entries, filterBeforeCursor(entries, FeedCursor{}))
+}
+
+func TestExcludeIDs(t *testing.T) {
+	assert.Equal(t, []int64{1, 3}, excludeIDs([]int64{1, 2, 3}, []int64{2}))
+	assert.Equal(t, []int64{}, excludeIDs([]int64{2}, []int64{2}))
+}
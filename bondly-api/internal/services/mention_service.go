@@ -0,0 +1,204 @@
+package services
+
+import (
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// 提及来源类型
+const (
+	MentionSourceComment = "comment"
+	MentionSourceContent = "content"
+)
+
+// maxMentionsPerText 单条评论或内容最多解析的提及数，超出部分忽略
+const maxMentionsPerText = 20
+
+var (
+	// mentionPattern 匹配 @ 后的昵称或钱包地址，@ 前不能是字母数字，以排除邮箱地址
+	mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_@.])@([\p{L}\p{N}_][\p{L}\p{N}_.-]*)`)
+	walletPattern  = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	// mentionCodePattern Markdown 代码块和行内代码，其中的 @ 不视为提及
+	mentionCodePattern = regexp.MustCompile("(?s)```.*?```|`[^`\n]*`")
+)
+
+// ParsedMentions 文本中解析出的提及
+type ParsedMentions struct {
+	Nicknames []string
+	Wallets   []string
+}
+
+// ParseMentions 解析文本中的 @昵称 和 @0x钱包地址，忽略代码中的内容；按出现顺序去重（不区分大小写）
+func ParseMentions(text string) ParsedMentions {
+	parsed := ParsedMentions{}
+	seen := make(map[string]bool)
+	text = mentionCodePattern.ReplaceAllString(text, " ")
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		name := strings.TrimRight(match[1], ".-")
+		if name == "" || utf8.RuneCountInString(name) > 64 {
+			continue
+		}
+		key := strings.ToLower(name)
+		if seen[key] {
+			continue
+		}
+		if len(seen) >= maxMentionsPerText {
+			break
+		}
+		seen[key] = true
+		if walletPattern.MatchString(name) {
+			parsed.Wallets = append(parsed.Wallets, name)
+		} else {
+			parsed.Nicknames = append(parsed.Nicknames, name)
+		}
+	}
+	return parsed
+}
+
+// MentionService 解析评论和内容中的提及，记录并通知被提及的用户
+type MentionService struct {
+	mentionRepo   *repositories.MentionRepository
+	userRepo      *repositories.UserRepository
+	notifications *NotificationService
+}
+
+func NewMentionService(mentionRepo *repositories.MentionRepository, userRepo *repositories.UserRepository, notifications *NotificationService) *MentionService {
+	return &MentionService{
+		mentionRepo:   mentionRepo,
+		userRepo:      userRepo,
+		notifications: notifications,
+	}
+}
+
+// OnCommentSaved 评论创建或编辑后记录新增的提及，被隐藏或删除的评论不处理
+func (s *MentionService) OnCommentSaved(ctx context.Context, comment *models.Comment) {
+	if s == nil || comment.Status != CommentStatusVisible {
+		return
+	}
+	s.record(ctx, MentionSourceComment, comment.ID, comment.AuthorID, comment.Content)
+}
+
+// OnContentPublished 内容发布或已发布内容修改后记录新增的提及，草稿中的提及不通知
+func (s *MentionService) OnContentPublished(ctx context.Context, content *models.Content) {
+	if s == nil || content.Status != ContentStatusPublished {
+		return
+	}
+	s.record(ctx, MentionSourceContent, content.ID, content.AuthorID, content.Content)
+}
+
+// ListMentions 分页获取提及用户的记录
+func (s *MentionService) ListMentions(ctx context.Context, userID int64, page, limit int) ([]models.Mention, int64, error) {
+	return s.mentionRepo.ListByUser(userID, (page-1)*limit, limit)
+}
+
+// record 解析并记录提及，同一来源只对首次被提及的用户发送通知；失败只记录日志
+func (s *MentionService) record(ctx context.Context, sourceType string, sourceID int64, authorID int64, text string) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	userIDs, err := s.resolve(ParseMentions(text))
+	if err != nil {
+		bizLog.DatabaseError("select", "users", "ResolveMentions", err)
+		return
+	}
+	if len(userIDs) == 0 {
+		return
+	}
+	existing, err := s.mentionRepo.ListMentionedUserIDs(sourceType, sourceID)
+	if err != nil {
+		bizLog.DatabaseError("select", "mentions", "ListMentionedUserIDs", err)
+		return
+	}
+	recorded := make(map[int64]bool, len(existing))
+	for _, id := range existing {
+		recorded[id] = true
+	}
+
+	now := time.Now()
+	mentions := make([]models.Mention, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if userID == authorID || recorded[userID] {
+			continue
+		}
+		mentions = append(mentions, models.Mention{
+			SourceType:      sourceType,
+			SourceID:        sourceID,
+			MentionedUserID: userID,
+			AuthorID:        authorID,
+			CreatedAt:       now,
+		})
+	}
+	if err := s.mentionRepo.CreateBatch(mentions); err != nil {
+		bizLog.DatabaseError("create", "mentions", "CreateBatch", err)
+		return
+	}
+	for _, mention := range mentions {
+		s.notifications.Notify(ctx, &models.Notification{
+			UserID:      mention.MentionedUserID,
+			Type:        NotificationTypeMention,
+			ActorID:     authorID,
+			SubjectType: sourceType,
+			SubjectID:   sourceID,
+			CreatedAt:   now,
+		})
+	}
+}
+
+// resolve 将提及解析为用户ID：钱包地址按绑定的钱包查找，昵称只在唯一匹配一个用户时生效
+func (s *MentionService) resolve(parsed ParsedMentions) ([]int64, error) {
+	ids := []int64{}
+	seen := make(map[int64]bool)
+	add := func(id int64) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for _, wallet := range parsed.Wallets {
+		user, err := s.userRepo.GetByWalletAddress(wallet)
+		if errors.Is(err, gorm.ErrRecordNotFound) && wallet != strings.ToLower(wallet) {
+			user, err = s.userRepo.GetByWalletAddress(strings.ToLower(wallet))
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		add(user.ID)
+	}
+
+	users, err := s.userRepo.ListByNicknames(parsed.Nicknames)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range uniqueNicknameMatches(parsed.Nicknames, users) {
+		add(id)
+	}
+	return ids, nil
+}
+
+// uniqueNicknameMatches 按提及顺序返回昵称唯一对应的用户ID，多个用户同名时无法确定被提及者而忽略
+func uniqueNicknameMatches(nicknames []string, users []models.User) []int64 {
+	byNickname := make(map[string][]int64)
+	for _, user := range users {
+		key := strings.ToLower(user.Nickname)
+		byNickname[key] = append(byNickname[key], user.ID)
+	}
+	ids := []int64{}
+	for _, nickname := range nicknames {
+		if matches := byNickname[strings.ToLower(nickname)]; len(matches) == 1 {
+			ids = append(ids, matches[0])
+		}
+	}
+	return ids
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"bondly-api/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMentions(t *testing.T) {
+	wallet := "0x52908400098527886E0F7030069857D2E4169EE7"
+	parsed := ParseMentions("hi @alice, @Bob. and @alice again; ping @" + wallet + " or mail bob@example.com")
+	assert.Equal(t, []string{"alice", "Bob"}, parsed.Nicknames)
+	assert.Equal(t, []string{wallet}, parsed.Wallets)
+
+	// 代码中的 @ 不是提及
+	parsed = ParseMentions("see `@carol` and\n```\n@dave\n```\n(@小明)")
+	assert.Equal(t, []string{"小明"}, parsed.Nicknames)
+	assert.Empty(t, parsed.Wallets)
+}
+
+func TestParseMentionsLimit(t *testing.T) {
+	text := ""
+	for i := 0; i < maxMentionsPerText+5; i++ {
+		text += " @user" + string(rune('a'+i))
+	}
+	assert.Len(t, ParseMentions(text).Nicknames, maxMentionsPerText)
+}
+
+func TestUniqueNicknameMatches(t *testing.T) {
+	users := []models.User{
+		{ID: 1, Nickname: "Alice"},
+		{ID: 2, Nickname: "Anonymous"},
+		{ID: 3, Nickname: "anonymous"},
+	}
+	assert.Equal(t, []int64{1}, uniqueNicknameMatches([]string{"anonymous", "alice", "nobody"}, users))
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"bondly-api/config"
+	"bondly-api/internal/email"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"context"
+	"fmt"
+	"time"
+)
+
+// 邮件摘要参数
+const (
+	digestRecipientBatch = 200 // 每批处理的用户数
+	maxDigestActivities  = 20  // 摘要中最多列出的动态数
+	maxDigestContents    = 10  // 摘要中最多列出的关注作者新内容数
+)
+
+// digestPeriods 各摘要频率的统计周期
+var digestPeriods = map[string]time.Duration{
+	DigestFrequencyDaily:  24 * time.Hour,
+	DigestFrequencyWeekly: 7 * 24 * time.Hour,
+}
+
+// digestPeriodLabels 摘要频率在邮件中的名称
+var digestPeriodLabels = map[string]string{
+	DigestFrequencyDaily:  "每日",
+	DigestFrequencyWeekly: "每周",
+}
+
+// notificationDigestLabels 各类通知在摘要中的描述，跟在触发者昵称之后
+var notificationDigestLabels = map[string]string{
+	NotificationTypeMention:       "提到了你",
+	NotificationTypeFollow:        "关注了你",
+	NotificationTypeComment:       "评论了你的内容",
+	NotificationTypeReply:         "回复了你的评论",
+	NotificationTypeLike:          "赞了你的内容",
+	NotificationTypeAirdrop:       "空投交易",
+	NotificationTypeProposalEnded: "提案已结束",
+}
+
+// NotificationDigestService 按用户设置的频率汇总通知和关注作者的新内容，发送摘要邮件
+type NotificationDigestService struct {
+	preferenceRepo   *repositories.NotificationPreferenceRepository
+	notificationRepo *repositories.NotificationRepository
+	userFollowRepo   *repositories.UserFollowRepository
+	contentRepo      *repositories.ContentRepository
+	userRepo         *repositories.UserRepository
+	preferences      *NotificationPreferenceService
+	emailService     *EmailService
+	checkInterval    time.Duration
+}
+
+func NewNotificationDigestService(preferenceRepo *repositories.NotificationPreferenceRepository, notificationRepo *repositories.NotificationRepository, userFollowRepo *repositories.UserFollowRepository, contentRepo *repositories.ContentRepository, userRepo *repositories.UserRepository, preferences *NotificationPreferenceService, emailService *EmailService, cfg config.DigestConfig) *NotificationDigestService {
+	return &NotificationDigestService{
+		preferenceRepo:   preferenceRepo,
+		notificationRepo: notificationRepo,
+		userFollowRepo:   userFollowRepo,
+		contentRepo:      contentRepo,
+		userRepo:         userRepo,
+		preferences:      preferences,
+		emailService:     emailService,
+		checkInterval:    cfg.CheckInterval,
+	}
+}
+
+// SendDue 为到期的用户发送摘要，返回发送的邮件数。单个用户失败只记录日志，下次检查时重试
+func (s *NotificationDigestService) SendDue(ctx context.Context) (int, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+	sent := 0
+	for _, frequency := range []string{DigestFrequencyDaily, DigestFrequencyWeekly} {
+		now := time.Now()
+		sentBefore := digestDueBefore(frequency, now, s.checkInterval)
+		var afterID int64
+		for {
+			recipients, err := s.preferenceRepo.ListDigestRecipients(frequency, defaultDigestFrequency, sentBefore, afterID, digestRecipientBatch)
+			if err != nil {
+				return sent, err
+			}
+			if len(recipients) == 0 {
+				break
+			}
+			for _, recipient := range recipients {
+				if ctx.Err() != nil {
+					return sent, ctx.Err()
+				}
+				delivered, err := s.send(ctx, recipient, frequency, sentBefore, now)
+				if err != nil {
+					bizLog.ThirdPartyError("email", "notification_digest", map[string]interface{}{"user_id": recipient.UserID}, err)
+					continue
+				}
+				if delivered {
+					sent++
+				}
+			}
+			afterID = recipients[len(recipients)-1].UserID
+		}
+	}
+	return sent, nil
+}
+
+// send 先认领再汇总并发送一个用户的摘要，已被其他实例认领时跳过；发送失败时撤销认领，下次检查时重试
+// 期间没有任何动态时不发邮件，但同样记录为已发送，下一期从现在开始统计
+func (s *NotificationDigestService) send(ctx context.Context, recipient repositories.DigestRecipient, frequency string, sentBefore, now time.Time) (bool, error) {
+	claimed, err := s.preferenceRepo.ClaimDigest(recipient.UserID, frequency, sentBefore, now)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	delivered, err := s.compose(ctx, recipient, frequency, now)
+	if err != nil {
+		if releaseErr := s.preferenceRepo.ReleaseDigest(recipient.UserID, recipient.LastDigestAt, now); releaseErr != nil {
+			loggerpkg.NewBusinessLogger(ctx).DatabaseError("update", "notification_settings", "ReleaseDigest", releaseErr)
+		}
+		return false, err
+	}
+	return delivered, nil
+}
+
+// compose 汇总一个用户本期的通知和关注作者的新内容，有动态时发送摘要邮件，返回是否发送
+func (s *NotificationDigestService) compose(ctx context.Context, recipient repositories.DigestRecipient, frequency string, now time.Time) (bool, error) {
+	since := digestPeriodStart(recipient.LastDigestAt, frequency, now)
+
+	preferences, err := s.preferenceRepo.ListByUser(recipient.UserID)
+	if err != nil {
+		return false, err
+	}
+	notifications, total, err := s.notificationRepo.ListBetween(recipient.UserID, digestNotificationTypes(resolveNotificationChannels(preferences)), since, now, maxDigestActivities)
+	if err != nil {
+		return false, err
+	}
+	followingIDs, err := s.userFollowRepo.ListFollowingIDs(recipient.UserID)
+	if err != nil {
+		return false, err
+	}
+	contents, err := s.contentRepo.ListPublishedBetween(followingIDs, since, now, maxDigestContents)
+	if err != nil {
+		return false, err
+	}
+
+	delivered := len(notifications) > 0 || len(contents) > 0
+	if delivered {
+		actors, err := s.actorNicknames(notifications)
+		if err != nil {
+			return false, err
+		}
+		data := email.DigestEmailData{
+			ServiceName:    "Bondly",
+			Nickname:       recipient.Nickname,
+			PeriodLabel:    digestPeriodLabels[frequency],
+			MoreActivities: total - int64(len(notifications)),
+			UnsubscribeURL: s.preferences.UnsubscribeURL(recipient.UserID),
+		}
+		for _, notification := range notifications {
+			data.Activities = append(data.Activities, email.DigestActivity{
+				Text: digestActivityText(notification, actors[notification.ActorID]),
+				Time: notification.CreatedAt.Format("01-02 15:04"),
+			})
+		}
+		for _, content := range contents {
+			item := email.DigestContent{Title: content.Title, Author: content.Author.Nickname}
+			if content.PublishedAt != nil {
+				item.PublishedAt = content.PublishedAt.Format("01-02 15:04")
+			}
+			data.NewContents = append(data.NewContents, item)
+		}
+		if err := s.emailService.SendDigestEmail(ctx, recipient.Email, data); err != nil {
+			return false, err
+		}
+	}
+	return delivered, nil
+}
+
+// actorNicknames 查询通知触发者的昵称
+func (s *NotificationDigestService) actorNicknames(notifications []models.Notification) (map[int64]string, error) {
+	ids := make([]int64, 0, len(notifications))
+	for _, notification := range notifications {
+		if notification.ActorID != 0 {
+			ids = append(ids, notification.ActorID)
+		}
+	}
+	nicknames := make(map[int64]string, len(ids))
+	if len(ids) == 0 {
+		return nicknames, nil
+	}
+	users, err := s.userRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		nicknames[user.ID] = user.Nickname
+	}
+	return nicknames, nil
+}
+
+// digestDueBefore 上次发送不晚于该时间的用户到期；提前一个检查间隔，避免发送时间随检查时刻逐期后移
+func digestDueBefore(frequency string, now time.Time, checkInterval time.Duration) time.Time {
+	return now.Add(-digestPeriods[frequency] + checkInterval)
+}
+
+// digestPeriodStart 本期摘要的统计起点：上次发送时间，从未发送时为一个周期之前
+func digestPeriodStart(lastDigestAt *time.Time, frequency string, now time.Time) time.Time {
+	if lastDigestAt != nil {
+		return *lastDigestAt
+	}
+	return now.Add(-digestPeriods[frequency])
+}
+
+// digestNotificationTypes 接收方式为 email 的通知类型，按 NotificationTypes 的顺序返回
+func digestNotificationTypes(channels map[string]string) []string {
+	types := []string{}
+	for _, notificationType := range NotificationTypes {
+		if channels[notificationType] == NotificationChannelEmail {
+			types = append(types, notificationType)
+		}
+	}
+	return types
+}
+
+// digestActivityText 通知在摘要中的描述，如「Alice 赞了你的内容《标题》」
+func digestActivityText(notification models.Notification, actor string) string {
+	text := notificationDigestLabels[notification.Type]
+	if text == "" {
+		text = notification.Type
+	}
+	if notification.Type == NotificationTypeAirdrop && notification.Data["status"] != "" {
+		text = fmt.Sprintf("%s%s", text, airdropStatusLabel(notification.Data["status"]))
+	}
+	if title := notification.Data["title"]; title != "" {
+		text = fmt.Sprintf("%s《%s》", text, title)
+	}
+	if actor != "" {
+		text = actor + " " + text
+	}
+	return text
+}
+
+// airdropStatusLabel 空投状态在摘要中的描述
+func airdropStatusLabel(status string) string {
+	switch status {
+	case "success":
+		return "已确认"
+	case "failed":
+		return "失败"
+	default:
+		return status
+	}
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"bondly-api/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDigestSchedule(t *testing.T) {
+	now := time.Date(2024, 5, 8, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 5, 7, 11, 0, 0, 0, time.UTC), digestDueBefore(DigestFrequencyDaily, now, time.Hour))
+	assert.Equal(t, time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC), digestDueBefore(DigestFrequencyWeekly, now, time.Hour))
+
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), digestPeriodStart(nil, DigestFrequencyWeekly, now), "从未发送时统计一个周期")
+	last := time.Date(2024, 5, 7, 9, 30, 0, 0, time.UTC)
+	assert.Equal(t, last, digestPeriodStart(&last, DigestFrequencyDaily, now))
+}
+
+func TestDigestNotificationTypes(t *testing.T) {
+	channels := resolveNotificationChannels([]models.NotificationPreference{
+		{Type: NotificationTypeLike, Channel: NotificationChannelEmail},
+		{Type: NotificationTypeComment, Channel: NotificationChannelInApp},
+	})
+	assert.Equal(t, []string{NotificationTypeMention, NotificationTypeReply, NotificationTypeLike, NotificationTypeAirdrop, NotificationTypeProposalEnded}, digestNotificationTypes(channels))
+}
+
+func TestDigestActivityText(t *testing.T) {
+	assert.Equal(t, "Alice 赞了你的内容《Hello》", digestActivityText(models.Notification{
+		Type: NotificationTypeLike,
+		Data: map[string]string{"title": "Hello"},
+	}, "Alice"))
+	assert.Equal(t, "Bob 关注了你", digestActivityText(models.Notification{Type: NotificationTypeFollow}, "Bob"))
+	assert.Equal(t, "空投交易已确认", digestActivityText(models.Notification{
+		Type: NotificationTypeAirdrop,
+		Data: map[string]string{"status": "success", "tx_hash": "0xabc"},
+	}, ""))
+	assert.Equal(t, "提案已结束《Treasury grant》", digestActivityText(models.Notification{
+		Type: NotificationTypeProposalEnded,
+		Data: map[string]string{"title": "Treasury grant", "status": "passed"},
+	}, ""))
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"bondly-api/config"
+	"bondly-api/internal/email"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 通知接收方式
+const (
+	NotificationChannelInApp = "in_app" // 只在站内通知中心展示
+	NotificationChannelEmail = "email"  // 站内展示，并汇总进邮件摘要
+	NotificationChannelOff   = "off"    // 不产生通知
+)
+
+// 邮件摘要频率
+const (
+	DigestFrequencyDaily  = "daily"
+	DigestFrequencyWeekly = "weekly"
+	DigestFrequencyOff    = "off"
+)
+
+// defaultDigestFrequency 用户未设置时的摘要频率，摘要邮件需用户主动开启
+const defaultDigestFrequency = DigestFrequencyOff
+
+// unsubscribeTokenTTL 退订链接的有效期，足够覆盖翻看旧摘要邮件的时间
+const unsubscribeTokenTTL = 90 * 24 * time.Hour
+
+// NotificationTypes 可设置偏好的通知类型
+var NotificationTypes = []string{
+	NotificationTypeMention,
+	NotificationTypeFollow,
+	NotificationTypeComment,
+	NotificationTypeReply,
+	NotificationTypeLike,
+	NotificationTypeAirdrop,
+	NotificationTypeProposalEnded,
+}
+
+// defaultNotificationChannels 用户未设置时各类通知的接收方式，数量多的关注和点赞默认不进邮件摘要
+var defaultNotificationChannels = map[string]string{
+	NotificationTypeMention:       NotificationChannelEmail,
+	NotificationTypeFollow:        NotificationChannelInApp,
+	NotificationTypeComment:       NotificationChannelEmail,
+	NotificationTypeReply:         NotificationChannelEmail,
+	NotificationTypeLike:          NotificationChannelInApp,
+	NotificationTypeAirdrop:       NotificationChannelEmail,
+	NotificationTypeProposalEnded: NotificationChannelEmail,
+}
+
+// NotificationPreferences 用户的通知偏好
+type NotificationPreferences struct {
+	DigestFrequency string            `json:"digest_frequency"` // daily、weekly 或 off
+	Channels        map[string]string `json:"channels"`         // 通知类型 -> in_app、email 或 off
+}
+
+// NotificationPreferenceService 通知偏好和邮件摘要退订
+type NotificationPreferenceService struct {
+	preferenceRepo    *repositories.NotificationPreferenceRepository
+	publicURL         string
+	unsubscribeSecret string
+}
+
+func NewNotificationPreferenceService(preferenceRepo *repositories.NotificationPreferenceRepository, cfg config.DigestConfig) *NotificationPreferenceService {
+	return &NotificationPreferenceService{
+		preferenceRepo:    preferenceRepo,
+		publicURL:         cfg.PublicURL,
+		unsubscribeSecret: cfg.UnsubscribeSecret,
+	}
+}
+
+// GetPreferences 获取用户的通知偏好，未设置的项返回默认值
+func (s *NotificationPreferenceService) GetPreferences(ctx context.Context, userID int64) (*NotificationPreferences, error) {
+	preferences, err := s.preferenceRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	frequency, err := s.digestFrequency(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &NotificationPreferences{
+		DigestFrequency: frequency,
+		Channels:        resolveNotificationChannels(preferences),
+	}, nil
+}
+
+// UpdatePreferences 修改摘要频率和各类通知的接收方式，digestFrequency 为空或 channels 中未出现的类型保持不变
+func (s *NotificationPreferenceService) UpdatePreferences(ctx context.Context, userID int64, digestFrequency string, channels map[string]string) (*NotificationPreferences, error) {
+	if digestFrequency != "" && !validDigestFrequency(digestFrequency) {
+		return nil, errors.New("invalid digest frequency")
+	}
+	now := time.Now()
+	preferences := make([]models.NotificationPreference, 0, len(channels))
+	for notificationType, channel := range channels {
+		if _, ok := defaultNotificationChannels[notificationType]; !ok {
+			return nil, errors.New("invalid notification type")
+		}
+		if channel != NotificationChannelInApp && channel != NotificationChannelEmail && channel != NotificationChannelOff {
+			return nil, errors.New("invalid channel")
+		}
+		preferences = append(preferences, models.NotificationPreference{UserID: userID, Type: notificationType, Channel: channel, UpdatedAt: now})
+	}
+
+	if err := s.preferenceRepo.Upsert(preferences); err != nil {
+		return nil, err
+	}
+	if digestFrequency != "" {
+		if err := s.preferenceRepo.SetDigestFrequency(userID, digestFrequency, now); err != nil {
+			return nil, err
+		}
+	}
+	return s.GetPreferences(ctx, userID)
+}
+
+// FilterRecipients 去掉关闭了该类通知的用户；查询失败时按默认设置全部保留
+func (s *NotificationPreferenceService) FilterRecipients(ctx context.Context, userIDs []int64, notificationType string) []int64 {
+	if s == nil || len(userIDs) == 0 {
+		return userIDs
+	}
+	preferences, err := s.preferenceRepo.ListByType(userIDs, notificationType)
+	if err != nil {
+		loggerpkg.NewBusinessLogger(ctx).DatabaseError("select", "notification_preferences", "ListByType", err)
+		return userIDs
+	}
+	off := make(map[int64]bool, len(preferences))
+	for _, preference := range preferences {
+		off[preference.UserID] = preference.Channel == NotificationChannelOff
+	}
+	recipients := make([]int64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if !off[userID] {
+			recipients = append(recipients, userID)
+		}
+	}
+	return recipients
+}
+
+// UnsubscribeURL 用户的一键退订链接
+func (s *NotificationPreferenceService) UnsubscribeURL(userID int64) string {
+	token := SignUnsubscribeToken(s.unsubscribeSecret, userID, time.Now().Add(unsubscribeTokenTTL))
+	return s.publicURL + "/api/v1/notifications/unsubscribe?token=" + url.QueryEscape(token)
+}
+
+// Unsubscribe 校验退订链接的签名并关闭该用户的邮件摘要
+func (s *NotificationPreferenceService) Unsubscribe(ctx context.Context, token string) error {
+	userID, err := VerifyUnsubscribeToken(s.unsubscribeSecret, token, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := s.preferenceRepo.SetDigestFrequency(userID, DigestFrequencyOff, time.Now()); err != nil {
+		return err
+	}
+	loggerpkg.NewBusinessLogger(ctx).BusinessLogic("退订邮件摘要", map[string]interface{}{"user_id": userID})
+	return nil
+}
+
+// RenderUnsubscribePage 渲染退订页面并返回 token 是否有效；有效且未退订时展示确认按钮，提交到同一链接
+func (s *NotificationPreferenceService) RenderUnsubscribePage(token string, done bool) (string, bool, error) {
+	_, err := VerifyUnsubscribeToken(s.unsubscribeSecret, token, time.Now())
+	valid := err == nil
+	page, err := email.NewUnsubscribePageTemplate().Render(email.UnsubscribePageData{
+		ServiceName: "Bondly",
+		ActionURL:   s.publicURL + "/api/v1/notifications/unsubscribe?token=" + url.QueryEscape(token),
+		Done:        done,
+		Invalid:     !valid,
+	})
+	return page, valid, err
+}
+
+// digestFrequency 获取用户的摘要频率，未设置时返回默认频率
+func (s *NotificationPreferenceService) digestFrequency(userID int64) (string, error) {
+	setting, err := s.preferenceRepo.GetSetting(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return defaultDigestFrequency, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return setting.DigestFrequency, nil
+}
+
+// resolveNotificationChannels 合并用户设置与默认接收方式，忽略已不存在的通知类型
+func resolveNotificationChannels(preferences []models.NotificationPreference) map[string]string {
+	channels := make(map[string]string, len(defaultNotificationChannels))
+	for notificationType, channel := range defaultNotificationChannels {
+		channels[notificationType] = channel
+	}
+	for _, preference := range preferences {
+		if _, ok := channels[preference.Type]; ok {
+			channels[preference.Type] = preference.Channel
+		}
+	}
+	return channels
+}
+
+// validDigestFrequency 判断摘要频率是否合法
+func validDigestFrequency(frequency string) bool {
+	return frequency == DigestFrequencyDaily || frequency == DigestFrequencyWeekly || frequency == DigestFrequencyOff
+}
+
+// SignUnsubscribeToken 生成退订令牌：用户ID.过期时间戳.签名
+func SignUnsubscribeToken(secret string, userID int64, expiresAt time.Time) string {
+	payload := strconv.FormatInt(userID, 10) + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "." + unsubscribeSignature(secret, payload)
+}
+
+// VerifyUnsubscribeToken 校验退订令牌的签名和有效期并返回用户ID；未配置密钥时拒绝所有令牌
+func VerifyUnsubscribeToken(secret, token string, now time.Time) (int64, error) {
+	invalid := errors.New("invalid unsubscribe token")
+	if secret == "" {
+		return 0, invalid
+	}
+	sep := strings.LastIndex(token, ".")
+	if sep < 0 {
+		return 0, invalid
+	}
+	payload, signature := token[:sep], token[sep+1:]
+	if !hmac.Equal([]byte(signature), []byte(unsubscribeSignature(secret, payload))) {
+		return 0, invalid
+	}
+	id, expires, ok := strings.Cut(payload, ".")
+	if !ok {
+		return 0, invalid
+	}
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || userID <= 0 {
+		return 0, invalid
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > expiresAt {
+		return 0, invalid
+	}
+	return userID, nil
+}
+
+// unsubscribeSignature 对用户ID和过期时间做 HMAC-SHA256 签名，加用途和版本前缀避免与其他签名或旧格式令牌混用
+func unsubscribeSignature(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "unsubscribe:digest:v2:%s", payload)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
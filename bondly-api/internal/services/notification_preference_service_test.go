@@ -0,0 +1,51 @@
+package services
+
+import (
+	"bondly-api/internal/models"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnsubscribeToken(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	token := SignUnsubscribeToken("secret", 42, now.Add(time.Hour))
+	userID, err := VerifyUnsubscribeToken("secret", token, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), userID)
+
+	_, err = VerifyUnsubscribeToken("other-secret", token, now)
+	assert.EqualError(t, err, "invalid unsubscribe token", "密钥不同")
+	_, err = VerifyUnsubscribeToken("", SignUnsubscribeToken("", 42, now.Add(time.Hour)), now)
+	assert.EqualError(t, err, "invalid unsubscribe token", "未配置密钥时拒绝")
+
+	_, err = VerifyUnsubscribeToken("secret", token, now.Add(2*time.Hour))
+	assert.EqualError(t, err, "invalid unsubscribe token", "已过期")
+
+	signature := token[strings.LastIndex(token, ".")+1:]
+	_, err = VerifyUnsubscribeToken("secret", "43."+strconv.FormatInt(now.Add(time.Hour).Unix(), 10)+"."+signature, now)
+	assert.EqualError(t, err, "invalid unsubscribe token", "篡改用户ID")
+	_, err = VerifyUnsubscribeToken("secret", "42."+strconv.FormatInt(now.Add(48*time.Hour).Unix(), 10)+"."+signature, now)
+	assert.EqualError(t, err, "invalid unsubscribe token", "篡改过期时间")
+
+	_, err = VerifyUnsubscribeToken("secret", "42", now)
+	assert.EqualError(t, err, "invalid unsubscribe token")
+	_, err = VerifyUnsubscribeToken("secret", "", now)
+	assert.EqualError(t, err, "invalid unsubscribe token")
+}
+
+func TestResolveNotificationChannels(t *testing.T) {
+	channels := resolveNotificationChannels([]models.NotificationPreference{
+		{Type: NotificationTypeLike, Channel: NotificationChannelEmail},
+		{Type: NotificationTypeMention, Channel: NotificationChannelOff},
+		{Type: "removed_type", Channel: NotificationChannelEmail},
+	})
+	assert.Len(t, channels, len(NotificationTypes))
+	assert.Equal(t, NotificationChannelEmail, channels[NotificationTypeLike])
+	assert.Equal(t, NotificationChannelOff, channels[NotificationTypeMention])
+	assert.Equal(t, NotificationChannelInApp, channels[NotificationTypeFollow], "未设置时使用默认值")
+	assert.NotContains(t, channels, "removed_type")
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/redis"
+	"bondly-api/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// 通知类型
+const (
+	NotificationTypeMention       = "mention"        // 在评论或内容中被 @ 提及
+	NotificationTypeFollow        = "follow"         // 被关注
+	NotificationTypeComment       = "comment"        // 自己的内容收到评论
+	NotificationTypeReply         = "reply"          // 自己的评论收到回复
+	NotificationTypeLike          = "like"           // 自己的内容被点赞
+	NotificationTypeAirdrop       = "airdrop"        // 空投交易确认完成或失败
+	NotificationTypeProposalEnded = "proposal_ended" // 发起或投票过的提案结束
+)
+
+// unreadCountTTL 未读数缓存有效期。通知变化时删除缓存而不是增减；
+// 统计与写入缓存之间产生的通知可能被较早的统计结果覆盖，短有效期限制了这种偏差持续的时间
+const unreadCountTTL = time.Minute
+
+// dedupedNotificationTypes 同一触发者对同一对象只通知一次的类型，如取消点赞后再次点赞
+var dedupedNotificationTypes = map[string]bool{
+	NotificationTypeLike: true,
+}
+
+// NotificationService 站内通知
+type NotificationService struct {
+	notificationRepo *repositories.NotificationRepository
+	preferences      *NotificationPreferenceService
+	blocks           *UserBlockService
+	realtime         *RealtimeService
+	redisClient      *redis.RedisClient
+}
+
+func NewNotificationService(notificationRepo *repositories.NotificationRepository, preferences *NotificationPreferenceService, blocks *UserBlockService, realtime *RealtimeService, redisClient *redis.RedisClient) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		preferences:      preferences,
+		blocks:           blocks,
+		realtime:         realtime,
+		redisClient:      redisClient,
+	}
+}
+
+// Notify 创建通知；用户自己触发的、接收者关闭了该类通知的、以及接收者与触发者之间存在屏蔽的通知不发送。失败只记录日志
+func (s *NotificationService) Notify(ctx context.Context, notification *models.Notification) {
+	if s == nil {
+		return
+	}
+	s.NotifyMany(ctx, []int64{notification.UserID}, *notification)
+}
+
+// NotifyMany 以 template 为模板向多个用户发送同一条通知，过滤规则同 Notify
+func (s *NotificationService) NotifyMany(ctx context.Context, userIDs []int64, template models.Notification) {
+	if s == nil || len(userIDs) == 0 {
+		return
+	}
+	if template.CreatedAt.IsZero() {
+		template.CreatedAt = time.Now()
+	}
+
+	notifications := make([]models.Notification, 0, len(userIDs))
+	recipients := s.preferences.FilterRecipients(ctx, notificationRecipients(userIDs, template.ActorID), template.Type)
+	for _, userID := range recipients {
+		if s.blocks.IsBlocked(ctx, userID, template.ActorID) {
+			continue
+		}
+		notification := template
+		notification.UserID = userID
+		notifications = append(notifications, notification)
+	}
+	if len(notifications) == 0 {
+		return
+	}
+
+	notifications, err := s.create(notifications, template.Type)
+	if err != nil {
+		loggerpkg.NewBusinessLogger(ctx).DatabaseError("create", "notifications", "NotifyMany", err)
+		return
+	}
+	for _, notification := range notifications {
+		s.invalidateUnread(ctx, notification.UserID)
+		s.realtime.Publish(ctx, UserNotificationsTopic(notification.UserID), RealtimeEventNotificationCreated, notification)
+	}
+}
+
+// ListNotifications 分页获取用户的通知
+func (s *NotificationService) ListNotifications(ctx context.Context, userID int64, unreadOnly bool, page, limit int) ([]models.Notification, int64, error) {
+	return s.notificationRepo.List(userID, unreadOnly, (page-1)*limit, limit)
+}
+
+// GetUnreadCount 获取用户的未读通知数，优先读取 Redis 缓存
+func (s *NotificationService) GetUnreadCount(ctx context.Context, userID int64) (int64, error) {
+	key := unreadCountKey(userID)
+	if cached, err := s.redisClient.GetClient().Get(ctx, key).Result(); err == nil {
+		if count, err := strconv.ParseInt(cached, 10, 64); err == nil {
+			return count, nil
+		}
+	} else if !errors.Is(err, goredis.Nil) {
+		loggerpkg.NewBusinessLogger(ctx).ThirdPartyError("redis", "notification_unread_get", map[string]interface{}{"user_id": userID}, err)
+	}
+
+	count, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return 0, err
+	}
+	// 只在缓存仍为空时写入，不覆盖并发请求写入的结果
+	if err := s.redisClient.GetClient().SetNX(ctx, key, count, unreadCountTTL).Err(); err != nil {
+		loggerpkg.NewBusinessLogger(ctx).ThirdPartyError("redis", "notification_unread_set", map[string]interface{}{"user_id": userID}, err)
+	}
+	return count, nil
+}
+
+// MarkRead 将一条通知标记为已读，已读时不变
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id int64) error {
+	if _, err := s.notificationRepo.GetByID(userID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("notification not found")
+		}
+		return err
+	}
+	updated, err := s.notificationRepo.MarkRead(userID, id, time.Now())
+	if err != nil {
+		return err
+	}
+	if updated > 0 {
+		s.invalidateUnread(ctx, userID)
+	}
+	return nil
+}
+
+// MarkAllRead 将用户的全部通知标记为已读，返回标记的条数
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	updated, err := s.notificationRepo.MarkAllRead(userID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	// 删除缓存而不是置 0，避免覆盖标记期间新产生的通知
+	s.invalidateUnread(ctx, userID)
+	return updated, nil
+}
+
+// create 写入通知并返回实际创建的通知；去重类型逐条写入，跳过已通知过的接收者
+func (s *NotificationService) create(notifications []models.Notification, notificationType string) ([]models.Notification, error) {
+	if !dedupedNotificationTypes[notificationType] {
+		return notifications, s.notificationRepo.CreateBatch(notifications)
+	}
+	created := make([]models.Notification, 0, len(notifications))
+	for i := range notifications {
+		ok, err := s.notificationRepo.CreateOnce(&notifications[i])
+		if err != nil {
+			return created, err
+		}
+		if ok {
+			created = append(created, notifications[i])
+		}
+	}
+	return created, nil
+}
+
+// invalidateUnread 删除用户的未读数缓存，下次读取时从数据库重新统计
+func (s *NotificationService) invalidateUnread(ctx context.Context, userID int64) {
+	if err := s.redisClient.Del(ctx, unreadCountKey(userID)); err != nil {
+		loggerpkg.NewBusinessLogger(ctx).ThirdPartyError("redis", "notification_unread_invalidate", map[string]interface{}{"user_id": userID}, err)
+	}
+}
+
+// notificationRecipients 按顺序去重接收者，并去掉无效ID和触发者本人
+func notificationRecipients(userIDs []int64, actorID int64) []int64 {
+	recipients := make([]int64, 0, len(userIDs))
+	seen := make(map[int64]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if userID == 0 || userID == actorID || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		recipients = append(recipients, userID)
+	}
+	return recipients
+}
+
+// unreadCountKey 用户未读通知数的 Redis 键
+func unreadCountKey(userID int64) string {
+	return fmt.Sprintf("notifications:unread:%d", userID)
+}
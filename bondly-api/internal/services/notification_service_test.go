@@ -0,0 +1,17 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationRecipients(t *testing.T) {
+	assert.Equal(t, []int64{3, 5}, notificationRecipients([]int64{3, 0, 7, 5, 3, 7}, 7))
+	assert.Equal(t, []int64{1, 2}, notificationRecipients([]int64{1, 2, 1}, 0), "系统通知没有触发者")
+	assert.Empty(t, notificationRecipients([]int64{7}, 7))
+}
+
+func TestUnreadCountKey(t *testing.T) {
+	assert.Equal(t, "notifications:unread:42", unreadCountKey(42))
+}
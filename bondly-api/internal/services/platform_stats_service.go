@@ -0,0 +1,285 @@
+package services
+
+import (
+	"bondly-api/internal/blockchain"
+	"bondly-api/internal/cache"
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"context"
+	"math/big"
+	"time"
+)
+
+// platformStatsCacheKey 当前平台统计在 Redis 中的缓存键
+const platformStatsCacheKey = "stats:platform"
+
+// stakersBatchSize 统计质押用户时每批读取的钱包用户数量
+const stakersBatchSize = 200
+
+// PlatformStatsService 平台统计服务
+// 聚合任务定期把用户、内容、提案数量及 GeneralStaking 的质押情况写入每日快照并缓存，接口只读取快照
+type PlatformStatsService struct {
+	statsRepo    *repositories.PlatformStatsRepository
+	userRepo     *repositories.UserRepository
+	contentRepo  *repositories.ContentRepository
+	proposalRepo *repositories.ProposalRepository
+	staking      *blockchain.GeneralStaking
+	cacheService cache.CacheService
+	cacheTTL     time.Duration
+}
+
+// NewPlatformStatsService 创建平台统计服务，staking 为 nil 时沿用上一次的质押数据
+func NewPlatformStatsService(statsRepo *repositories.PlatformStatsRepository, userRepo *repositories.UserRepository, contentRepo *repositories.ContentRepository, proposalRepo *repositories.ProposalRepository, staking *blockchain.GeneralStaking, cacheService cache.CacheService, aggregateInterval time.Duration) *PlatformStatsService {
+	return &PlatformStatsService{
+		statsRepo:    statsRepo,
+		userRepo:     userRepo,
+		contentRepo:  contentRepo,
+		proposalRepo: proposalRepo,
+		staking:      staking,
+		cacheService: cacheService,
+		cacheTTL:     2 * aggregateInterval,
+	}
+}
+
+// GetStats 获取当前平台统计，优先读取缓存，其次读取最近的快照
+// 聚合只在定时任务中执行（启动时先执行一次），首个快照生成前返回全零统计且不缓存
+func (s *PlatformStatsService) GetStats(ctx context.Context) (*dto.StatsData, error) {
+	var stats dto.StatsData
+	if err := s.cacheService.Get(ctx, platformStatsCacheKey, &stats); err == nil {
+		return &stats, nil
+	}
+
+	latest, err := s.statsRepo.GetLatest()
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		return &dto.StatsData{TotalValueLocked: "0"}, nil
+	}
+
+	result := platformStatsData(latest)
+	s.cacheService.Set(ctx, platformStatsCacheKey, result, s.cacheTTL)
+	return result, nil
+}
+
+// GetDailySeries 获取最近 days 天的每日统计，缺失的日期沿用前一天的累计值
+func (s *PlatformStatsService) GetDailySeries(ctx context.Context, days int) ([]dto.StatsDailyPoint, error) {
+	today := statsDay(time.Now())
+	from := today.AddDate(0, 0, -(days - 1))
+
+	rows, err := s.statsRepo.ListDaily(from, today)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := s.statsRepo.GetLatestBefore(from)
+	if err != nil {
+		return nil, err
+	}
+
+	return fillDailySeries(rows, previous, from, days), nil
+}
+
+// Aggregate 重新计算今天和昨天的统计快照并刷新缓存，返回今天的快照
+// 昨天的快照在跨日后补齐最终的累计值；链上质押数据只能读取当前值，因此只写入今天
+func (s *PlatformStatsService) Aggregate(ctx context.Context) (*models.PlatformDailyStat, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	now := time.Now()
+	today := statsDay(now)
+	yesterday := today.AddDate(0, 0, -1)
+
+	latest, err := s.statsRepo.GetLatest()
+	if err != nil {
+		return nil, err
+	}
+
+	if latest != nil && latest.Date.Equal(yesterday) {
+		// 昨天的最终快照沿用昨天最后一次读取的链上数据
+		final, err := s.countDay(yesterday, now)
+		if err != nil {
+			return nil, err
+		}
+		final.ActiveStakers = latest.ActiveStakers
+		final.TotalValueLocked = latest.TotalValueLocked
+		if err := s.statsRepo.UpsertDaily(final); err != nil {
+			bizLog.DatabaseError("upsert", "platform_daily_stats", "UpsertDaily", err)
+			return nil, err
+		}
+	}
+
+	stat, err := s.countDay(today, now)
+	if err != nil {
+		return nil, err
+	}
+
+	stat.TotalValueLocked = "0"
+	if latest != nil {
+		stat.ActiveStakers = latest.ActiveStakers
+		stat.TotalValueLocked = latest.TotalValueLocked
+	}
+	if s.staking != nil {
+		if tvl, stakers, err := s.readStaking(ctx); err != nil {
+			// 链上不可用时沿用上一次的质押数据
+			bizLog.ThirdPartyError("blockchain", "platform_stats_staking", nil, err)
+		} else {
+			stat.TotalValueLocked = tvl.String()
+			stat.ActiveStakers = stakers
+		}
+	}
+
+	if err := s.statsRepo.UpsertDaily(stat); err != nil {
+		bizLog.DatabaseError("upsert", "platform_daily_stats", "UpsertDaily", err)
+		return nil, err
+	}
+	s.cacheService.Set(ctx, platformStatsCacheKey, platformStatsData(stat), s.cacheTTL)
+
+	return stat, nil
+}
+
+// countDay 统计某日结束时（今天为当前时刻）的累计数量及当日新增数量
+func (s *PlatformStatsService) countDay(day, now time.Time) (*models.PlatformDailyStat, error) {
+	end := day.AddDate(0, 0, 1)
+	if end.After(now) {
+		end = now
+	}
+
+	users, newUsers, err := countCreated(s.userRepo.CountCreatedBefore, day, end)
+	if err != nil {
+		return nil, err
+	}
+	contents, newContents, err := countCreated(s.contentRepo.CountCreatedBefore, day, end)
+	if err != nil {
+		return nil, err
+	}
+	proposals, newProposals, err := countCreated(s.proposalRepo.CountCreatedBefore, day, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PlatformDailyStat{
+		Date:           day,
+		TotalUsers:     users,
+		TotalContent:   contents,
+		TotalProposals: proposals,
+		NewUsers:       newUsers,
+		NewContent:     newContents,
+		NewProposals:   newProposals,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// countCreated 统计 end 之前创建的累计数量，以及 [start, end) 内的新增数量
+func countCreated(countBefore func(time.Time) (int64, error), start, end time.Time) (int64, int64, error) {
+	total, err := countBefore(end)
+	if err != nil {
+		return 0, 0, err
+	}
+	before, err := countBefore(start)
+	if err != nil {
+		return 0, 0, err
+	}
+	return total, total - before, nil
+}
+
+// readStaking 读取 GeneralStaking 总质押量，并统计有质押的钱包用户数
+func (s *PlatformStatsService) readStaking(ctx context.Context) (*big.Int, int64, error) {
+	tvl, err := s.staking.TotalStaked(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var stakers int64
+	var afterID int64
+	for {
+		users, err := s.userRepo.ListWithWallet(afterID, stakersBatchSize)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(users) == 0 {
+			break
+		}
+		addresses := make([]string, 0, len(users))
+		for _, user := range users {
+			addresses = append(addresses, *user.WalletAddress)
+		}
+		// 每批一次 JSON-RPC 批量请求，而不是每个钱包一次调用
+		staked, err := s.staking.GetStakedBatch(ctx, addresses)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, address := range addresses {
+			if amount := staked[address]; amount != nil && amount.Sign() > 0 {
+				stakers++
+			}
+		}
+		afterID = users[len(users)-1].ID
+		if len(users) < stakersBatchSize {
+			break
+		}
+	}
+
+	return tvl, stakers, nil
+}
+
+// platformStatsData 将快照转换为接口响应
+func platformStatsData(stat *models.PlatformDailyStat) *dto.StatsData {
+	return &dto.StatsData{
+		TotalUsers:       stat.TotalUsers,
+		TotalContent:     stat.TotalContent,
+		TotalProposals:   stat.TotalProposals,
+		ActiveStakers:    stat.ActiveStakers,
+		TotalValueLocked: stat.TotalValueLocked,
+		UpdatedAt:        stat.UpdatedAt,
+	}
+}
+
+// fillDailySeries 按日期补全统计序列：缺失的日期沿用前一天的累计值，新增数量为 0
+func fillDailySeries(rows []models.PlatformDailyStat, previous *models.PlatformDailyStat, from time.Time, days int) []dto.StatsDailyPoint {
+	byDate := make(map[string]models.PlatformDailyStat, len(rows))
+	for _, row := range rows {
+		byDate[row.Date.Format("2006-01-02")] = row
+	}
+
+	carry := dto.StatsDailyPoint{TotalValueLocked: "0"}
+	if previous != nil {
+		carry = statsDailyPoint(*previous)
+	}
+
+	series := make([]dto.StatsDailyPoint, 0, days)
+	for i := 0; i < days; i++ {
+		date := from.AddDate(0, 0, i).Format("2006-01-02")
+		point := carry
+		if row, ok := byDate[date]; ok {
+			point = statsDailyPoint(row)
+		} else {
+			point.NewUsers, point.NewContent, point.NewProposals = 0, 0, 0
+		}
+		point.Date = date
+		series = append(series, point)
+		carry = point
+	}
+	return series
+}
+
+// statsDailyPoint 将快照转换为序列数据点
+func statsDailyPoint(stat models.PlatformDailyStat) dto.StatsDailyPoint {
+	return dto.StatsDailyPoint{
+		Date:             stat.Date.Format("2006-01-02"),
+		TotalUsers:       stat.TotalUsers,
+		TotalContent:     stat.TotalContent,
+		TotalProposals:   stat.TotalProposals,
+		NewUsers:         stat.NewUsers,
+		NewContent:       stat.NewContent,
+		NewProposals:     stat.NewProposals,
+		ActiveStakers:    stat.ActiveStakers,
+		TotalValueLocked: stat.TotalValueLocked,
+	}
+}
+
+// statsDay 返回时间所在日期的零点（UTC）
+func statsDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"bondly-api/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsDay(t *testing.T) {
+	day := statsDay(time.Date(2024, 3, 5, 23, 30, 0, 0, time.FixedZone("UTC+8", 8*3600)))
+	assert.Equal(t, time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), day)
+}
+
+func TestFillDailySeries(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	previous := &models.PlatformDailyStat{Date: from.AddDate(0, 0, -3), TotalUsers: 10, NewUsers: 2, TotalValueLocked: "100"}
+	rows := []models.PlatformDailyStat{
+		{Date: from.AddDate(0, 0, 1), TotalUsers: 15, NewUsers: 5, TotalValueLocked: "300"},
+	}
+
+	series := fillDailySeries(rows, previous, from, 3)
+
+	assert.Len(t, series, 3)
+	assert.Equal(t, "2024-03-01", series[0].Date)
+	assert.Equal(t, int64(10), series[0].TotalUsers, "缺失的日期沿用之前的累计值")
+	assert.Equal(t, int64(0), series[0].NewUsers)
+	assert.Equal(t, "100", series[0].TotalValueLocked)
+	assert.Equal(t, int64(5), series[1].NewUsers)
+	assert.Equal(t, "2024-03-03", series[2].Date)
+	assert.Equal(t, int64(15), series[2].TotalUsers)
+	assert.Equal(t, int64(0), series[2].NewUsers)
+	assert.Equal(t, "300", series[2].TotalValueLocked)
+}
+
+func TestFillDailySeriesWithoutHistory(t *testing.T) {
+	series := fillDailySeries(nil, nil, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), 2)
+
+	assert.Len(t, series, 2)
+	assert.Equal(t, int64(0), series[1].TotalUsers)
+	assert.Equal(t, "0", series[1].TotalValueLocked)
+}
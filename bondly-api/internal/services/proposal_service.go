@@ -1,6 +1,7 @@
 package services
 
 import (
+	loggerpkg "bondly-api/internal/logger"
 	"bondly-api/internal/models"
 	"bondly-api/internal/repositories"
 	"context"
@@ -11,27 +12,45 @@ import (
 )
 
 type ProposalService struct {
-	proposalRepo *repositories.ProposalRepository
+	proposalRepo    *repositories.ProposalRepository
+	voteRepo        *repositories.VoteRepository
+	reputationRules *ReputationRuleService
+	achievements    *AchievementService
+	notifications   *NotificationService
 }
 
-func NewProposalService(proposalRepo *repositories.ProposalRepository) *ProposalService {
+func NewProposalService(proposalRepo *repositories.ProposalRepository, voteRepo *repositories.VoteRepository, reputationRules *ReputationRuleService, achievements *AchievementService, notifications *NotificationService) *ProposalService {
 	return &ProposalService{
-		proposalRepo: proposalRepo,
+		proposalRepo:    proposalRepo,
+		voteRepo:        voteRepo,
+		reputationRules: reputationRules,
+		achievements:    achievements,
+		notifications:   notifications,
 	}
 }
 
 // CreateProposal 创建提案
 func (s *ProposalService) CreateProposal(ctx context.Context, proposal *models.Proposal) error {
+	// 新提案总是 active 且没有票数，通过与否只由结束时的计票结果决定，
+	// 成就的通过提案数和拨款的 approved 状态都依赖这一点
+	proposal.Status = "active"
+	proposal.VotesFor = 0
+	proposal.VotesAgainst = 0
+
 	// 设置默认值
-	if proposal.Status == "" {
-		proposal.Status = "active"
-	}
 	if proposal.StartTime.IsZero() {
 		proposal.StartTime = time.Now()
 	}
 	if proposal.EndTime.IsZero() {
 		proposal.EndTime = time.Now().Add(7 * 24 * time.Hour) // 默认7天
 	}
+	proposal.Topic = normalizeTopic(proposal.Topic)
+	if proposal.VotingStrategy == "" {
+		proposal.VotingStrategy = DefaultVotingStrategy
+	}
+	if !IsValidVotingStrategy(proposal.VotingStrategy) {
+		return errors.New("invalid voting strategy")
+	}
 
 	return s.proposalRepo.Create(proposal)
 }
@@ -67,8 +86,22 @@ func (s *ProposalService) UpdateProposal(ctx context.Context, id int64, updateDa
 	if updateData.Description != "" {
 		existingProposal.Description = updateData.Description
 	}
-	if updateData.Status != "" {
-		existingProposal.Status = updateData.Status
+	if updateData.Topic != "" {
+		existingProposal.Topic = normalizeTopic(updateData.Topic)
+	}
+	if updateData.VotingStrategy != "" && updateData.VotingStrategy != existingProposal.VotingStrategy {
+		// 投票策略决定已投票数的计票方式，创建后不允许修改
+		return nil, errors.New("voting strategy cannot be changed")
+	}
+	if updateData.Status != "" && updateData.Status != existingProposal.Status {
+		// 提案状态由结束时的计票结果决定，见 VoteService.FinalizeEnded
+		return nil, errors.New("proposal status cannot be changed")
+	}
+	scheduleChanged := (!updateData.StartTime.IsZero() && !updateData.StartTime.Equal(existingProposal.StartTime)) ||
+		(!updateData.EndTime.IsZero() && !updateData.EndTime.Equal(existingProposal.EndTime))
+	if scheduleChanged && !time.Now().Before(existingProposal.StartTime) {
+		// 投票开始后修改时间会改变权重快照或提前结算
+		return nil, errors.New("proposal schedule cannot be changed after voting starts")
 	}
 	if !updateData.StartTime.IsZero() {
 		existingProposal.StartTime = updateData.StartTime
@@ -151,6 +184,34 @@ func (s *ProposalService) GetProposalByStatus(ctx context.Context, status string
 }
 
 // UpdateVotes 更新投票数
-func (s *ProposalService) UpdateVotes(ctx context.Context, id int64, votesFor, votesAgainst int) error {
+func (s *ProposalService) UpdateVotes(ctx context.Context, id int64, votesFor, votesAgainst int64) error {
 	return s.proposalRepo.UpdateVotes(id, votesFor, votesAgainst)
 }
+
+// onProposalFinalized 提案结算后的处理：通过时发放提案通过的声誉和成就，并通知发起人和投票人
+// 只由 VoteService.FinalizeEnded 在提案状态从 active 变更成功后调用一次
+func (s *ProposalService) onProposalFinalized(ctx context.Context, proposal *models.Proposal) {
+	if proposal.Status == "passed" {
+		s.reputationRules.OnProposalPassed(ctx, proposal)
+		s.achievements.OnProposalPassed(ctx, proposal)
+	}
+	s.notifyProposalEnded(ctx, proposal)
+}
+
+// notifyProposalEnded 提案结束时通知发起人和全部投票人
+func (s *ProposalService) notifyProposalEnded(ctx context.Context, proposal *models.Proposal) {
+	if s.notifications == nil {
+		return
+	}
+	voterIDs, err := s.voteRepo.ListVoterIDs(proposal.ID)
+	if err != nil {
+		loggerpkg.NewBusinessLogger(ctx).DatabaseError("select", "votes", "ListVoterIDs", err)
+		return
+	}
+	s.notifications.NotifyMany(ctx, append([]int64{proposal.ProposerID}, voterIDs...), models.Notification{
+		Type:        NotificationTypeProposalEnded,
+		SubjectType: "proposal",
+		SubjectID:   proposal.ID,
+		Data:        map[string]string{"title": proposal.Title, "status": proposal.Status},
+	})
+}
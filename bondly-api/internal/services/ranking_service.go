@@ -0,0 +1,272 @@
+package services
+
+import (
+	"bondly-api/config"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/redis"
+	"bondly-api/internal/repositories"
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// 内容排序方式
+const (
+	ContentSortNew           = "new"           // 按创建时间倒序
+	ContentSortHot           = "hot"           // 热度：互动量取对数并随发布时间衰减
+	ContentSortTop           = "top"           // 好评：点赞率的 Wilson 置信下界
+	ContentSortControversial = "controversial" // 争议：赞踩数量接近且总量大
+)
+
+// 排行时间窗口，top 和 controversial 只统计窗口内发布的内容
+const (
+	RankingWindowDay   = "day"
+	RankingWindowWeek  = "week"
+	RankingWindowMonth = "month"
+)
+
+// rankingWindows 时间窗口长度
+var rankingWindows = map[string]time.Duration{
+	RankingWindowDay:   24 * time.Hour,
+	RankingWindowWeek:  7 * 24 * time.Hour,
+	RankingWindowMonth: 30 * 24 * time.Hour,
+}
+
+// 热度分数参数
+const (
+	hotEpoch         = 1704067200 // 2024-01-01 UTC，发布时间从该时刻起算
+	hotDecaySeconds  = 45000      // 每 12.5 小时的发布时间差相当于互动量相差 10 倍
+	hotCommentWeight = 2.0        // 每条评论相当于的净点赞数
+	hotViewWeight    = 0.05       // 每次浏览相当于的净点赞数
+	wilsonZ          = 1.96       // 95% 置信度
+)
+
+// rankingBuiltKey 最近一次全量重建的时间；重建只在定时任务中执行（启动时先执行一次），读取方不触发重建
+const rankingBuiltKey = "ranking:built"
+
+// RankingService 内容排行服务
+// 分数保存在 Redis 有序集合中：互动和评论发生时增量更新单条内容，定时任务全量重建以计入浏览量并淘汰窗口外的内容
+type RankingService struct {
+	redisClient     *redis.RedisClient
+	contentRepo     *repositories.ContentRepository
+	rebuildInterval time.Duration
+	hotHorizon      time.Duration
+}
+
+func NewRankingService(redisClient *redis.RedisClient, contentRepo *repositories.ContentRepository, cfg config.RankingConfig) *RankingService {
+	return &RankingService{
+		redisClient:     redisClient,
+		contentRepo:     contentRepo,
+		rebuildInterval: cfg.RebuildInterval,
+		hotHorizon:      cfg.HotHorizon,
+	}
+}
+
+// ValidateContentSort 校验排序方式和时间窗口，window 为空时默认 week
+func ValidateContentSort(sort, window string) (string, string, error) {
+	if sort == "" {
+		sort = ContentSortNew
+	}
+	switch sort {
+	case ContentSortNew, ContentSortHot, ContentSortTop, ContentSortControversial:
+	default:
+		return "", "", errors.New("invalid sort")
+	}
+	if window == "" {
+		window = RankingWindowWeek
+	}
+	if _, ok := rankingWindows[window]; !ok {
+		return "", "", errors.New("invalid window")
+	}
+	return sort, window, nil
+}
+
+// ListRankedIDs 分页获取排行榜中的内容ID及排行内内容总数；首次重建完成前排行可能为空或只有增量更新的内容
+func (s *RankingService) ListRankedIDs(ctx context.Context, sort, window string, page, limit int) ([]int64, int64, error) {
+	key := rankingKey(sort, window)
+	start := int64((page - 1) * limit)
+	members, err := s.redisClient.GetClient().ZRevRange(ctx, key, start, start+int64(limit)-1).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.redisClient.ZCard(ctx, key)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]int64, 0, len(members))
+	for _, member := range members {
+		if id, err := strconv.ParseInt(member, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, total, nil
+}
+
+// OnContentChanged 内容的互动、评论或状态变化时增量更新其排行分数，未发布的内容从排行中移除
+func (s *RankingService) OnContentChanged(ctx context.Context, contentID int64) {
+	if s == nil {
+		return
+	}
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	stats, err := s.contentRepo.GetRankingStats(contentID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		bizLog.DatabaseError("select", "contents", "GetRankingStats", err)
+		return
+	}
+
+	scores := map[string]float64{}
+	if stats != nil {
+		scores = rankingScores(*stats, time.Now(), s.hotHorizon)
+	}
+	_, err = s.redisClient.GetClient().Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for _, key := range rankingKeys() {
+			if score, ok := scores[key]; ok {
+				pipe.ZAdd(ctx, key, goredis.Z{Score: score, Member: contentID})
+			} else {
+				pipe.ZRem(ctx, key, contentID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		bizLog.ThirdPartyError("redis", "ranking_update", map[string]interface{}{"content_id": contentID}, err)
+	}
+}
+
+// Rebuild 从数据库全量重建全部排行榜
+func (s *RankingService) Rebuild(ctx context.Context) error {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+	now := time.Now()
+
+	horizon := s.hotHorizon
+	if month := rankingWindows[RankingWindowMonth]; month > horizon {
+		horizon = month
+	}
+	stats, err := s.contentRepo.ListRankingStats(now.Add(-horizon))
+	if err != nil {
+		bizLog.DatabaseError("select", "contents", "ListRankingStats", err)
+		return err
+	}
+
+	members := make(map[string][]goredis.Z, len(rankingKeys()))
+	for _, item := range stats {
+		for key, score := range rankingScores(item, now, s.hotHorizon) {
+			members[key] = append(members[key], goredis.Z{Score: score, Member: item.ID})
+		}
+	}
+
+	// 先写入临时键再原子替换，避免重建期间读到不完整的排行
+	_, err = s.redisClient.GetClient().TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for _, key := range rankingKeys() {
+			if len(members[key]) == 0 {
+				pipe.Del(ctx, key)
+				continue
+			}
+			tmpKey := key + ":rebuild"
+			pipe.Del(ctx, tmpKey)
+			pipe.ZAdd(ctx, tmpKey, members[key]...)
+			pipe.Rename(ctx, tmpKey, key)
+		}
+		pipe.Set(ctx, rankingBuiltKey, now.Unix(), 2*s.rebuildInterval)
+		return nil
+	})
+	if err != nil {
+		bizLog.ThirdPartyError("redis", "ranking_rebuild", map[string]interface{}{"contents": len(stats)}, err)
+		return err
+	}
+
+	bizLog.BusinessLogic("重建内容排行", map[string]interface{}{"contents": len(stats)})
+	return nil
+}
+
+// rankingKey 排行榜的 Redis 键，hot 不区分时间窗口
+func rankingKey(sort, window string) string {
+	if sort == ContentSortHot {
+		return "ranking:hot"
+	}
+	return "ranking:" + sort + ":" + window
+}
+
+// rankingKeys 全部排行榜的 Redis 键
+func rankingKeys() []string {
+	keys := []string{rankingKey(ContentSortHot, "")}
+	for _, sort := range []string{ContentSortTop, ContentSortControversial} {
+		for _, window := range []string{RankingWindowDay, RankingWindowWeek, RankingWindowMonth} {
+			keys = append(keys, rankingKey(sort, window))
+		}
+	}
+	return keys
+}
+
+// rankingScores 计算内容在各排行榜中的分数，只包含内容应当进入的排行榜
+func rankingScores(stats repositories.ContentRankingStats, now time.Time, hotHorizon time.Duration) map[string]float64 {
+	scores := make(map[string]float64)
+	if stats.Status != "published" || stats.PublishedAt == nil {
+		return scores
+	}
+	age := now.Sub(*stats.PublishedAt)
+
+	if age <= hotHorizon {
+		scores[rankingKey(ContentSortHot, "")] = hotScore(stats, *stats.PublishedAt)
+	}
+	top := wilsonLowerBound(stats.Likes, stats.Dislikes)
+	controversial := controversyScore(stats.Likes, stats.Dislikes)
+	for window, length := range rankingWindows {
+		if age > length {
+			continue
+		}
+		scores[rankingKey(ContentSortTop, window)] = top
+		if controversial > 0 {
+			scores[rankingKey(ContentSortControversial, window)] = controversial
+		}
+	}
+	return scores
+}
+
+// hotScore 热度分数：净互动量取以 10 为底的对数，再加上与发布时间成正比的项，新内容自然排在前面而无需随时间重算
+func hotScore(stats repositories.ContentRankingStats, publishedAt time.Time) float64 {
+	engagement := float64(stats.Likes-stats.Dislikes) +
+		hotCommentWeight*float64(stats.Comments) +
+		hotViewWeight*float64(stats.Views)
+
+	order := math.Log10(math.Max(math.Abs(engagement), 1))
+	sign := 0.0
+	if engagement > 0 {
+		sign = 1
+	} else if engagement < 0 {
+		sign = -1
+	}
+	seconds := float64(publishedAt.Unix() - hotEpoch)
+	return math.Round((sign*order+seconds/hotDecaySeconds)*1e7) / 1e7
+}
+
+// wilsonLowerBound 点赞率的 Wilson 置信区间下界，票数少的内容不会因偶然的高好评率排在前面
+func wilsonLowerBound(likes, dislikes int64) float64 {
+	n := float64(likes + dislikes)
+	if n == 0 {
+		return 0
+	}
+	p := float64(likes) / n
+	z2 := wilsonZ * wilsonZ
+	return (p + z2/(2*n) - wilsonZ*math.Sqrt((p*(1-p)+z2/(4*n))/n)) / (1 + z2/n)
+}
+
+// controversyScore 争议分数：赞踩总量为底、较少一方与较多一方之比为指数，只有赞踩都有时大于 0
+func controversyScore(likes, dislikes int64) float64 {
+	if likes <= 0 || dislikes <= 0 {
+		return 0
+	}
+	magnitude := float64(likes + dislikes)
+	balance := float64(dislikes) / float64(likes)
+	if likes < dislikes {
+		balance = float64(likes) / float64(dislikes)
+	}
+	return math.Pow(magnitude, balance)
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"bondly-api/internal/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateContentSort(t *testing.T) {
+	sort, window, err := ValidateContentSort("", "")
+	assert.NoError(t, err)
+	assert.Equal(t, ContentSortNew, sort)
+	assert.Equal(t, RankingWindowWeek, window)
+
+	_, _, err = ValidateContentSort("best", "")
+	assert.EqualError(t, err, "invalid sort")
+	_, _, err = ValidateContentSort(ContentSortTop, "year")
+	assert.EqualError(t, err, "invalid window")
+}
+
+func TestHotScore(t *testing.T) {
+	publishedAt := time.Unix(hotEpoch+hotDecaySeconds, 0)
+	popular := repositories.ContentRankingStats{Likes: 12, Dislikes: 2}
+	quiet := repositories.ContentRankingStats{}
+
+	assert.InDelta(t, 2.0, hotScore(popular, publishedAt), 1e-6, "净互动10取对数为1，加上时间项1")
+	assert.InDelta(t, 1.0, hotScore(quiet, publishedAt), 1e-6)
+	assert.Greater(t, hotScore(quiet, publishedAt.Add(13*time.Hour)), hotScore(popular, publishedAt), "新内容可以超过旧的高互动内容")
+}
+
+func TestWilsonLowerBound(t *testing.T) {
+	assert.Zero(t, wilsonLowerBound(0, 0))
+	assert.Greater(t, wilsonLowerBound(90, 10), wilsonLowerBound(1, 0), "样本多的高好评率排在偶然的满分之前")
+	assert.Less(t, wilsonLowerBound(90, 10), 0.9)
+}
+
+func TestControversyScore(t *testing.T) {
+	assert.Zero(t, controversyScore(10, 0))
+	assert.InDelta(t, 20.0, controversyScore(10, 10), 1e-9)
+	assert.Greater(t, controversyScore(10, 10), controversyScore(18, 2))
+}
+
+func TestRankingScoresWindows(t *testing.T) {
+	now := time.Now()
+	publishedAt := now.Add(-3 * 24 * time.Hour)
+	stats := repositories.ContentRankingStats{Status: "published", Likes: 5, Dislikes: 3, PublishedAt: &publishedAt}
+
+	scores := rankingScores(stats, now, 14*24*time.Hour)
+	assert.Contains(t, scores, "ranking:hot")
+	assert.Contains(t, scores, "ranking:top:week")
+	assert.Contains(t, scores, "ranking:controversial:month")
+	assert.NotContains(t, scores, "ranking:top:day", "超出一天窗口")
+
+	draft := repositories.ContentRankingStats{Status: "draft", PublishedAt: &publishedAt}
+	assert.Empty(t, rankingScores(draft, now, 14*24*time.Hour))
+}
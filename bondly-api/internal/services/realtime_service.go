@@ -0,0 +1,337 @@
+package services
+
+import (
+	"bondly-api/config"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/redis"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 实时推送消息类型
+const (
+	RealtimeMessageEvent        = "event"        // 主题上发布的事件
+	RealtimeMessageSubscribed   = "subscribed"   // 订阅成功
+	RealtimeMessageUnsubscribed = "unsubscribed" // 取消订阅成功
+	RealtimeMessageError        = "error"        // 客户端请求出错
+)
+
+// 实时推送事件
+const (
+	RealtimeEventCommentCreated      = "comment.created"
+	RealtimeEventCommentUpdated      = "comment.updated" // 评论被编辑或审核
+	RealtimeEventCommentDeleted      = "comment.deleted"
+	RealtimeEventVotesUpdated        = "votes.updated"
+	RealtimeEventNotificationCreated = "notification.created"
+)
+
+// realtimeChannel 各实例共用的 Redis 发布订阅频道，每个实例只把消息转发给本机订阅了对应主题的连接
+const realtimeChannel = "realtime:events"
+
+// realtimeTopicPattern 支持的主题：content:{id}:comments、proposal:{id}:votes、user:{id}:notifications
+var realtimeTopicPattern = regexp.MustCompile(`^(content|proposal|user):([1-9][0-9]*):(comments|votes|notifications)$`)
+
+// realtimeTopicKinds 主题前缀与后缀的对应关系
+var realtimeTopicKinds = map[string]string{
+	"content":  "comments",
+	"proposal": "votes",
+	"user":     "notifications",
+}
+
+// RealtimeMessage 推送给客户端的消息
+type RealtimeMessage struct {
+	Type  string          `json:"type"`
+	Topic string          `json:"topic,omitempty"`
+	Event string          `json:"event,omitempty"`
+	Data  json.RawMessage `json:"data,omitempty"`
+	Error string          `json:"error,omitempty"`
+	At    time.Time       `json:"at"`
+}
+
+// ContentCommentsTopic 内容评论主题
+func ContentCommentsTopic(contentID int64) string {
+	return fmt.Sprintf("content:%d:comments", contentID)
+}
+
+// ProposalVotesTopic 提案计票主题
+func ProposalVotesTopic(proposalID int64) string {
+	return fmt.Sprintf("proposal:%d:votes", proposalID)
+}
+
+// UserNotificationsTopic 用户通知主题，只有用户本人可以订阅
+func UserNotificationsTopic(userID int64) string {
+	return fmt.Sprintf("user:%d:notifications", userID)
+}
+
+// AuthorizeRealtimeTopic 校验主题格式以及 userID 是否可以订阅该主题
+func AuthorizeRealtimeTopic(topic string, userID int64) error {
+	match := realtimeTopicPattern.FindStringSubmatch(topic)
+	if match == nil || realtimeTopicKinds[match[1]] != match[3] {
+		return errors.New("invalid topic")
+	}
+	if match[1] == "user" {
+		id, err := strconv.ParseInt(match[2], 10, 64)
+		if err != nil || id != userID {
+			return errors.New("topic forbidden")
+		}
+	}
+	return nil
+}
+
+// RealtimeContentReader 订阅内容评论主题时读取内容的作者和状态，由 repositories.ContentRepository 实现
+type RealtimeContentReader interface {
+	GetByID(id int64) (*models.Content, error)
+}
+
+// RealtimeSubscriber 一个 WebSocket 或 SSE 连接；消息写入有界缓冲，缓冲写满说明客户端消费过慢，连接会被断开
+type RealtimeSubscriber struct {
+	UserID int64
+	Role   string
+	send   chan []byte
+	done   chan struct{}
+	once   sync.Once
+	topics map[string]bool // 由 RealtimeService.mu 保护
+}
+
+// Messages 待写给客户端的消息
+func (sub *RealtimeSubscriber) Messages() <-chan []byte {
+	return sub.send
+}
+
+// Done 连接被服务端关闭（消费过慢或服务停止）时关闭
+func (sub *RealtimeSubscriber) Done() <-chan struct{} {
+	return sub.done
+}
+
+// RealtimeService 实时推送网关：通过 Redis 发布订阅在实例间广播事件，再分发给本机订阅了对应主题的连接
+type RealtimeService struct {
+	redisClient       *redis.RedisClient
+	contents          RealtimeContentReader
+	sendBuffer        int
+	maxTopics         int
+	heartbeatInterval time.Duration
+
+	mu          sync.RWMutex
+	topics      map[string]map[*RealtimeSubscriber]bool
+	subscribers map[*RealtimeSubscriber]bool
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewRealtimeService(redisClient *redis.RedisClient, contents RealtimeContentReader, cfg config.RealtimeConfig) *RealtimeService {
+	return &RealtimeService{
+		redisClient:       redisClient,
+		contents:          contents,
+		sendBuffer:        cfg.SendBuffer,
+		maxTopics:         cfg.MaxTopics,
+		heartbeatInterval: cfg.HeartbeatInterval,
+		topics:            make(map[string]map[*RealtimeSubscriber]bool),
+		subscribers:       make(map[*RealtimeSubscriber]bool),
+	}
+}
+
+// HeartbeatInterval 心跳间隔，WebSocket 发送 ping，SSE 发送注释行
+func (s *RealtimeService) HeartbeatInterval() time.Duration {
+	return s.heartbeatInterval
+}
+
+// Start 订阅 Redis 频道并开始分发消息，连接断开后由 go-redis 自动重连
+func (s *RealtimeService) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	pubsub := s.redisClient.GetClient().Subscribe(ctx, realtimeChannel)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer pubsub.Close()
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				s.dispatch([]byte(msg.Payload))
+			}
+		}
+	}()
+	loggerpkg.Log.Info("Realtime gateway started")
+}
+
+// Stop 停止分发并关闭所有连接
+func (s *RealtimeService) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+
+	s.mu.RLock()
+	subscribers := make([]*RealtimeSubscriber, 0, len(s.subscribers))
+	for sub := range s.subscribers {
+		subscribers = append(subscribers, sub)
+	}
+	s.mu.RUnlock()
+	for _, sub := range subscribers {
+		s.Disconnect(sub)
+	}
+	loggerpkg.Log.Info("Realtime gateway stopped")
+}
+
+// Publish 向主题发布事件，所有实例上订阅了该主题的连接都会收到。失败只记录日志
+func (s *RealtimeService) Publish(ctx context.Context, topic string, event string, data interface{}) {
+	if s == nil {
+		return
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		loggerpkg.NewBusinessLogger(ctx).ThirdPartyError("redis", "realtime_encode", map[string]interface{}{"topic": topic, "event": event}, err)
+		return
+	}
+	payload, _ := json.Marshal(RealtimeMessage{
+		Type:  RealtimeMessageEvent,
+		Topic: topic,
+		Event: event,
+		Data:  raw,
+		At:    time.Now(),
+	})
+	if err := s.redisClient.GetClient().Publish(ctx, realtimeChannel, payload).Err(); err != nil {
+		loggerpkg.NewBusinessLogger(ctx).ThirdPartyError("redis", "realtime_publish", map[string]interface{}{"topic": topic, "event": event}, err)
+	}
+}
+
+// Connect 注册一个连接并自动订阅用户本人的通知主题，role 为用户角色，管理员可订阅未发布内容的评论
+func (s *RealtimeService) Connect(userID int64, role string) *RealtimeSubscriber {
+	sub := &RealtimeSubscriber{
+		UserID: userID,
+		Role:   role,
+		send:   make(chan []byte, s.sendBuffer),
+		done:   make(chan struct{}),
+		topics: make(map[string]bool),
+	}
+	s.mu.Lock()
+	s.subscribers[sub] = true
+	s.mu.Unlock()
+	_ = s.Subscribe(sub, UserNotificationsTopic(userID))
+	return sub
+}
+
+// Disconnect 注销连接并取消其全部订阅，可重复调用
+func (s *RealtimeService) Disconnect(sub *RealtimeSubscriber) {
+	s.mu.Lock()
+	for topic := range sub.topics {
+		s.removeLocked(sub, topic)
+	}
+	delete(s.subscribers, sub)
+	s.mu.Unlock()
+	sub.once.Do(func() { close(sub.done) })
+}
+
+// Subscribe 为连接订阅主题，重复订阅不报错
+func (s *RealtimeService) Subscribe(sub *RealtimeSubscriber, topic string) error {
+	if err := AuthorizeRealtimeTopic(topic, sub.UserID); err != nil {
+		return err
+	}
+	var contentID int64
+	if _, err := fmt.Sscanf(topic, "content:%d:comments", &contentID); err == nil {
+		if err := s.authorizeContentTopic(sub, contentID); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sub.topics[topic] {
+		return nil
+	}
+	if len(sub.topics) >= s.maxTopics {
+		return errors.New("too many topics")
+	}
+	sub.topics[topic] = true
+	if s.topics[topic] == nil {
+		s.topics[topic] = make(map[*RealtimeSubscriber]bool)
+	}
+	s.topics[topic][sub] = true
+	return nil
+}
+
+// authorizeContentTopic 内容评论主题只对已发布内容开放，草稿、审核中和定时发布的内容仅作者和管理员可订阅；
+// 内容不存在时同样返回 topic forbidden，不暴露内容是否存在
+func (s *RealtimeService) authorizeContentTopic(sub *RealtimeSubscriber, contentID int64) error {
+	content, err := s.contents.GetByID(contentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New("topic forbidden")
+	}
+	if err != nil {
+		return err
+	}
+	if content.Status == ContentStatusPublished || content.AuthorID == sub.UserID || sub.Role == "admin" {
+		return nil
+	}
+	return errors.New("topic forbidden")
+}
+
+// Unsubscribe 取消连接对主题的订阅
+func (s *RealtimeService) Unsubscribe(sub *RealtimeSubscriber, topic string) {
+	s.mu.Lock()
+	s.removeLocked(sub, topic)
+	s.mu.Unlock()
+}
+
+// Reply 向单个连接发送订阅确认或错误等控制消息
+func (s *RealtimeService) Reply(sub *RealtimeSubscriber, messageType string, topic string, errMsg string) {
+	payload, _ := json.Marshal(RealtimeMessage{Type: messageType, Topic: topic, Error: errMsg, At: time.Now()})
+	s.deliver([]*RealtimeSubscriber{sub}, payload)
+}
+
+// dispatch 把 Redis 频道上的消息转发给本机订阅了该主题的连接
+func (s *RealtimeService) dispatch(payload []byte) {
+	var msg RealtimeMessage
+	if err := json.Unmarshal(payload, &msg); err != nil || msg.Topic == "" {
+		loggerpkg.Log.Warnf("Realtime gateway dropped malformed message: %v", err)
+		return
+	}
+	s.mu.RLock()
+	subscribers := make([]*RealtimeSubscriber, 0, len(s.topics[msg.Topic]))
+	for sub := range s.topics[msg.Topic] {
+		subscribers = append(subscribers, sub)
+	}
+	s.mu.RUnlock()
+	s.deliver(subscribers, payload)
+}
+
+// deliver 非阻塞地写入连接的发送缓冲，缓冲已满的连接被断开，避免慢客户端拖住分发或无限占用内存；客户端重连后应重新拉取数据
+func (s *RealtimeService) deliver(subscribers []*RealtimeSubscriber, payload []byte) {
+	for _, sub := range subscribers {
+		select {
+		case <-sub.done:
+		case sub.send <- payload:
+		default:
+			loggerpkg.Log.Warnf("Realtime subscriber of user %d is too slow, disconnecting", sub.UserID)
+			s.Disconnect(sub)
+		}
+	}
+}
+
+// removeLocked 取消订阅，调用方需持有 s.mu
+func (s *RealtimeService) removeLocked(sub *RealtimeSubscriber, topic string) {
+	delete(sub.topics, topic)
+	if subscribers := s.topics[topic]; subscribers != nil {
+		delete(subscribers, sub)
+		if len(subscribers) == 0 {
+			delete(s.topics, topic)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"bondly-api/config"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorizeRealtimeTopic(t *testing.T) {
+	assert.NoError(t, AuthorizeRealtimeTopic("content:12:comments", 7))
+	assert.NoError(t, AuthorizeRealtimeTopic("proposal:3:votes", 7))
+	assert.NoError(t, AuthorizeRealtimeTopic("user:7:notifications", 7))
+
+	assert.EqualError(t, AuthorizeRealtimeTopic("user:8:notifications", 7), "topic forbidden")
+	assert.EqualError(t, AuthorizeRealtimeTopic("content:12:votes", 7), "invalid topic", "前缀与后缀不匹配")
+	assert.EqualError(t, AuthorizeRealtimeTopic("content:0:comments", 7), "invalid topic")
+	assert.EqualError(t, AuthorizeRealtimeTopic("content:*:comments", 7), "invalid topic")
+	assert.EqualError(t, AuthorizeRealtimeTopic("", 7), "invalid topic")
+}
+
+// fakeRealtimeContents 按ID返回内容，不存在时返回 gorm.ErrRecordNotFound
+type fakeRealtimeContents map[int64]*models.Content
+
+func (f fakeRealtimeContents) GetByID(id int64) (*models.Content, error) {
+	if content, ok := f[id]; ok {
+		return content, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestRealtimeContentTopicVisibility(t *testing.T) {
+	s := NewRealtimeService(nil, fakeRealtimeContents{
+		1: {ID: 1, AuthorID: 9, Status: ContentStatusPublished},
+		2: {ID: 2, AuthorID: 9, Status: ContentStatusDraft},
+		3: {ID: 3, AuthorID: 9, Status: ContentStatusScheduled},
+	}, config.RealtimeConfig{SendBuffer: 1, MaxTopics: 10})
+
+	reader := s.Connect(7, "user")
+	assert.NoError(t, s.Subscribe(reader, ContentCommentsTopic(1)))
+	assert.EqualError(t, s.Subscribe(reader, ContentCommentsTopic(2)), "topic forbidden", "草稿不对其他用户开放")
+	assert.EqualError(t, s.Subscribe(reader, ContentCommentsTopic(3)), "topic forbidden", "定时发布前不对其他用户开放")
+	assert.EqualError(t, s.Subscribe(reader, ContentCommentsTopic(4)), "topic forbidden", "不暴露内容是否存在")
+
+	author := s.Connect(9, "user")
+	assert.NoError(t, s.Subscribe(author, ContentCommentsTopic(2)))
+	admin := s.Connect(1, "admin")
+	assert.NoError(t, s.Subscribe(admin, ContentCommentsTopic(3)))
+}
+
+func TestRealtimeSubscriptions(t *testing.T) {
+	s := NewRealtimeService(nil, fakeRealtimeContents{1: {ID: 1, AuthorID: 9, Status: ContentStatusPublished}}, config.RealtimeConfig{SendBuffer: 1, MaxTopics: 2})
+	sub := s.Connect(7, "user")
+	assert.True(t, sub.topics[UserNotificationsTopic(7)], "连接后自动订阅本人通知")
+
+	assert.NoError(t, s.Subscribe(sub, ContentCommentsTopic(1)))
+	assert.NoError(t, s.Subscribe(sub, ContentCommentsTopic(1)), "重复订阅不报错")
+	assert.EqualError(t, s.Subscribe(sub, ProposalVotesTopic(1)), "too many topics")
+
+	s.Unsubscribe(sub, ContentCommentsTopic(1))
+	assert.NotContains(t, s.topics, ContentCommentsTopic(1))
+
+	s.Disconnect(sub)
+	assert.Empty(t, s.topics)
+	assert.Empty(t, s.subscribers)
+}
+
+func TestRealtimeDispatchDisconnectsSlowSubscriber(t *testing.T) {
+	loggerpkg.Init("error", "json")
+	s := NewRealtimeService(nil, fakeRealtimeContents{}, config.RealtimeConfig{SendBuffer: 1, MaxTopics: 10})
+	sub := s.Connect(7, "user")
+	payload := []byte(`{"type":"event","topic":"user:7:notifications","event":"notification.created"}`)
+
+	s.dispatch(payload)
+	assert.Equal(t, payload, <-sub.Messages())
+
+	s.dispatch(payload)
+	s.dispatch(payload)
+	<-sub.Done()
+	assert.Empty(t, s.subscribers, "缓冲写满的连接被断开")
+
+	s.dispatch(payload)
+	assert.Len(t, sub.Messages(), 1, "断开后不再投递")
+}
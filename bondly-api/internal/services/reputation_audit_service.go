@@ -0,0 +1,197 @@
+package services
+
+import (
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// 声誉手动变更动作
+const (
+	ReputationActionAdd      = "add"
+	ReputationActionSubtract = "subtract"
+	ReputationActionSync     = "sync"
+)
+
+// 审计记录状态
+const (
+	ReputationAuditPending   = "pending"
+	ReputationAuditSucceeded = "succeeded"
+	ReputationAuditFailed    = "failed"
+)
+
+// ReputationMutation 一次需要审计的声誉手动变更
+type ReputationMutation struct {
+	Action         string
+	IdempotencyKey string
+	ActorID        int64
+	TargetUserID   int64
+	Amount         int
+	Reason         string
+}
+
+// ReputationAuditService 执行声誉手动变更并写入审计记录
+// 每次变更都必须携带幂等键，相同幂等键的重复请求直接返回首次执行的结果
+type ReputationAuditService struct {
+	auditRepo         *repositories.ReputationAuditRepository
+	userRepo          *repositories.UserRepository
+	reputationService *ReputationService
+}
+
+func NewReputationAuditService(auditRepo *repositories.ReputationAuditRepository, userRepo *repositories.UserRepository, reputationService *ReputationService) *ReputationAuditService {
+	return &ReputationAuditService{
+		auditRepo:         auditRepo,
+		userRepo:          userRepo,
+		reputationService: reputationService,
+	}
+}
+
+// Execute 执行声誉手动变更，返回审计记录以及是否为重放的结果
+func (s *ReputationAuditService) Execute(ctx context.Context, mutation ReputationMutation) (*models.ReputationAuditLog, bool, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+	hash := mutationHash(mutation)
+
+	existing, err := s.auditRepo.GetByIdempotencyKey(mutation.IdempotencyKey)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+	if existing != nil {
+		return s.replay(existing, hash)
+	}
+
+	user, err := s.userRepo.GetByID(mutation.TargetUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, errors.New("user not found")
+		}
+		return nil, false, err
+	}
+
+	log := &models.ReputationAuditLog{
+		IdempotencyKey: mutation.IdempotencyKey,
+		RequestHash:    hash,
+		Action:         mutation.Action,
+		ActorID:        mutation.ActorID,
+		TargetUserID:   mutation.TargetUserID,
+		Amount:         mutation.Amount,
+		Reason:         mutation.Reason,
+		ScoreBefore:    user.ReputationScore,
+		ScoreAfter:     user.ReputationScore,
+		Status:         ReputationAuditPending,
+		TraceID:        traceIDFromContext(ctx),
+	}
+	created, err := s.auditRepo.Create(log)
+	if err != nil {
+		bizLog.DatabaseError("insert", "reputation_audit_logs", "Create", err)
+		return nil, false, err
+	}
+	if !created {
+		// 并发请求使用了相同的幂等键
+		existing, err := s.auditRepo.GetByIdempotencyKey(mutation.IdempotencyKey)
+		if err != nil {
+			return nil, false, err
+		}
+		return s.replay(existing, hash)
+	}
+
+	var event *models.ReputationEvent
+	var opErr error
+	switch mutation.Action {
+	case ReputationActionAdd:
+		event, opErr = s.reputationService.AddReputation(ctx, mutation.TargetUserID, mutation.Amount, mutation.Reason)
+	case ReputationActionSubtract:
+		event, opErr = s.reputationService.SubtractReputation(ctx, mutation.TargetUserID, mutation.Amount, mutation.Reason)
+	case ReputationActionSync:
+		event, opErr = s.reputationService.SyncReputationFromChain(ctx, mutation.TargetUserID)
+	default:
+		opErr = fmt.Errorf("unsupported reputation action: %s", mutation.Action)
+	}
+
+	if event != nil {
+		// 变更前后的分数取自账本写入时在行锁内读到的值，不受并发变更影响
+		log.ScoreBefore = event.ScoreAfter - event.Delta
+		log.ScoreAfter = event.ScoreAfter
+	} else if after, err := s.userRepo.GetByID(mutation.TargetUserID); err == nil {
+		// 没有产生变更时前后分数相同
+		log.ScoreBefore = after.ReputationScore
+		log.ScoreAfter = after.ReputationScore
+	}
+	log.Status = ReputationAuditSucceeded
+	if opErr != nil {
+		log.Status = ReputationAuditFailed
+		log.Error = opErr.Error()
+	}
+	if err := s.auditRepo.Complete(log); err != nil {
+		bizLog.DatabaseError("update", "reputation_audit_logs", "Complete", err)
+	}
+
+	bizLog.SecurityEvent("reputation_mutation", map[string]interface{}{
+		"audit_id":        log.ID,
+		"action":          log.Action,
+		"actor_id":        log.ActorID,
+		"target_user_id":  log.TargetUserID,
+		"amount":          log.Amount,
+		"score_before":    log.ScoreBefore,
+		"score_after":     log.ScoreAfter,
+		"status":          log.Status,
+		"idempotency_key": log.IdempotencyKey,
+	})
+
+	return log, false, opErr
+}
+
+// ListAuditLogs 分页获取审计记录，actorID、targetUserID 为 0 或 action 为空时不过滤
+func (s *ReputationAuditService) ListAuditLogs(ctx context.Context, actorID, targetUserID int64, action string, page, limit int) ([]models.ReputationAuditLog, int64, error) {
+	offset := (page - 1) * limit
+	filter := repositories.ReputationAuditFilter{
+		ActorID:      actorID,
+		TargetUserID: targetUserID,
+		Action:       action,
+	}
+
+	logs, err := s.auditRepo.List(filter, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.auditRepo.Count(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+// replay 返回幂等键首次执行的结果，请求内容不一致或仍在执行时返回错误
+func (s *ReputationAuditService) replay(existing *models.ReputationAuditLog, hash string) (*models.ReputationAuditLog, bool, error) {
+	if existing.RequestHash != hash {
+		return nil, false, errors.New("idempotency key reused with different request")
+	}
+	switch existing.Status {
+	case ReputationAuditPending:
+		return nil, false, errors.New("request with this idempotency key is in progress")
+	case ReputationAuditFailed:
+		return existing, true, errors.New(existing.Error)
+	}
+	return existing, true, nil
+}
+
+// mutationHash 计算请求内容的哈希，操作人不同也视为不同请求
+func mutationHash(mutation ReputationMutation) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%d|%d|%s",
+		mutation.Action, mutation.ActorID, mutation.TargetUserID, mutation.Amount, mutation.Reason)))
+	return hex.EncodeToString(sum[:])
+}
+
+// traceIDFromContext 获取请求的 trace ID
+func traceIDFromContext(ctx context.Context) string {
+	if traceID, ok := loggerpkg.FromContext(ctx).Data["trace_id"].(string); ok {
+		return traceID
+	}
+	return ""
+}
@@ -0,0 +1,23 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMutationHash(t *testing.T) {
+	base := ReputationMutation{Action: ReputationActionAdd, ActorID: 1, TargetUserID: 2, Amount: 50, Reason: "优质内容"}
+
+	same := base
+	same.IdempotencyKey = "another-key"
+	assert.Equal(t, mutationHash(base), mutationHash(same), "幂等键本身不参与哈希")
+
+	otherAmount := base
+	otherAmount.Amount = 60
+	assert.NotEqual(t, mutationHash(base), mutationHash(otherAmount))
+
+	otherActor := base
+	otherActor.ActorID = 3
+	assert.NotEqual(t, mutationHash(base), mutationHash(otherActor), "不同操作人视为不同请求")
+}
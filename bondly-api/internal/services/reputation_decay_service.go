@@ -0,0 +1,121 @@
+package services
+
+import (
+	"bondly-api/config"
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/repositories"
+	"context"
+	"math"
+	"time"
+)
+
+// decayBatchSize 衰减任务每批处理的用户数
+const decayBatchSize = 500
+
+// ReputationDecayService 按半衰期定期衰减当前声誉分数
+// 衰减以 decay 来源的声誉事件记账，累计声誉（排除衰减和赛季清零的事件之和）不受影响
+type ReputationDecayService struct {
+	userRepo          *repositories.UserRepository
+	reputationService *ReputationService
+	halfLife          time.Duration
+}
+
+func NewReputationDecayService(userRepo *repositories.UserRepository, reputationService *ReputationService, cfg config.ReputationConfig) *ReputationDecayService {
+	return &ReputationDecayService{
+		userRepo:          userRepo,
+		reputationService: reputationService,
+		halfLife:          cfg.DecayHalfLife,
+	}
+}
+
+// ApplyDecay 对所有声誉大于 0 的用户按距上次衰减经过的时间计算衰减量并记账
+// 衰减量四舍五入不足 1 时不记账也不移动起点，低分用户会在时间足够长后再衰减
+func (s *ReputationDecayService) ApplyDecay(ctx context.Context) (*dto.ReputationDecayResult, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+	now := time.Now()
+	result := &dto.ReputationDecayResult{}
+
+	// 每个实例都会调度衰减任务，只由拿到锁的实例执行
+	lock, err := s.userRepo.TryLockReputationDecay(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if lock == nil {
+		bizLog.BusinessLogic("声誉衰减由其他实例执行，跳过", map[string]interface{}{})
+		return result, nil
+	}
+	defer func() {
+		if err := lock.Release(); err != nil {
+			loggerpkg.Log.Errorf("Failed to release reputation decay lock: %v", err)
+		}
+	}()
+
+	// 声誉为 0 的用户每次都重置起点，避免重新获得声誉后按很久之前的起点衰减
+	anchored, err := s.userRepo.AnchorReputationDecay(now)
+	if err != nil {
+		bizLog.DatabaseError("update", "users", "AnchorReputationDecay", err)
+		return nil, err
+	}
+	result.Anchored = int(anchored)
+
+	var afterID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		users, err := s.userRepo.ListWithPositiveReputation(afterID, decayBatchSize)
+		if err != nil {
+			bizLog.DatabaseError("select", "users", "ListWithPositiveReputation", err)
+			return result, err
+		}
+		if len(users) == 0 {
+			break
+		}
+
+		for _, user := range users {
+			afterID = user.ID
+			if user.ReputationDecayedAt == nil {
+				continue
+			}
+
+			amount := decayAmount(user.ReputationScore, now.Sub(*user.ReputationDecayedAt), s.halfLife)
+			if amount <= 0 {
+				continue
+			}
+
+			// 衰减事件和起点在同一事务中写入，起点已被移动时不重复衰减
+			event, err := s.reputationService.RecordDecay(ctx, user.ID, amount, *user.ReputationDecayedAt, now)
+			if err != nil {
+				result.Failed++
+				continue
+			}
+			if event == nil {
+				continue
+			}
+
+			result.Users++
+			result.TotalDecayed += -event.Delta
+		}
+	}
+
+	bizLog.BusinessLogic("声誉衰减", map[string]interface{}{
+		"users":         result.Users,
+		"total_decayed": result.TotalDecayed,
+		"failed":        result.Failed,
+		"anchored":      result.Anchored,
+		"half_life":     s.halfLife.String(),
+	})
+
+	return result, nil
+}
+
+// decayAmount 计算分数经过 elapsed 后按半衰期衰减的数量，剩余分数四舍五入
+func decayAmount(score int, elapsed, halfLife time.Duration) int {
+	if score <= 0 || elapsed <= 0 || halfLife <= 0 {
+		return 0
+	}
+	remaining := float64(score) * math.Pow(0.5, elapsed.Hours()/halfLife.Hours())
+	return score - int(math.Round(remaining))
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecayAmount(t *testing.T) {
+	halfLife := 180 * 24 * time.Hour
+
+	assert.Equal(t, 500, decayAmount(1000, halfLife, halfLife), "经过一个半衰期衰减一半")
+	assert.Equal(t, 750, decayAmount(1000, 2*halfLife, halfLife))
+	assert.Equal(t, 4, decayAmount(1000, 24*time.Hour, halfLife))
+	assert.Equal(t, 0, decayAmount(10, 24*time.Hour, halfLife), "衰减量不足 1 时不衰减")
+	assert.Equal(t, 1, decayAmount(10, 30*24*time.Hour, halfLife), "低分在时间足够长后衰减")
+	assert.Equal(t, 0, decayAmount(0, halfLife, halfLife))
+	assert.Equal(t, 0, decayAmount(1000, 0, halfLife))
+	assert.Equal(t, 0, decayAmount(1000, halfLife, 0), "半衰期未配置时不衰减")
+}
+
+func TestNonEarnedReputationSources(t *testing.T) {
+	for _, sources := range [][]string{nonEarnedReputationSources, seasonExcludedSources} {
+		assert.Contains(t, sources, ReputationSourceDecay)
+		assert.Contains(t, sources, ReputationSourceSeasonReset)
+		assert.Contains(t, sources, ReputationSourceChainSync, "链上同步的校正不计入累计和赛季声誉")
+		assert.NotContains(t, sources, ReputationSourceRule)
+	}
+	assert.NotContains(t, databaseOnlyReputationSources, ReputationSourceChainSync)
+}
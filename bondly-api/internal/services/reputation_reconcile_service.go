@@ -0,0 +1,333 @@
+package services
+
+import (
+	"bondly-api/config"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 对账时以哪一方为准
+const (
+	ReconcileAuthorityDatabase = "database" // 数据库为准，将差额写入链上
+	ReconcileAuthorityChain    = "chain"    // 链上为准，校正数据库
+)
+
+// 对账触发方式
+const (
+	ReconcileTriggerScheduled = "scheduled"
+	ReconcileTriggerManual    = "manual"
+)
+
+// 差异处理结果
+const (
+	DriftActionDryRun      = "dry_run"
+	DriftActionDBCorrected = "db_corrected"
+	DriftActionChainPushed = "chain_pushed"
+	DriftActionFailed      = "failed"
+)
+
+// reconcileBatchSize 对账每批处理的用户数
+const reconcileBatchSize = 200
+
+// ReconcileOptions 对账选项
+type ReconcileOptions struct {
+	Trigger   string
+	Authority string
+	DryRun    bool
+}
+
+// ReputationReconcileService 数据库与 ReputationVault 声誉对账
+// 逐批遍历绑定钱包的用户，比较数据库分数（扣除尚未批量上链的规则变更）与链上分数，
+// 记录差异和漂移指标，并按权威方校正另一方；dry-run 时只生成报告
+type ReputationReconcileService struct {
+	reconcileRepo     *repositories.ReputationReconcileRepository
+	userRepo          *repositories.UserRepository
+	ruleRepo          *repositories.ReputationRuleRepository
+	reputationService *ReputationService
+	config            config.ReputationConfig
+}
+
+func NewReputationReconcileService(reconcileRepo *repositories.ReputationReconcileRepository, userRepo *repositories.UserRepository, ruleRepo *repositories.ReputationRuleRepository, reputationService *ReputationService, cfg config.ReputationConfig) *ReputationReconcileService {
+	return &ReputationReconcileService{
+		reconcileRepo:     reconcileRepo,
+		userRepo:          userRepo,
+		ruleRepo:          ruleRepo,
+		reputationService: reputationService,
+		config:            cfg,
+	}
+}
+
+// DefaultOptions 返回配置中的对账选项
+func (s *ReputationReconcileService) DefaultOptions(trigger string) ReconcileOptions {
+	return ReconcileOptions{
+		Trigger:   trigger,
+		Authority: s.config.ReconcileAuthority,
+		DryRun:    s.config.ReconcileDryRun,
+	}
+}
+
+// Run 同步执行一次对账，供定时任务调用
+func (s *ReputationReconcileService) Run(ctx context.Context, opts ReconcileOptions) (*models.ReputationReconcileRun, error) {
+	run, lock, err := s.begin(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	s.execute(ctx, run, lock)
+	if run.Status == "failed" {
+		return run, errors.New(run.Error)
+	}
+	return run, nil
+}
+
+// Start 在后台开始一次对账并立即返回任务记录，进度和结果通过报告接口查询
+func (s *ReputationReconcileService) Start(ctx context.Context, opts ReconcileOptions) (*models.ReputationReconcileRun, error) {
+	run, lock, err := s.begin(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := *run
+	go s.execute(context.WithoutCancel(ctx), run, lock)
+	return &snapshot, nil
+}
+
+// GetRun 获取对账任务及其发现的差异
+func (s *ReputationReconcileService) GetRun(ctx context.Context, runID int64, page, limit int) (*models.ReputationReconcileRun, []models.ReputationDrift, int64, error) {
+	run, err := s.reconcileRepo.GetRun(runID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, 0, errors.New("reconcile run not found")
+		}
+		return nil, nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	drifts, err := s.reconcileRepo.ListDrifts(runID, offset, limit)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	total, err := s.reconcileRepo.CountDrifts(runID)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return run, drifts, total, nil
+}
+
+// ListRuns 分页获取对账任务
+func (s *ReputationReconcileService) ListRuns(ctx context.Context, page, limit int) ([]models.ReputationReconcileRun, int64, error) {
+	offset := (page - 1) * limit
+	runs, err := s.reconcileRepo.ListRuns(offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.reconcileRepo.CountRuns()
+	if err != nil {
+		return nil, 0, err
+	}
+	return runs, total, nil
+}
+
+// begin 校验选项、获取声誉上链锁并创建任务记录。锁与规则批量上链共用且跨进程生效，
+// 同一时间只允许一个对账任务，对账期间也不会有批量上链改变链上分数和待上链变更
+func (s *ReputationReconcileService) begin(ctx context.Context, opts ReconcileOptions) (*models.ReputationReconcileRun, *repositories.AdvisoryLock, error) {
+	if opts.Authority != ReconcileAuthorityDatabase && opts.Authority != ReconcileAuthorityChain {
+		return nil, nil, errors.New("invalid reconcile authority")
+	}
+	if s.reputationService.reputationVault == nil {
+		return nil, nil, errors.New("reputation vault not available")
+	}
+	if !opts.DryRun && opts.Authority == ReconcileAuthorityDatabase && s.reputationService.config.RelayWalletKey == "" {
+		return nil, nil, errors.New("relay wallet not configured")
+	}
+
+	lock, err := s.ruleRepo.TryLockChainSync(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if lock == nil {
+		return nil, nil, errors.New("reconciliation already running")
+	}
+
+	run := &models.ReputationReconcileRun{
+		Trigger:   opts.Trigger,
+		Authority: opts.Authority,
+		DryRun:    opts.DryRun,
+		Status:    "running",
+		StartedAt: time.Now(),
+	}
+	if err := s.reconcileRepo.CreateRun(run); err != nil {
+		releaseChainSyncLock(lock)
+		loggerpkg.NewBusinessLogger(ctx).DatabaseError("insert", "reputation_reconcile_runs", "CreateRun", err)
+		return nil, nil, err
+	}
+	return run, lock, nil
+}
+
+// execute 逐批对账所有绑定钱包的用户，每批结束后保存进度
+func (s *ReputationReconcileService) execute(ctx context.Context, run *models.ReputationReconcileRun, lock *repositories.AdvisoryLock) {
+	defer releaseChainSyncLock(lock)
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	var afterID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			s.finish(run, err)
+			return
+		}
+
+		users, err := s.userRepo.ListWithWallet(afterID, reconcileBatchSize)
+		if err != nil {
+			bizLog.DatabaseError("select", "users", "ListWithWallet", err)
+			s.finish(run, err)
+			return
+		}
+		if len(users) == 0 {
+			break
+		}
+
+		for _, user := range users {
+			afterID = user.ID
+			s.reconcileUser(ctx, run, &user)
+		}
+
+		if err := s.reconcileRepo.UpdateRun(run); err != nil {
+			bizLog.DatabaseError("update", "reputation_reconcile_runs", "UpdateRun", err)
+		}
+	}
+
+	s.finish(run, nil)
+
+	bizLog.Performance("reputation_reconcile", time.Since(run.StartedAt), map[string]interface{}{
+		"run_id":        run.ID,
+		"authority":     run.Authority,
+		"dry_run":       run.DryRun,
+		"users_checked": run.UsersChecked,
+		"users_drifted": run.UsersDrifted,
+		"total_drift":   run.TotalDrift,
+		"max_drift":     run.MaxDrift,
+		"corrected":     run.Corrected,
+		"failed":        run.Failed,
+	})
+}
+
+// reconcileUser 比较单个用户的数据库与链上分数，记录并处理差异
+func (s *ReputationReconcileService) reconcileUser(ctx context.Context, run *models.ReputationReconcileRun, user *models.User) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+	run.UsersChecked++
+
+	chainScore, err := s.reputationService.getReputationFromChain(ctx, *user.WalletAddress)
+	if err != nil {
+		run.Failed++
+		bizLog.ThirdPartyError("blockchain", "getReputation", map[string]interface{}{
+			"user_id": user.ID,
+		}, err)
+		return
+	}
+
+	// 批次开始时读取的分数可能已过期，读完链上分数后重新读取数据库分数
+	if current, err := s.userRepo.GetByID(user.ID); err == nil {
+		user.ReputationScore = current.ReputationScore
+	}
+	pending, err := s.reputationService.unsyncedDelta(user.ID)
+	if err != nil {
+		run.Failed++
+		bizLog.DatabaseError("select", "reputation_rule_hits", "unsyncedDelta", err)
+		return
+	}
+
+	drift := reputationDrift(user.ReputationScore, pending, chainScore)
+	if drift == 0 {
+		return
+	}
+
+	magnitude := int64(drift)
+	if magnitude < 0 {
+		magnitude = -magnitude
+	}
+	run.UsersDrifted++
+	run.TotalDrift += magnitude
+	if magnitude > run.MaxDrift {
+		run.MaxDrift = magnitude
+	}
+
+	record := &models.ReputationDrift{
+		RunID:         run.ID,
+		UserID:        user.ID,
+		WalletAddress: *user.WalletAddress,
+		DBScore:       user.ReputationScore,
+		PendingDelta:  pending,
+		ChainScore:    chainScore,
+		Drift:         drift,
+		Action:        DriftActionDryRun,
+	}
+
+	if !run.DryRun {
+		if err := s.correct(ctx, run.Authority, user, drift, record); err != nil {
+			record.Action = DriftActionFailed
+			record.Error = err.Error()
+			run.Failed++
+		} else {
+			run.Corrected++
+		}
+	}
+
+	if err := s.reconcileRepo.CreateDrift(record); err != nil {
+		bizLog.DatabaseError("insert", "reputation_drifts", "CreateDrift", err)
+	}
+}
+
+// correct 按权威方修正差异：链上为准时将差额记入数据库账本，数据库为准时将反向差额写入链上
+func (s *ReputationReconcileService) correct(ctx context.Context, authority string, user *models.User, drift int, record *models.ReputationDrift) error {
+	if authority == ReconcileAuthorityChain {
+		if _, err := s.reputationService.RecordChange(ctx, ReputationChange{
+			UserID: user.ID,
+			Delta:  drift,
+			Reason: "链上对账校正",
+			Source: ReputationSourceChainSync,
+		}); err != nil {
+			return fmt.Errorf("failed to correct database: %w", err)
+		}
+		record.Action = DriftActionDBCorrected
+		return nil
+	}
+
+	txHash, err := s.reputationService.SubmitChainDelta(ctx, *user.WalletAddress, -drift)
+	if err != nil {
+		return fmt.Errorf("failed to push delta on chain: %w", err)
+	}
+	record.Action = DriftActionChainPushed
+	record.TxHash = txHash
+	return nil
+}
+
+// finish 保存任务的最终状态
+func (s *ReputationReconcileService) finish(run *models.ReputationReconcileRun, err error) {
+	now := time.Now()
+	run.FinishedAt = &now
+	run.Status = "completed"
+	if err != nil {
+		run.Status = "failed"
+		run.Error = err.Error()
+	}
+	if err := s.reconcileRepo.UpdateRun(run); err != nil {
+		loggerpkg.Log.Errorf("Failed to save reputation reconcile run %d: %v", run.ID, err)
+	}
+}
+
+// releaseChainSyncLock 释放声誉上链锁，失败时只记录日志
+func releaseChainSyncLock(lock *repositories.AdvisoryLock) {
+	if err := lock.Release(); err != nil {
+		loggerpkg.Log.Errorf("Failed to release reputation chain sync lock: %v", err)
+	}
+}
+
+// reputationDrift 计算链上分数相对期望值的差额，期望的链上分数为数据库分数减去尚未上链的变更
+func reputationDrift(dbScore, pendingDelta, chainScore int) int {
+	return chainScore - (dbScore - pendingDelta)
+}
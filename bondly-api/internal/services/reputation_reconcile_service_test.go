@@ -0,0 +1,77 @@
+package services
+
+import (
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestReputationDrift(t *testing.T) {
+	assert.Equal(t, 0, reputationDrift(100, 0, 100), "分数一致")
+	assert.Equal(t, 0, reputationDrift(120, 20, 100), "待上链的规则变更不算差异")
+	assert.Equal(t, -30, reputationDrift(130, 0, 100), "链上少于数据库")
+	assert.Equal(t, 15, reputationDrift(100, 5, 110), "链上多于数据库")
+}
+
+// newReconcileTestService 创建使用 sqlmock 数据库、没有链上合约的对账服务
+func newReconcileTestService(t *testing.T) (*ReputationReconcileService, sqlmock.Sqlmock) {
+	loggerpkg.Init("error", "json")
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	reputationService := &ReputationService{eventRepo: repositories.NewReputationEventRepository(db)}
+	return &ReputationReconcileService{reputationService: reputationService}, mock
+}
+
+func TestReputationReconcileCorrect_ChainAuthority(t *testing.T) {
+	service, mock := newReconcileTestService(t)
+	wallet := "0xabc"
+	user := &models.User{ID: 7, WalletAddress: &wallet, ReputationScore: 130}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT "id","reputation_score" FROM "users" WHERE "users"."id" = \$1 .*FOR UPDATE`).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "reputation_score"}).AddRow(7, 130))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "reputation_events"`)).
+		WithArgs(int64(7), -30, 100, "链上对账校正", ReputationSourceChainSync, "", nil, "", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "reputation_score"=$1`)).
+		WithArgs(100, sqlmock.AnyArg(), int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	record := &models.ReputationDrift{Action: DriftActionDryRun}
+	require.NoError(t, service.correct(context.Background(), ReconcileAuthorityChain, user, -30, record))
+	assert.Equal(t, DriftActionDBCorrected, record.Action)
+	assert.Empty(t, record.TxHash)
+	assert.Contains(t, nonEarnedReputationSources, ReputationSourceChainSync, "对账校正不计入赚取的声誉")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReputationReconcileCorrect_DatabaseAuthorityFailure(t *testing.T) {
+	service, mock := newReconcileTestService(t)
+	wallet := "0xabc"
+	user := &models.User{ID: 7, WalletAddress: &wallet, ReputationScore: 130}
+
+	record := &models.ReputationDrift{Action: DriftActionDryRun}
+	err := service.correct(context.Background(), ReconcileAuthorityDatabase, user, -30, record)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to push delta on chain")
+	assert.Equal(t, DriftActionDryRun, record.Action, "上链失败时不修改处理结果，由调用方记为 failed")
+	assert.NoError(t, mock.ExpectationsWereMet(), "数据库为准时不写数据库账本")
+}
@@ -0,0 +1,516 @@
+package services
+
+import (
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 声誉规则触发事件
+const (
+	RuleEventContentPublished = "content_published" // 发布内容
+	RuleEventContentLiked     = "content_liked"     // 内容获得点赞
+	RuleEventContentDisliked  = "content_disliked"  // 内容被点踩
+	RuleEventCommentCreated   = "comment_created"   // 发表评论
+	RuleEventCommentReceived  = "comment_received"  // 内容获得评论
+	RuleEventFollowerGained   = "follower_gained"   // 获得关注
+	RuleEventProposalPassed   = "proposal_passed"   // 提案通过
+)
+
+// chainBatchSize 单次链上同步处理的命中记录数量
+const chainBatchSize = 500
+
+// defaultReputationRules 默认规则，启动时写入数据库，之后可由管理员调整
+var defaultReputationRules = []models.ReputationRule{
+	{Event: RuleEventContentPublished, Delta: 10, DailyCap: 30, Enabled: true, Description: "发布内容"},
+	{Event: RuleEventContentLiked, Delta: 2, DailyCap: 20, Enabled: true, Description: "内容获得点赞"},
+	{Event: RuleEventContentDisliked, Delta: -1, DailyCap: 10, Enabled: true, Description: "内容被点踩"},
+	{Event: RuleEventCommentCreated, Delta: 1, DailyCap: 5, Enabled: true, Description: "发表评论"},
+	{Event: RuleEventCommentReceived, Delta: 1, DailyCap: 10, Enabled: true, Description: "内容获得评论"},
+	{Event: RuleEventFollowerGained, Delta: 1, DailyCap: 10, Enabled: true, Description: "获得关注"},
+	{Event: RuleEventProposalPassed, Delta: 20, DailyCap: 0, Enabled: true, Description: "提案通过"},
+}
+
+// peerRuleEvents 由他人行为触发的事件，需要做反自我交易检查
+var peerRuleEvents = map[string]bool{
+	RuleEventContentLiked:    true,
+	RuleEventContentDisliked: true,
+	RuleEventCommentReceived: true,
+	RuleEventFollowerGained:  true,
+}
+
+// RuleActivity 触发声誉规则的平台活动
+type RuleActivity struct {
+	Event       string
+	UserID      int64 // 获得声誉变更的用户
+	ActorID     int64 // 触发活动的用户
+	RelatedType string
+	RelatedID   int64
+}
+
+// ReputationRuleService 声誉规则引擎，根据平台活动自动奖惩声誉
+// 活动钩子允许在 nil 接收者上调用，此时不做任何处理，便于独立工具复用业务服务
+type ReputationRuleService struct {
+	ruleRepo          *repositories.ReputationRuleRepository
+	userRepo          *repositories.UserRepository
+	contentRepo       *repositories.ContentRepository
+	walletBindingRepo *repositories.WalletBindingRepository
+	reputationService *ReputationService
+	chainBatchEnabled bool
+}
+
+// NewReputationRuleService 创建声誉规则引擎
+func NewReputationRuleService(
+	ruleRepo *repositories.ReputationRuleRepository,
+	userRepo *repositories.UserRepository,
+	contentRepo *repositories.ContentRepository,
+	walletBindingRepo *repositories.WalletBindingRepository,
+	reputationService *ReputationService,
+	chainBatchEnabled bool,
+) *ReputationRuleService {
+	return &ReputationRuleService{
+		ruleRepo:          ruleRepo,
+		userRepo:          userRepo,
+		contentRepo:       contentRepo,
+		walletBindingRepo: walletBindingRepo,
+		reputationService: reputationService,
+		chainBatchEnabled: chainBatchEnabled,
+	}
+}
+
+// EnsureDefaultRules 写入缺失的默认规则
+func (s *ReputationRuleService) EnsureDefaultRules(ctx context.Context) error {
+	rules := make([]models.ReputationRule, len(defaultReputationRules))
+	copy(rules, defaultReputationRules)
+	return s.ruleRepo.EnsureRules(rules)
+}
+
+// ListRules 获取全部规则
+func (s *ReputationRuleService) ListRules(ctx context.Context) ([]models.ReputationRule, error) {
+	return s.ruleRepo.ListRules()
+}
+
+// UpdateRule 更新规则
+func (s *ReputationRuleService) UpdateRule(ctx context.Context, event string, req *dto.UpdateReputationRuleRequest) (*models.ReputationRule, error) {
+	rule, err := s.ruleRepo.GetRule(event)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("rule not found")
+		}
+		return nil, err
+	}
+
+	if req.Delta != nil {
+		rule.Delta = *req.Delta
+	}
+	if req.DailyCap != nil {
+		if *req.DailyCap < 0 {
+			return nil, errors.New("daily cap must not be negative")
+		}
+		rule.DailyCap = *req.DailyCap
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	if req.Description != nil {
+		rule.Description = *req.Description
+	}
+
+	if err := s.ruleRepo.UpdateRule(rule); err != nil {
+		return nil, err
+	}
+
+	loggerpkg.NewBusinessLogger(ctx).BusinessLogic("更新声誉规则", map[string]interface{}{
+		"event":     rule.Event,
+		"delta":     rule.Delta,
+		"daily_cap": rule.DailyCap,
+		"enabled":   rule.Enabled,
+	})
+
+	return rule, nil
+}
+
+// OnContentPublished 内容发布
+func (s *ReputationRuleService) OnContentPublished(ctx context.Context, content *models.Content) {
+	if s == nil {
+		return
+	}
+
+	s.Trigger(ctx, RuleActivity{
+		Event:       RuleEventContentPublished,
+		UserID:      content.AuthorID,
+		ActorID:     content.AuthorID,
+		RelatedType: "content",
+		RelatedID:   content.ID,
+	})
+}
+
+// OnContentInteraction 内容互动（点赞、点踩）
+func (s *ReputationRuleService) OnContentInteraction(ctx context.Context, contentID, actorID int64, interactionType string) {
+	if s == nil {
+		return
+	}
+
+	var event string
+	switch interactionType {
+	case "like":
+		event = RuleEventContentLiked
+	case "dislike":
+		event = RuleEventContentDisliked
+	default:
+		return
+	}
+
+	content, err := s.contentRepo.GetByID(contentID)
+	if err != nil {
+		loggerpkg.NewBusinessLogger(ctx).DatabaseError("select", "contents", "GetByID", err)
+		return
+	}
+
+	s.Trigger(ctx, RuleActivity{
+		Event:       event,
+		UserID:      content.AuthorID,
+		ActorID:     actorID,
+		RelatedType: "content",
+		RelatedID:   contentID,
+	})
+}
+
+// OnCommentCreated 发表评论，评论者和被评论内容的作者分别触发规则
+func (s *ReputationRuleService) OnCommentCreated(ctx context.Context, comment *models.Comment) {
+	if s == nil {
+		return
+	}
+
+	s.Trigger(ctx, RuleActivity{
+		Event:       RuleEventCommentCreated,
+		UserID:      comment.AuthorID,
+		ActorID:     comment.AuthorID,
+		RelatedType: "comment",
+		RelatedID:   comment.ID,
+	})
+
+	// 仅 contents 表的内容参与被评论奖励
+	if comment.ContentID == nil {
+		return
+	}
+	content, err := s.contentRepo.GetByID(*comment.ContentID)
+	if err != nil {
+		loggerpkg.NewBusinessLogger(ctx).DatabaseError("select", "contents", "GetByID", err)
+		return
+	}
+
+	s.Trigger(ctx, RuleActivity{
+		Event:       RuleEventCommentReceived,
+		UserID:      content.AuthorID,
+		ActorID:     comment.AuthorID,
+		RelatedType: "content",
+		RelatedID:   content.ID,
+	})
+}
+
+// OnFollow 关注用户
+func (s *ReputationRuleService) OnFollow(ctx context.Context, followerID, followedID int64) {
+	if s == nil {
+		return
+	}
+
+	s.Trigger(ctx, RuleActivity{
+		Event:       RuleEventFollowerGained,
+		UserID:      followedID,
+		ActorID:     followerID,
+		RelatedType: "user",
+		RelatedID:   followedID,
+	})
+}
+
+// OnProposalPassed 提案通过
+func (s *ReputationRuleService) OnProposalPassed(ctx context.Context, proposal *models.Proposal) {
+	if s == nil {
+		return
+	}
+
+	s.Trigger(ctx, RuleActivity{
+		Event:       RuleEventProposalPassed,
+		UserID:      proposal.ProposerID,
+		ActorID:     proposal.ProposerID,
+		RelatedType: "proposal",
+		RelatedID:   proposal.ID,
+	})
+}
+
+// Trigger 按规则处理一次平台活动，失败只记录日志，不影响触发方的主流程
+func (s *ReputationRuleService) Trigger(ctx context.Context, activity RuleActivity) {
+	if s == nil {
+		return
+	}
+
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	if _, err := s.apply(ctx, activity); err != nil {
+		bizLog.ThirdPartyError("reputation_rule", activity.Event, map[string]interface{}{
+			"user_id":      activity.UserID,
+			"actor_id":     activity.ActorID,
+			"related_type": activity.RelatedType,
+			"related_id":   activity.RelatedID,
+		}, err)
+	}
+}
+
+// apply 执行规则：启用检查、反自我交易、去重、每日上限，然后记账，返回实际生效的变更值
+func (s *ReputationRuleService) apply(ctx context.Context, activity RuleActivity) (int, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	rule, err := s.ruleRepo.GetRule(activity.Event)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if !rule.Enabled || rule.Delta == 0 {
+		return 0, nil
+	}
+
+	if peerRuleEvents[activity.Event] {
+		selfDealing, err := s.isSelfDealing(activity.UserID, activity.ActorID)
+		if err != nil {
+			return 0, err
+		}
+		if selfDealing {
+			bizLog.BusinessLogic("声誉规则跳过：自我交易", map[string]interface{}{
+				"event":    activity.Event,
+				"user_id":  activity.UserID,
+				"actor_id": activity.ActorID,
+			})
+			return 0, nil
+		}
+	}
+
+	now := time.Now()
+	used, err := s.ruleRepo.SumDailyDelta(activity.UserID, activity.Event, startOfDay(now))
+	if err != nil {
+		return 0, err
+	}
+	delta := applyDailyCap(rule.Delta, used, rule.DailyCap)
+	if delta == 0 {
+		return 0, nil
+	}
+
+	chainStatus := "pending"
+	if !s.chainBatchEnabled {
+		chainStatus = "skipped"
+	}
+
+	// 先写命中记录，唯一索引保证同一活动只奖惩一次
+	hit := &models.ReputationRuleHit{
+		Event:       activity.Event,
+		UserID:      activity.UserID,
+		ActorID:     activity.ActorID,
+		RelatedType: activity.RelatedType,
+		RelatedID:   activity.RelatedID,
+		Delta:       delta,
+		ChainStatus: chainStatus,
+		CreatedAt:   now,
+	}
+	created, err := s.ruleRepo.CreateHit(hit)
+	if err != nil {
+		return 0, err
+	}
+	if !created {
+		return 0, nil
+	}
+
+	relatedID := activity.RelatedID
+	event, err := s.reputationService.RecordChange(ctx, ReputationChange{
+		UserID:      activity.UserID,
+		Delta:       delta,
+		Reason:      rule.Description,
+		Source:      ReputationSourceRule,
+		RelatedType: activity.RelatedType,
+		RelatedID:   &relatedID,
+	})
+	if err != nil {
+		if delErr := s.ruleRepo.DeleteHit(hit.ID); delErr != nil {
+			bizLog.DatabaseError("delete", "reputation_rule_hits", "DeleteHit", delErr)
+		}
+		return 0, err
+	}
+
+	// 扣减可能因分数不足被截断，按实际生效值记录
+	if err := s.ruleRepo.SetHitEvent(hit.ID, event.ID, event.Delta); err != nil {
+		return 0, err
+	}
+
+	return event.Delta, nil
+}
+
+// FlushToChain 将待同步的规则变更按用户汇总后批量写入 ReputationVault
+func (s *ReputationRuleService) FlushToChain(ctx context.Context) (*dto.ReputationChainBatchResult, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	// 与声誉对账共用跨进程锁，对账期间不上链，避免对账把刚上链的变更再记一次
+	lock, err := s.ruleRepo.TryLockChainSync(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if lock == nil {
+		return nil, errors.New("reputation chain sync in progress")
+	}
+	defer releaseChainSyncLock(lock)
+
+	hits, err := s.ruleRepo.ListPendingHits(chainBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.ReputationChainBatchResult{TxHashes: []string{}}
+	for userID, userHits := range groupHitsByUser(hits) {
+		ids := make([]int64, 0, len(userHits))
+		delta := 0
+		for _, hit := range userHits {
+			ids = append(ids, hit.ID)
+			delta += hit.Delta
+		}
+		result.Users++
+
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return result, err
+		}
+		if user == nil || user.WalletAddress == nil || delta == 0 {
+			if err := s.ruleRepo.MarkHits(ids, "skipped", "", time.Now()); err != nil {
+				return result, err
+			}
+			result.Skipped += len(ids)
+			continue
+		}
+
+		txHash, err := s.reputationService.SubmitChainDelta(ctx, *user.WalletAddress, delta)
+		if err != nil {
+			// 保持 pending，下一批次重试
+			bizLog.ThirdPartyError("blockchain", "reputation_batch", map[string]interface{}{
+				"user_id": userID,
+				"delta":   delta,
+			}, err)
+			result.Failed += len(ids)
+			continue
+		}
+
+		if err := s.ruleRepo.MarkHits(ids, "submitted", txHash, time.Now()); err != nil {
+			return result, err
+		}
+		result.Submitted += len(ids)
+		result.TxHashes = append(result.TxHashes, txHash)
+	}
+
+	bizLog.BusinessLogic("声誉规则链上批量同步", map[string]interface{}{
+		"users":     result.Users,
+		"submitted": result.Submitted,
+		"skipped":   result.Skipped,
+		"failed":    result.Failed,
+	})
+
+	return result, nil
+}
+
+// isSelfDealing 判断两个用户是否为同一人或属于同一钱包集群
+func (s *ReputationRuleService) isSelfDealing(userID, actorID int64) (bool, error) {
+	if userID == actorID {
+		return true, nil
+	}
+
+	userWallets, err := s.walletCluster(userID)
+	if err != nil {
+		return false, err
+	}
+	actorWallets, err := s.walletCluster(actorID)
+	if err != nil {
+		return false, err
+	}
+
+	return walletClustersOverlap(userWallets, actorWallets), nil
+}
+
+// walletCluster 获取用户关联的全部钱包地址（登录钱包、托管钱包、绑定钱包）
+func (s *ReputationRuleService) walletCluster(userID int64) (map[string]bool, error) {
+	wallets := make(map[string]bool)
+	add := func(address *string) {
+		if address != nil && *address != "" {
+			wallets[strings.ToLower(*address)] = true
+		}
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return wallets, nil
+		}
+		return nil, err
+	}
+	add(user.WalletAddress)
+	add(user.CustodyWalletAddress)
+
+	bindings, err := s.walletBindingRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range bindings {
+		add(&bindings[i].WalletAddress)
+	}
+
+	return wallets, nil
+}
+
+// walletClustersOverlap 两个钱包集合存在相同地址即视为同一集群
+func walletClustersOverlap(a, b map[string]bool) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	for address := range a {
+		if b[address] {
+			return true
+		}
+	}
+	return false
+}
+
+// applyDailyCap 按每日上限截断变更值，used 为当天已使用的绝对值
+func applyDailyCap(delta, used, dailyCap int) int {
+	if dailyCap <= 0 {
+		return delta
+	}
+	remaining := dailyCap - used
+	if remaining <= 0 {
+		return 0
+	}
+	if delta > remaining {
+		return remaining
+	}
+	if delta < -remaining {
+		return -remaining
+	}
+	return delta
+}
+
+// groupHitsByUser 按用户分组命中记录
+func groupHitsByUser(hits []models.ReputationRuleHit) map[int64][]models.ReputationRuleHit {
+	grouped := make(map[int64][]models.ReputationRuleHit)
+	for _, hit := range hits {
+		grouped[hit.UserID] = append(grouped[hit.UserID], hit)
+	}
+	return grouped
+}
+
+// startOfDay 当天零点
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
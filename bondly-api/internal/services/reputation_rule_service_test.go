@@ -0,0 +1,31 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyDailyCap(t *testing.T) {
+	assert.Equal(t, 5, applyDailyCap(5, 100, 0), "上限为 0 时不限制")
+	assert.Equal(t, 2, applyDailyCap(2, 10, 20))
+	assert.Equal(t, 1, applyDailyCap(2, 19, 20), "超出上限的部分被截断")
+	assert.Equal(t, 0, applyDailyCap(2, 20, 20))
+	assert.Equal(t, -1, applyDailyCap(-3, 9, 10), "扣减同样受上限约束")
+}
+
+func TestWalletClustersOverlap(t *testing.T) {
+	alice := map[string]bool{"0xaaa": true, "0xbbb": true}
+	bob := map[string]bool{"0xccc": true}
+	aliceAlt := map[string]bool{"0xbbb": true, "0xddd": true}
+
+	assert.False(t, walletClustersOverlap(alice, bob))
+	assert.True(t, walletClustersOverlap(alice, aliceAlt))
+	assert.False(t, walletClustersOverlap(map[string]bool{}, alice))
+}
+
+func TestStartOfDay(t *testing.T) {
+	at := time.Date(2024, 3, 5, 17, 42, 9, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), startOfDay(at))
+}
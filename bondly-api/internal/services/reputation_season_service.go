@@ -0,0 +1,310 @@
+package services
+
+import (
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 赛季状态
+const (
+	ReputationSeasonActive   = "active"
+	ReputationSeasonArchived = "archived"
+)
+
+// seasonExcludedSources 不计入赛季得分的事件来源：期初余额不属于任何赛季，其余同累计声誉（衰减、赛季清零、链上同步）
+var seasonExcludedSources = append([]string{ReputationSourceOpeningBalance}, nonEarnedReputationSources...)
+
+// ReputationSeasonService 声誉赛季与累计声誉
+// 赛季得分和累计声誉都由声誉事件汇总得出，赛季结束时冻结排行榜归档
+type ReputationSeasonService struct {
+	seasonRepo *repositories.ReputationSeasonRepository
+	eventRepo  *repositories.ReputationEventRepository
+	userRepo   *repositories.UserRepository
+}
+
+func NewReputationSeasonService(seasonRepo *repositories.ReputationSeasonRepository, eventRepo *repositories.ReputationEventRepository, userRepo *repositories.UserRepository) *ReputationSeasonService {
+	return &ReputationSeasonService{
+		seasonRepo: seasonRepo,
+		eventRepo:  eventRepo,
+		userRepo:   userRepo,
+	}
+}
+
+// ListSeasons 获取全部赛季
+func (s *ReputationSeasonService) ListSeasons(ctx context.Context) ([]models.ReputationSeason, error) {
+	return s.seasonRepo.List()
+}
+
+// StartSeason 结束当前赛季（如有）并开启新赛季，可选将所有用户当前声誉清零
+// 归档、清零和创建在同一事务中完成，并由 advisory lock 串行化，并发请求不会产生两个进行中的赛季
+func (s *ReputationSeasonService) StartSeason(ctx context.Context, req *dto.StartReputationSeasonRequest) (*models.ReputationSeason, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+	now := time.Now()
+
+	season := &models.ReputationSeason{
+		Name:        req.Name,
+		Status:      ReputationSeasonActive,
+		ResetScores: req.ResetScores,
+		StartedAt:   now,
+	}
+	var reset int64
+	err := s.seasonRepo.WithSeasonLock(func(seasons *repositories.ReputationSeasonRepository, events *repositories.ReputationEventRepository) error {
+		active, err := seasons.GetActive()
+		if err != nil {
+			return err
+		}
+		if active != nil {
+			if err := s.archive(ctx, seasons, events, active, now); err != nil {
+				return err
+			}
+		}
+
+		if req.ResetScores {
+			if reset, err = events.ResetScores("赛季重置", ReputationSourceSeasonReset, now); err != nil {
+				bizLog.DatabaseError("update", "users", "ResetScores", err)
+				return err
+			}
+		}
+
+		if err := seasons.Create(season); err != nil {
+			bizLog.DatabaseError("insert", "reputation_seasons", "Create", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bizLog.BusinessLogic("开启声誉赛季", map[string]interface{}{
+		"season_id":    season.ID,
+		"name":         season.Name,
+		"reset_scores": season.ResetScores,
+		"reset_users":  reset,
+	})
+
+	return season, nil
+}
+
+// EndSeason 结束当前赛季并归档排行榜
+func (s *ReputationSeasonService) EndSeason(ctx context.Context) (*models.ReputationSeason, error) {
+	var active *models.ReputationSeason
+	err := s.seasonRepo.WithSeasonLock(func(seasons *repositories.ReputationSeasonRepository, events *repositories.ReputationEventRepository) error {
+		var err error
+		if active, err = seasons.GetActive(); err != nil {
+			return err
+		}
+		if active == nil {
+			return errors.New("no active season")
+		}
+		return s.archive(ctx, seasons, events, active, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return active, nil
+}
+
+// GetSeasonLeaderboard 获取赛季排行榜，已归档赛季返回冻结的排名，进行中的赛季实时汇总
+func (s *ReputationSeasonService) GetSeasonLeaderboard(ctx context.Context, seasonID int64, page, limit int) (*dto.ReputationSeasonLeaderboardResponse, error) {
+	season, err := s.seasonRepo.GetByID(seasonID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("season not found")
+		}
+		return nil, err
+	}
+
+	offset := (page - 1) * limit
+	data := &dto.ReputationSeasonLeaderboardResponse{
+		Season: toSeasonItem(season),
+		Page:   page,
+		Limit:  limit,
+	}
+
+	var total int64
+	if season.Status == ReputationSeasonArchived {
+		standings, err := s.seasonRepo.ListStandings(season.ID, offset, limit)
+		if err != nil {
+			return nil, err
+		}
+		if total, err = s.seasonRepo.CountStandings(season.ID); err != nil {
+			return nil, err
+		}
+		data.Rankings = make([]dto.ReputationRankingItem, 0, len(standings))
+		for _, standing := range standings {
+			standing.User.ID = standing.UserID
+			data.Rankings = append(data.Rankings, rankingItem(standing.Rank, &standing.User, standing.Score))
+		}
+	} else {
+		scores, err := s.eventRepo.SumByUsersBetween(season.StartedAt, time.Time{}, seasonExcludedSources, offset, limit)
+		if err != nil {
+			return nil, err
+		}
+		if total, err = s.eventRepo.CountUsersBetween(season.StartedAt, time.Time{}, seasonExcludedSources); err != nil {
+			return nil, err
+		}
+		if data.Rankings, err = s.resolveRankings(scores, offset); err != nil {
+			return nil, err
+		}
+	}
+
+	data.Total = int(total)
+	data.TotalPages = int((total + int64(limit) - 1) / int64(limit))
+	return data, nil
+}
+
+// GetLifetimeRanking 获取累计声誉排行榜，累计声誉不受衰减和赛季清零影响
+func (s *ReputationSeasonService) GetLifetimeRanking(ctx context.Context, limit int) ([]dto.ReputationRankingItem, error) {
+	scores, err := s.eventRepo.SumByUsersBetween(time.Time{}, time.Time{}, nonEarnedReputationSources, 0, limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolveRankings(scores, 0)
+}
+
+// GetUserLifetime 获取用户当前声誉、累计声誉、累计衰减及历史赛季排名
+func (s *ReputationSeasonService) GetUserLifetime(ctx context.Context, userID int64) (*dto.ReputationLifetimeResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	lifetime, err := s.eventRepo.SumByUserExcluding(userID, nonEarnedReputationSources)
+	if err != nil {
+		return nil, err
+	}
+	decayed, err := s.eventRepo.SumByUserAndSource(userID, ReputationSourceDecay)
+	if err != nil {
+		return nil, err
+	}
+
+	standings, err := s.seasonRepo.ListStandingsByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	seasons, err := s.seasonRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int64]string, len(seasons))
+	for _, season := range seasons {
+		names[season.ID] = season.Name
+	}
+
+	data := &dto.ReputationLifetimeResponse{
+		UserID:   user.ID,
+		Current:  user.ReputationScore,
+		Lifetime: lifetime,
+		Decayed:  -decayed,
+		Seasons:  make([]dto.ReputationSeasonStandingItem, 0, len(standings)),
+	}
+	for _, standing := range standings {
+		data.Seasons = append(data.Seasons, dto.ReputationSeasonStandingItem{
+			SeasonID:   standing.SeasonID,
+			SeasonName: names[standing.SeasonID],
+			Rank:       standing.Rank,
+			Score:      standing.Score,
+		})
+	}
+
+	return data, nil
+}
+
+// archive 汇总赛季得分冻结为排行榜，并将赛季标记为已归档，seasons 和 events 为 WithSeasonLock 中的事务仓库
+func (s *ReputationSeasonService) archive(ctx context.Context, seasons *repositories.ReputationSeasonRepository, events *repositories.ReputationEventRepository, season *models.ReputationSeason, endedAt time.Time) error {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	scores, err := events.SumByUsersBetween(season.StartedAt, endedAt, seasonExcludedSources, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	standings := make([]models.ReputationSeasonStanding, 0, len(scores))
+	for i, score := range scores {
+		standings = append(standings, models.ReputationSeasonStanding{
+			SeasonID: season.ID,
+			UserID:   score.UserID,
+			Rank:     i + 1,
+			Score:    score.Score,
+		})
+	}
+
+	season.Status = ReputationSeasonArchived
+	season.EndedAt = &endedAt
+	if err := seasons.Archive(season, standings); err != nil {
+		bizLog.DatabaseError("insert", "reputation_season_standings", "Archive", err)
+		return err
+	}
+
+	bizLog.BusinessLogic("归档声誉赛季", map[string]interface{}{
+		"season_id":    season.ID,
+		"participants": len(standings),
+	})
+	return nil
+}
+
+// resolveRankings 加载用户信息并按顺序生成排行榜
+func (s *ReputationSeasonService) resolveRankings(scores []repositories.UserScore, offset int) ([]dto.ReputationRankingItem, error) {
+	ids := make([]int64, 0, len(scores))
+	for _, score := range scores {
+		ids = append(ids, score.UserID)
+	}
+
+	users, err := s.userRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*models.User, len(users))
+	for i := range users {
+		byID[users[i].ID] = &users[i]
+	}
+
+	rankings := make([]dto.ReputationRankingItem, 0, len(scores))
+	for i, score := range scores {
+		user, ok := byID[score.UserID]
+		if !ok {
+			user = &models.User{ID: score.UserID}
+		}
+		rankings = append(rankings, rankingItem(offset+i+1, user, score.Score))
+	}
+	return rankings, nil
+}
+
+// rankingItem 生成排行榜项目
+func rankingItem(rank int, user *models.User, score int64) dto.ReputationRankingItem {
+	return dto.ReputationRankingItem{
+		Rank:          rank,
+		UserID:        user.ID,
+		Nickname:      user.Nickname,
+		WalletAddress: user.WalletAddress,
+		AvatarURL:     user.AvatarURL,
+		Reputation:    int(score),
+	}
+}
+
+// toSeasonItem 转换赛季为响应结构
+func toSeasonItem(season *models.ReputationSeason) dto.ReputationSeasonItem {
+	item := dto.ReputationSeasonItem{
+		ID:          season.ID,
+		Name:        season.Name,
+		Status:      season.Status,
+		ResetScores: season.ResetScores,
+		StartedAt:   season.StartedAt.Format(time.RFC3339),
+	}
+	if season.EndedAt != nil {
+		endedAt := season.EndedAt.Format(time.RFC3339)
+		item.EndedAt = &endedAt
+	}
+	return item
+}
@@ -7,19 +7,51 @@ import (
 	"bondly-api/internal/models"
 	"bondly-api/internal/repositories"
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
+// 声誉事件来源
+const (
+	ReputationSourceManual         = "manual"          // 管理员手动调整
+	ReputationSourceChainSync      = "chain_sync"      // 链上同步
+	ReputationSourceOpeningBalance = "opening_balance" // 账本上线前的期初余额
+	ReputationSourceRule           = "rule"            // 声誉规则自动奖惩
+	ReputationSourceDecay          = "decay"           // 按半衰期定期衰减
+	ReputationSourceSeasonReset    = "season_reset"    // 新赛季开始时清零
+)
+
+// nonEarnedReputationSources 不计入累计获得声誉的事件来源，链上同步只是校正差异，不是新获得的声誉
+var nonEarnedReputationSources = []string{ReputationSourceDecay, ReputationSourceSeasonReset, ReputationSourceChainSync}
+
+// databaseOnlyReputationSources 只记在数据库、不写入链上的事件来源
+var databaseOnlyReputationSources = []string{ReputationSourceDecay, ReputationSourceSeasonReset}
+
+// ReputationChange 声誉变更
+type ReputationChange struct {
+	UserID      int64
+	Delta       int
+	Reason      string
+	Source      string
+	RelatedType string
+	RelatedID   *int64
+	TxHash      string
+}
+
 type ReputationService struct {
 	userRepo        *repositories.UserRepository
+	eventRepo       *repositories.ReputationEventRepository
+	ruleRepo        *repositories.ReputationRuleRepository
 	reputationVault *blockchain.ReputationVault
 	config          config.EthereumConfig
 }
 
-func NewReputationService(userRepo *repositories.UserRepository, config config.EthereumConfig) *ReputationService {
+func NewReputationService(userRepo *repositories.UserRepository, eventRepo *repositories.ReputationEventRepository, ruleRepo *repositories.ReputationRuleRepository, config config.EthereumConfig) *ReputationService {
 	// 初始化ReputationVault合约客户端
 	reputationVault, err := blockchain.NewReputationVault(config)
 	if err != nil {
@@ -29,12 +61,15 @@ func NewReputationService(userRepo *repositories.UserRepository, config config.E
 
 	return &ReputationService{
 		userRepo:        userRepo,
+		eventRepo:       eventRepo,
+		ruleRepo:        ruleRepo,
 		reputationVault: reputationVault,
 		config:          config,
 	}
 }
 
 // GetUserReputation 获取用户声誉分数（优先从链上获取）
+// 只读取不记账，链上与数据库的差异由对账任务或带审计的手动同步处理
 func (s *ReputationService) GetUserReputation(ctx context.Context, userID int64) (int, error) {
 	bizLog := loggerpkg.NewBusinessLogger(ctx)
 
@@ -56,10 +91,11 @@ func (s *ReputationService) GetUserReputation(ctx context.Context, userID int64)
 				"error":          err,
 			}).Warn("Failed to get reputation from chain, using database value")
 		} else {
-			// 链上获取成功，同步到数据库
-			if err := s.syncReputationToDatabase(ctx, userID, chainReputation); err != nil {
-				bizLog.DatabaseError("update", "users", "UpdateReputationScore", err)
-			}
+			bizLog.BusinessLogic("获取声誉分数", map[string]interface{}{
+				"user_id":    userID,
+				"reputation": chainReputation,
+				"source":     "chain",
+			})
 			return chainReputation, nil
 		}
 	}
@@ -74,7 +110,7 @@ func (s *ReputationService) GetUserReputation(ctx context.Context, userID int64)
 	return user.ReputationScore, nil
 }
 
-// GetUserReputationByAddress 根据钱包地址获取用户声誉分数
+// GetUserReputationByAddress 根据钱包地址获取用户声誉分数，与 GetUserReputation 一样只读取不记账
 func (s *ReputationService) GetUserReputationByAddress(ctx context.Context, walletAddress string) (int, error) {
 	bizLog := loggerpkg.NewBusinessLogger(ctx)
 
@@ -84,11 +120,11 @@ func (s *ReputationService) GetUserReputationByAddress(ctx context.Context, wall
 		if err != nil {
 			bizLog.ThirdPartyError("blockchain", "getReputation", nil, err)
 		} else {
-			// 尝试同步到数据库
-			user, err := s.userRepo.GetByWalletAddress(walletAddress)
-			if err == nil {
-				s.syncReputationToDatabase(ctx, user.ID, chainReputation)
-			}
+			bizLog.BusinessLogic("获取声誉分数", map[string]interface{}{
+				"wallet_address": walletAddress,
+				"reputation":     chainReputation,
+				"source":         "chain",
+			})
 			return chainReputation, nil
 		}
 	}
@@ -109,17 +145,18 @@ func (s *ReputationService) GetUserReputationByAddress(ctx context.Context, wall
 	return user.ReputationScore, nil
 }
 
-// AddReputation 增加用户声誉分数
-func (s *ReputationService) AddReputation(ctx context.Context, userID int64, amount int, reason string) error {
+// AddReputation 增加用户声誉分数，返回记入账本的事件
+func (s *ReputationService) AddReputation(ctx context.Context, userID int64, amount int, reason string) (*models.ReputationEvent, error) {
 	bizLog := loggerpkg.NewBusinessLogger(ctx)
 
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
 		bizLog.DatabaseError("select", "users", "GetByID", err)
-		return fmt.Errorf("failed to get user: %w", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	// 如果用户有钱包地址且ReputationVault可用，同时更新链上数据
+	// 如果用户有钱包地址且ReputationVault可用，先更新链上数据，链上失败时不记账，避免数据库与链上分叉
+	var chainTxHash string
 	if user.WalletAddress != nil && s.reputationVault != nil && s.config.RelayWalletKey != "" {
 		amountBig := big.NewInt(int64(amount))
 		txHash, err := s.reputationVault.AddReputation(ctx, *user.WalletAddress, amountBig, s.config.RelayWalletKey)
@@ -133,54 +170,56 @@ func (s *ReputationService) AddReputation(ctx context.Context, userID int64, amo
 				"wallet_address": *user.WalletAddress,
 				"amount":         amount,
 				"error":          err,
-			}).Warn("Failed to add reputation on chain, updating database only")
-		} else {
-			bizLog.Success("addReputation", map[string]interface{}{
-				"tx_hash": txHash,
-			})
-			logrus.WithFields(logrus.Fields{
-				"user_id":        userID,
-				"wallet_address": *user.WalletAddress,
-				"amount":         amount,
-				"tx_hash":        txHash,
-			}).Info("Successfully added reputation on chain")
+			}).Warn("Failed to add reputation on chain, database not updated")
+			return nil, fmt.Errorf("failed to add reputation on chain: %w", err)
 		}
+		chainTxHash = txHash
+		bizLog.Success("addReputation", map[string]interface{}{
+			"tx_hash": txHash,
+		})
+		logrus.WithFields(logrus.Fields{
+			"user_id":        userID,
+			"wallet_address": *user.WalletAddress,
+			"amount":         amount,
+			"tx_hash":        txHash,
+		}).Info("Successfully added reputation on chain")
 	}
 
-	// 更新数据库
-	newScore := user.ReputationScore + amount
-	if newScore < 0 {
-		newScore = 0
-	}
-
-	err = s.userRepo.UpdateReputationScore(userID, newScore)
+	// 记录声誉事件并更新数据库投影
+	event, err := s.RecordChange(ctx, ReputationChange{
+		UserID: userID,
+		Delta:  amount,
+		Reason: reason,
+		Source: ReputationSourceManual,
+		TxHash: chainTxHash,
+	})
 	if err != nil {
-		bizLog.DatabaseError("update", "users", "UpdateReputationScore", err)
-		return fmt.Errorf("failed to update reputation score: %w", err)
+		return nil, fmt.Errorf("failed to update reputation score: %w", err)
 	}
 
 	bizLog.Success("reputationChanged", map[string]interface{}{
 		"user_id":   userID,
-		"old_score": user.ReputationScore,
-		"new_score": newScore,
-		"change":    amount,
+		"old_score": event.ScoreAfter - event.Delta,
+		"new_score": event.ScoreAfter,
+		"change":    event.Delta,
 		"reason":    reason,
 	})
 
-	return nil
+	return event, nil
 }
 
-// SubtractReputation 减少用户声誉分数
-func (s *ReputationService) SubtractReputation(ctx context.Context, userID int64, amount int, reason string) error {
+// SubtractReputation 减少用户声誉分数，返回记入账本的事件
+func (s *ReputationService) SubtractReputation(ctx context.Context, userID int64, amount int, reason string) (*models.ReputationEvent, error) {
 	bizLog := loggerpkg.NewBusinessLogger(ctx)
 
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
 		bizLog.DatabaseError("select", "users", "GetByID", err)
-		return fmt.Errorf("failed to get user: %w", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	// 如果用户有钱包地址且ReputationVault可用，同时更新链上数据
+	// 如果用户有钱包地址且ReputationVault可用，先更新链上数据，链上失败时不记账，避免数据库与链上分叉
+	var chainTxHash string
 	if user.WalletAddress != nil && s.reputationVault != nil && s.config.RelayWalletKey != "" {
 		amountBig := big.NewInt(int64(amount))
 		txHash, err := s.reputationVault.SubtractReputation(ctx, *user.WalletAddress, amountBig, s.config.RelayWalletKey)
@@ -194,71 +233,72 @@ func (s *ReputationService) SubtractReputation(ctx context.Context, userID int64
 				"wallet_address": *user.WalletAddress,
 				"amount":         amount,
 				"error":          err,
-			}).Warn("Failed to subtract reputation on chain, updating database only")
-		} else {
-			bizLog.Success("subtractReputation", map[string]interface{}{
-				"tx_hash": txHash,
-			})
-			logrus.WithFields(logrus.Fields{
-				"user_id":        userID,
-				"wallet_address": *user.WalletAddress,
-				"amount":         amount,
-				"tx_hash":        txHash,
-			}).Info("Successfully subtracted reputation on chain")
+			}).Warn("Failed to subtract reputation on chain, database not updated")
+			return nil, fmt.Errorf("failed to subtract reputation on chain: %w", err)
 		}
+		chainTxHash = txHash
+		bizLog.Success("subtractReputation", map[string]interface{}{
+			"tx_hash": txHash,
+		})
+		logrus.WithFields(logrus.Fields{
+			"user_id":        userID,
+			"wallet_address": *user.WalletAddress,
+			"amount":         amount,
+			"tx_hash":        txHash,
+		}).Info("Successfully subtracted reputation on chain")
 	}
 
-	// 更新数据库
-	newScore := user.ReputationScore - amount
-	if newScore < 0 {
-		newScore = 0
-	}
-
-	err = s.userRepo.UpdateReputationScore(userID, newScore)
+	// 记录声誉事件并更新数据库投影
+	event, err := s.RecordChange(ctx, ReputationChange{
+		UserID: userID,
+		Delta:  -amount,
+		Reason: reason,
+		Source: ReputationSourceManual,
+		TxHash: chainTxHash,
+	})
 	if err != nil {
-		bizLog.DatabaseError("update", "users", "UpdateReputationScore", err)
-		return fmt.Errorf("failed to update reputation score: %w", err)
+		return nil, fmt.Errorf("failed to update reputation score: %w", err)
 	}
 
 	bizLog.Success("reputationChanged", map[string]interface{}{
 		"user_id":   userID,
-		"old_score": user.ReputationScore,
-		"new_score": newScore,
-		"change":    -amount,
+		"old_score": event.ScoreAfter - event.Delta,
+		"new_score": event.ScoreAfter,
+		"change":    event.Delta,
 		"reason":    reason,
 	})
 
-	return nil
+	return event, nil
 }
 
-// SyncReputationFromChain 从链上同步用户声誉分数到数据库
-func (s *ReputationService) SyncReputationFromChain(ctx context.Context, userID int64) error {
+// SyncReputationFromChain 从链上同步用户声誉分数到数据库，返回记入账本的校正事件，没有差异时为 nil
+func (s *ReputationService) SyncReputationFromChain(ctx context.Context, userID int64) (*models.ReputationEvent, error) {
 	bizLog := loggerpkg.NewBusinessLogger(ctx)
 
 	if s.reputationVault == nil {
-		return fmt.Errorf("reputation vault not available")
+		return nil, fmt.Errorf("reputation vault not available")
 	}
 
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
 		bizLog.DatabaseError("select", "users", "GetByID", err)
-		return fmt.Errorf("failed to get user: %w", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
 	if user.WalletAddress == nil {
-		return fmt.Errorf("user does not have a wallet address")
+		return nil, fmt.Errorf("user does not have a wallet address")
 	}
 
 	chainReputation, err := s.getReputationFromChain(ctx, *user.WalletAddress)
 	if err != nil {
 		bizLog.ThirdPartyError("blockchain", "getReputation", nil, err)
-		return fmt.Errorf("failed to get reputation from chain: %w", err)
+		return nil, fmt.Errorf("failed to get reputation from chain: %w", err)
 	}
 
-	err = s.syncReputationToDatabase(ctx, userID, chainReputation)
+	event, err := s.syncReputationToDatabase(ctx, userID, chainReputation)
 	if err != nil {
-		bizLog.DatabaseError("update", "users", "UpdateReputationScore", err)
-		return fmt.Errorf("failed to sync reputation to database: %w", err)
+		bizLog.DatabaseError("insert", "reputation_events", "syncReputationToDatabase", err)
+		return nil, fmt.Errorf("failed to sync reputation to database: %w", err)
 	}
 
 	bizLog.BusinessLogic("同步链上声誉", map[string]interface{}{
@@ -267,7 +307,7 @@ func (s *ReputationService) SyncReputationFromChain(ctx context.Context, userID
 		"chain_reputation": chainReputation,
 	})
 
-	return nil
+	return event, nil
 }
 
 // IsEligibleForGovernance 检查用户是否符合治理条件
@@ -309,11 +349,117 @@ func (s *ReputationService) IsEligibleForGovernance(ctx context.Context, userID
 	return eligible, nil
 }
 
+// RecordChange 记录一条声誉事件，并在同一事务中更新用户声誉分数投影
+func (s *ReputationService) RecordChange(ctx context.Context, change ReputationChange) (*models.ReputationEvent, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	event := &models.ReputationEvent{
+		UserID:      change.UserID,
+		Delta:       change.Delta,
+		Reason:      change.Reason,
+		Source:      change.Source,
+		RelatedType: change.RelatedType,
+		RelatedID:   change.RelatedID,
+		TxHash:      change.TxHash,
+	}
+	if err := s.eventRepo.Append(event); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		bizLog.DatabaseError("insert", "reputation_events", "Append", err)
+		return nil, err
+	}
+
+	bizLog.BusinessLogic("记录声誉事件", map[string]interface{}{
+		"user_id":     event.UserID,
+		"delta":       event.Delta,
+		"score_after": event.ScoreAfter,
+		"reason":      event.Reason,
+		"source":      event.Source,
+	})
+
+	return event, nil
+}
+
+// RecordDecay 记录一条衰减事件，并在同一事务中把用户的衰减起点从 decayedAt 移到 at
+// 起点已被移动时不记账，返回 nil 事件
+func (s *ReputationService) RecordDecay(ctx context.Context, userID int64, amount int, decayedAt, at time.Time) (*models.ReputationEvent, error) {
+	event := &models.ReputationEvent{
+		UserID: userID,
+		Delta:  -amount,
+		Reason: "声誉衰减",
+		Source: ReputationSourceDecay,
+	}
+	applied, err := s.eventRepo.AppendDecay(event, decayedAt, at)
+	if err != nil {
+		loggerpkg.NewBusinessLogger(ctx).DatabaseError("insert", "reputation_events", "AppendDecay", err)
+		return nil, err
+	}
+	if !applied {
+		return nil, nil
+	}
+	return event, nil
+}
+
+// SubmitChainDelta 将声誉变更写入 ReputationVault，正数调用 addReputation，负数调用 subtractReputation
+func (s *ReputationService) SubmitChainDelta(ctx context.Context, walletAddress string, delta int) (string, error) {
+	if s.reputationVault == nil || s.config.RelayWalletKey == "" {
+		return "", fmt.Errorf("reputation vault not available")
+	}
+
+	if delta >= 0 {
+		return s.reputationVault.AddReputation(ctx, walletAddress, big.NewInt(int64(delta)), s.config.RelayWalletKey)
+	}
+	return s.reputationVault.SubtractReputation(ctx, walletAddress, big.NewInt(int64(-delta)), s.config.RelayWalletKey)
+}
+
+// GetReputationHistory 分页获取用户声誉变更历史及按原因汇总
+func (s *ReputationService) GetReputationHistory(ctx context.Context, userID int64, reason string, page, limit int) ([]models.ReputationEvent, int64, []repositories.ReputationReasonAggregate, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, nil, errors.New("user not found")
+		}
+		return nil, 0, nil, err
+	}
+
+	offset := (page - 1) * limit
+
+	events, err := s.eventRepo.ListByUser(userID, reason, offset, limit)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	total, err := s.eventRepo.CountByUser(userID, reason)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	aggregates, err := s.eventRepo.AggregateByReason(userID)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	return events, total, aggregates, nil
+}
+
 // GetTopUsersByReputation 获取声誉排行榜
 func (s *ReputationService) GetTopUsersByReputation(ctx context.Context, limit int) ([]models.User, error) {
 	return s.userRepo.GetTopUsersByReputation(limit)
 }
 
+// unsyncedDelta 用户只记在数据库、未写入链上的声誉变更之和，期望的链上分数为数据库分数减去该值
+func (s *ReputationService) unsyncedDelta(userID int64) (int, error) {
+	rules, err := s.ruleRepo.SumUnsyncedByUser(userID)
+	if err != nil {
+		return 0, err
+	}
+	local, err := s.eventRepo.SumByUserSources(userID, databaseOnlyReputationSources)
+	if err != nil {
+		return 0, err
+	}
+	return rules + int(local), nil
+}
+
 // getReputationFromChain 从链上获取声誉分数
 func (s *ReputationService) getReputationFromChain(ctx context.Context, walletAddress string) (int, error) {
 	reputation, err := s.reputationVault.GetReputation(ctx, walletAddress)
@@ -329,7 +475,28 @@ func (s *ReputationService) getReputationFromChain(ctx context.Context, walletAd
 	return int(reputation.Int64()), nil
 }
 
-// syncReputationToDatabase 同步声誉分数到数据库
-func (s *ReputationService) syncReputationToDatabase(ctx context.Context, userID int64, reputation int) error {
-	return s.userRepo.UpdateReputationScore(userID, reputation)
+// syncReputationToDatabase 按链上分数校正数据库，差额记为链上同步事件
+// 只存在于数据库的变更（未上链的规则变更、衰减和赛季清零）不算差异，先从数据库分数中扣除再比较，避免同步抹掉这些变更
+func (s *ReputationService) syncReputationToDatabase(ctx context.Context, userID int64, reputation int) (*models.ReputationEvent, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	unsynced, err := s.unsyncedDelta(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	delta := reputationDrift(user.ReputationScore, unsynced, reputation)
+	if delta == 0 {
+		return nil, nil
+	}
+
+	return s.RecordChange(ctx, ReputationChange{
+		UserID: userID,
+		Delta:  delta,
+		Reason: "链上同步",
+		Source: ReputationSourceChainSync,
+	})
 }
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"bondly-api/config"
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/repositories"
+	"context"
+	"errors"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 搜索关键词长度限制（字符数）
+const maxSearchKeywordLength = 100
+
+// searchSnippetRadius 子串匹配时片段在匹配词两侧保留的字符数
+const searchSnippetRadius = 60
+
+// SearchTypes 支持的搜索类型
+var SearchTypes = []string{repositories.SearchTypeContent, repositories.SearchTypeUser, repositories.SearchTypeProposal}
+
+// textSearchConfigPattern 全文检索配置名只允许标识符，防止拼接进 SQL 时注入
+var textSearchConfigPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// SearchService 全文搜索服务
+// 基于 PostgreSQL tsvector 检索已发布内容、用户和提案，可选 pg_trgm 子串匹配以支持未分词的中文
+type SearchService struct {
+	searchRepo       *repositories.SearchRepository
+	textSearchConfig string
+	trigramFallback  bool
+}
+
+// NewSearchService 创建搜索服务，检索配置名不合法时回退为 simple
+func NewSearchService(searchRepo *repositories.SearchRepository, cfg config.SearchConfig) *SearchService {
+	return &SearchService{
+		searchRepo:       searchRepo,
+		textSearchConfig: NormalizeTextSearchConfig(cfg.TextSearchConfig),
+		trigramFallback:  cfg.TrigramFallback,
+	}
+}
+
+// NormalizeTextSearchConfig 校验全文检索配置名，不合法时返回 simple
+func NormalizeTextSearchConfig(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !textSearchConfigPattern.MatchString(name) {
+		return "simple"
+	}
+	return name
+}
+
+// Search 搜索内容、用户和提案，按相关度排序，返回当前页结果、各类型命中数量及总数
+func (s *SearchService) Search(ctx context.Context, keyword string, types []string, page, limit int) ([]dto.SearchResultItem, map[string]int64, int64, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return nil, nil, 0, errors.New("search keyword required")
+	}
+	if utf8.RuneCountInString(keyword) > maxSearchKeywordLength {
+		return nil, nil, 0, errors.New("search keyword too long")
+	}
+	types, err := normalizeSearchTypes(types)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	hits, counts, err := s.searchRepo.Search(repositories.SearchQuery{
+		Keyword:          keyword,
+		Types:            types,
+		TextSearchConfig: s.textSearchConfig,
+		TrigramFallback:  s.trigramFallback,
+		Offset:           (page - 1) * limit,
+		Limit:            limit,
+	})
+	if err != nil {
+		bizLog.DatabaseError("select", "search", "Search", err)
+		return nil, nil, 0, err
+	}
+
+	items := make([]dto.SearchResultItem, 0, len(hits))
+	for _, hit := range hits {
+		snippet := renderSearchHeadline(hit.Headline)
+		if !strings.Contains(snippet, "<mark>") {
+			// 分词器未命中（如中文子串匹配）时按子串生成片段
+			if fallback := highlightSubstring(hit.Body, keyword, searchSnippetRadius); fallback != "" {
+				snippet = fallback
+			}
+		}
+		items = append(items, dto.SearchResultItem{
+			Type:      hit.Type,
+			ID:        hit.ID,
+			Title:     hit.Title,
+			Snippet:   snippet,
+			Rank:      hit.Rank,
+			CreatedAt: hit.CreatedAt,
+		})
+	}
+
+	var total int64
+	for _, count := range counts {
+		total += count
+	}
+
+	return items, counts, total, nil
+}
+
+// normalizeSearchTypes 校验并去重搜索类型，为空时搜索全部类型
+func normalizeSearchTypes(types []string) ([]string, error) {
+	if len(types) == 0 {
+		return SearchTypes, nil
+	}
+	seen := make(map[string]bool, len(types))
+	result := make([]string, 0, len(types))
+	for _, name := range types {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		valid := false
+		for _, t := range SearchTypes {
+			if t == name {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, errors.New("invalid search type")
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+	if len(result) == 0 {
+		return SearchTypes, nil
+	}
+	return result, nil
+}
+
+// renderSearchHeadline 转义 ts_headline 片段，并将匹配标记替换为 <mark>
+func renderSearchHeadline(headline string) string {
+	escaped := html.EscapeString(strings.TrimSpace(headline))
+	return strings.NewReplacer(
+		repositories.SearchHighlightStart, "<mark>",
+		repositories.SearchHighlightStop, "</mark>",
+	).Replace(escaped)
+}
+
+// highlightSubstring 在正文中查找关键词（不区分大小写），截取两侧 radius 个字符并以 <mark> 标记，未找到时返回空字符串
+func highlightSubstring(text, keyword string, radius int) string {
+	textRunes := []rune(text)
+	keywordRunes := []rune(keyword)
+	if len(keywordRunes) == 0 || len(keywordRunes) > len(textRunes) {
+		return ""
+	}
+
+	index := -1
+	for i := 0; i+len(keywordRunes) <= len(textRunes); i++ {
+		matched := true
+		for j, r := range keywordRunes {
+			if unicode.ToLower(textRunes[i+j]) != unicode.ToLower(r) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return ""
+	}
+
+	start := index - radius
+	if start < 0 {
+		start = 0
+	}
+	end := index + len(keywordRunes) + radius
+	if end > len(textRunes) {
+		end = len(textRunes)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	b.WriteString(html.EscapeString(string(textRunes[start:index])))
+	b.WriteString("<mark>")
+	b.WriteString(html.EscapeString(string(textRunes[index : index+len(keywordRunes)])))
+	b.WriteString("</mark>")
+	b.WriteString(html.EscapeString(string(textRunes[index+len(keywordRunes) : end])))
+	if end < len(textRunes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTextSearchConfig(t *testing.T) {
+	assert.Equal(t, "simple", NormalizeTextSearchConfig(""))
+	assert.Equal(t, "english", NormalizeTextSearchConfig(" English "))
+	assert.Equal(t, "zh_cn", NormalizeTextSearchConfig("zh_cn"))
+	assert.Equal(t, "simple", NormalizeTextSearchConfig("simple'); DROP TABLE users; --"), "非法配置名回退为 simple")
+}
+
+func TestNormalizeSearchTypes(t *testing.T) {
+	types, err := normalizeSearchTypes(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, SearchTypes, types)
+
+	types, err = normalizeSearchTypes([]string{"user", " content", "user"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user", "content"}, types)
+
+	_, err = normalizeSearchTypes([]string{"comment"})
+	assert.EqualError(t, err, "invalid search type")
+}
+
+func TestRenderSearchHeadline(t *testing.T) {
+	assert.Equal(t, "&lt;b&gt;Web3&lt;/b&gt; <mark>social</mark> graph", renderSearchHeadline("<b>Web3</b> [[hl]]social[[/hl]] graph"))
+}
+
+func TestHighlightSubstring(t *testing.T) {
+	assert.Equal(t, "去中心化<mark>社交</mark>网络", highlightSubstring("去中心化社交网络", "社交", 10))
+	assert.Equal(t, "…中心化<mark>社交</mark>网络和…", highlightSubstring("去中心化社交网络和治理", "社交", 3))
+	assert.Equal(t, "<mark>Web3</mark> &amp; DeFi", highlightSubstring("Web3 & DeFi", "web3", 10), "不区分大小写并转义 HTML")
+	assert.Equal(t, "", highlightSubstring("去中心化社交网络", "治理", 10))
+}
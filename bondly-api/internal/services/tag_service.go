@@ -0,0 +1,256 @@
+package services
+
+import (
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// 标签限制
+const (
+	maxTagLength      = 32 // 标签最大字符数
+	MaxTagsPerContent = 5  // 每篇内容最多标签数
+)
+
+// TagService 内容标签服务
+type TagService struct {
+	tagRepo *repositories.TagRepository
+}
+
+func NewTagService(tagRepo *repositories.TagRepository) *TagService {
+	return &TagService{
+		tagRepo: tagRepo,
+	}
+}
+
+// NormalizeTagName 规范化标签展示名称：去掉首尾空白和前导 #，合并连续空白
+func NormalizeTagName(name string) string {
+	name = strings.TrimSpace(name)
+	name = strings.TrimLeft(name, "#")
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// NormalizeTagSlug 由标签名称生成 slug：小写、空白替换为连字符，仅允许字母、数字、- 和 _，不合法时返回空字符串
+func NormalizeTagSlug(name string) string {
+	name = NormalizeTagName(name)
+	if name == "" || utf8.RuneCountInString(name) > maxTagLength {
+		return ""
+	}
+	slug := strings.ToLower(strings.ReplaceAll(name, " ", "-"))
+	for _, r := range slug {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return ""
+		}
+	}
+	return slug
+}
+
+// normalizeTags 校验并按 slug 去重标签，保持原有顺序
+func normalizeTags(tags []models.Tag) ([]models.Tag, error) {
+	seen := make(map[string]bool, len(tags))
+	result := make([]models.Tag, 0, len(tags))
+	for _, tag := range tags {
+		slug := NormalizeTagSlug(tag.Name)
+		if slug == "" {
+			return nil, errors.New("invalid tag")
+		}
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		result = append(result, models.Tag{Name: NormalizeTagName(tag.Name), Slug: slug})
+	}
+	if len(result) > MaxTagsPerContent {
+		return nil, errors.New("too many tags")
+	}
+	return result, nil
+}
+
+// SetContentTags 替换内容的标签，不存在的标签自动创建，tags 须已经过 normalizeTags 处理
+func (s *TagService) SetContentTags(ctx context.Context, contentID int64, tags []models.Tag) ([]models.Tag, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	saved, err := s.tagRepo.EnsureTags(tags)
+	if err != nil {
+		bizLog.DatabaseError("insert", "tags", "EnsureTags", err)
+		return nil, err
+	}
+
+	tagIDs := make([]int64, 0, len(saved))
+	for _, tag := range saved {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	if err := s.tagRepo.ReplaceContentTags(contentID, tagIDs); err != nil {
+		bizLog.DatabaseError("update", "content_tags", "ReplaceContentTags", err)
+		return nil, err
+	}
+	return saved, nil
+}
+
+// AttachTags 为内容列表填充标签，查询失败时保持为空
+func (s *TagService) AttachTags(ctx context.Context, contents []models.Content) {
+	if s == nil || len(contents) == 0 {
+		return
+	}
+
+	ids := make([]int64, 0, len(contents))
+	for _, content := range contents {
+		ids = append(ids, content.ID)
+	}
+	tagsByContent, err := s.tagRepo.ListByContentIDs(ids)
+	if err != nil {
+		loggerpkg.NewBusinessLogger(ctx).DatabaseError("select", "content_tags", "ListByContentIDs", err)
+		return
+	}
+	for i := range contents {
+		contents[i].Tags = tagsByContent[contents[i].ID]
+		if contents[i].Tags == nil {
+			contents[i].Tags = []models.Tag{}
+		}
+	}
+}
+
+// Autocomplete 按前缀补全标签，内容数量多的在前
+func (s *TagService) Autocomplete(ctx context.Context, prefix string, limit int) ([]models.Tag, error) {
+	prefix = strings.ToLower(NormalizeTagName(prefix))
+	prefix = strings.ReplaceAll(prefix, " ", "-")
+	return s.tagRepo.Search(prefix, limit)
+}
+
+// GetTag 获取标签详情及关注人数
+func (s *TagService) GetTag(ctx context.Context, slug string) (*models.Tag, int64, error) {
+	tag, err := s.getBySlug(slug)
+	if err != nil {
+		return nil, 0, err
+	}
+	followers, err := s.tagRepo.CountFollowers(tag.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return tag, followers, nil
+}
+
+// FollowTag 关注标签
+func (s *TagService) FollowTag(ctx context.Context, userID int64, slug string) error {
+	tag, err := s.getBySlug(slug)
+	if err != nil {
+		return err
+	}
+	created, err := s.tagRepo.Follow(userID, tag.ID)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return errors.New("already following this tag")
+	}
+	return nil
+}
+
+// UnfollowTag 取消关注标签
+func (s *TagService) UnfollowTag(ctx context.Context, userID int64, slug string) error {
+	tag, err := s.getBySlug(slug)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.tagRepo.Unfollow(userID, tag.ID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("not following this tag")
+	}
+	return nil
+}
+
+// ListFollowedTags 获取用户关注的标签
+func (s *TagService) ListFollowedTags(ctx context.Context, userID int64) ([]models.TagFollow, error) {
+	return s.tagRepo.ListFollowed(userID)
+}
+
+// TrendingTags 获取最近 days 天内互动量最高的标签
+func (s *TagService) TrendingTags(ctx context.Context, days, limit int) ([]repositories.TrendingTag, error) {
+	since := time.Now().AddDate(0, 0, -days)
+	return s.tagRepo.ListTrending(since, limit)
+}
+
+// RenameTag 修改标签名称，slug 随之更新；新 slug 已被其他标签占用时应使用合并
+func (s *TagService) RenameTag(ctx context.Context, slug, name string) (*models.Tag, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	tag, err := s.getBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	newSlug := NormalizeTagSlug(name)
+	if newSlug == "" {
+		return nil, errors.New("invalid tag")
+	}
+	if newSlug != tag.Slug {
+		if _, err := s.getBySlug(newSlug); err == nil {
+			return nil, errors.New("tag already exists")
+		} else if err.Error() != "tag not found" {
+			return nil, err
+		}
+	}
+
+	if err := s.tagRepo.Rename(tag.ID, NormalizeTagName(name), newSlug); err != nil {
+		bizLog.DatabaseError("update", "tags", "Rename", err)
+		return nil, err
+	}
+	bizLog.BusinessLogic("重命名标签", map[string]interface{}{
+		"tag_id":   tag.ID,
+		"old_slug": tag.Slug,
+		"new_slug": newSlug,
+	})
+	return s.getBySlug(newSlug)
+}
+
+// MergeTag 将源标签并入目标标签，内容关联和关注者转移到目标标签后删除源标签
+func (s *TagService) MergeTag(ctx context.Context, sourceSlug, targetSlug string) (*models.Tag, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	source, err := s.getBySlug(sourceSlug)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.getBySlug(NormalizeTagSlug(targetSlug))
+	if err != nil {
+		return nil, err
+	}
+	if source.ID == target.ID {
+		return nil, errors.New("cannot merge tag into itself")
+	}
+
+	if err := s.tagRepo.Merge(source.ID, target.ID); err != nil {
+		bizLog.DatabaseError("update", "tags", "Merge", err)
+		return nil, err
+	}
+	bizLog.BusinessLogic("合并标签", map[string]interface{}{
+		"source": source.Slug,
+		"target": target.Slug,
+	})
+	return s.getBySlug(target.Slug)
+}
+
+// getBySlug 根据 slug 获取标签
+func (s *TagService) getBySlug(slug string) (*models.Tag, error) {
+	if slug == "" {
+		return nil, errors.New("tag not found")
+	}
+	tag, err := s.tagRepo.GetBySlug(slug)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, err
+	}
+	return tag, nil
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"bondly-api/internal/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTagSlug(t *testing.T) {
+	assert.Equal(t, "web3", NormalizeTagSlug("  #Web3 "))
+	assert.Equal(t, "open-source", NormalizeTagSlug("Open   Source"))
+	assert.Equal(t, "区块链_dao", NormalizeTagSlug("区块链_DAO"))
+	assert.Equal(t, "", NormalizeTagSlug("#"), "空标签")
+	assert.Equal(t, "", NormalizeTagSlug("c++"), "不允许的字符")
+	assert.Equal(t, "", NormalizeTagSlug(strings.Repeat("a", maxTagLength+1)), "超过长度限制")
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := normalizeTags([]models.Tag{{Name: "Web3"}, {Name: "#web3"}, {Name: "Open Source"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Tag{{Name: "Web3", Slug: "web3"}, {Name: "Open Source", Slug: "open-source"}}, tags)
+
+	_, err = normalizeTags([]models.Tag{{Name: "ok"}, {Name: "bad tag!"}})
+	assert.EqualError(t, err, "invalid tag")
+
+	tooMany := []models.Tag{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}, {Name: "f"}}
+	_, err = normalizeTags(tooMany)
+	assert.EqualError(t, err, "too many tags")
+
+	tags, err = normalizeTags([]models.Tag{})
+	assert.NoError(t, err)
+	assert.Empty(t, tags)
+}
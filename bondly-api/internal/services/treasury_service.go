@@ -0,0 +1,520 @@
+package services
+
+import (
+	"bondly-api/config"
+	"bondly-api/internal/blockchain"
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"bondly-api/internal/utils"
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// treasuryFlowCursor 金库资金事件索引器在 chain_index_cursors 中的名称
+const treasuryFlowCursor = "treasury_flows"
+
+// TreasuryBudgetCategories 拨款申请可用的预算类别
+var TreasuryBudgetCategories = []string{
+	models.TreasuryCategoryDevelopment,
+	models.TreasuryCategoryMarketing,
+	models.TreasuryCategoryCommunity,
+	models.TreasuryCategoryGrants,
+	models.TreasuryCategoryOperations,
+	models.TreasuryCategorySecurity,
+}
+
+// treasuryAssets 金库支持的资产，按面板展示顺序排列
+var treasuryAssets = []string{blockchain.TreasuryAssetETH, blockchain.TreasuryAssetBOND}
+
+// TreasuryService 金库服务
+// 汇总 BondlyTreasury 的链上余额与已索引的资金流水，并管理治理提案的拨款申请
+type TreasuryService struct {
+	treasuryRepo *repositories.TreasuryRepository
+	proposalRepo *repositories.ProposalRepository
+	treasury     *blockchain.BondlyTreasury
+	cfg          config.TreasuryConfig
+}
+
+// NewTreasuryService 创建金库服务，treasury 为 nil 时面板只展示已索引的数据且无法索引新事件
+func NewTreasuryService(treasuryRepo *repositories.TreasuryRepository, proposalRepo *repositories.ProposalRepository, treasury *blockchain.BondlyTreasury, cfg config.TreasuryConfig) *TreasuryService {
+	return &TreasuryService{
+		treasuryRepo: treasuryRepo,
+		proposalRepo: proposalRepo,
+		treasury:     treasury,
+		cfg:          cfg,
+	}
+}
+
+// GetDashboard 获取金库面板：各资产余额、累计流入流出以及按预算类别的拨款汇总
+func (s *TreasuryService) GetDashboard(ctx context.Context) (*dto.TreasuryDashboardResponse, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	flowTotals, err := s.treasuryRepo.SumFlows()
+	if err != nil {
+		return nil, err
+	}
+	lastBlock, _, err := s.treasuryRepo.GetCursor(treasuryFlowCursor)
+	if err != nil {
+		return nil, err
+	}
+	disbursementTotals, err := s.treasuryRepo.SumDisbursements()
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard := &dto.TreasuryDashboardResponse{
+		LastIndexedBlock: lastBlock,
+		Assets:           summarizeTreasuryFlows(flowTotals),
+		Budgets:          summarizeTreasuryBudgets(disbursementTotals),
+		Categories:       TreasuryBudgetCategories,
+	}
+
+	if s.treasury != nil {
+		dashboard.ContractAddress = s.treasury.Address()
+		if err := s.fillChainBalances(ctx, dashboard.Assets); err != nil {
+			// 链上不可用时仍返回已索引的数据
+			bizLog.ThirdPartyError("blockchain", "treasury_balances", nil, err)
+		} else {
+			dashboard.ChainAvailable = true
+		}
+	}
+
+	return dashboard, nil
+}
+
+// ListFlows 分页获取已索引的资金流水
+func (s *TreasuryService) ListFlows(ctx context.Context, asset, direction string, page, limit int) ([]models.TreasuryFlow, int64, error) {
+	offset := (page - 1) * limit
+	flows, err := s.treasuryRepo.ListFlows(asset, direction, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.treasuryRepo.CountFlows(asset, direction)
+	if err != nil {
+		return nil, 0, err
+	}
+	return flows, total, nil
+}
+
+// RequestDisbursement 为投票中的提案申请金库拨款，仅提案发起人或管理员可申请
+func (s *TreasuryService) RequestDisbursement(ctx context.Context, requesterID int64, isAdmin bool, req *dto.CreateDisbursementRequest) (*models.TreasuryDisbursement, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	if !IsValidTreasuryCategory(req.Category) {
+		return nil, errors.New("invalid budget category")
+	}
+	amount, ok := new(big.Int).SetString(req.Amount, 10)
+	if !ok || amount.Sign() <= 0 {
+		return nil, errors.New("invalid amount")
+	}
+	if !utils.ValidateAddress(req.Recipient) {
+		return nil, errors.New("invalid recipient address")
+	}
+
+	proposal, err := s.proposalRepo.GetByID(req.ProposalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("proposal not found")
+		}
+		return nil, err
+	}
+	if proposal.ProposerID != requesterID && !isAdmin {
+		return nil, errors.New("not proposal proposer")
+	}
+	if proposal.Status != "active" {
+		return nil, errors.New("proposal not active")
+	}
+
+	disbursement := &models.TreasuryDisbursement{
+		ProposalID:  proposal.ID,
+		RequesterID: requesterID,
+		Asset:       req.Asset,
+		Amount:      amount.String(),
+		Recipient:   req.Recipient,
+		Category:    req.Category,
+		Purpose:     req.Purpose,
+		Status:      "requested",
+	}
+	created, err := s.treasuryRepo.CreateDisbursement(disbursement)
+	if err != nil {
+		bizLog.DatabaseError("insert", "treasury_disbursements", "CreateDisbursement", err)
+		return nil, err
+	}
+	if !created {
+		return nil, errors.New("disbursement already requested")
+	}
+
+	bizLog.BusinessLogic("提案申请金库拨款", map[string]interface{}{
+		"proposal_id": proposal.ID,
+		"asset":       disbursement.Asset,
+		"amount":      disbursement.Amount,
+		"category":    disbursement.Category,
+	})
+
+	disbursement.Proposal = *proposal
+	disbursement.Status = treasuryDisbursementStatus(disbursement.Status, proposal.Status)
+	return disbursement, nil
+}
+
+// GetDisbursement 获取拨款申请
+func (s *TreasuryService) GetDisbursement(ctx context.Context, id int64) (*models.TreasuryDisbursement, error) {
+	disbursement, err := s.treasuryRepo.GetDisbursement(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("disbursement not found")
+		}
+		return nil, err
+	}
+	disbursement.Status = treasuryDisbursementStatus(disbursement.Status, disbursement.Proposal.Status)
+	return disbursement, nil
+}
+
+// ListDisbursements 分页获取拨款申请
+func (s *TreasuryService) ListDisbursements(ctx context.Context, category string, page, limit int) ([]models.TreasuryDisbursement, int64, error) {
+	offset := (page - 1) * limit
+	disbursements, err := s.treasuryRepo.ListDisbursements(category, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.treasuryRepo.CountDisbursements(category)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range disbursements {
+		disbursements[i].Status = treasuryDisbursementStatus(disbursements[i].Status, disbursements[i].Proposal.Status)
+	}
+	return disbursements, total, nil
+}
+
+// LinkChainProposal 为待执行的拨款登记金库合约中的提案ID，索引到该提案的执行事件时据此更新拨款
+func (s *TreasuryService) LinkChainProposal(ctx context.Context, id int64, chainProposalID string) (*models.TreasuryDisbursement, error) {
+	value, ok := new(big.Int).SetString(chainProposalID, 10)
+	if !ok || value.Sign() < 0 {
+		return nil, errors.New("invalid chain proposal id")
+	}
+	chainProposalID = value.String()
+
+	disbursement, err := s.GetDisbursement(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	linked, err := s.treasuryRepo.LinkChainProposal(id, chainProposalID)
+	if err != nil {
+		return nil, err
+	}
+	if !linked {
+		if disbursement.Status == "executed" || disbursement.Status == "mismatched" {
+			return nil, errors.New("disbursement already executed")
+		}
+		return nil, errors.New("chain proposal already linked")
+	}
+
+	loggerpkg.NewBusinessLogger(ctx).BusinessLogic("登记拨款链上提案", map[string]interface{}{
+		"disbursement_id":   id,
+		"proposal_id":       disbursement.ProposalID,
+		"chain_proposal_id": chainProposalID,
+	})
+
+	disbursement.ChainProposalID = &chainProposalID
+	return disbursement, nil
+}
+
+// IndexFlows 从上次索引的位置开始拉取 BondlyTreasury 资金事件，直到已有足够确认数的最新区块
+// 提案执行事件按拨款登记的链上提案ID同步更新拨款状态
+func (s *TreasuryService) IndexFlows(ctx context.Context) (*dto.TreasuryIndexResult, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	if s.treasury == nil {
+		return nil, errors.New("treasury not available")
+	}
+
+	lastBlock, found, err := s.treasuryRepo.GetCursor(treasuryFlowCursor)
+	if err != nil {
+		return nil, err
+	}
+	from := treasuryIndexStart(lastBlock, found, s.cfg.StartBlock)
+
+	head, err := s.treasury.LatestBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.TreasuryIndexResult{FromBlock: int64(from), ToBlock: int64(from) - 1}
+	latest, ok := treasuryConfirmedBlock(head, s.cfg.Confirmations)
+	if !ok {
+		return result, nil
+	}
+	for from <= latest {
+		to := treasuryIndexRangeEnd(from, latest, s.cfg.BlockRange)
+
+		flows, err := s.treasury.FetchFlows(ctx, from, to)
+		if err != nil {
+			return result, err
+		}
+		for i := range flows {
+			created, updated, err := s.recordFlow(ctx, &flows[i])
+			if err != nil {
+				bizLog.DatabaseError("insert", "treasury_flows", "recordFlow", err)
+				return result, err
+			}
+			if created {
+				result.Flows++
+			}
+			if updated {
+				result.Disbursements++
+			}
+		}
+
+		if err := s.treasuryRepo.SaveCursor(treasuryFlowCursor, int64(to)); err != nil {
+			return result, err
+		}
+		result.ToBlock = int64(to)
+		from = to + 1
+	}
+
+	if result.Flows > 0 || result.Disbursements > 0 {
+		bizLog.BusinessLogic("索引金库资金事件", map[string]interface{}{
+			"from_block":    result.FromBlock,
+			"to_block":      result.ToBlock,
+			"flows":         result.Flows,
+			"disbursements": result.Disbursements,
+		})
+	}
+
+	return result, nil
+}
+
+// recordFlow 保存一条资金流水，提案执行事件同时更新登记了该链上提案ID、待执行或执行失败的拨款
+func (s *TreasuryService) recordFlow(ctx context.Context, log *blockchain.TreasuryFlowLog) (created bool, updated bool, err error) {
+	flow := &models.TreasuryFlow{
+		TxHash:       log.TxHash,
+		LogIndex:     int64(log.LogIndex),
+		BlockNumber:  int64(log.BlockNumber),
+		BlockTime:    log.BlockTime,
+		Event:        log.Event,
+		Asset:        log.Asset,
+		Direction:    log.Direction,
+		Counterparty: log.Counterparty,
+		Amount:       "0",
+		Reason:       log.Reason,
+		Success:      log.Success,
+	}
+	if log.Amount != nil {
+		flow.Amount = log.Amount.String()
+	}
+
+	var disbursement *models.TreasuryDisbursement
+	if log.ProposalID != nil {
+		flow.ChainProposalID = log.ProposalID.String()
+		disbursement, err = s.treasuryRepo.GetDisbursementByChainProposal(flow.ChainProposalID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, false, err
+		}
+		if disbursement != nil {
+			flow.ProposalID = &disbursement.ProposalID
+		}
+	}
+
+	created, err = s.treasuryRepo.CreateFlow(flow)
+	if err != nil || !created || disbursement == nil {
+		return created, false, err
+	}
+
+	status := treasuryExecutionStatus(disbursement, log)
+	if status == "mismatched" {
+		loggerpkg.NewBusinessLogger(ctx).SecurityEvent("treasury_disbursement_mismatch", map[string]interface{}{
+			"disbursement_id":   disbursement.ID,
+			"chain_proposal_id": flow.ChainProposalID,
+			"expected_asset":    disbursement.Asset,
+			"expected_amount":   disbursement.Amount,
+			"expected_target":   disbursement.Recipient,
+			"asset":             flow.Asset,
+			"amount":            flow.Amount,
+			"target":            flow.Counterparty,
+			"tx_hash":           flow.TxHash,
+		})
+	}
+	executedAt := log.BlockTime
+	if executedAt.IsZero() {
+		executedAt = time.Now()
+	}
+	updated, err = s.treasuryRepo.MarkDisbursementExecuted(disbursement.ID, status, log.TxHash, executedAt)
+	return created, updated, err
+}
+
+// fillChainBalances 读取金库合约的实际余额和记账状态
+func (s *TreasuryService) fillChainBalances(ctx context.Context, assets []dto.TreasuryAssetSummary) error {
+	for i := range assets {
+		var balance *big.Int
+		var status *blockchain.TreasuryFundsStatus
+		var err error
+
+		switch assets[i].Asset {
+		case blockchain.TreasuryAssetETH:
+			if balance, err = s.treasury.EthBalance(ctx); err != nil {
+				return err
+			}
+			status, err = s.treasury.GetEthFundsStatus(ctx)
+		case blockchain.TreasuryAssetBOND:
+			if balance, err = s.treasury.BondBalance(ctx); err != nil {
+				return err
+			}
+			status, err = s.treasury.GetBondFundsStatus(ctx)
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		assets[i].Balance = balance.String()
+		assets[i].Total = status.Total.String()
+		assets[i].Available = status.Available.String()
+		assets[i].Locked = status.Locked.String()
+	}
+	return nil
+}
+
+// IsValidTreasuryCategory 判断是否为支持的预算类别
+func IsValidTreasuryCategory(category string) bool {
+	for _, c := range TreasuryBudgetCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// treasuryDisbursementStatus 计算拨款的展示状态
+// 已执行、执行失败或与申请不一致以链上结果为准，其余由提案状态推导：通过为 approved，否决为 rejected
+// 执行失败的拨款在链上重新执行成功后由索引任务更新为 executed
+func treasuryDisbursementStatus(status, proposalStatus string) string {
+	if status == "executed" || status == "failed" || status == "mismatched" {
+		return status
+	}
+	switch proposalStatus {
+	case "passed", "executed":
+		return "approved"
+	case "rejected":
+		return "rejected"
+	default:
+		return "requested"
+	}
+}
+
+// treasuryExecutionStatus 根据提案执行事件计算拨款状态：资产、金额或收款地址与申请不一致时为 mismatched
+func treasuryExecutionStatus(disbursement *models.TreasuryDisbursement, log *blockchain.TreasuryFlowLog) string {
+	amount, ok := new(big.Int).SetString(disbursement.Amount, 10)
+	if !ok || log.Amount == nil || amount.Cmp(log.Amount) != 0 ||
+		disbursement.Asset != log.Asset ||
+		!strings.EqualFold(disbursement.Recipient, log.Counterparty) {
+		return "mismatched"
+	}
+	if !log.Success {
+		return "failed"
+	}
+	return "executed"
+}
+
+// treasuryConfirmedBlock 已有 confirmations 个确认的最新区块，链高度不足时返回 false
+func treasuryConfirmedBlock(head, confirmations uint64) (uint64, bool) {
+	if head < confirmations {
+		return 0, false
+	}
+	return head - confirmations, true
+}
+
+// treasuryIndexStart 计算本次索引的起始区块
+func treasuryIndexStart(lastBlock int64, found bool, startBlock uint64) uint64 {
+	if !found || uint64(lastBlock) < startBlock {
+		return startBlock
+	}
+	return uint64(lastBlock) + 1
+}
+
+// treasuryIndexRangeEnd 计算单次查询的结束区块，跨度不超过 blockRange
+func treasuryIndexRangeEnd(from, latest, blockRange uint64) uint64 {
+	if blockRange == 0 {
+		return latest
+	}
+	to := from + blockRange - 1
+	if to > latest {
+		return latest
+	}
+	return to
+}
+
+// summarizeTreasuryFlows 将流水汇总整理为各资产的累计流入流出
+func summarizeTreasuryFlows(totals []repositories.TreasuryFlowTotal) []dto.TreasuryAssetSummary {
+	assets := make([]dto.TreasuryAssetSummary, 0, len(treasuryAssets))
+	for _, asset := range treasuryAssets {
+		inflow, outflow := new(big.Int), new(big.Int)
+		for _, total := range totals {
+			if total.Asset != asset {
+				continue
+			}
+			switch total.Direction {
+			case blockchain.TreasuryFlowIn:
+				inflow.Add(inflow, sumWei([]string{total.Total}))
+			case blockchain.TreasuryFlowOut:
+				outflow.Add(outflow, sumWei([]string{total.Total}))
+			}
+		}
+		assets = append(assets, dto.TreasuryAssetSummary{
+			Asset:   asset,
+			Inflow:  inflow.String(),
+			Outflow: outflow.String(),
+		})
+	}
+	return assets
+}
+
+// summarizeTreasuryBudgets 按预算类别和资产汇总拨款，失败和被否决的拨款不计入
+func summarizeTreasuryBudgets(totals []repositories.TreasuryDisbursementTotal) []dto.TreasuryBudgetSummary {
+	type amounts struct{ requested, approved, spent *big.Int }
+	grouped := make(map[string]*amounts)
+
+	for _, total := range totals {
+		key := total.Category + "/" + total.Asset
+		group, ok := grouped[key]
+		if !ok {
+			group = &amounts{new(big.Int), new(big.Int), new(big.Int)}
+			grouped[key] = group
+		}
+
+		value := sumWei([]string{total.Total})
+		switch treasuryDisbursementStatus(total.Status, total.ProposalStatus) {
+		case "requested":
+			group.requested.Add(group.requested, value)
+		case "approved":
+			group.approved.Add(group.approved, value)
+		case "executed":
+			group.spent.Add(group.spent, value)
+		}
+	}
+
+	budgets := make([]dto.TreasuryBudgetSummary, 0, len(grouped))
+	for _, category := range TreasuryBudgetCategories {
+		for _, asset := range treasuryAssets {
+			group, ok := grouped[category+"/"+asset]
+			if !ok {
+				continue
+			}
+			budgets = append(budgets, dto.TreasuryBudgetSummary{
+				Category:  category,
+				Asset:     asset,
+				Requested: group.requested.String(),
+				Approved:  group.approved.String(),
+				Spent:     group.spent.String(),
+			})
+		}
+	}
+	return budgets
+}
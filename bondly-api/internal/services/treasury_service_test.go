@@ -0,0 +1,70 @@
+package services
+
+import (
+	"bondly-api/internal/blockchain"
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreasuryDisbursementStatus(t *testing.T) {
+	assert.Equal(t, "requested", treasuryDisbursementStatus("requested", "active"))
+	assert.Equal(t, "approved", treasuryDisbursementStatus("requested", "passed"))
+	assert.Equal(t, "rejected", treasuryDisbursementStatus("requested", "rejected"))
+	assert.Equal(t, "executed", treasuryDisbursementStatus("executed", "passed"), "链上执行结果优先")
+	assert.Equal(t, "failed", treasuryDisbursementStatus("failed", "passed"))
+	assert.Equal(t, "mismatched", treasuryDisbursementStatus("mismatched", "passed"))
+}
+
+func TestTreasuryExecutionStatus(t *testing.T) {
+	disbursement := &models.TreasuryDisbursement{Asset: "BOND", Amount: "1000", Recipient: "0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b6"}
+	log := func(asset string, amount int64, target string, success bool) *blockchain.TreasuryFlowLog {
+		return &blockchain.TreasuryFlowLog{Asset: asset, Amount: big.NewInt(amount), Counterparty: target, Success: success}
+	}
+
+	assert.Equal(t, "executed", treasuryExecutionStatus(disbursement, log("BOND", 1000, "0x742D35CC6634C0532925A3B8D4C9DB96C4B4D8B6", true)), "地址不区分大小写")
+	assert.Equal(t, "failed", treasuryExecutionStatus(disbursement, log("BOND", 1000, disbursement.Recipient, false)))
+	assert.Equal(t, "mismatched", treasuryExecutionStatus(disbursement, log("BOND", 999, disbursement.Recipient, true)), "金额不一致")
+	assert.Equal(t, "mismatched", treasuryExecutionStatus(disbursement, log("BOND", 1000, "0x0000000000000000000000000000000000000001", true)), "收款地址不一致")
+	assert.Equal(t, "mismatched", treasuryExecutionStatus(disbursement, log("ETH", 1000, disbursement.Recipient, true)), "资产不一致")
+}
+
+func TestTreasuryConfirmedBlock(t *testing.T) {
+	block, ok := treasuryConfirmedBlock(1000, 12)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(988), block)
+
+	_, ok = treasuryConfirmedBlock(5, 12)
+	assert.False(t, ok, "链高度不足确认数时不索引")
+}
+
+func TestTreasuryIndexRange(t *testing.T) {
+	assert.Equal(t, uint64(100), treasuryIndexStart(0, false, 100), "首次索引从起始区块开始")
+	assert.Equal(t, uint64(151), treasuryIndexStart(150, true, 100))
+	assert.Equal(t, uint64(100), treasuryIndexStart(50, true, 100), "起始区块调大后跳过之前的区块")
+
+	assert.Equal(t, uint64(1099), treasuryIndexRangeEnd(100, 5000, 1000))
+	assert.Equal(t, uint64(500), treasuryIndexRangeEnd(100, 500, 1000))
+	assert.Equal(t, uint64(5000), treasuryIndexRangeEnd(100, 5000, 0))
+}
+
+func TestSummarizeTreasuryBudgets(t *testing.T) {
+	budgets := summarizeTreasuryBudgets([]repositories.TreasuryDisbursementTotal{
+		{Category: "grants", Asset: "BOND", Status: "requested", ProposalStatus: "active", Total: "5"},
+		{Category: "grants", Asset: "BOND", Status: "requested", ProposalStatus: "passed", Total: "7"},
+		{Category: "grants", Asset: "BOND", Status: "executed", ProposalStatus: "executed", Total: "11"},
+		{Category: "grants", Asset: "BOND", Status: "requested", ProposalStatus: "rejected", Total: "13"},
+		{Category: "development", Asset: "ETH", Status: "failed", ProposalStatus: "passed", Total: "17"},
+	})
+
+	assert.Len(t, budgets, 2)
+	assert.Equal(t, "development", budgets[0].Category, "按预算类别顺序排列")
+	assert.Equal(t, "0", budgets[0].Spent, "执行失败不计入")
+	assert.Equal(t, "grants", budgets[1].Category)
+	assert.Equal(t, "5", budgets[1].Requested)
+	assert.Equal(t, "7", budgets[1].Approved)
+	assert.Equal(t, "11", budgets[1].Spent)
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserBlockService 用户屏蔽
+type UserBlockService struct {
+	blockRepo *repositories.UserBlockRepository
+	userRepo  *repositories.UserRepository
+}
+
+func NewUserBlockService(blockRepo *repositories.UserBlockRepository, userRepo *repositories.UserRepository) *UserBlockService {
+	return &UserBlockService{
+		blockRepo: blockRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// BlockUser 屏蔽用户，重复屏蔽不报错
+func (s *UserBlockService) BlockUser(ctx context.Context, blockerID, blockedID int64) error {
+	if blockerID == blockedID {
+		return errors.New("cannot block yourself")
+	}
+	if _, err := s.userRepo.GetByID(blockedID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user not found")
+		}
+		return err
+	}
+	return s.blockRepo.Create(&models.UserBlock{BlockerID: blockerID, BlockedID: blockedID, CreatedAt: time.Now()})
+}
+
+// UnblockUser 取消屏蔽，未屏蔽时不报错
+func (s *UserBlockService) UnblockUser(ctx context.Context, blockerID, blockedID int64) error {
+	return s.blockRepo.Delete(blockerID, blockedID)
+}
+
+// ListBlocked 获取用户屏蔽的人列表
+func (s *UserBlockService) ListBlocked(ctx context.Context, blockerID int64, page, limit int) ([]models.UserBlock, int64, error) {
+	blocks, err := s.blockRepo.ListBlocked(blockerID, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.blockRepo.CountBlocked(blockerID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return blocks, total, nil
+}
+
+// IsBlocked 判断两个用户之间是否存在任一方向的屏蔽，查询失败时按未屏蔽处理
+func (s *UserBlockService) IsBlocked(ctx context.Context, userID, otherID int64) bool {
+	if s == nil || userID == 0 || otherID == 0 {
+		return false
+	}
+	blocked, err := s.blockRepo.ExistsEither(userID, otherID)
+	return err == nil && blocked
+}
@@ -8,12 +8,20 @@ import (
 )
 
 type UserFollowService struct {
-	userFollowRepo *repositories.UserFollowRepository
+	userFollowRepo  *repositories.UserFollowRepository
+	feed            *FeedService
+	reputationRules *ReputationRuleService
+	achievements    *AchievementService
+	notifications   *NotificationService
 }
 
-func NewUserFollowService(userFollowRepo *repositories.UserFollowRepository) *UserFollowService {
+func NewUserFollowService(userFollowRepo *repositories.UserFollowRepository, feed *FeedService, reputationRules *ReputationRuleService, achievements *AchievementService, notifications *NotificationService) *UserFollowService {
 	return &UserFollowService{
-		userFollowRepo: userFollowRepo,
+		userFollowRepo:  userFollowRepo,
+		feed:            feed,
+		reputationRules: reputationRules,
+		achievements:    achievements,
+		notifications:   notifications,
 	}
 }
 
@@ -38,7 +46,21 @@ func (s *UserFollowService) FollowUser(ctx context.Context, followerID, followed
 		FollowedID: followedID,
 	}
 
-	return s.userFollowRepo.CreateFollow(follow)
+	if err := s.userFollowRepo.CreateFollow(follow); err != nil {
+		return err
+	}
+
+	s.feed.InvalidateFeed(ctx, followerID)
+	s.reputationRules.OnFollow(ctx, followerID, followedID)
+	s.achievements.OnFollow(ctx, followedID)
+	s.notifications.Notify(ctx, &models.Notification{
+		UserID:      followedID,
+		Type:        NotificationTypeFollow,
+		ActorID:     followerID,
+		SubjectType: "user",
+		SubjectID:   followerID,
+	})
+	return nil
 }
 
 // UnfollowUser 取消关注用户
@@ -52,7 +74,12 @@ func (s *UserFollowService) UnfollowUser(ctx context.Context, followerID, follow
 		return errors.New("not following this user")
 	}
 
-	return s.userFollowRepo.DeleteFollow(followerID, followedID)
+	if err := s.userFollowRepo.DeleteFollow(followerID, followedID); err != nil {
+		return err
+	}
+
+	s.feed.OnUnfollow(ctx, followerID, followedID)
+	return nil
 }
 
 // IsFollowing 检查是否关注
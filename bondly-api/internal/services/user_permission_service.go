@@ -0,0 +1,102 @@
+package services
+
+import (
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// grantablePermissions 可以授予用户的权限
+var grantablePermissions = map[string]bool{
+	models.PermissionReputationManager: true,
+	models.PermissionTagModerator:      true,
+	models.PermissionContentModerator:  true,
+	models.PermissionCommentModerator:  true,
+}
+
+// UserPermissionService 用户权限授予与撤销
+type UserPermissionService struct {
+	permissionRepo *repositories.UserPermissionRepository
+	userRepo       *repositories.UserRepository
+}
+
+func NewUserPermissionService(permissionRepo *repositories.UserPermissionRepository, userRepo *repositories.UserRepository) *UserPermissionService {
+	return &UserPermissionService{
+		permissionRepo: permissionRepo,
+		userRepo:       userRepo,
+	}
+}
+
+// ListPermissions 获取用户已授予的权限
+func (s *UserPermissionService) ListPermissions(ctx context.Context, userID int64) ([]models.UserPermission, error) {
+	if err := s.ensureUser(userID); err != nil {
+		return nil, err
+	}
+	return s.permissionRepo.ListByUser(userID)
+}
+
+// GrantPermission 授予用户权限
+func (s *UserPermissionService) GrantPermission(ctx context.Context, userID int64, permission string, grantedBy int64) error {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	if !grantablePermissions[permission] {
+		return errors.New("unknown permission")
+	}
+	if err := s.ensureUser(userID); err != nil {
+		return err
+	}
+
+	if err := s.permissionRepo.Grant(&models.UserPermission{
+		UserID:     userID,
+		Permission: permission,
+		GrantedBy:  grantedBy,
+	}); err != nil {
+		bizLog.DatabaseError("insert", "user_permissions", "Grant", err)
+		return err
+	}
+
+	bizLog.SecurityEvent("permission_granted", map[string]interface{}{
+		"user_id":    userID,
+		"permission": permission,
+		"granted_by": grantedBy,
+		"trace_id":   traceIDFromContext(ctx),
+	})
+	return nil
+}
+
+// RevokePermission 撤销用户权限
+func (s *UserPermissionService) RevokePermission(ctx context.Context, userID int64, permission string, revokedBy int64) error {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	revoked, err := s.permissionRepo.Revoke(userID, permission)
+	if err != nil {
+		bizLog.DatabaseError("delete", "user_permissions", "Revoke", err)
+		return err
+	}
+	if !revoked {
+		return errors.New("permission not granted")
+	}
+
+	bizLog.SecurityEvent("permission_revoked", map[string]interface{}{
+		"user_id":    userID,
+		"permission": permission,
+		"revoked_by": revokedBy,
+		"trace_id":   traceIDFromContext(ctx),
+	})
+	return nil
+}
+
+// ensureUser 检查用户是否存在
+func (s *UserPermissionService) ensureUser(userID int64) error {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user not found")
+		}
+		return err
+	}
+	return nil
+}
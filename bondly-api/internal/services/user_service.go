@@ -379,18 +379,6 @@ func (s *UserService) UpdateLastLogin(ctx context.Context, id int64) error {
 	return nil
 }
 
-// UpdateReputationScore 更新用户声誉分数
-func (s *UserService) UpdateReputationScore(ctx context.Context, id int64, score int) error {
-	if err := s.userRepo.UpdateReputationScore(id, score); err != nil {
-		return errors.NewUserUpdateFailedError(err)
-	}
-
-	// 清除缓存
-	s.clearUserCache(ctx, id)
-
-	return nil
-}
-
 // ListUsers 获取用户列表
 func (s *UserService) ListUsers(ctx context.Context, offset, limit int) ([]models.User, error) {
 	return s.userRepo.List(offset, limit)
@@ -0,0 +1,379 @@
+package services
+
+import (
+	"bondly-api/internal/dto"
+	loggerpkg "bondly-api/internal/logger"
+	"bondly-api/internal/models"
+	"bondly-api/internal/repositories"
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// VoteService 提案投票服务
+type VoteService struct {
+	voteRepo          *repositories.VoteRepository
+	proposalRepo      *repositories.ProposalRepository
+	proposals         *ProposalService
+	delegationService *DelegationService
+	strategies        map[string]VotingStrategy
+	achievements      *AchievementService
+	realtime          *RealtimeService
+}
+
+// NewVoteService 创建提案投票服务
+func NewVoteService(voteRepo *repositories.VoteRepository, proposalRepo *repositories.ProposalRepository, proposals *ProposalService, delegationService *DelegationService, strategies map[string]VotingStrategy, achievements *AchievementService, realtime *RealtimeService) *VoteService {
+	return &VoteService{
+		voteRepo:          voteRepo,
+		proposalRepo:      proposalRepo,
+		proposals:         proposals,
+		delegationService: delegationService,
+		strategies:        strategies,
+		achievements:      achievements,
+		realtime:          realtime,
+	}
+}
+
+// CastVote 对提案投票，投票和重新计票在锁定提案的同一事务中完成
+func (s *VoteService) CastVote(ctx context.Context, proposalID, voterID int64, support bool) (*models.Vote, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	proposal, err := s.proposalRepo.GetByID(proposalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("proposal not found")
+		}
+		return nil, err
+	}
+
+	strategy, err := s.strategyFor(proposal.VotingStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if proposal.Status != "active" || now.Before(proposal.StartTime) || now.After(proposal.EndTime) {
+		return nil, errors.New("proposal is not open for voting")
+	}
+
+	// 链上投票权在加锁前读取并保存快照，提案行锁内不发起链上请求
+	if err := s.snapshotPower(ctx, proposal, strategy, voterID); err != nil {
+		bizLog.ThirdPartyError("voting_strategy", "snapshot_power", map[string]interface{}{"proposal_id": proposalID, "voter_id": voterID}, err)
+		return nil, err
+	}
+
+	var updated *models.Vote
+	var changed bool
+	err = s.voteRepo.WithProposalLock(proposalID, func(tx *repositories.VoteRepository) error {
+		if _, err := tx.GetByProposalAndVoter(proposalID, voterID); err == nil {
+			return errors.New("already voted")
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		vote := &models.Vote{
+			ProposalID: proposalID,
+			VoterID:    voterID,
+			Vote:       support,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		if err := tx.Create(vote); err != nil {
+			bizLog.DatabaseError("insert", "votes", "Create", err)
+			return err
+		}
+
+		if changed, err = s.tally(ctx, tx, proposal, now); err != nil {
+			bizLog.DatabaseError("update", "votes", "RecalculateTally", err)
+			return err
+		}
+
+		// 返回重新计票后的权重
+		updated, err = tx.GetByProposalAndVoter(proposalID, voterID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		s.publishTally(ctx, proposal)
+	}
+
+	bizLog.BusinessLogic("提案投票成功", map[string]interface{}{
+		"proposal_id":      proposalID,
+		"voter_id":         voterID,
+		"vote":             support,
+		"weight":           updated.Weight,
+		"delegated_weight": updated.DelegatedWeight,
+	})
+
+	s.achievements.OnVoteCast(ctx, voterID)
+
+	return updated, nil
+}
+
+// RecalculateTally 锁定提案后按投票策略重新计算所有投票的权重和提案票数，票数变化时推送
+func (s *VoteService) RecalculateTally(ctx context.Context, proposal *models.Proposal, asOf time.Time) error {
+	var changed bool
+	err := s.voteRepo.WithProposalLock(proposal.ID, func(tx *repositories.VoteRepository) error {
+		var err error
+		changed, err = s.tally(ctx, tx, proposal, asOf)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if changed {
+		s.publishTally(ctx, proposal)
+	}
+	return nil
+}
+
+// tally 以提案开始时间为快照计算投票权重并写回，返回票数是否变化
+// asOf 为计票时间，信念投票的权重随投票持续时间累积，计票时间不超过提案结束时间
+func (s *VoteService) tally(ctx context.Context, tx *repositories.VoteRepository, proposal *models.Proposal, asOf time.Time) (bool, error) {
+	strategy, err := s.strategyFor(proposal.VotingStrategy)
+	if err != nil {
+		return false, err
+	}
+	if asOf.After(proposal.EndTime) {
+		asOf = proposal.EndTime
+	}
+
+	votes, err := tx.ListAllByProposal(proposal.ID)
+	if err != nil {
+		return false, err
+	}
+
+	voterIDs := make([]int64, 0, len(votes))
+	for _, vote := range votes {
+		voterIDs = append(voterIDs, vote.VoterID)
+	}
+
+	var savedPower map[int64]int64
+	if readsChainPower(strategy) {
+		if savedPower, err = tx.ListPowerSnapshots(proposal.ID); err != nil {
+			return false, err
+		}
+	}
+	own, delegated, err := s.delegationService.ComputeVoteWeights(ctx, proposal.Topic, proposal.StartTime, voterIDs, strategy, savedPower)
+	if err != nil {
+		return false, err
+	}
+
+	var votesFor, votesAgainst int64
+	for _, vote := range votes {
+		delegatedWeight := strategy.Weight(delegated[vote.VoterID], vote.CreatedAt, asOf)
+		weight := strategy.Weight(own[vote.VoterID], vote.CreatedAt, asOf) + delegatedWeight
+		if weight != vote.Weight || delegatedWeight != vote.DelegatedWeight {
+			if err := tx.UpdateWeight(vote.ID, weight, delegatedWeight); err != nil {
+				return false, err
+			}
+		}
+		if vote.Vote {
+			votesFor += weight
+		} else {
+			votesAgainst += weight
+		}
+	}
+
+	changed := votesFor != proposal.VotesFor || votesAgainst != proposal.VotesAgainst
+	proposal.VotesFor = votesFor
+	proposal.VotesAgainst = votesAgainst
+	if err := tx.UpdateTally(proposal.ID, votesFor, votesAgainst); err != nil {
+		return false, err
+	}
+	return changed, nil
+}
+
+// snapshotPower 为读取链上数据的投票策略保存投票人和提案委托图中用户的投票权快照，已保存的用户不再读取
+// 委托图中的用户在提案的第一票时保存，其余投票人在各自首次投票时保存，之后的计票和结算都使用同一数值
+// 已投票但还没有快照的用户（快照上线前的投票）一并补齐
+func (s *VoteService) snapshotPower(ctx context.Context, proposal *models.Proposal, strategy VotingStrategy, voterID int64) error {
+	if !readsChainPower(strategy) {
+		return nil
+	}
+	saved, err := s.voteRepo.ListPowerSnapshots(proposal.ID)
+	if err != nil {
+		return err
+	}
+	voterIDs, err := s.voteRepo.ListVoterIDs(proposal.ID)
+	if err != nil {
+		return err
+	}
+	power, err := s.delegationService.VotingPowerAt(ctx, proposal.Topic, proposal.StartTime, append(voterIDs, voterID), strategy, saved)
+	if err != nil {
+		return err
+	}
+	return s.voteRepo.CreatePowerSnapshots(proposal.ID, power, time.Now())
+}
+
+// publishTally 推送提案的最新票数
+func (s *VoteService) publishTally(ctx context.Context, proposal *models.Proposal) {
+	s.realtime.Publish(ctx, ProposalVotesTopic(proposal.ID), RealtimeEventVotesUpdated, map[string]interface{}{
+		"proposal_id":   proposal.ID,
+		"votes_for":     proposal.VotesFor,
+		"votes_against": proposal.VotesAgainst,
+	})
+}
+
+// GetResult 获取提案计票结果，只读取已保存的票数；信念投票由投票和定时任务重新计票
+func (s *VoteService) GetResult(ctx context.Context, proposalID int64) (*dto.ProposalResultResponse, error) {
+	proposal, err := s.proposalRepo.GetByID(proposalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("proposal not found")
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	talliedAt := proposal.UpdatedAt
+	if proposal.VotingStrategy == VotingStrategyConviction && talliedAt.After(proposal.EndTime) {
+		talliedAt = proposal.EndTime
+	}
+
+	totalVoters, err := s.voteRepo.CountByProposal(proposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	strategy := proposal.VotingStrategy
+	if strategy == "" {
+		strategy = DefaultVotingStrategy
+	}
+
+	total := proposal.VotesFor + proposal.VotesAgainst
+	result := &dto.ProposalResultResponse{
+		ProposalID:     proposal.ID,
+		VotingStrategy: strategy,
+		Status:         proposal.Status,
+		VotesFor:       proposal.VotesFor,
+		VotesAgainst:   proposal.VotesAgainst,
+		TotalWeight:    total,
+		TotalVoters:    totalVoters,
+		Passing:        proposal.VotesFor > proposal.VotesAgainst,
+		Final:          now.After(proposal.EndTime),
+		TalliedAt:      talliedAt.Format(time.RFC3339),
+		EndTime:        proposal.EndTime.Format(time.RFC3339),
+	}
+	if total > 0 {
+		result.ForPercentage = math.Round(float64(proposal.VotesFor)*10000/float64(total)) / 100
+	}
+
+	return result, nil
+}
+
+// TallyConviction 按当前时间重新计算信念投票提案的票数，返回处理的提案数，供定时任务调用
+// 结束后的提案还会再计票一次，使结果对应结束时间的权重
+func (s *VoteService) TallyConviction(ctx context.Context) (int, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+	now := time.Now()
+
+	proposals, err := s.proposalRepo.ListTallyDue(VotingStrategyConviction, now)
+	if err != nil {
+		bizLog.DatabaseError("select", "proposals", "ListTallyDue", err)
+		return 0, err
+	}
+
+	tallied := 0
+	for i := range proposals {
+		if err := ctx.Err(); err != nil {
+			return tallied, err
+		}
+		if err := s.RecalculateTally(ctx, &proposals[i], now); err != nil {
+			bizLog.DatabaseError("update", "votes", "RecalculateTally", err)
+			continue
+		}
+		tallied++
+	}
+	return tallied, nil
+}
+
+// FinalizeEnded 按结束时间重新计票并结算已结束的提案，赞成票多于反对票为 passed，否则为 rejected，
+// 返回结算的提案数，供定时任务调用。状态在锁定提案的事务中只从 active 变更一次，
+// 多个实例同时执行时只有完成变更的实例发放提案通过的声誉和成就
+func (s *VoteService) FinalizeEnded(ctx context.Context) (int, error) {
+	bizLog := loggerpkg.NewBusinessLogger(ctx)
+
+	proposals, err := s.proposalRepo.ListEndedActive(time.Now())
+	if err != nil {
+		bizLog.DatabaseError("select", "proposals", "ListEndedActive", err)
+		return 0, err
+	}
+
+	finalized := 0
+	for i := range proposals {
+		if err := ctx.Err(); err != nil {
+			return finalized, err
+		}
+		proposal := &proposals[i]
+
+		var claimed bool
+		err := s.voteRepo.WithProposalLock(proposal.ID, func(tx *repositories.VoteRepository) error {
+			if _, err := s.tally(ctx, tx, proposal, proposal.EndTime); err != nil {
+				return err
+			}
+			status := "rejected"
+			if proposal.VotesFor > proposal.VotesAgainst {
+				status = "passed"
+			}
+			var err error
+			if claimed, err = tx.FinalizeProposal(proposal.ID, status); err != nil {
+				return err
+			}
+			proposal.Status = status
+			return nil
+		})
+		if err != nil {
+			bizLog.DatabaseError("update", "proposals", "FinalizeProposal", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		s.proposals.onProposalFinalized(ctx, proposal)
+		finalized++
+	}
+	return finalized, nil
+}
+
+// strategyFor 获取提案使用的投票策略，历史提案未设置时使用默认策略
+func (s *VoteService) strategyFor(name string) (VotingStrategy, error) {
+	if name == "" {
+		name = DefaultVotingStrategy
+	}
+	strategy, ok := s.strategies[name]
+	if !ok {
+		return nil, errors.New("voting strategy not available")
+	}
+	return strategy, nil
+}
+
+// ListVotes 分页获取提案的投票列表
+func (s *VoteService) ListVotes(ctx context.Context, proposalID int64, page, limit int) ([]models.Vote, int64, error) {
+	if _, err := s.proposalRepo.GetByID(proposalID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, errors.New("proposal not found")
+		}
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+
+	votes, err := s.voteRepo.ListByProposal(proposalID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.voteRepo.CountByProposal(proposalID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return votes, total, nil
+}
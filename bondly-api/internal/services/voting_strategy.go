@@ -0,0 +1,228 @@
+package services
+
+import (
+	"bondly-api/internal/models"
+	"context"
+	"math"
+	"math/big"
+	"time"
+)
+
+// 投票策略
+const (
+	VotingStrategySimple         = "simple"          // 一人一票
+	VotingStrategyLinear         = "linear"          // 按声誉线性加权
+	VotingStrategyQuadratic      = "quadratic"       // 声誉开平方
+	VotingStrategyQuadraticStake = "quadratic_stake" // 质押 BOND 开平方
+	VotingStrategyConviction     = "conviction"      // 信念投票，权重随投票时长累积
+)
+
+// DefaultVotingStrategy 默认投票策略
+const DefaultVotingStrategy = VotingStrategyLinear
+
+// weiPerBond 1 BOND = 1e18 wei
+var weiPerBond = big.NewInt(1_000_000_000_000_000_000)
+
+// VotingStrategy 投票权重策略
+type VotingStrategy interface {
+	// Name 策略名称
+	Name() string
+	// Power 计算用户的基础投票权，基础投票权会沿委托链传递
+	Power(ctx context.Context, users []models.User) (map[int64]int64, error)
+	// Weight 将基础投票权换算为计票权重，votedAt 为投票时间，asOf 为计票时间
+	Weight(power int64, votedAt, asOf time.Time) int64
+}
+
+// chainPowerStrategy 基础投票权读取链上当前数据、无法回推到提案开始时间的策略
+// 这类策略在用户首次参与计票时于事务外读取一次并保存为快照，计票只使用快照
+type chainPowerStrategy interface {
+	readsChainPower()
+}
+
+// readsChainPower 判断策略的投票权是否来自链上当前数据
+func readsChainPower(strategy VotingStrategy) bool {
+	_, ok := strategy.(chainPowerStrategy)
+	return ok
+}
+
+// StakeReader 质押数量查询接口，由 blockchain.GeneralStaking 实现
+type StakeReader interface {
+	// GetStakedBatch 批量获取地址的质押数量（wei），结果以传入地址为键
+	GetStakedBatch(ctx context.Context, userAddresses []string) (map[string]*big.Int, error)
+}
+
+// IsValidVotingStrategy 检查投票策略名称是否受支持
+func IsValidVotingStrategy(name string) bool {
+	switch name {
+	case VotingStrategySimple, VotingStrategyLinear, VotingStrategyQuadratic,
+		VotingStrategyQuadraticStake, VotingStrategyConviction:
+		return true
+	}
+	return false
+}
+
+// NewVotingStrategies 创建可用的投票策略，stakeReader 为空时不提供质押二次方投票
+func NewVotingStrategies(stakeReader StakeReader, convictionHalfLife time.Duration) map[string]VotingStrategy {
+	strategies := map[string]VotingStrategy{
+		VotingStrategySimple:     simpleStrategy{},
+		VotingStrategyLinear:     linearStrategy{},
+		VotingStrategyQuadratic:  quadraticStrategy{},
+		VotingStrategyConviction: convictionStrategy{halfLife: convictionHalfLife},
+	}
+	if stakeReader != nil {
+		strategies[VotingStrategyQuadraticStake] = quadraticStakeStrategy{stakeReader: stakeReader}
+	}
+	return strategies
+}
+
+// simpleStrategy 一人一票
+type simpleStrategy struct{}
+
+func (simpleStrategy) Name() string { return VotingStrategySimple }
+
+func (simpleStrategy) Power(ctx context.Context, users []models.User) (map[int64]int64, error) {
+	power := make(map[int64]int64, len(users))
+	for _, user := range users {
+		power[user.ID] = 1
+	}
+	return power, nil
+}
+
+func (simpleStrategy) Weight(power int64, votedAt, asOf time.Time) int64 { return power }
+
+// linearStrategy 按声誉分数线性加权
+type linearStrategy struct{}
+
+func (linearStrategy) Name() string { return VotingStrategyLinear }
+
+func (linearStrategy) Power(ctx context.Context, users []models.User) (map[int64]int64, error) {
+	return reputationPower(users), nil
+}
+
+func (linearStrategy) Weight(power int64, votedAt, asOf time.Time) int64 { return power }
+
+// quadraticStrategy 声誉分数开平方，削弱高声誉用户的影响
+type quadraticStrategy struct{}
+
+func (quadraticStrategy) Name() string { return VotingStrategyQuadratic }
+
+func (quadraticStrategy) Power(ctx context.Context, users []models.User) (map[int64]int64, error) {
+	power := reputationPower(users)
+	for userID, amount := range power {
+		power[userID] = isqrt(amount)
+	}
+	return power, nil
+}
+
+func (quadraticStrategy) Weight(power int64, votedAt, asOf time.Time) int64 { return power }
+
+// quadraticStakeStrategy 质押 BOND 数量开平方，读取链上当前质押量，所有用户合并为一次批量请求；结果保存为提案的投票权快照
+type quadraticStakeStrategy struct {
+	stakeReader StakeReader
+}
+
+func (quadraticStakeStrategy) Name() string { return VotingStrategyQuadraticStake }
+
+func (s quadraticStakeStrategy) Power(ctx context.Context, users []models.User) (map[int64]int64, error) {
+	addresses := make([]string, 0, len(users))
+	for i := range users {
+		if address := votingAddress(&users[i]); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	if len(addresses) == 0 {
+		return map[int64]int64{}, nil
+	}
+
+	staked, err := s.stakeReader.GetStakedBatch(ctx, addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	power := make(map[int64]int64, len(users))
+	for i := range users {
+		if address := votingAddress(&users[i]); address != "" {
+			power[users[i].ID] = stakePower(staked[address])
+		}
+	}
+	return power, nil
+}
+
+func (quadraticStakeStrategy) Weight(power int64, votedAt, asOf time.Time) int64 { return power }
+
+func (quadraticStakeStrategy) readsChainPower() {}
+
+// convictionStrategy 信念投票，以声誉为基础，权重随投票持续时间按半衰期逼近满额
+type convictionStrategy struct {
+	halfLife time.Duration
+}
+
+func (convictionStrategy) Name() string { return VotingStrategyConviction }
+
+func (convictionStrategy) Power(ctx context.Context, users []models.User) (map[int64]int64, error) {
+	return reputationPower(users), nil
+}
+
+func (s convictionStrategy) Weight(power int64, votedAt, asOf time.Time) int64 {
+	return convictionWeight(power, asOf.Sub(votedAt), s.halfLife)
+}
+
+// reputationPower 以声誉分数作为基础投票权
+func reputationPower(users []models.User) map[int64]int64 {
+	power := make(map[int64]int64, len(users))
+	for _, user := range users {
+		power[user.ID] = int64(user.ReputationScore)
+	}
+	return power
+}
+
+// votingAddress 获取用户用于查询链上质押的地址，优先使用绑定钱包
+func votingAddress(user *models.User) string {
+	if user.WalletAddress != nil && *user.WalletAddress != "" {
+		return *user.WalletAddress
+	}
+	if user.CustodyWalletAddress != nil && *user.CustodyWalletAddress != "" {
+		return *user.CustodyWalletAddress
+	}
+	return ""
+}
+
+// stakePower 将质押数量（wei）换算为整 BOND 后开平方
+func stakePower(staked *big.Int) int64 {
+	if staked == nil || staked.Sign() <= 0 {
+		return 0
+	}
+	bonds := new(big.Int).Quo(staked, weiPerBond)
+	root := new(big.Int).Sqrt(bonds)
+	if !root.IsInt64() {
+		return math.MaxInt64
+	}
+	return root.Int64()
+}
+
+// isqrt 整数平方根（向下取整）
+func isqrt(n int64) int64 {
+	if n <= 0 {
+		return 0
+	}
+	root := int64(math.Sqrt(float64(n)))
+	for root*root > n {
+		root--
+	}
+	for (root+1)*(root+1) <= n {
+		root++
+	}
+	return root
+}
+
+// convictionWeight 计算信念权重：power * (1 - 0.5^(elapsed/halfLife))
+func convictionWeight(power int64, elapsed, halfLife time.Duration) int64 {
+	if power <= 0 || elapsed <= 0 {
+		return 0
+	}
+	if halfLife <= 0 {
+		return power
+	}
+	ratio := 1 - math.Pow(0.5, float64(elapsed)/float64(halfLife))
+	return int64(math.Round(float64(power) * ratio))
+}
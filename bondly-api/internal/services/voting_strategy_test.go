@@ -0,0 +1,94 @@
+package services
+
+import (
+	"bondly-api/internal/models"
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsqrt(t *testing.T) {
+	assert.Equal(t, int64(0), isqrt(-4))
+	assert.Equal(t, int64(0), isqrt(0))
+	assert.Equal(t, int64(3), isqrt(15))
+	assert.Equal(t, int64(4), isqrt(16))
+	assert.Equal(t, int64(1000), isqrt(1_000_000))
+}
+
+func TestStakePower(t *testing.T) {
+	hundredBond := new(big.Int).Mul(big.NewInt(100), weiPerBond)
+	assert.Equal(t, int64(10), stakePower(hundredBond))
+	assert.Equal(t, int64(0), stakePower(big.NewInt(999)), "不足 1 BOND 不计投票权")
+	assert.Equal(t, int64(0), stakePower(nil))
+}
+
+func TestConvictionWeight(t *testing.T) {
+	halfLife := 72 * time.Hour
+
+	assert.Equal(t, int64(0), convictionWeight(100, 0, halfLife))
+	assert.Equal(t, int64(50), convictionWeight(100, halfLife, halfLife))
+	assert.Equal(t, int64(75), convictionWeight(100, 2*halfLife, halfLife))
+	assert.Equal(t, int64(100), convictionWeight(100, time.Hour, 0), "未配置半衰期时不做衰减")
+}
+
+func TestQuadraticStrategyPower(t *testing.T) {
+	users := []models.User{
+		{ID: 1, ReputationScore: 100},
+		{ID: 2, ReputationScore: 10000},
+	}
+
+	power, err := quadraticStrategy{}.Power(context.Background(), users)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), power[1])
+	assert.Equal(t, int64(100), power[2])
+
+	simple, err := simpleStrategy{}.Power(context.Background(), users)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), simple[2])
+}
+
+// batchStakeReader 记录批量请求次数的质押查询
+type batchStakeReader struct {
+	staked map[string]*big.Int
+	calls  int
+}
+
+func (r *batchStakeReader) GetStakedBatch(ctx context.Context, userAddresses []string) (map[string]*big.Int, error) {
+	r.calls++
+	result := make(map[string]*big.Int, len(userAddresses))
+	for _, address := range userAddresses {
+		result[address] = r.staked[address]
+	}
+	return result, nil
+}
+
+func TestQuadraticStakeStrategyPower_SingleBatch(t *testing.T) {
+	wallet := "0x0000000000000000000000000000000000000001"
+	custody := "0x0000000000000000000000000000000000000002"
+	reader := &batchStakeReader{staked: map[string]*big.Int{
+		wallet:  new(big.Int).Mul(big.NewInt(100), weiPerBond),
+		custody: new(big.Int).Mul(big.NewInt(400), weiPerBond),
+	}}
+	users := []models.User{
+		{ID: 1, WalletAddress: &wallet},
+		{ID: 2, CustodyWalletAddress: &custody},
+		{ID: 3},
+	}
+
+	power, err := quadraticStakeStrategy{stakeReader: reader}.Power(context.Background(), users)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reader.calls, "所有用户合并为一次批量请求")
+	assert.Equal(t, int64(10), power[1])
+	assert.Equal(t, int64(20), power[2])
+	assert.Equal(t, int64(0), power[3], "没有钱包的用户不计投票权")
+}
+
+func TestReadsChainPower(t *testing.T) {
+	strategies := NewVotingStrategies(&batchStakeReader{}, time.Hour)
+	for name, strategy := range strategies {
+		assert.Equal(t, name == VotingStrategyQuadraticStake, readsChainPower(strategy), name)
+	}
+}
@@ -58,8 +58,8 @@ echo "🔍 4. 测试根据钱包地址获取声誉（示例地址）"
 test_api "GET" "/reputation/address/0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b6" "" "根据钱包地址获取声誉"
 
 echo ""
-echo "🔍 5. 测试同步链上声誉（用户ID: 1）"
-test_api "POST" "/reputation/sync/1" "" "从链上同步声誉分数"
+echo "🔍 5. 测试同步链上声誉（需要声誉管理权限，预期会失败）"
+test_api "POST" "/reputation/sync/1" "" "从链上同步声誉分数（无认证）"
 
 echo ""
 echo "🔍 6. 测试增加声誉分数（需要认证，预期会失败）"
@@ -81,7 +81,7 @@ test_api "POST" "/reputation/subtract" "$subtract_reputation_data" "减少声誉
 
 echo ""
 echo "🏁 声誉系统 API 测试完成！"
-echo "💡 注意: 增加/减少声誉的API需要认证令牌，未认证的请求会返回401错误。"
+echo "💡 注意: 增加/减少/同步声誉的API需要 reputation_manager 权限（管理员默认拥有）和 Idempotency-Key 请求头，未认证的请求会返回401错误。"
 echo "🔗 API文档: http://localhost:8080/swagger/index.html"
 
 # 清理临时文件